@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/audit"
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	origLog := auditLog
+	t.Cleanup(func() { auditLog = origLog })
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web"}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{web}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, web, "SIGTERM", false).Return(nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, runReport(t, client, "--log-level", "error", "--audit-log", path, "kill", "--signal", "SIGTERM", "web"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 3, "start, targets and kill are logged whatever the log level")
+	assert.Contains(t, string(lines[0]), `"event":"`+audit.EventStart+`"`)
+	assert.Contains(t, string(lines[1]), `"targets":[{"id":"w1","name":"web"}]`)
+	assert.Contains(t, string(lines[2]), `"action":"kill"`)
+	assert.Contains(t, string(lines[2]), `"outcome":"ok"`)
+}
+
+func TestAuditLog_Unwritable(t *testing.T) {
+	origLog := auditLog
+	t.Cleanup(func() { auditLog = origLog })
+	err := runReport(t, ctr.NewMockClient(t), "--audit-log", filepath.Join(t.TempDir(), "missing", "audit.log"), "kill", "web")
+	require.ErrorContains(t, err, "failed to open audit log")
+}
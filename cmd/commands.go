@@ -4,9 +4,15 @@ import (
 	"fmt"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
+	cleanupCmd "github.com/alexei-led/pumba/pkg/chaos/cleanup/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	dnsCmd "github.com/alexei-led/pumba/pkg/chaos/dns/cmd"
+	httpCmd "github.com/alexei-led/pumba/pkg/chaos/httpfault/cmd"
 	ipTablesCmd "github.com/alexei-led/pumba/pkg/chaos/iptables/cmd"
 	"github.com/alexei-led/pumba/pkg/chaos/lifecycle/cmd"
+	linkCmd "github.com/alexei-led/pumba/pkg/chaos/link/cmd"
 	netemCmd "github.com/alexei-led/pumba/pkg/chaos/netem/cmd"
+	statusCmd "github.com/alexei-led/pumba/pkg/chaos/status/cmd"
 	stressCmd "github.com/alexei-led/pumba/pkg/chaos/stress/cmd"
 	"github.com/urfave/cli"
 )
@@ -15,6 +21,7 @@ import (
 func initializeCLICommands(runtime chaos.Runtime) []cli.Command {
 	return []cli.Command{
 		*cmd.NewKillCLICommand(topContext, runtime),
+		*cmd.NewCrashloopCLICommand(topContext, runtime),
 		*cmd.NewExecCLICommand(topContext, runtime),
 		*cmd.NewRestartCLICommand(topContext, runtime),
 		*cmd.NewStopCLICommand(topContext, runtime),
@@ -23,7 +30,7 @@ func initializeCLICommands(runtime chaos.Runtime) []cli.Command {
 		*stressCmd.NewStressCLICommand(topContext, runtime),
 		{
 			Name: "netem",
-			Flags: []cli.Flag{
+			Flags: cliflags.Ranges([]cli.Flag{
 				cli.DurationFlag{
 					Name:  "duration, d",
 					Usage: "network emulation duration; should be smaller than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
@@ -54,12 +61,31 @@ func initializeCLICommands(runtime chaos.Runtime) []cli.Command {
 					Name:  "pull-image",
 					Usage: "force pull tc-image",
 				},
+				cli.StringFlag{
+					Name:  "capture",
+					Usage: "record the traffic with tcpdump into rotating pcap files in this absolute directory on the runtime host (requires tcpdump in tc-image)",
+				},
+				cli.IntFlag{
+					Name:  "capture-size",
+					Usage: "size of each pcap file in megabytes before rotating",
+					Value: 100, //nolint:mnd
+				},
+				cli.IntFlag{
+					Name:  "capture-files",
+					Usage: "number of rotated pcap files to keep",
+					Value: 5, //nolint:mnd
+				},
+				cli.StringFlag{
+					Name:  "verify",
+					Usage: "read back the netem qdisc after injecting and after removing it: none, warn (log a warning when it does not match) or fail (fail the run)",
+					Value: "none",
+				},
 				cli.IntFlag{
 					Name:  "limit",
 					Usage: "limit number of matching containers (0: target all)",
 					Value: 0,
 				},
-			},
+			}),
 			Usage:       "emulate the properties of wide area networks",
 			ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", re2Prefix),
 			Description: "delay, loss, duplicate and re-order (run 'netem') packets, and limit the bandwidth, to emulate different network problems",
@@ -75,7 +101,7 @@ func initializeCLICommands(runtime chaos.Runtime) []cli.Command {
 		},
 		{
 			Name: "iptables",
-			Flags: []cli.Flag{
+			Flags: cliflags.Ranges([]cli.Flag{
 				cli.DurationFlag{
 					Name:  "duration, d",
 					Usage: "network emulation duration; should be smaller than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
@@ -115,18 +141,86 @@ func initializeCLICommands(runtime chaos.Runtime) []cli.Command {
 					Name:  "pull-image",
 					Usage: "force pull iptables-image",
 				},
+				cli.StringFlag{
+					Name:  "capture",
+					Usage: "record the traffic with tcpdump into rotating pcap files in this absolute directory on the runtime host (requires tcpdump in iptables-image)",
+				},
+				cli.IntFlag{
+					Name:  "capture-size",
+					Usage: "size of each pcap file in megabytes before rotating",
+					Value: 100, //nolint:mnd
+				},
+				cli.IntFlag{
+					Name:  "capture-files",
+					Usage: "number of rotated pcap files to keep",
+					Value: 5, //nolint:mnd
+				},
+				cli.StringFlag{
+					Name:  "verify",
+					Usage: "read back the packet filter rules after injecting and after removing it: none, warn (log a warning when it does not match) or fail (fail the run)",
+					Value: "none",
+				},
+				cli.StringFlag{
+					Name:  "backend",
+					Usage: "packet filter backend (iptables, nftables or auto: use nftables when nft works in the target network namespace)",
+					Value: "iptables",
+				},
 				cli.IntFlag{
 					Name:  "limit",
 					Usage: "limit number of matching containers (0: target all)",
 					Value: 0,
 				},
-			},
+			}),
 			Usage:       "apply IPv4 packet filter on incoming IP packets",
 			ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", re2Prefix),
-			Description: "emulate loss of incoming packets, all ports and address arguments will result in separate rules",
+			Description: "emulate loss or throttling of incoming packets, all ports and address arguments will result in separate rules",
 			Subcommands: []cli.Command{
 				*ipTablesCmd.NewLossCLICommand(topContext, runtime),
+				*ipTablesCmd.NewThrottleCLICommand(topContext, runtime),
+			},
+		},
+		{
+			Name: "link",
+			Flags: cliflags.Ranges([]cli.Flag{
+				cli.DurationFlag{
+					Name:  "duration, d",
+					Usage: "link chaos duration; should be smaller than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
+				},
+				cli.StringFlag{
+					Name:  "interface, i",
+					Usage: "network interface to take down or change the MTU of",
+					Value: defaultInterface,
+				},
+				cli.StringFlag{
+					Name:  "link-image",
+					Usage: "Docker image with ip (iproute2 package)",
+					Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+				},
+				cli.BoolTFlag{
+					Name:  "pull-image",
+					Usage: "force pull link-image",
+				},
+				cli.IntFlag{
+					Name:  "limit",
+					Usage: "limit number of matching containers (0: target all)",
+					Value: 0,
+				},
+			}),
+			Usage:       "emulate network interface failures",
+			ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", re2Prefix),
+			Description: "take a network interface down, lower its MTU or disconnect the container from a network, and restore it after the duration",
+			Subcommands: []cli.Command{
+				*linkCmd.NewDownCLICommand(topContext, runtime),
+				*linkCmd.NewMTUCLICommand(topContext, runtime),
+				*linkCmd.NewDisconnectCLICommand(topContext, runtime),
 			},
 		},
+		*dnsCmd.NewDNSCLICommand(topContext, runtime),
+		*httpCmd.NewHTTPCLICommand(topContext, runtime),
+		*cleanupCmd.NewCleanupCLICommand(topContext, runtime),
+		*statusCmd.NewStatusCLICommand(topContext, runtime),
+		doctorCommand(),
+		dnsProxyCommand(),
+		httpProxyCommand(),
 	}
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
+	"github.com/urfave/cli"
+)
+
+// doctorCommandName is skipped by before(): the doctor command creates the
+// runtime client itself so a connection failure becomes a failed check.
+const doctorCommandName = "doctor"
+
+// doctorOut is where the doctor command prints its checks.
+var doctorOut io.Writer = os.Stdout
+
+// doctorCommand checks that the selected runtime can run chaos commands.
+func doctorCommand() cli.Command {
+	return cli.Command{
+		Name:  doctorCommandName,
+		Usage: "check that the selected runtime, host kernel and sidecar images can run chaos commands",
+		Description: "checks runtime connectivity and version, cgroup driver and version, rootless mode and sidecar image availability, " +
+			"then probes NET_ADMIN and the netem, iptables and nftables kernel support in a throwaway sidecar; " +
+			"exits non-zero when a check fails",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "image",
+				Usage: "Docker image with tc, iptables and nft, checked and used for the kernel probes",
+				Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+			},
+			cli.StringFlag{
+				Name:  "stress-image",
+				Usage: "stress-ng image to check (empty: skip)",
+				Value: "ghcr.io/alexei-led/stress-ng:latest",
+			},
+			cli.BoolTFlag{
+				Name:  "pull-image",
+				Usage: "pull the images instead of looking them up locally",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			req := &ctr.DiagnoseRequest{Sidecar: c.String("image"), Pull: c.BoolT("pull-image")}
+			if img := c.String("stress-image"); img != "" {
+				req.Images = []string{img}
+			}
+			client, err := createRuntimeClient(c)
+			if err != nil {
+				return writeChecks(doctorOut, []ctr.Check{preflight.Fail(preflight.CheckRuntime, err.Error(),
+					"check --runtime and the socket flags of the selected runtime")})
+			}
+			// app.After closes the client
+			runtimeClient = client
+			return writeChecks(doctorOut, client.Diagnose(topContext, req))
+		},
+	}
+}
+
+// writeChecks prints one line per check, followed by the hint of checks
+// that did not pass, and returns an error when a check failed.
+func writeChecks(w io.Writer, checks []ctr.Check) error {
+	for _, check := range checks {
+		fmt.Fprintf(w, "%-4s  %-12s  %s\n", strings.ToUpper(check.Status), check.Name, check.Detail)
+		if check.Status != ctr.CheckPass && check.Hint != "" {
+			fmt.Fprintf(w, "%20s%s\n", "", check.Hint)
+		}
+	}
+	if preflight.Failed(checks) {
+		return errors.New("preflight checks failed")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+// runDoctor runs `pumba <args> doctor` and returns its output.
+func runDoctor(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	origOut, origClient := doctorOut, runtimeClient
+	t.Cleanup(func() { doctorOut, runtimeClient = origOut, origClient })
+	var out bytes.Buffer
+	doctorOut = &out
+
+	app := cli.NewApp()
+	app.Flags = globalFlags("/tmp/certs")
+	app.Before = before
+	app.Commands = []cli.Command{doctorCommand()}
+	err := app.Run(append(append([]string{"pumba"}, args...), doctorCommandName))
+	return out.String(), err
+}
+
+func TestDoctorCommand(t *testing.T) {
+	restoreFactories(t)
+	client := ctr.NewMockClient(t)
+	newDockerClient = func(string, *tls.Config) (ctr.Client, error) { return client, nil }
+	client.EXPECT().Diagnose(mock.Anything, &ctr.DiagnoseRequest{
+		Sidecar: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+		Images:  []string{"ghcr.io/alexei-led/stress-ng:latest"},
+		Pull:    true,
+	}).Return([]ctr.Check{
+		{Name: "runtime", Status: ctr.CheckPass, Detail: "server 27.3.1"},
+		{Name: "rootless", Status: ctr.CheckWarn, Detail: "daemon runs rootless", Hint: "use a rootful daemon"},
+	}).Once()
+
+	out, err := runDoctor(t, "--runtime", "docker")
+	require.NoError(t, err, "warnings do not fail the doctor")
+	assert.Equal(t, "PASS  runtime       server 27.3.1\n"+
+		"WARN  rootless      daemon runs rootless\n"+
+		"                    use a rootful daemon\n", out)
+}
+
+func TestDoctorCommand_ConnectionFailure(t *testing.T) {
+	restoreFactories(t)
+	newPodmanClient = func(string) (ctr.Client, error) { return nil, errors.New("query /info: EOF") }
+
+	out, err := runDoctor(t, "--runtime", "podman")
+	require.EqualError(t, err, "preflight checks failed")
+	assert.Contains(t, out, "FAIL  runtime       could not create podman client: query /info: EOF\n")
+	assert.Contains(t, out, "check --runtime")
+}
+
+func TestWriteChecks_Failed(t *testing.T) {
+	var out bytes.Buffer
+	err := writeChecks(&out, []ctr.Check{
+		{Name: "sch_netem", Status: ctr.CheckFail, Detail: "tc failed", Hint: "modprobe sch_netem"},
+		{Name: "nftables", Status: ctr.CheckPass, Detail: "ok", Hint: "ignored"},
+	})
+	require.EqualError(t, err, "preflight checks failed")
+	assert.Equal(t, "FAIL  sch_netem     tc failed\n"+
+		"                    modprobe sch_netem\n"+
+		"PASS  nftables      ok\n", out.String())
+}
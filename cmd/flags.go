@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/alexei-led/pumba/pkg/chaos/report"
 	"github.com/urfave/cli"
 )
 
@@ -81,6 +82,58 @@ func globalFlags(rootCertPath string) []cli.Flag {
 			Name:  "interval, i",
 			Usage: "recurrent interval for chaos command; use with optional unit suffix: 'ms/s/m/h'",
 		},
+		cli.StringFlag{
+			Name:  "cron",
+			Usage: "instead of --interval, run the chaos command at the minutes a cron expression selects: minute hour day-of-month month day-of-week, e.g. '*/15 10-15 * * mon-fri'; local time unless prefixed with 'TZ=<zone> '",
+		},
+		cli.DurationFlag{
+			Name:  "jitter",
+			Usage: "add a random wait of up to this duration before every --interval or --cron run",
+		},
+		cli.StringSliceFlag{
+			Name:  "blackout",
+			Usage: "skip runs due in a blackout window: a cron expression blacking out the minutes it selects, e.g. '* * * * sat,sun', or '<RFC3339 start>/<RFC3339 end>'; repeatable",
+		},
+		cli.IntFlag{
+			Name:  "max-runs",
+			Usage: "stop recurring chaos after this many runs",
+		},
+		cli.DurationFlag{
+			Name:  "deadline",
+			Usage: "stop recurring chaos this long after it started, ending the current run",
+		},
+		cli.IntFlag{
+			Name:  "max-parallel",
+			Usage: "run the action of parallel chaos commands (netem, iptables, stress, ...) on at most this many containers at once; 0 for no limit",
+		},
+		cli.IntFlag{
+			Name:  "wave-size",
+			Usage: "roll chaos out in waves of this many containers, a wave starting once the one before it ended; a failed wave stops the rollout",
+		},
+		cli.DurationFlag{
+			Name:  "wave-pause",
+			Usage: "pause between --wave-size waves; use with optional unit suffix: 'ms/s/m/h'",
+		},
+		cli.IntFlag{
+			Name:  "seed",
+			Usage: "seed for drawing the values of range flags, e.g. '--duration 10s..2m', '--time 50..500' or '--signal SIGTERM|SIGKILL'; 0 seeds with the time",
+		},
+		cli.BoolFlag{
+			Name:  "sample-per-container",
+			Usage: "draw the values of range flags for every target container, instead of once per run",
+		},
+		cli.BoolFlag{
+			Name:  "inventory",
+			Usage: "list containers once, then keep them up to date from the runtime event stream instead of listing them on every run",
+		},
+		cli.StringFlag{
+			Name:  "trigger",
+			Usage: "instead of --interval, run the chaos command on every target container as soon as it starts or restarts ('start') or turns healthy ('healthy', docker and podman only); implies --inventory",
+		},
+		cli.DurationFlag{
+			Name:  "trigger-delay",
+			Usage: "wait between the --trigger event and the chaos; use with optional unit suffix: 'ms/s/m/h'",
+		},
 		cli.StringSliceFlag{
 			Name:  "label",
 			Usage: "filter containers by labels, e.g. '--label key=value' (use '--label k1=v1 --label k2=v2' or '--label k1=v1,k2=v2' for multiple, AND logic)",
@@ -94,6 +147,45 @@ func globalFlags(rootCertPath string) []cli.Flag {
 			Usage:  "dry run does not create chaos, only logs planned chaos commands",
 			EnvVar: "DRY-RUN",
 		},
+		cli.BoolFlag{
+			Name:  "plan",
+			Usage: "print a JSON plan of the resolved targets and the exact runtime commands without changing anything; implies --dry-run",
+		},
+		cli.StringFlag{
+			Name:   "audit-log",
+			Usage:  "append JSON lines auditing the invoking user and host, command line, resolved targets and every runtime mutation to this file, or to 'syslog', 'syslog+udp://host:port' or 'syslog+tcp://host:port'; not filtered by --log-level",
+			EnvVar: "PUMBA_AUDIT_LOG",
+		},
+		cli.StringFlag{
+			Name:   "otel-endpoint",
+			Usage:  "export OpenTelemetry traces of chaos runs via OTLP/HTTP to this endpoint: host:port or URL (a URL without path gets /v1/traces)",
+			EnvVar: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,OTEL_EXPORTER_OTLP_ENDPOINT",
+		},
+		cli.BoolFlag{
+			Name:  "otel-insecure",
+			Usage: "use plain HTTP for a host:port --otel-endpoint",
+		},
+		cli.StringFlag{
+			Name:  "report-file",
+			Usage: "write a report of every run (containers hit, actions and parameters, times, cleanup outcome, skipped errors, verify read-backs) to this file",
+		},
+		cli.StringFlag{
+			Name:  "report-format",
+			Usage: "report file format: json or junit (JUnit XML, rendered by CI systems)",
+			Value: report.FormatJSON,
+		},
+		cli.StringFlag{
+			Name:  "before-hook",
+			Usage: "shell command to run before every run and every container action, or 'exec:<container>:<command>' to exec it in a running container; gets PUMBA_HOOK, PUMBA_SCOPE, PUMBA_ACTION, PUMBA_RUN, PUMBA_CONTAINER_ID and PUMBA_CONTAINER_NAME",
+		},
+		cli.StringFlag{
+			Name:  "after-hook",
+			Usage: "shell command to run after every run and every container action, like --before-hook; also gets PUMBA_ERROR when the run or action failed",
+		},
+		cli.BoolFlag{
+			Name:  "hook-abort",
+			Usage: "fail the run or container action when a hook fails, skipping the injection on a failed --before-hook; otherwise hook failures are logged",
+		},
 		cli.BoolFlag{
 			Name:  "skip-error",
 			Usage: "skip chaos command error and retry to execute the command on next interval tick",
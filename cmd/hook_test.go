@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web"}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{web}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, web, "SIGKILL", false).Return(nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	path := filepath.Join(t.TempDir(), "hooks.log")
+	hook := `echo "$PUMBA_HOOK $PUMBA_SCOPE $PUMBA_ACTION $PUMBA_CONTAINER_ID" >> ` + path
+	require.NoError(t, runReport(t, client, "--before-hook", hook, "--after-hook", hook, "kill", "web"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "before run kill \nbefore container kill w1\nafter container kill w1\nafter run kill \n", string(data))
+}
+
+func TestHooks_BadExecHook(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().Close().Return(nil).Once()
+
+	err := runReport(t, client, "--before-hook", "exec:grafana", "kill", "web")
+	require.EqualError(t, err, `bad hook "exec:grafana": expected exec:<container>:<command>`)
+}
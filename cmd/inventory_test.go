@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// watchingClient is a runtime client streaming no events.
+type watchingClient struct {
+	*ctr.MockClient
+}
+
+func (watchingClient) WatchContainers(ctx context.Context) (<-chan ctr.Event, <-chan error) {
+	return make(chan ctr.Event), make(chan error)
+}
+
+func TestInventory(t *testing.T) {
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web", State: ctr.StateRunning}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, ctr.ListOpts{All: true}).Return([]*ctr.Container{web}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, web, "SIGKILL", false).Return(nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	require.NoError(t, runReport(t, watchingClient{client}, "--inventory", "kill", "web"))
+}
+
+func TestInventory_NoEvents(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().Close().Return(nil).Once()
+
+	err := runReport(t, client, "--inventory", "kill", "web")
+	assert.EqualError(t, err, "--inventory: the runtime does not stream container events")
+}
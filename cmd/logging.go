@@ -2,12 +2,14 @@ package main
 
 import (
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	"github.com/johntdyer/slackrus"
 	log "github.com/sirupsen/logrus"
 )
 
 // setupLogging configures the global logrus logger from --log-level, --json,
-// --slackhook, and --slackchannel global flags. Called once from before().
+// --otel-endpoint, --slackhook, and --slackchannel global flags. Called once
+// from before().
 func setupLogging(f cliflags.Flags) {
 	switch level := f.String("log-level"); level {
 	case "debug", "DEBUG":
@@ -28,6 +30,10 @@ func setupLogging(f cliflags.Flags) {
 	if f.Bool("json") {
 		log.SetFormatter(&log.JSONFormatter{})
 	}
+	// added before the Slack hook, so Slack messages carry the trace IDs
+	if f.String("otel-endpoint") != "" {
+		log.AddHook(tracing.LogHook{})
+	}
 	if f.String("slackhook") != "" {
 		log.AddHook(&slackrus.SlackrusHook{
 			HookURL:        f.String("slackhook"),
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/alexei-led/pumba/pkg/audit"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/plan"
+	"github.com/alexei-led/pumba/pkg/chaos/report"
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
@@ -21,8 +28,29 @@ var (
 	// then read by every CLI builder via the chaos.Runtime closure passed to
 	// initializeCLICommands. app.After calls Close on the same value.
 	runtimeClient ctr.Client
+
+	// planRecorder wraps runtimeClient in --plan mode; app.After prints the
+	// plan it recorded to planOut.
+	planRecorder *plan.Recorder
+	planOut      io.Writer = os.Stdout
+
+	// reportRecorder wraps runtimeClient when --report-file is set; app.After
+	// writes the report it recorded.
+	reportRecorder *report.Recorder
+
+	// auditLog records the mutations of runtimeClient when --audit-log is
+	// set; app.After closes it.
+	auditLog *audit.Logger
+
+	// shutdownTracing flushes the spans exported to --otel-endpoint; set by
+	// before() and called by app.After.
+	shutdownTracing func(context.Context) error
 )
 
+// tracingShutdownTimeout bounds how long app.After waits for pending spans
+// to be exported.
+const tracingShutdownTimeout = 5 * time.Second
+
 var (
 	// version that is passed on compile time through -ldflags
 	version = "local"
@@ -76,12 +104,7 @@ func main() {
 	app.Usage = "Pumba is a resilience testing tool, that helps applications tolerate random Docker container failures: process, network and performance."
 	app.ArgsUsage = fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q)", re2Prefix)
 	app.Before = before
-	app.After = func(_ *cli.Context) error {
-		if runtimeClient != nil {
-			return runtimeClient.Close()
-		}
-		return nil
-	}
+	app.After = after
 	app.Commands = initializeCLICommands(func() ctr.Client { return runtimeClient })
 	app.Flags = globalFlags(rootCertPath)
 
@@ -92,11 +115,144 @@ func main() {
 
 func before(c *cli.Context) error {
 	setupLogging(cliflags.NewV1FromApp(c))
+	if name := c.Args().First(); isHelperCommand(name) || name == doctorCommandName {
+		return nil
+	}
+	if _, err := report.ParseFormat(c.GlobalString("report-format")); err != nil {
+		return err
+	}
+	if endpoint := c.GlobalString("otel-endpoint"); endpoint != "" {
+		shutdown, err := tracing.Setup(topContext, endpoint, c.GlobalBool("otel-insecure"), version)
+		if err != nil {
+			return err
+		}
+		shutdownTracing = shutdown
+	}
+	if dest := c.GlobalString("audit-log"); dest != "" {
+		logger, err := audit.Open(dest, os.Args)
+		if err != nil {
+			return err
+		}
+		auditLog = logger
+		// fails before any chaos when the audit log cannot be written
+		if err := auditLog.Log(audit.Entry{Event: audit.EventStart}); err != nil {
+			return err
+		}
+	}
 	client, err := createRuntimeClient(c)
 	if err != nil {
 		return err
 	}
+	// triggered chaos follows the changes of the inventory
+	if flag := inventoryFlag(c); flag != "" {
+		inv, err := startInventory(client, flag)
+		if err != nil {
+			return err
+		}
+		client = inv
+	}
+	if shutdownTracing != nil {
+		client = tracing.NewClient(client)
+	}
+	if auditLog != nil {
+		client = audit.NewClient(client, auditLog)
+	}
 	runtimeClient = client
+	if c.GlobalBool("plan") {
+		planRecorder = plan.NewRecorder(client)
+		runtimeClient = planRecorder
+	}
+	if c.GlobalString("report-file") != "" {
+		reportRecorder = report.NewRecorder(runtimeClient)
+		runtimeClient = reportRecorder
+	}
+	return nil
+}
+
+func after(c *cli.Context) error {
+	if planRecorder != nil {
+		p := planRecorder.Plan()
+		p.Runtime, p.Command = c.GlobalString("runtime"), os.Args[1:]
+		if err := p.Write(planOut); err != nil {
+			return fmt.Errorf("failed to write plan: %w", err)
+		}
+	}
+	// the report error is returned after the shutdown below
+	var reportErr error
+	if reportRecorder != nil {
+		reportErr = writeReport(c)
+	}
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(topContext), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.WithError(err).Warn("failed to export traces")
+		}
+	}
+	if auditLog != nil {
+		if err := auditLog.Close(); err != nil {
+			log.WithError(err).Warn("failed to close audit log")
+		}
+	}
+	if runtimeClient != nil {
+		return errors.Join(reportErr, runtimeClient.Close())
+	}
+	return reportErr
+}
+
+// inventoryFlag returns the global flag, or the command, requiring the
+// container inventory, if any. Crash loops wait for restarts on its changes.
+func inventoryFlag(c *cli.Context) string {
+	switch {
+	case c.GlobalString("trigger") != "":
+		return "--trigger"
+	case c.GlobalBool("inventory"):
+		return "--inventory"
+	case c.Args().First() == "crashloop" && !c.GlobalBool("dry-run") && !c.GlobalBool("plan"):
+		return "crashloop"
+	}
+	return ""
+}
+
+// startInventory wraps client in an inventory following its event stream,
+// closing client when the inventory cannot start; flag names the global flag
+// requiring it.
+func startInventory(client ctr.Client, flag string) (*inventory.Inventory, error) {
+	w, ok := client.(ctr.Watcher)
+	if !ok {
+		client.Close()
+		return nil, fmt.Errorf("%s: the runtime does not stream container events", flag)
+	}
+	inv := inventory.New(client, w)
+	if err := inv.Start(topContext); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return inv, nil
+}
+
+// writeReport writes the report of the command to --report-file, and fails
+// when the report does, so a cleanup failure skipped with --skip-error still
+// exits non-zero.
+func writeReport(c *cli.Context) error {
+	rep := reportRecorder.Report()
+	rep.Name, rep.Runtime, rep.Command = c.Args().First(), c.GlobalString("runtime"), os.Args[1:]
+	format, _ := report.ParseFormat(c.GlobalString("report-format"))
+	path := c.GlobalString("report-file")
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := rep.Write(f, format); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if rep.Failed() {
+		return fmt.Errorf("chaos run failed: see %s", path)
+	}
 	return nil
 }
 
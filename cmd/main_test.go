@@ -186,3 +186,18 @@ func TestCreateRuntimeClient_Unknown(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported runtime: rkt")
 }
+
+func TestBefore_HelperCommandSkipsRuntimeClient(t *testing.T) {
+	restoreFactories(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range globalFlags("/tmp/certs") {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse([]string{"--runtime", "rkt", "dns-proxy", "--action", "drop"}))
+	ctx := cli.NewContext(cli.NewApp(), fs, nil)
+
+	// an unsupported runtime would fail client creation; the helper must not get there
+	require.NoError(t, before(ctx))
+	assert.Nil(t, runtimeClient)
+}
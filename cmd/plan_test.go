@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos/plan"
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+// runPlan runs `pumba --plan <args>` against client and returns the plan.
+func runPlan(t *testing.T, client ctr.Client, args ...string) *plan.Plan {
+	t.Helper()
+	restoreFactories(t)
+	origOut, origClient, origRecorder := planOut, runtimeClient, planRecorder
+	t.Cleanup(func() { planOut, runtimeClient, planRecorder = origOut, origClient, origRecorder })
+	newDockerClient = func(string, *tls.Config) (ctr.Client, error) { return client, nil }
+	var out bytes.Buffer
+	planOut = &out
+
+	app := cli.NewApp()
+	app.Flags = globalFlags("/tmp/certs")
+	app.Before = before
+	app.After = after
+	app.Commands = initializeCLICommands(func() ctr.Client { return runtimeClient })
+	require.NoError(t, app.Run(append([]string{"pumba", "--plan"}, args...)))
+
+	var p plan.Plan
+	require.NoError(t, json.Unmarshal(out.Bytes(), &p))
+	return &p
+}
+
+func TestPlan_Netem(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{
+		{ContainerID: "abc", ContainerName: "/web", Labels: map[string]string{"app": "web"}},
+	}, nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	p := runPlan(t, client, "netem", "--duration", "1m", "--target", "10.0.0.1", "delay", "--time", "100", "web")
+	assert.Equal(t, "docker", p.Runtime)
+	assert.Equal(t, []plan.Target{{ID: "abc", Name: "web", Labels: map[string]string{"app": "web"}}}, p.Targets)
+	require.Len(t, p.Steps, 2)
+	inject, cleanup := p.Steps[0], p.Steps[1]
+	assert.Equal(t, plan.PhaseInject, inject.Phase)
+	assert.Equal(t, "tc", inject.Tool)
+	assert.Equal(t, "1m0s", inject.Duration)
+	assert.Equal(t, "ghcr.io/alexei-led/pumba-alpine-nettools:latest", inject.Image)
+	assert.Equal(t, []string{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"}, inject.Commands[0])
+	assert.Equal(t, plan.PhaseCleanup, cleanup.Phase)
+	assert.Equal(t, []string{"qdisc", "del", "dev", "eth0", "parent", "1:1", "handle", "10:"}, cleanup.Commands[0])
+}
+
+func TestPlan_Pause(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{
+		{ContainerID: "2", ContainerName: "db"}, {ContainerID: "1", ContainerName: "api"},
+	}, nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	p := runPlan(t, client, "pause", "--duration", "10s", "api", "db")
+	assert.Equal(t, []plan.Target{{ID: "1", Name: "api"}, {ID: "2", Name: "db"}}, p.Targets)
+	var steps []string
+	for _, s := range p.Steps {
+		steps = append(steps, s.Name+" "+s.Phase+" "+s.Action)
+	}
+	assert.Equal(t, []string{"api inject pause", "api cleanup unpause", "db inject pause", "db cleanup unpause"}, steps)
+}
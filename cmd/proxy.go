@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/alexei-led/pumba/pkg/chaos/dns"
+	"github.com/alexei-led/pumba/pkg/chaos/httpfault"
+	"github.com/alexei-led/pumba/pkg/dnsproxy"
+	"github.com/alexei-led/pumba/pkg/httpproxy"
+	"github.com/urfave/cli"
+)
+
+// helperCommands run inside chaos sidecars rather than against a container
+// runtime; before() skips runtime client creation for them.
+var helperCommands = []string{dns.ProxyCommand, httpfault.ProxyCommand}
+
+func isHelperCommand(name string) bool {
+	return slices.Contains(helperCommands, name)
+}
+
+// dnsProxyCommand is the hidden command the dns chaos sidecar runs.
+func dnsProxyCommand() cli.Command {
+	return cli.Command{
+		Name:   dns.ProxyCommand,
+		Usage:  "run the DNS fault-injection proxy (used by the dns command sidecar)",
+		Hidden: true,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "listen",
+				Usage: "UDP and TCP address to serve DNS on",
+				Value: ":" + strconv.Itoa(dnsproxy.DefaultPort),
+			},
+			cli.StringFlag{
+				Name:  "upstream",
+				Usage: "upstream resolver address (host[:port]); default: first nameserver in /etc/resolv.conf",
+			},
+			cli.StringFlag{
+				Name:  "action",
+				Usage: "fault injected into matching queries: drop, delay, nxdomain, servfail or spoof",
+			},
+			cli.StringSliceFlag{
+				Name:  "domain",
+				Usage: "domain pattern to inject faults for (default: all domains)",
+			},
+			cli.DurationFlag{
+				Name:  "delay",
+				Usage: "time to hold matching queries before forwarding them",
+			},
+			cli.StringFlag{
+				Name:  "spoof-ip",
+				Usage: "address returned for matching A or AAAA queries",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			proxy, err := dnsproxy.New(dnsproxy.Config{
+				Listen:   c.String("listen"),
+				Upstream: c.String("upstream"),
+				Action:   c.String("action"),
+				Domains:  c.StringSlice("domain"),
+				Delay:    c.Duration("delay"),
+				SpoofIP:  net.ParseIP(c.String("spoof-ip")),
+			})
+			if err != nil {
+				return err
+			}
+			return proxy.ListenAndServe(topContext)
+		},
+	}
+}
+
+// httpProxyCommand is the hidden command the http chaos sidecar runs.
+func httpProxyCommand() cli.Command {
+	return cli.Command{
+		Name:   httpfault.ProxyCommand,
+		Usage:  "run the HTTP/gRPC fault-injection proxy (used by the http command sidecar)",
+		Hidden: true,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "listen",
+				Usage: "address to serve HTTP/1.1 and h2c on",
+				Value: ":" + strconv.Itoa(httpproxy.DefaultPort),
+			},
+			cli.StringFlag{
+				Name:  "upstream",
+				Usage: "service address (host:port) requests are proxied to when their connection was not redirected; redirected connections go to their original destination",
+			},
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "URL path prefix or pattern of requests to inject faults into",
+			},
+			cli.StringFlag{
+				Name:  "method",
+				Usage: "HTTP method of requests to inject faults into",
+			},
+			cli.StringSliceFlag{
+				Name:  "header",
+				Usage: "header <name>=<value> requests must carry",
+			},
+			cli.Float64Flag{
+				Name:  "percent",
+				Usage: "percentage of matching requests to inject faults into",
+				Value: 100, //nolint:mnd
+			},
+			cli.DurationFlag{
+				Name:  "delay",
+				Usage: "delay matching requests",
+			},
+			cli.IntFlag{
+				Name:  "abort-status",
+				Usage: "HTTP status code for aborted requests",
+			},
+			cli.IntFlag{
+				Name:  "grpc-status",
+				Usage: "gRPC status code for aborted calls",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			headers, err := httpproxy.ParseHeaders(c.StringSlice("header"))
+			if err != nil {
+				return err
+			}
+			proxy, err := httpproxy.New(httpproxy.Config{
+				Listen:      c.String("listen"),
+				Upstream:    c.String("upstream"),
+				Path:        c.String("path"),
+				Method:      c.String("method"),
+				Headers:     headers,
+				Percent:     c.Float64("percent"),
+				Delay:       c.Duration("delay"),
+				AbortStatus: c.Int("abort-status"),
+				GRPCStatus:  c.Int("grpc-status"),
+			})
+			if err != nil {
+				return err
+			}
+			return proxy.ListenAndServe(topContext)
+		},
+	}
+}
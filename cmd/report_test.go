@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos/report"
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+// runReport runs `pumba <args>` against client and returns the run error.
+func runReport(t *testing.T, client ctr.Client, args ...string) error {
+	t.Helper()
+	restoreFactories(t)
+	origClient, origRecorder := runtimeClient, reportRecorder
+	t.Cleanup(func() { runtimeClient, reportRecorder = origClient, origRecorder })
+	newDockerClient = func(string, *tls.Config) (ctr.Client, error) { return client, nil }
+
+	app := cli.NewApp()
+	app.Flags = globalFlags("/tmp/certs")
+	app.Before = before
+	app.After = after
+	app.Commands = initializeCLICommands(func() ctr.Client { return runtimeClient })
+	return app.Run(append([]string{"pumba"}, args...))
+}
+
+func TestReportFile(t *testing.T) {
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web"}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{web}, nil).Once()
+	client.EXPECT().PauseContainer(mock.Anything, web, false).Return(nil).Once()
+	client.EXPECT().UnpauseContainer(mock.Anything, web, false).Return(nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, runReport(t, client, "--report-file", path, "pause", "--duration", "10ms", "web"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var rep report.Report
+	require.NoError(t, json.Unmarshal(data, &rep))
+	assert.Equal(t, "pause", rep.Name)
+	assert.Equal(t, "docker", rep.Runtime)
+	require.Len(t, rep.Ticks, 1)
+	assert.Equal(t, []string{"web"}, rep.Ticks[0].Containers)
+	require.Len(t, rep.Ticks[0].Actions, 2)
+	assert.Equal(t, "pause", rep.Ticks[0].Actions[0].Action)
+	assert.True(t, rep.Ticks[0].Actions[1].Cleanup)
+	assert.False(t, rep.Ticks[0].End.IsZero())
+}
+
+func TestReportFile_JUnitOnFailure(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	path := filepath.Join(t.TempDir(), "report.xml")
+	require.Error(t, runReport(t, client, "--report-file", path, "--report-format", "junit", "kill", "web"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "the report is written when the command fails")
+	assert.Contains(t, string(data), `<testsuites name="pumba kill" tests="1" failures="1"`)
+}
+
+func TestReportFile_FailedCleanup(t *testing.T) {
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web"}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*ctr.Container{web}, nil).Once()
+	client.EXPECT().PauseContainer(mock.Anything, web, false).Return(nil).Once()
+	client.EXPECT().UnpauseContainer(mock.Anything, web, false).Return(assert.AnError).Once()
+	client.EXPECT().Close().Return(nil).Once()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	err := runReport(t, client, "--report-file", path, "--skip-error", "pause", "--duration", "10ms", "web")
+	require.EqualError(t, err, "chaos run failed: see "+path, "a failed restore exits non-zero despite --skip-error")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var rep report.Report
+	require.NoError(t, json.Unmarshal(data, &rep))
+	assert.True(t, rep.Failed())
+}
+
+func TestReportFile_BadFormat(t *testing.T) {
+	err := runReport(t, ctr.NewMockClient(t), "--report-file", "report.txt", "--report-format", "txt", "kill", "web")
+	require.EqualError(t, err, `bad report format "txt": must be json or junit`)
+}
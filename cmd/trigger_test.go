@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingClient is a runtime client streaming the events of its channel.
+type streamingClient struct {
+	*ctr.MockClient
+	events chan ctr.Event
+}
+
+func (s streamingClient) WatchContainers(context.Context) (<-chan ctr.Event, <-chan error) {
+	return s.events, make(chan error)
+}
+
+func TestTrigger(t *testing.T) {
+	origContext := topContext
+	t.Cleanup(func() { topContext = origContext })
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	topContext = ctx
+
+	web := &ctr.Container{ContainerID: "w1", ContainerName: "web", State: ctr.StateExited}
+	db := &ctr.Container{ContainerID: "d1", ContainerName: "db", State: ctr.StateExited}
+	client := ctr.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, ctr.ListOpts{All: true}).Return([]*ctr.Container{web, db}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, mock.MatchedBy(func(c *ctr.Container) bool { return c.ID() == "w1" }), "SIGKILL", false).
+		RunAndReturn(func(context.Context, *ctr.Container, string, bool) error {
+			cancel()
+			return nil
+		})
+	client.EXPECT().Close().Return(nil).Once()
+
+	// the runtime keeps restarting both containers until web is killed
+	events := make(chan ctr.Event)
+	go func() {
+		for {
+			for _, id := range []string{"d1", "w1"} {
+				select {
+				case events <- ctr.Event{Kind: ctr.EventStart, ContainerID: id}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, runReport(t, streamingClient{client, events}, "--trigger", "start", "kill", "web"))
+}
+
+func TestTrigger_NoEvents(t *testing.T) {
+	client := ctr.NewMockClient(t)
+	client.EXPECT().Close().Return(nil).Once()
+
+	err := runReport(t, client, "--trigger", "start", "kill", "web")
+	assert.EqualError(t, err, "--trigger: the runtime does not stream container events")
+}
@@ -0,0 +1,135 @@
+// Package audit writes an append-only log of the chaos Pumba performs, for
+// compliance in shared environments: who ran which command on which host,
+// the containers it resolved and every runtime mutation with its timestamps
+// and outcome. Entries are JSON lines, written to a file or to syslog
+// regardless of --log-level.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/url"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Events of an audit entry.
+const (
+	// EventStart is logged once, when the command starts.
+	EventStart = "start"
+	// EventTargets lists the containers a command resolved.
+	EventTargets = "targets"
+	// EventMutation is a runtime change.
+	EventMutation = "mutation"
+)
+
+// Outcomes of a mutation.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Target is a container resolved by target selection.
+type Target struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Entry is a line of the audit log. Every entry carries the invoking user,
+// host, process and command line, so each line stands on its own.
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	User  string    `json:"user"`
+	// SudoUser is the user that ran Pumba through sudo.
+	SudoUser string            `json:"sudo_user,omitempty"`
+	Host     string            `json:"host"`
+	PID      int               `json:"pid"`
+	Command  []string          `json:"command"`
+	Action   string            `json:"action,omitempty"`
+	Targets  []Target          `json:"targets,omitempty"`
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Params   map[string]string `json:"params,omitempty"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+	Start    *time.Time        `json:"start,omitempty"`
+	Outcome  string            `json:"outcome,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Logger writes audit entries, one JSON line per write.
+type Logger struct {
+	mu   sync.Mutex
+	w    io.WriteCloser
+	base Entry
+	now  func() time.Time
+}
+
+// New returns a Logger writing to w the entries of the command args.
+func New(w io.WriteCloser, args []string) *Logger {
+	base := Entry{User: currentUser(), SudoUser: os.Getenv("SUDO_USER"), PID: os.Getpid(), Command: args}
+	base.Host, _ = os.Hostname()
+	return &Logger{w: w, base: base, now: time.Now}
+}
+
+// Open returns a Logger writing to dest: "syslog" for the local syslog
+// daemon, syslog+udp://host:port or syslog+tcp://host:port for a remote one,
+// or the path of a file, appended to.
+func Open(dest string, args []string) (*Logger, error) {
+	w, err := open(dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", dest, err)
+	}
+	return New(w, args), nil
+}
+
+func open(dest string) (io.WriteCloser, error) {
+	const priority = syslog.LOG_NOTICE | syslog.LOG_AUTHPRIV
+	if dest == "syslog" {
+		return syslog.New(priority, "pumba")
+	}
+	if network, ok := strings.CutPrefix(dest, "syslog+"); ok {
+		u, err := url.Parse(network)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "udp" && u.Scheme != "tcp" {
+			return nil, fmt.Errorf("bad syslog network %q: must be udp or tcp", u.Scheme)
+		}
+		return syslog.Dial(u.Scheme, u.Host, priority, "pumba")
+	}
+	return os.OpenFile(dest, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) //nolint:mnd
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Log writes e, completed with the invocation fields and the time.
+func (l *Logger) Log(e Entry) error {
+	e.Time = l.now().UTC()
+	e.User, e.SudoUser, e.Host, e.PID, e.Command = l.base.User, l.base.SudoUser, l.base.Host, l.base.PID, l.base.Command
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the destination.
+func (l *Logger) Close() error {
+	return l.w.Close()
+}
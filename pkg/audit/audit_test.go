@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/netem"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// buffer is an in-memory destination failing with err the writes
+// containing failOn, or all writes when failOn is empty.
+type buffer struct {
+	bytes.Buffer
+	err    error
+	failOn string
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	if b.err != nil && bytes.Contains(p, []byte(b.failOn)) {
+		return 0, b.err
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *buffer) Close() error { return nil }
+
+func (b *buffer) entries(t *testing.T) []Entry {
+	t.Helper()
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(b.Bytes()))
+	for scanner.Scan() {
+		var e Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func newTestLogger(w *buffer) *Logger {
+	l := New(w, []string{"pumba", "kill", "web"})
+	l.base.User, l.base.Host, l.base.PID = "alice", "ci-1", 42
+	l.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return l
+}
+
+func TestLogger_Log(t *testing.T) {
+	var w buffer
+	require.NoError(t, newTestLogger(&w).Log(Entry{Event: EventStart}))
+	assert.JSONEq(t, `{"time":"2026-01-02T03:04:05Z","event":"start","user":"alice","host":"ci-1","pid":42,"command":["pumba","kill","web"]}`,
+		w.String())
+}
+
+func TestOpen_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for range 2 {
+		l, err := Open(path, []string{"pumba"})
+		require.NoError(t, err)
+		require.NoError(t, l.Log(Entry{Event: EventStart}))
+		require.NoError(t, l.Close())
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")), "entries are appended")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestOpen_BadSyslogNetwork(t *testing.T) {
+	_, err := Open("syslog+unix://localhost", nil)
+	require.EqualError(t, err, `failed to open audit log syslog+unix://localhost: bad syslog network "unix": must be udp or tcp`)
+}
+
+func TestClient_Mutations(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*container.Container{web}, nil).Twice()
+	client.EXPECT().KillContainer(mock.Anything, web, "SIGKILL", false).Return(nil).Once()
+	client.EXPECT().PauseContainer(mock.Anything, web, true).Return(errors.New("conflict")).Once()
+
+	var w buffer
+	a := NewClient(client, newTestLogger(&w))
+	containers, err := a.ListContainers(context.TODO(), nil, container.ListOpts{})
+	require.NoError(t, err)
+	_, err = a.ListContainers(context.TODO(), nil, container.ListOpts{})
+	require.NoError(t, err, "listing alone is not logged")
+	require.NoError(t, intercept.Targets(context.TODO(), a, containers))
+	require.NoError(t, a.KillContainer(context.TODO(), web, "SIGKILL", false))
+	require.EqualError(t, a.PauseContainer(context.TODO(), web, true), "conflict")
+
+	entries := w.entries(t)
+	require.Len(t, entries, 3)
+	assert.Equal(t, EventTargets, entries[0].Event)
+	assert.Equal(t, []Target{{ID: "w1", Name: "web"}}, entries[0].Targets)
+	assert.Equal(t, "kill", entries[1].Action)
+	assert.Equal(t, "web", entries[1].Name)
+	assert.Equal(t, map[string]string{"signal": "SIGKILL"}, entries[1].Params)
+	assert.Equal(t, OutcomeOK, entries[1].Outcome)
+	assert.NotNil(t, entries[1].Start)
+	assert.Equal(t, "alice", entries[1].User)
+	assert.Equal(t, OutcomeError, entries[2].Outcome)
+	assert.Equal(t, "conflict", entries[2].Error)
+	assert.True(t, entries[2].DryRun)
+}
+
+func TestClient_WriteFailureKeepsResult(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().StopContainer(mock.Anything, web, 10, false).Return(nil).Once()
+	client.EXPECT().StartContainer(mock.Anything, web, false).Return(errors.New("conflict")).Once()
+
+	a := NewClient(client, newTestLogger(&buffer{err: errors.New("disk full")}))
+	require.NoError(t, a.StopContainer(context.TODO(), web, 10, false), "the change was made")
+	require.EqualError(t, a.StartContainer(context.TODO(), web, false), "conflict")
+	require.EqualError(t, intercept.Targets(context.TODO(), a, []*container.Container{web}), "failed to write audit log: disk full",
+		"nothing ran yet")
+}
+
+func TestClient_WriteFailureStillCleansUp(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*container.Container{web}, nil).Once()
+	client.EXPECT().NetemContainer(mock.Anything, mock.Anything).Return(nil).Once()
+	client.EXPECT().StopNetemContainer(mock.Anything, mock.Anything).Return(nil).Once()
+
+	w := &buffer{err: errors.New("disk full"), failOn: `"action":"netem-add"`}
+	a := NewClient(client, newTestLogger(w))
+	cmd, err := netem.NewDelayCommand(a, &chaos.GlobalParams{Names: []string{"web"}},
+		&container.NetemRequest{Interface: "eth0", Duration: 10 * time.Millisecond}, 0, 100, 0, 0, "")
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.TODO(), false))
+
+	entries := w.entries(t)
+	require.Len(t, entries, 2)
+	assert.Equal(t, EventTargets, entries[0].Event)
+	assert.Equal(t, "netem-del", entries[1].Action, "the netem qdisc is removed despite the failed write")
+}
+
+func TestAction(t *testing.T) {
+	tests := []struct {
+		call *intercept.Call
+		want string
+	}{
+		{call: &intercept.Call{Action: "kill"}, want: "kill"},
+		{call: &intercept.Call{Action: "netem"}, want: "netem-add"},
+		{call: &intercept.Call{Action: "iptables", Cleanup: true}, want: "iptables-del"},
+		{call: &intercept.Call{Action: "stress"}, want: "stress-start"},
+		{call: &intercept.Call{Action: "stop-sidecar", Cleanup: true}, want: "stress-stop"},
+		{call: &intercept.Call{Action: "dns", Request: &container.ProxyRequest{}}, want: "dns-proxy-start"},
+		{call: &intercept.Call{Action: "http", Cleanup: true, Request: &container.ProxyRequest{}}, want: "http-proxy-stop"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, action(tt.call))
+	}
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	log "github.com/sirupsen/logrus"
+)
+
+// Interceptor logs the containers a command selected and every mutation of
+// a runtime call. A failed targets write fails the command before it
+// changes anything. A mutation is logged once it is done, so a failed write
+// is only warned about: failing the call would make the command skip
+// reverting a change that was made.
+type Interceptor struct {
+	log *Logger
+}
+
+// NewClient returns a client passing calls to client and logging them to
+// log.
+func NewClient(client container.Client, log *Logger) *intercept.Client {
+	return intercept.New(client, &Interceptor{log: log})
+}
+
+// Targets logs the containers a command selected.
+func (a *Interceptor) Targets(_ context.Context, containers []*container.Container) error {
+	if len(containers) == 0 {
+		return nil
+	}
+	targets := make([]Target, 0, len(containers))
+	for _, c := range containers {
+		targets = append(targets, Target{ID: c.ID(), Name: intercept.Name(c)})
+	}
+	return a.log.Log(Entry{Event: EventTargets, Targets: targets})
+}
+
+// Intercept runs the call and logs it, unless it only reads state.
+func (a *Interceptor) Intercept(ctx context.Context, call *intercept.Call, next func(context.Context) error) error {
+	if call.Read {
+		return next(ctx)
+	}
+	start := a.log.now().UTC()
+	err := next(ctx)
+	e := Entry{Event: EventMutation, Action: action(call), Params: call.Fields(), DryRun: call.DryRun, Start: &start, Outcome: OutcomeOK}
+	if c := call.Container; c != nil {
+		e.ID, e.Name = c.ID(), intercept.Name(c)
+	}
+	if err != nil {
+		e.Outcome, e.Error = OutcomeError, err.Error()
+	}
+	if logErr := a.log.Log(e); logErr != nil {
+		log.WithError(logErr).WithField("action", e.Action).Warn("failed to audit runtime change")
+	}
+	return err
+}
+
+// action names the call in the log: the changes adding chaos and reverting
+// it are told apart by their names.
+func action(call *intercept.Call) string {
+	if _, ok := call.Request.(*container.ProxyRequest); ok {
+		if call.Cleanup {
+			return call.Action + "-proxy-stop"
+		}
+		return call.Action + "-proxy-start"
+	}
+	switch call.Action {
+	case "netem", "iptables":
+		if call.Cleanup {
+			return call.Action + "-del"
+		}
+		return call.Action + "-add"
+	case "stress":
+		return "stress-start"
+	case "stop-sidecar":
+		return "stress-stop"
+	}
+	return call.Action
+}
@@ -0,0 +1,224 @@
+// Package artifacts recognizes the chaos Pumba leaves in the network
+// namespace of a container: netem qdiscs, iptables rules and the nftables
+// table. It backs the `cleanup` command, which removes them, and the
+// `status` command, which reports them.
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos/iptables"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/dnsproxy"
+	"github.com/alexei-led/pumba/pkg/httpproxy"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// handles of the qdisc tree built by netem requests filtering by IP or
+	// port: a root prio qdisc with the netem qdisc on its third band
+	prioHandle  = "1:"
+	prioBand    = "1:3"
+	netemHandle = "30:"
+)
+
+// Client is the narrow interface needed to scan a network namespace.
+type Client interface {
+	container.Netem
+	container.IPTables
+}
+
+// Netem is a netem qdisc in one of the shapes Pumba installs.
+type Netem struct {
+	Dev string `json:"dev"`
+	// Filtered marks the prio tree of an injection filtered by IP or port.
+	Filtered bool `json:"filtered"`
+	// Options are the netem parameters, e.g. "limit 1000 delay 100ms".
+	Options string `json:"options"`
+}
+
+// NetNS holds the Pumba artifacts found in a container network namespace.
+type NetNS struct {
+	Netem []Netem
+	// Rules are the `iptables -S` fields of the Pumba rules.
+	Rules [][]string
+	// NFTable reports the Pumba nftables table.
+	NFTable bool
+}
+
+// Empty reports whether no artifact was found.
+func (ns *NetNS) Empty() bool {
+	return len(ns.Netem) == 0 && len(ns.Rules) == 0 && !ns.NFTable
+}
+
+// ScanNetNS reads the qdiscs and packet filter state of the network
+// namespace of c. sidecar returns the nettools sidecar of each runtime call.
+// Failed reads are returned together with whatever was found; a target
+// without nft is not an error.
+func ScanNetNS(ctx context.Context, client Client, c *container.Container, sidecar func() container.SidecarSpec) (*NetNS, error) {
+	ns := &NetNS{}
+	var errs []error
+	out, err := client.NetemStatus(ctx, &container.NetemRequest{Container: c, Sidecar: sidecar()})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list qdiscs of %s: %w", c.Name(), err))
+	}
+	ns.Netem = NetemQdiscs(ParseQdiscs(out))
+
+	out, err = client.IPTablesStatus(ctx, &container.IPTablesRequest{
+		Container: c, Sidecar: sidecar(), Backend: container.FilterBackendIPTables,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list iptables rules of %s: %w", c.Name(), err))
+	}
+	ns.Rules = Rules(out)
+
+	out, err = client.IPTablesStatus(ctx, &container.IPTablesRequest{
+		Container: c, Sidecar: sidecar(), Backend: container.FilterBackendNFTables,
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", c.Name()).Debug("nft is not available, skipping nftables scan")
+	}
+	ns.NFTable = err == nil && HasNFTablesTable(out)
+	return ns, errors.Join(errs...)
+}
+
+// Qdisc is a line of `tc qdisc show` output; Parent is "root" for root
+// qdiscs.
+type Qdisc struct {
+	Kind, Handle, Dev, Parent, Options string
+}
+
+// ParseQdiscs parses `tc -s qdisc show` output, ignoring statistics lines.
+func ParseQdiscs(out string) []Qdisc {
+	var qs []Qdisc
+	for line := range strings.Lines(out) {
+		f := strings.Fields(line)
+		if len(f) < 3 || f[0] != "qdisc" { //nolint:mnd
+			continue
+		}
+		q := Qdisc{Kind: f[1], Handle: f[2]}
+		i := 3
+		for ; i < len(f) && q.Parent == ""; i++ {
+			switch {
+			case f[i] == "root":
+				q.Parent = "root"
+			case f[i] == "dev" && i+1 < len(f):
+				q.Dev = f[i+1]
+			case f[i] == "parent" && i+1 < len(f):
+				i++
+				q.Parent = f[i]
+			}
+		}
+		if i+1 < len(f) && f[i] == "refcnt" {
+			i += 2
+		}
+		if i < len(f) {
+			q.Options = strings.Join(f[i:], " ")
+		}
+		qs = append(qs, q)
+	}
+	return qs
+}
+
+// NetemQdiscs returns the netem qdiscs in one of the shapes Pumba installs:
+// at the root, or as 30: on band 1:3 of a root 1: prio qdisc.
+func NetemQdiscs(qs []Qdisc) []Netem {
+	var prio []string
+	for _, q := range qs {
+		if q.Kind == "prio" && q.Handle == prioHandle && q.Parent == "root" {
+			prio = append(prio, q.Dev)
+		}
+	}
+	var netem []Netem
+	for _, q := range qs {
+		if q.Kind != "netem" {
+			continue
+		}
+		switch {
+		case q.Parent == "root":
+			netem = append(netem, Netem{Dev: q.Dev, Options: q.Options})
+		case q.Parent == prioBand && q.Handle == netemHandle && slices.Contains(prio, q.Dev):
+			netem = append(netem, Netem{Dev: q.Dev, Filtered: true, Options: q.Options})
+		}
+	}
+	return netem
+}
+
+// Rules returns the Pumba rules listed in `iptables -S` output of the filter
+// and nat tables: random or nth drops (loss), connection throttling and the
+// redirects of the dns and http proxies.
+func Rules(out string) [][]string {
+	var rules [][]string
+	for line := range strings.Lines(out) {
+		f := strings.Fields(line)
+		if len(f) < 2 || f[0] != "-A" || !isPumbaRule(f) { //nolint:mnd
+			continue
+		}
+		rules = append(rules, f)
+	}
+	return rules
+}
+
+// DeleteArgs returns the iptables arguments removing rule. REDIRECT only
+// exists in the nat table, so it selects the table.
+func DeleteArgs(rule []string) []string {
+	args := append([]string{"-D"}, rule[1:]...)
+	if hasOption(rule, "-j", "REDIRECT") {
+		args = append([]string{"-t", "nat"}, args...)
+	}
+	return args
+}
+
+// isPumbaRule reports whether the `iptables -S` rule fields f were
+// installed by Pumba.
+func isPumbaRule(f []string) bool {
+	if hasOption(f, "-j", "REDIRECT") {
+		return hasOption(f, "--to-ports", strconv.Itoa(dnsproxy.DefaultPort)) ||
+			hasOption(f, "--to-ports", strconv.Itoa(httpproxy.DefaultPort))
+	}
+	if f[1] != "INPUT" || !hasOption(f, "-j", "DROP") {
+		return false
+	}
+	return hasOption(f, "-m", "statistic") ||
+		slices.ContainsFunc(optionValues(f, "--hashlimit-name"), iptables.IsHashlimitName) ||
+		(hasOption(f, "-m", "conntrack") && hasOption(f, "-m", "connlimit"))
+}
+
+// hasOption reports whether fields carry option name with value.
+func hasOption(fields []string, name, value string) bool {
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == name && fields[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// optionValues returns the values of option name in fields.
+func optionValues(fields []string, name string) []string {
+	var values []string
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == name {
+			values = append(values, fields[i+1])
+		}
+	}
+	return values
+}
+
+// HasNFTablesTable reports whether `nft list tables` output lists the Pumba
+// table.
+func HasNFTablesTable(out string) bool {
+	table := strings.Join([]string{"table", nftables.Family, nftables.Table}, " ")
+	for line := range strings.Lines(out) {
+		if strings.Join(strings.Fields(line), " ") == table {
+			return true
+		}
+	}
+	return false
+}
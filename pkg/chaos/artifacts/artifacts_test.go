@@ -0,0 +1,85 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const qdiscOutput = `qdisc noqueue 0: dev lo root refcnt 2
+ Sent 0 bytes 0 pkt (dropped 0, overlimits 0 requeues 0)
+qdisc netem 8001: dev eth0 root refcnt 2 limit 1000 delay 100ms
+ Sent 1042 bytes 12 pkt (dropped 0, overlimits 0 requeues 0)
+qdisc prio 1: dev eth1 root refcnt 2 bands 3 priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1
+qdisc sfq 10: dev eth1 parent 1:1 limit 127p quantum 1514b
+qdisc netem 30: dev eth1 parent 1:3 limit 1000 loss 10%
+qdisc netem 40: dev eth2 parent 2:1 limit 1000 delay 5ms
+`
+
+const rulesOutput = `-P INPUT ACCEPT
+-A INPUT -i eth0 -p tcp -m statistic --mode random --probability 0.20000000000 -j DROP
+-A INPUT -i eth0 -m conntrack --ctstate NEW -m hashlimit --hashlimit-above 10/sec --hashlimit-burst 5 --hashlimit-name pumba -j DROP
+-A INPUT -i eth0 -m conntrack --ctstate NEW -m connlimit --connlimit-above 5 --connlimit-mask 0 -j DROP
+-A INPUT -s 10.0.0.1/32 -j DROP
+-P PREROUTING ACCEPT
+-A PREROUTING -p tcp -m tcp --dport 8080 -j REDIRECT --to-ports 15080
+-A OUTPUT -p udp -m udp --dport 53 -m owner ! --uid-owner 65534 -j REDIRECT --to-ports 15353
+-A OUTPUT -p tcp -m tcp --dport 80 -j REDIRECT --to-ports 3128
+`
+
+func TestNetemQdiscs(t *testing.T) {
+	assert.Equal(t, []Netem{
+		{Dev: "eth0", Options: "limit 1000 delay 100ms"},
+		{Dev: "eth1", Filtered: true, Options: "limit 1000 loss 10%"},
+	}, NetemQdiscs(ParseQdiscs(qdiscOutput)))
+	assert.Empty(t, NetemQdiscs(ParseQdiscs("qdisc noqueue 0: dev eth0 root refcnt 2\n")))
+}
+
+func TestRules(t *testing.T) {
+	rules := Rules(rulesOutput)
+	got := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		got = append(got, DeleteArgs(rule))
+	}
+	assert.Equal(t, [][]string{
+		{"-D", "INPUT", "-i", "eth0", "-p", "tcp", "-m", "statistic", "--mode", "random", "--probability", "0.20000000000", "-j", "DROP"},
+		{"-D", "INPUT", "-i", "eth0", "-m", "conntrack", "--ctstate", "NEW", "-m", "hashlimit", "--hashlimit-above", "10/sec",
+			"--hashlimit-burst", "5", "--hashlimit-name", "pumba", "-j", "DROP"},
+		{"-D", "INPUT", "-i", "eth0", "-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit", "--connlimit-above", "5",
+			"--connlimit-mask", "0", "-j", "DROP"},
+		{"-t", "nat", "-D", "PREROUTING", "-p", "tcp", "-m", "tcp", "--dport", "8080", "-j", "REDIRECT", "--to-ports", "15080"},
+		{"-t", "nat", "-D", "OUTPUT", "-p", "udp", "-m", "udp", "--dport", "53", "-m", "owner", "!", "--uid-owner", "65534",
+			"-j", "REDIRECT", "--to-ports", "15353"},
+	}, got)
+}
+
+func TestRules_HashlimitNames(t *testing.T) {
+	rules := Rules("-A INPUT -m hashlimit --hashlimit-above 5/sec --hashlimit-name pumba0badcafe -j DROP\n" +
+		"-A INPUT -m hashlimit --hashlimit-above 5/sec --hashlimit-name api -j DROP\n")
+	require.Len(t, rules, 1, "only the per-run tables of throttle rules are Pumba's")
+	assert.Contains(t, rules[0], "pumba0badcafe")
+}
+
+func TestScanNetNS(t *testing.T) {
+	c := &container.Container{ContainerID: "abc", ContainerName: "/web"}
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().NetemStatus(mock.Anything, mock.Anything).Return("", errors.New("tc failed")).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendIPTables
+	})).Return(rulesOutput, nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendNFTables
+	})).Return("", errors.New("nft: not found")).Once()
+
+	ns, err := ScanNetNS(context.Background(), mockClient, c, func() container.SidecarSpec { return container.SidecarSpec{} })
+	require.ErrorContains(t, err, "tc failed")
+	assert.NotContains(t, err.Error(), "nft", "missing nft is not an error")
+	assert.Len(t, ns.Rules, 5)
+	assert.False(t, ns.NFTable)
+	assert.False(t, ns.Empty())
+}
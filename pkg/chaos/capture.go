@@ -0,0 +1,41 @@
+package chaos
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+)
+
+// ParseCapture reads the packet capture flags (--capture, --capture-size,
+// --capture-files) shared by the netem and iptables parent commands. It
+// returns nil when --capture is not set. The capture runs tcpdump in the
+// given network tools sidecar image on iface, filtered to traffic to or from
+// nets on ports.
+func ParseCapture(c cliflags.Flags, iface string, sidecar container.SidecarSpec, nets []*net.IPNet, ports []string) (*container.CaptureSpec, error) {
+	dir := c.String("capture")
+	if dir == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(dir) {
+		return nil, errors.New("capture directory must be an absolute path on the runtime host")
+	}
+	if sidecar.Image == "" {
+		return nil, errors.New("packet capture requires a network tools image with tcpdump")
+	}
+	size, files := c.Int("capture-size"), c.Int("capture-files")
+	if size <= 0 || files <= 0 {
+		return nil, errors.New("capture-size and capture-files must be positive")
+	}
+	return &container.CaptureSpec{
+		Dir:       dir,
+		Interface: iface,
+		Filter:    container.CaptureFilter(nets, ports),
+		FileSize:  size,
+		FileCount: files,
+		Image:     sidecar.Image,
+		Pull:      sidecar.Pull,
+	}, nil
+}
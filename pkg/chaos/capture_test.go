@@ -0,0 +1,63 @@
+package chaos
+
+import (
+	"net"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func captureFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{Name: "capture"},
+		cli.IntFlag{Name: "capture-size", Value: 100},
+		cli.IntFlag{Name: "capture-files", Value: 5},
+	}
+}
+
+func TestParseCapture(t *testing.T) {
+	sidecar := container.SidecarSpec{Image: "nettools:test", Pull: true}
+	_, ipNet, err := net.ParseCIDR("10.0.0.0/24")
+	require.NoError(t, err)
+
+	c := buildFlags(t, captureFlags(), []string{"--capture", "/tmp/pcap", "--capture-size", "10"}, nil, nil)
+	spec, err := ParseCapture(c, "eth0", sidecar, []*net.IPNet{ipNet}, []string{"80"})
+	require.NoError(t, err)
+	assert.Equal(t, &container.CaptureSpec{
+		Dir:       "/tmp/pcap",
+		Interface: "eth0",
+		Filter:    "net 10.0.0.0/24 and port 80",
+		FileSize:  10,
+		FileCount: 5,
+		Image:     "nettools:test",
+		Pull:      true,
+	}, spec)
+
+	spec, err = ParseCapture(buildFlags(t, captureFlags(), nil, nil, nil), "eth0", sidecar, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, spec, "no capture without --capture")
+}
+
+func TestParseCapture_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		image   string
+		wantErr string
+	}{
+		{"relative dir", []string{"--capture", "pcap"}, "nettools", "absolute path"},
+		{"no image", []string{"--capture", "/tmp/pcap"}, "", "requires a network tools image"},
+		{"zero size", []string{"--capture", "/tmp/pcap", "--capture-size", "0"}, "nettools", "must be positive"},
+		{"zero files", []string{"--capture", "/tmp/pcap", "--capture-files", "0"}, "nettools", "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := buildFlags(t, captureFlags(), tt.args, nil, nil)
+			_, err := ParseCapture(c, "eth0", container.SidecarSpec{Image: tt.image}, nil, nil)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Package cleanup implements the `cleanup` command removing chaos artifacts
+// left behind when Pumba is killed before it can revert an injection:
+// sidecar containers and, with --netns, in the network namespace of the
+// named target containers, netem qdiscs, iptables rules and the nftables
+// table Pumba installs. With the global --dry-run flag it only reports what
+// it finds.
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/artifacts"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	log "github.com/sirupsen/logrus"
+)
+
+// anyIPNet stands in for the IP filter of an orphaned filtered netem
+// injection. The original filter is unknown, but any filter makes the
+// runtime tear down the whole prio tree instead of a root netem qdisc.
+var anyIPNet = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)} //nolint:mnd
+
+// cleanupClient is the narrow interface needed by the cleanup command.
+type cleanupClient interface {
+	container.Lister
+	container.Lifecycle
+	container.Netem
+	container.IPTables
+}
+
+// Params holds the parsed parameters of the cleanup command.
+type Params struct {
+	// OlderThan spares sidecars created more recently, which may belong to
+	// a chaos command that is still running.
+	OlderThan time.Duration
+	// Image is the nettools image used to inspect and clean the target
+	// network namespaces; empty runs tc and iptables in the target itself.
+	Image string
+	Pull  bool
+	// SkipLabelled also treats every container carrying the skip label as a
+	// sidecar, to catch sidecars created by older Pumba versions.
+	SkipLabelled bool
+	// NetNS also cleans the network namespaces of the named targets. tc and
+	// iptables have no notion of ownership, so it is opt-in and needs
+	// explicitly named targets.
+	NetNS bool
+}
+
+// `cleanup` command
+type cleanupCommand struct {
+	client cleanupClient
+	gp     *chaos.GlobalParams
+	p      *Params
+	// pull is cleared once the nettools image has been pulled
+	pull bool
+	now  func() time.Time
+}
+
+// NewCleanupCommand create new cleanup command
+func NewCleanupCommand(client cleanupClient, gp *chaos.GlobalParams, p *Params) (chaos.Command, error) {
+	if p.OlderThan < 0 {
+		return nil, errors.New("invalid older-than value: must be >= 0")
+	}
+	if p.NetNS && len(gp.Names) == 0 && gp.Pattern == "" {
+		return nil, errors.New("--netns needs explicitly named target containers")
+	}
+	return &cleanupCommand{client: client, gp: gp, p: p, pull: p.Pull, now: time.Now}, nil
+}
+
+// Run cleanup command
+func (n *cleanupCommand) Run(ctx context.Context, _ bool) error {
+	live, err := n.removeSidecars(ctx)
+	errs := []error{err}
+	if !n.p.NetNS {
+		return errors.Join(errs...)
+	}
+	targets, err := container.ListNContainers(ctx, n.client, n.gp.Names, n.gp.Pattern, n.gp.Labels, 0)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list containers: %w", err))
+		return errors.Join(errs...)
+	}
+	if err := intercept.Targets(ctx, n.client, targets); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, c := range targets {
+		logger := log.WithFields(log.Fields{"name": c.Name(), "id": c.ID()})
+		if live[c.ID()] {
+			// a chaos command may still be running against it
+			logger.Info("skipping network namespace of container with a recent sidecar")
+			continue
+		}
+		logger.Debug("scanning container network namespace")
+		errs = append(errs, n.cleanNetNS(ctx, c))
+	}
+	return errors.Join(errs...)
+}
+
+// sidecar returns the nettools sidecar of the next runtime call, pulling the
+// image only the first time.
+func (n *cleanupCommand) sidecar() container.SidecarSpec {
+	spec := container.SidecarSpec{Image: n.p.Image, Pull: n.pull}
+	if n.p.Image != "" {
+		n.pull = false
+	}
+	return spec
+}
+
+// removeSidecars removes Pumba sidecar containers, running or not, created
+// more than OlderThan ago. A sidecar without a creation time is removed. It
+// returns the IDs of the targets of the sidecars it spared.
+func (n *cleanupCommand) removeSidecars(ctx context.Context) (map[string]bool, error) {
+	isSidecar := func(c *container.Container) bool {
+		return c.IsPumbaSidecar() || (n.p.SkipLabelled && c.IsPumbaSkip() && !c.IsPumba())
+	}
+	sidecars, err := n.client.ListContainers(ctx, isSidecar, container.ListOpts{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sidecar containers: %w", err)
+	}
+	live := map[string]bool{}
+	var errs []error
+	for _, c := range sidecars {
+		logger := log.WithFields(log.Fields{"name": c.Name(), "id": c.ID(), "created": c.Created})
+		if !c.Created.IsZero() && n.now().Sub(c.Created) < n.p.OlderThan {
+			logger.Debug("skipping recent sidecar container")
+			if target := c.SidecarTarget(); target != "" {
+				live[target] = true
+			}
+			continue
+		}
+		logger.Info("removing orphaned sidecar container")
+		opts := container.RemoveOpts{Force: true, Volumes: true, DryRun: n.gp.DryRun}
+		if err := n.client.RemoveContainer(ctx, c, opts); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove sidecar container %s: %w", c.Name(), err))
+		}
+	}
+	return live, errors.Join(errs...)
+}
+
+// cleanNetNS removes the netem qdiscs, iptables rules and nftables table
+// found in the network namespace of c.
+func (n *cleanupCommand) cleanNetNS(ctx context.Context, c *container.Container) error {
+	ns, err := artifacts.ScanNetNS(ctx, n.client, c, n.sidecar)
+	errs := []error{err}
+	for _, q := range ns.Netem {
+		log.WithFields(log.Fields{"name": c.Name(), "id": c.ID(), "iface": q.Dev, "filtered": q.Filtered}).
+			Info("removing orphaned netem qdisc")
+		req := &container.NetemRequest{Container: c, Interface: q.Dev, Sidecar: n.sidecar(), DryRun: n.gp.DryRun}
+		if q.Filtered {
+			req.IPs = []*net.IPNet{anyIPNet}
+		}
+		if err := n.client.StopNetemContainer(ctx, req); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove netem qdisc from %s on %s: %w", q.Dev, c.Name(), err))
+		}
+	}
+	for _, rule := range ns.Rules {
+		log.WithFields(log.Fields{"name": c.Name(), "id": c.ID(), "rule": strings.Join(rule, " ")}).
+			Info("removing orphaned iptables rule")
+		req := &container.IPTablesRequest{
+			Container: c, CmdPrefix: artifacts.DeleteArgs(rule), Sidecar: n.sidecar(),
+			Backend: container.FilterBackendIPTables, DryRun: n.gp.DryRun,
+		}
+		if err := n.client.StopIPTablesContainer(ctx, req); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove iptables rule from %s: %w", c.Name(), err))
+		}
+	}
+	if ns.NFTable {
+		log.WithFields(log.Fields{"name": c.Name(), "id": c.ID()}).Info("removing orphaned nftables table")
+		// any removal request is translated into deleting the Pumba table
+		req := &container.IPTablesRequest{
+			Container: c, CmdPrefix: []string{"-D", "INPUT"}, CmdSuffix: []string{"-j", "DROP"},
+			Sidecar: n.sidecar(), Backend: container.FilterBackendNFTables, DryRun: n.gp.DryRun,
+		}
+		if err := n.client.StopIPTablesContainer(ctx, req); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove nftables table from %s: %w", c.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,150 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const qdiscOutput = `qdisc noqueue 0: dev lo root refcnt 2
+ Sent 0 bytes 0 pkt (dropped 0, overlimits 0 requeues 0)
+qdisc netem 8001: dev eth0 root refcnt 2 limit 1000 delay 100ms
+ Sent 1042 bytes 12 pkt (dropped 0, overlimits 0 requeues 0)
+qdisc prio 1: dev eth1 root refcnt 2 bands 3 priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1
+qdisc sfq 10: dev eth1 parent 1:1 limit 127p quantum 1514b
+qdisc netem 30: dev eth1 parent 1:3 limit 1000 loss 10%
+qdisc netem 40: dev eth2 parent 2:1 limit 1000 delay 5ms
+`
+
+func TestNewCleanupCommand_Validation(t *testing.T) {
+	_, err := NewCleanupCommand(container.NewMockClient(t), &chaos.GlobalParams{}, &Params{OlderThan: -time.Second})
+	assert.ErrorContains(t, err, "invalid older-than")
+	_, err = NewCleanupCommand(container.NewMockClient(t), &chaos.GlobalParams{}, &Params{NetNS: true})
+	assert.EqualError(t, err, "--netns needs explicitly named target containers")
+}
+
+func TestCleanupCommand_RunSidecarsOnly(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{All: true}).
+		Return(nil, nil).Once()
+	// without --netns no namespace is scanned, so a live injection of another
+	// Pumba process or a qdisc of the user stays in place
+	cmd, err := NewCleanupCommand(mockClient, &chaos.GlobalParams{}, &Params{OlderThan: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
+
+func TestCleanupCommand_Run(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	orphan := &container.Container{ContainerID: "s1", ContainerName: "/pumba-sidecar-1", Labels: container.SidecarLabels("tc", "abc"), Created: now.Add(-2 * time.Hour)}
+	recent := &container.Container{ContainerID: "s2", ContainerName: "/pumba-sidecar-2", Labels: container.SidecarLabels("tc", "live"), Created: now.Add(-time.Minute)}
+	target := &container.Container{ContainerID: "abc", ContainerName: "/web"}
+	busy := &container.Container{ContainerID: "live", ContainerName: "/api"}
+
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{All: true}).
+		Return([]*container.Container{orphan, recent}, nil).Once()
+	mockClient.EXPECT().RemoveContainer(mock.Anything, orphan, container.RemoveOpts{Force: true, Volumes: true, DryRun: true}).
+		Return(nil).Once()
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return([]*container.Container{target, busy}, nil).Once()
+
+	// busy has a recent sidecar, so its namespace is not touched
+	var pulls []bool
+	mockClient.EXPECT().NetemStatus(mock.Anything, mock.MatchedBy(func(r *container.NetemRequest) bool {
+		return r.Container == target && r.Interface == ""
+	})).Run(func(_ context.Context, r *container.NetemRequest) {
+		pulls = append(pulls, r.Sidecar.Pull)
+	}).Return(qdiscOutput, nil).Once()
+	mockClient.EXPECT().StopNetemContainer(mock.Anything, mock.MatchedBy(func(r *container.NetemRequest) bool {
+		return r.Interface == "eth0" && len(r.IPs) == 0 && r.DryRun
+	})).Return(nil).Once()
+	mockClient.EXPECT().StopNetemContainer(mock.Anything, mock.MatchedBy(func(r *container.NetemRequest) bool {
+		return r.Interface == "eth1" && len(r.IPs) == 1 && r.DryRun
+	})).Return(nil).Once()
+
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendIPTables
+	})).Run(func(_ context.Context, r *container.IPTablesRequest) {
+		pulls = append(pulls, r.Sidecar.Pull)
+	}).Return("-A INPUT -i eth0 -m statistic --mode nth --every 3 --packet 0 -j DROP\n", nil).Once()
+	mockClient.EXPECT().StopIPTablesContainer(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendIPTables && r.CmdPrefix[0] == "-D" && r.DryRun
+	})).Return(nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendNFTables
+	})).Return("table ip filter\ntable inet pumba\n", nil).Once()
+	mockClient.EXPECT().StopIPTablesContainer(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendNFTables && r.DryRun
+	})).Return(nil).Once()
+
+	gp := &chaos.GlobalParams{Names: []string{"web", "api"}, DryRun: true}
+	cmd, err := NewCleanupCommand(mockClient, gp, &Params{OlderThan: time.Hour, Image: "nettools", Pull: true, NetNS: true})
+	require.NoError(t, err)
+	cmd.(*cleanupCommand).now = func() time.Time { return now }
+	require.NoError(t, cmd.Run(context.Background(), false))
+	assert.Equal(t, []bool{true, false}, pulls, "nettools image is pulled once")
+}
+
+func TestCleanupCommand_RunCollectsErrors(t *testing.T) {
+	target := &container.Container{ContainerID: "abc", ContainerName: "/web"}
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{All: true}).
+		Return(nil, errors.New("list failed")).Once()
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return([]*container.Container{target}, nil).Once()
+	mockClient.EXPECT().NetemStatus(mock.Anything, mock.Anything).Return("", errors.New("tc failed")).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendIPTables
+	})).Return("", nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendNFTables
+	})).Return("", errors.New("nft: not found")).Once()
+
+	cmd, err := NewCleanupCommand(mockClient, &chaos.GlobalParams{Pattern: "^web"}, &Params{NetNS: true})
+	require.NoError(t, err)
+	err = cmd.Run(context.Background(), false)
+	assert.ErrorContains(t, err, "list failed")
+	assert.ErrorContains(t, err, "tc failed")
+	assert.NotContains(t, err.Error(), "nft", "missing nft is not an error")
+}
+
+func TestRemoveSidecars_UserContainerNamedLikeSidecar(t *testing.T) {
+	user := &container.Container{ContainerID: "a", ContainerName: "/pumba-capture-db", Created: time.Now().Add(-48 * time.Hour)}
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		RunAndReturn(func(_ context.Context, fn container.FilterFunc, _ container.ListOpts) ([]*container.Container, error) {
+			if fn(user) {
+				return []*container.Container{user}, nil
+			}
+			return nil, nil
+		}).Once()
+	// no RemoveContainer expectation: the mock fails the test if it is removed
+	n := &cleanupCommand{client: mockClient, gp: &chaos.GlobalParams{}, p: &Params{}, now: time.Now}
+	_, err := n.removeSidecars(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRemoveSidecars_SkipLabelled(t *testing.T) {
+	labelled := &container.Container{ContainerID: "a", ContainerName: "/old", Labels: map[string]string{"com.gaiaadm.pumba.skip": "true"}}
+	pumba := &container.Container{ContainerID: "b", ContainerName: "/pumba", Labels: map[string]string{"com.gaiaadm.pumba": "true", "com.gaiaadm.pumba.skip": "true"}}
+	for _, skipLabelled := range []bool{false, true} {
+		mockClient := container.NewMockClient(t)
+		var matched []bool
+		mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+			Run(func(_ context.Context, fn container.FilterFunc, _ container.ListOpts) {
+				matched = []bool{fn(labelled), fn(pumba)}
+			}).Return(nil, nil).Once()
+		n := &cleanupCommand{client: mockClient, gp: &chaos.GlobalParams{}, p: &Params{SkipLabelled: skipLabelled}, now: time.Now}
+		_, err := n.removeSidecars(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []bool{skipLabelled, false}, matched)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cleanup"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// NewCleanupCLICommand initialize CLI cleanup command.
+func NewCleanupCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[cleanup.Params]{
+		Name: "cleanup",
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "older-than",
+				Usage: "remove only sidecar containers created longer ago than this; use with optional unit suffix: 'ms/s/m/h'",
+				Value: time.Hour,
+			},
+			cli.StringFlag{
+				Name:  "image",
+				Usage: "Docker image with tc, iptables and nft, used to inspect and clean container network namespaces (empty: run the tools in the container)",
+				Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+			},
+			cli.BoolTFlag{
+				Name:  "pull-image",
+				Usage: "force pull image",
+			},
+			cli.BoolFlag{
+				Name:  "skip-labelled",
+				Usage: "also remove every container labelled com.gaiaadm.pumba.skip, to catch sidecars of older Pumba versions",
+			},
+			cli.BoolFlag{
+				Name:  "netns",
+				Usage: "also remove netem qdiscs, iptables rules and the nftables table from the network namespace of the named containers",
+			},
+		},
+		Usage:       "remove orphaned chaos artifacts: sidecars, netem qdiscs and iptables rules",
+		ArgsUsage:   fmt.Sprintf("containers to scan with --netns (name, list of names, or RE2 regex if prefixed with %q)", chaos.Re2Prefix),
+		Description: "removes Pumba sidecar containers older than --older-than; with --netns, also removes the netem qdiscs, iptables rules and nftables table Pumba installs from the network namespace of the named containers, sparing those a sidecar younger than --older-than still targets; use the global --dry-run flag to only report them",
+		Parse:       parseCleanupParams,
+		Build:       buildCleanupCommand,
+	})
+}
+
+func parseCleanupParams(c cliflags.Flags, _ *chaos.GlobalParams) (cleanup.Params, error) {
+	return cleanup.Params{
+		OlderThan:    c.Duration("older-than"),
+		Image:        c.String("image"),
+		Pull:         c.BoolT("pull-image"),
+		SkipLabelled: c.Bool("skip-labelled"),
+		NetNS:        c.Bool("netns"),
+	}, nil
+}
+
+func buildCleanupCommand(client container.Client, gp *chaos.GlobalParams, p cleanup.Params) (chaos.Command, error) {
+	return cleanup.NewCleanupCommand(client, gp, &p)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func newTestCLIContext(t *testing.T, flags []cli.Flag, args []string) *cli.Context {
+	t.Helper()
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(cli.NewApp(), fs, nil)
+}
+
+func TestParseCleanupParams(t *testing.T) {
+	cmd := NewCleanupCLICommand(context.Background(), func() container.Client { return nil })
+	assert.Equal(t, "cleanup", cmd.Name)
+
+	c := newTestCLIContext(t, cmd.Flags, nil)
+	p, err := parseCleanupParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, p.OlderThan)
+	assert.Equal(t, "ghcr.io/alexei-led/pumba-alpine-nettools:latest", p.Image)
+	assert.True(t, p.Pull, "BoolT pull-image defaults true")
+	assert.False(t, p.SkipLabelled)
+	assert.False(t, p.NetNS)
+
+	c = newTestCLIContext(t, cmd.Flags, []string{"--older-than", "10m", "--skip-labelled", "--netns", "--image", ""})
+	p, err = parseCleanupParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, p.OlderThan)
+	assert.Empty(t, p.Image)
+	assert.True(t, p.SkipLabelled)
+	assert.True(t, p.NetNS)
+
+	_, err = buildCleanupCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.EqualError(t, err, "--netns needs explicitly named target containers")
+	built, err := buildCleanupCommand(container.NewMockClient(t), &chaos.GlobalParams{Names: []string{"web"}}, p)
+	require.NoError(t, err)
+	assert.NotNil(t, built)
+}
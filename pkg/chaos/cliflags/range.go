@@ -0,0 +1,310 @@
+package cliflags
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+// Range is a flag value accepting one value, a range "min..max" of numbers
+// or durations, or choices "a|b|c". Read through a Draw (see Sample), a range
+// or choices read as one value drawn from them.
+type Range struct {
+	kind rangeKind
+	raw  string
+	// values are the choices, or the bounds of a span
+	values []string
+	span   bool
+}
+
+type rangeKind int
+
+const (
+	kindString rangeKind = iota
+	kindInt
+	kindFloat
+	kindDuration
+)
+
+// number parses v as a number of the kind; durations in nanoseconds.
+func (k rangeKind) number(v string) (float64, error) {
+	switch k {
+	case kindInt:
+		n, err := strconv.Atoi(v)
+		return float64(n), err
+	case kindFloat:
+		return strconv.ParseFloat(v, 64)
+	case kindDuration:
+		d, err := time.ParseDuration(v)
+		return float64(d), err
+	}
+	return 0, nil
+}
+
+// Set parses s, keeping the value unchanged when s is malformed.
+func (r *Range) Set(s string) error {
+	values, span := strings.Split(s, "|"), false
+	if lo, hi, ok := strings.Cut(s, ".."); ok && r.kind != kindString && len(values) == 1 {
+		values, span = []string{lo, hi}, true
+	}
+	bounds := make([]float64, len(values))
+	for i, v := range values {
+		n, err := r.kind.number(v)
+		if err != nil {
+			return fmt.Errorf("bad value %q in %q", v, s)
+		}
+		bounds[i] = n
+	}
+	if span && bounds[0] > bounds[1] {
+		return fmt.Errorf("bad range %q: min above max", s)
+	}
+	r.raw, r.values, r.span = s, values, span
+	return nil
+}
+
+// String returns the value as given.
+func (r *Range) String() string {
+	if r == nil {
+		return ""
+	}
+	return r.raw
+}
+
+// Ranged reports whether the value is a range or choices, not one value.
+func (r *Range) Ranged() bool {
+	return r.span || len(r.values) > 1
+}
+
+// draw returns a value drawn from the range using rng.
+func (r *Range) draw(rng *rand.Rand) string {
+	if !r.span {
+		return r.values[rng.Intn(len(r.values))]
+	}
+	lo, _ := r.kind.number(r.values[0])
+	hi, _ := r.kind.number(r.values[1])
+	switch r.kind {
+	case kindInt:
+		return strconv.Itoa(int(lo) + rng.Intn(int(hi-lo)+1))
+	case kindDuration:
+		d := time.Duration(lo) + time.Duration(rng.Int63n(int64(hi-lo)+1))
+		return d.Round(time.Millisecond).String()
+	default:
+		v := lo + rng.Float64()*(hi-lo)
+		return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+	}
+}
+
+// extreme returns the lowest value of the range, or its highest with upper.
+// Choices of strings have no order: their first or last stands in.
+func (r *Range) extreme(upper bool) string {
+	if r.kind == kindString {
+		if upper {
+			return r.values[len(r.values)-1]
+		}
+		return r.values[0]
+	}
+	best, bestN := r.values[0], 0.0
+	for i, v := range r.values {
+		n, _ := r.kind.number(v)
+		if i == 0 || (upper && n > bestN) || (!upper && n < bestN) {
+			best, bestN = v, n
+		}
+	}
+	return best
+}
+
+// rangeable are the chaos parameters accepting a range. Flags sizing or
+// bounding a run (--limit, --port, --capture-size, ...) keep one value: a
+// range of them would be read as one value, not drawn.
+var rangeable = map[string]bool{
+	"duration": true, "delay": true, "time": true, "jitter": true, "timeout": true, "restart-timeout": true,
+	"percent": true, "correlation": true, "probability": true, "every": true, "packet": true,
+	"pg": true, "pb": true, "one-h": true, "one-k": true, "p13": true, "p31": true, "p32": true, "p23": true, "p14": true,
+	"packetoverhead": true, "cellsize": true, "celloverhead": true, "mtu": true,
+	"burst": true, "max-connections": true, "count": true, "abort-status": true, "grpc-status": true,
+}
+
+// Ranges returns flags with the duration, int and float64 flags of chaos
+// parameters accepting a range or choices, keeping their names, usage,
+// default and environment variable; see Range. Other flags are kept.
+func Ranges(flags []cli.Flag) []cli.Flag {
+	out := make([]cli.Flag, len(flags))
+	for i, f := range flags {
+		if name, _, _ := strings.Cut(f.GetName(), ","); !rangeable[strings.TrimSpace(name)] {
+			out[i] = f
+			continue
+		}
+		switch f := f.(type) {
+		case cli.DurationFlag:
+			var def string
+			if f.Value != 0 {
+				def = f.Value.String()
+			}
+			out[i] = rangeFlag(f.Name, f.Usage, f.EnvVar, f.Hidden, kindDuration, def)
+		case cli.IntFlag:
+			var def string
+			if f.Value != 0 {
+				def = strconv.Itoa(f.Value)
+			}
+			out[i] = rangeFlag(f.Name, f.Usage, f.EnvVar, f.Hidden, kindInt, def)
+		case cli.Float64Flag:
+			var def string
+			if f.Value != 0 {
+				def = strconv.FormatFloat(f.Value, 'f', -1, 64)
+			}
+			out[i] = rangeFlag(f.Name, f.Usage, f.EnvVar, f.Hidden, kindFloat, def)
+		default:
+			out[i] = f
+		}
+	}
+	return out
+}
+
+// Choices returns f accepting choices "a|b|c"; see Range.
+func Choices(f cli.StringFlag) cli.Flag {
+	return rangeFlag(f.Name, f.Usage, f.EnvVar, f.Hidden, kindString, f.Value)
+}
+
+func rangeFlag(name, usage, envVar string, hidden bool, kind rangeKind, def string) RangeFlag {
+	return RangeFlag{Name: name, Usage: usage, EnvVar: envVar, Hidden: hidden, Value: def, kind: kind}
+}
+
+// RangeFlag is a cli.Flag holding a Range; see Ranges and Choices. Unlike a
+// cli.GenericFlag it sets a new Range on every Apply, so a flag declared once
+// does not carry the values of one parse into the next.
+type RangeFlag struct {
+	Name   string
+	Usage  string
+	EnvVar string
+	Hidden bool
+	// Value is the default value.
+	Value string
+	kind  rangeKind
+}
+
+// String returns the help text of the flag.
+func (f RangeFlag) String() string {
+	usage := f.Usage
+	switch {
+	case f.kind == kindString && f.Value != "":
+		usage += fmt.Sprintf(" (default: %q)", f.Value)
+	case f.Value != "":
+		usage += fmt.Sprintf(" (default: %s)", f.Value)
+	}
+	return cli.FlagEnvHinter(f.EnvVar, cli.FlagNamePrefixer(f.Name, "value")+"\t"+usage)
+}
+
+// GetName returns the name of the flag.
+func (f RangeFlag) GetName() string {
+	return f.Name
+}
+
+// Apply adds the flag to set.
+func (f RangeFlag) Apply(set *flag.FlagSet) {
+	_ = f.ApplyWithError(set)
+}
+
+// ApplyWithError adds the flag to set, failing on a malformed value of its
+// environment variable.
+func (f RangeFlag) ApplyWithError(set *flag.FlagSet) error {
+	r := &Range{kind: f.kind, raw: f.Value, values: []string{f.Value}}
+	for envVar := range strings.SplitSeq(f.EnvVar, ",") {
+		if v, ok := os.LookupEnv(strings.TrimSpace(envVar)); ok && envVar != "" {
+			if err := r.Set(v); err != nil {
+				return fmt.Errorf("could not parse %s as value for flag %s: %w", v, f.Name, err)
+			}
+			break
+		}
+	}
+	for name := range strings.SplitSeq(f.Name, ",") {
+		set.Var(r, strings.TrimSpace(name), f.Usage)
+	}
+	return nil
+}
+
+// Sampler draws range flag values from a seeded source.
+type Sampler struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewSampler returns a Sampler drawing the same values for the same seed.
+func NewSampler(seed int64) *Sampler {
+	return &Sampler{rng: rand.New(rand.NewSource(seed))} //nolint:gosec
+}
+
+// Draw returns a new draw: range flags read through it read as values drawn
+// anew.
+func (s *Sampler) Draw() *Draw {
+	return &Draw{sampler: s, values: make(map[*Range]string), drawn: make(map[string]string)}
+}
+
+// Bound returns a draw reading every range at its lowest value, or at its
+// highest with upper, to validate the whole range of a flag up front.
+func Bound(upper bool) *Draw {
+	bound := -1
+	if upper {
+		bound = 1
+	}
+	return &Draw{bound: bound, values: make(map[*Range]string), drawn: make(map[string]string)}
+}
+
+// Draw is one draw of range flag values; a flag read twice reads the same
+// value.
+type Draw struct {
+	sampler *Sampler
+	// bound is -1 or 1 for a Bound draw
+	bound  int
+	mu     sync.Mutex
+	values map[*Range]string
+	drawn  map[string]string
+}
+
+func (d *Draw) value(name string, r *Range) string {
+	if !r.Ranged() {
+		return r.raw
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, ok := d.values[r]
+	if !ok {
+		if d.bound != 0 {
+			v = r.extreme(d.bound > 0)
+		} else {
+			d.sampler.mu.Lock()
+			v = r.draw(d.sampler.rng)
+			d.sampler.mu.Unlock()
+		}
+		d.values[r], d.drawn[name] = v, v
+	}
+	return v
+}
+
+// Drawn returns the values drawn so far, by flag name.
+func (d *Draw) Drawn() map[string]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	drawn := make(map[string]string, len(d.drawn))
+	for name, v := range d.drawn {
+		drawn[name] = v
+	}
+	return drawn
+}
+
+// Sample returns f reading range flags, on f, its parents and the globals,
+// through d. Adapters other than V1 are returned as they are.
+func Sample(f Flags, d *Draw) Flags {
+	if v, ok := f.(V1); ok {
+		v.draw = d
+		return v
+	}
+	return f
+}
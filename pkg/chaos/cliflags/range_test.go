@@ -0,0 +1,156 @@
+package cliflags_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func rangeFlags() []cli.Flag {
+	return append(cliflags.Ranges([]cli.Flag{
+		cli.DurationFlag{Name: "duration", Value: time.Minute},
+		cli.IntFlag{Name: "time"},
+		cli.Float64Flag{Name: "percent", Value: 1.5},
+		cli.BoolFlag{Name: "random"},
+	}), cliflags.Choices(cli.StringFlag{Name: "signal", Value: "SIGKILL"}))
+}
+
+func TestRanges_SingleValues(t *testing.T) {
+	flags := rangeFlags()
+	_, ok := flags[3].(cli.BoolFlag)
+	assert.True(t, ok, "other flags are kept")
+
+	f := cliflags.NewV1(newCtx(t, flags, []string{"--time", "100"}))
+	assert.Equal(t, time.Minute, f.Duration("duration"), "defaults are kept")
+	assert.Equal(t, 100, f.Int("time"))
+	assert.InDelta(t, 1.5, f.Float64("percent"), 0)
+	assert.Equal(t, "SIGKILL", f.String("signal"))
+}
+
+func TestRanges_Draw(t *testing.T) {
+	ctx := newCtx(t, rangeFlags(), []string{
+		"--duration", "10s..2m", "--time", "50..500", "--percent", "1..20", "--signal", "SIGTERM|SIGKILL",
+	})
+	sampler := cliflags.NewSampler(1)
+	for range 50 {
+		draw := sampler.Draw()
+		f := cliflags.Sample(cliflags.NewV1(ctx), draw)
+		d := f.Duration("duration")
+		assert.True(t, d >= 10*time.Second && d <= 2*time.Minute, d)
+		assert.Equal(t, d, f.Duration("duration"), "a draw reads the same value twice")
+		n := f.Int("time")
+		assert.True(t, n >= 50 && n <= 500, n)
+		p := f.Float64("percent")
+		assert.True(t, p >= 1 && p <= 20, p)
+		assert.Contains(t, []string{"SIGTERM", "SIGKILL"}, f.String("signal"))
+		assert.Len(t, draw.Drawn(), 4)
+	}
+
+	draws := func() []string {
+		sampler := cliflags.NewSampler(42)
+		var values []string
+		for range 5 {
+			f := cliflags.Sample(cliflags.NewV1(ctx), sampler.Draw())
+			values = append(values, f.Duration("duration").String(), f.String("signal"))
+		}
+		return values
+	}
+	assert.Equal(t, draws(), draws(), "the same seed draws the same values")
+}
+
+func TestRanges_Bound(t *testing.T) {
+	ctx := newCtx(t, rangeFlags(), []string{
+		"--duration", "10s..2m", "--time", "500|50|90", "--signal", "SIGTERM|SIGKILL",
+	})
+	lowest := cliflags.Sample(cliflags.NewV1(ctx), cliflags.Bound(false))
+	assert.Equal(t, 10*time.Second, lowest.Duration("duration"))
+	assert.Equal(t, 50, lowest.Int("time"))
+	assert.Equal(t, "SIGTERM", lowest.String("signal"))
+	assert.InDelta(t, 1.5, lowest.Float64("percent"), 0, "single values are kept")
+	highest := cliflags.Bound(true)
+	f := cliflags.Sample(cliflags.NewV1(ctx), highest)
+	assert.Equal(t, 2*time.Minute, f.Duration("duration"))
+	assert.Equal(t, 500, f.Int("time"))
+	assert.Equal(t, "SIGKILL", f.String("signal"))
+	assert.Len(t, highest.Drawn(), 3)
+}
+
+func TestRanges_NotSampled(t *testing.T) {
+	f := cliflags.NewV1(newCtx(t, rangeFlags(), []string{"--time", "7", "--percent", "1..20"}))
+	assert.Equal(t, 7, f.Int("time"))
+	require.NoError(t, cliflags.Err(f), "a single value needs no draw")
+	assert.InDelta(t, 0, f.Global().Float64("percent"), 0)
+	require.EqualError(t, cliflags.Err(f), "--percent 1..20: a range is not accepted here",
+		"a range read without a draw is an error, not zero")
+}
+
+func TestRanges_ChaosParametersOnly(t *testing.T) {
+	flags := cliflags.Ranges([]cli.Flag{
+		cli.IntFlag{Name: "limit, l"},
+		cli.IntFlag{Name: "capture-size"},
+		cli.IntFlag{Name: "time, t"},
+	})
+	_, ok := flags[0].(cli.IntFlag)
+	assert.True(t, ok, "--limit keeps one value")
+	_, ok = flags[1].(cli.IntFlag)
+	assert.True(t, ok, "--capture-size keeps one value")
+	_, ok = flags[2].(cliflags.RangeFlag)
+	assert.True(t, ok, "--time takes a range")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(nopWriter{})
+	for _, fl := range flags {
+		fl.Apply(fs)
+	}
+	require.Error(t, fs.Parse([]string{"--limit", "1..5"}))
+}
+
+func TestRanges_Parent(t *testing.T) {
+	parent := newCtx(t, cliflags.Ranges([]cli.Flag{cli.DurationFlag{Name: "duration"}}), []string{"--duration", "1s..2s"})
+	child := childCtx(t, parent, []cli.Flag{cli.IntFlag{Name: "limit"}}, nil)
+	draw := cliflags.NewSampler(1).Draw()
+	d := cliflags.Sample(cliflags.NewV1(child), draw).Parent().Duration("duration")
+	assert.True(t, d >= time.Second && d <= 2*time.Second, d)
+	assert.Equal(t, map[string]string{"duration": d.String()}, draw.Drawn())
+}
+
+func TestRanges_Errors(t *testing.T) {
+	tests := map[string][]string{
+		`invalid value "1m..10s" for flag -duration: bad range "1m..10s": min above max`: {"--duration", "1m..10s"},
+		`invalid value "1..x" for flag -time: bad value "x" in "1..x"`:                   {"--time", "1..x"},
+		`invalid value "5|a" for flag -percent: bad value "a" in "5|a"`:                  {"--percent", "5|a"},
+	}
+	for want, args := range tests {
+		t.Run(want, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(nopWriter{})
+			for _, fl := range rangeFlags() {
+				fl.Apply(fs)
+			}
+			require.EqualError(t, fs.Parse(args), want)
+		})
+	}
+}
+
+func TestRangeFlag_Env(t *testing.T) {
+	t.Setenv("TEST_PERCENT", "5|10")
+	flags := cliflags.Ranges([]cli.Flag{cli.Float64Flag{Name: "percent", EnvVar: "TEST_PERCENT"}})
+	f := cliflags.Sample(cliflags.NewV1(newCtx(t, flags, nil)), cliflags.NewSampler(1).Draw())
+	assert.Contains(t, []float64{5, 10}, f.Float64("percent"))
+	assert.Equal(t, "--percent value\t [$TEST_PERCENT]", flags[0].String())
+
+	// a flag declared once starts every parse from its default
+	g := cliflags.NewV1(newCtx(t, rangeFlags(), []string{"--time", "7"}))
+	assert.Equal(t, 7, g.Int("time"))
+	g = cliflags.NewV1(newCtx(t, rangeFlags(), nil))
+	assert.Equal(t, 0, g.Int("time"))
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -1,6 +1,9 @@
 package cliflags
 
 import (
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/urfave/cli"
@@ -12,11 +15,45 @@ import (
 // from the root via GlobalBool).
 type V1 struct {
 	Ctx *cli.Context
+	// draw, when set, draws the values of range flags; see Sample.
+	draw *Draw
+	// undrawn records the range flags read without a draw; see Err.
+	undrawn *undrawn
+}
+
+// undrawn is the first range flag read without a draw.
+type undrawn struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (u *undrawn) add(name string, r *Range) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.err == nil {
+		u.err = fmt.Errorf("--%s %s: a range is not accepted here", name, r.raw)
+	}
+}
+
+// Err returns an error when a range flag was read through f, its parents or
+// the globals without a draw (see Sample): such a read has no value to read
+// and reads as zero. Adapters other than V1 have no range flags.
+func Err(f Flags) error {
+	v, ok := f.(V1)
+	if !ok || v.undrawn == nil {
+		return nil
+	}
+	v.undrawn.mu.Lock()
+	defer v.undrawn.mu.Unlock()
+	return v.undrawn.err
 }
 
 // NewV1 wraps a *cli.Context as Flags. Returning Flags (interface) lets
 // callers swap adapters without touching parser signatures.
-func NewV1(ctx *cli.Context) Flags { return V1{Ctx: ctx} }
+func NewV1(ctx *cli.Context) Flags { return V1{Ctx: ctx, undrawn: &undrawn{}} }
 
 // NewV1FromApp wraps the application-level (root) *cli.Context as Flags.
 // Use it from app.Before / app.After callbacks and any other call site that
@@ -24,10 +61,31 @@ func NewV1(ctx *cli.Context) Flags { return V1{Ctx: ctx} }
 // The constructor walks to the root via Global() so that a subcommand context
 // passed by mistake still yields correct global-flag reads instead of silently
 // returning subcommand-scoped values.
-func NewV1FromApp(ctx *cli.Context) Flags { return V1{Ctx: ctx}.Global() }
+func NewV1FromApp(ctx *cli.Context) Flags { return V1{Ctx: ctx, undrawn: &undrawn{}}.Global() }
+
+// drawn returns the value of the named range flag, drawn through f's Draw.
+// Without a Draw, a range reads as no value and is recorded for Err.
+func (f V1) drawn(name string) (string, bool) {
+	r, ok := f.Ctx.Generic(name).(*Range)
+	switch {
+	case !ok:
+		return "", false
+	case f.draw == nil && r.Ranged():
+		f.undrawn.add(name, r)
+		return "", true
+	case f.draw == nil:
+		return "", false
+	}
+	return f.draw.value(name, r), true
+}
 
 // String returns the value of the named string flag.
-func (f V1) String(name string) string { return f.Ctx.String(name) }
+func (f V1) String(name string) string {
+	if v, ok := f.drawn(name); ok {
+		return v
+	}
+	return f.Ctx.String(name)
+}
 
 // Bool returns the value of the named bool flag (defaults false).
 func (f V1) Bool(name string) bool { return f.Ctx.Bool(name) }
@@ -36,13 +94,31 @@ func (f V1) Bool(name string) bool { return f.Ctx.Bool(name) }
 func (f V1) BoolT(name string) bool { return f.Ctx.BoolT(name) }
 
 // Duration returns the value of the named duration flag.
-func (f V1) Duration(name string) time.Duration { return f.Ctx.Duration(name) }
+func (f V1) Duration(name string) time.Duration {
+	if v, ok := f.drawn(name); ok {
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return f.Ctx.Duration(name)
+}
 
 // Int returns the value of the named int flag.
-func (f V1) Int(name string) int { return f.Ctx.Int(name) }
+func (f V1) Int(name string) int {
+	if v, ok := f.drawn(name); ok {
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return f.Ctx.Int(name)
+}
 
 // Float64 returns the value of the named float64 flag.
-func (f V1) Float64(name string) float64 { return f.Ctx.Float64(name) }
+func (f V1) Float64(name string) float64 {
+	if v, ok := f.drawn(name); ok {
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	}
+	return f.Ctx.Float64(name)
+}
 
 // StringSlice returns the value of the named string-slice flag.
 func (f V1) StringSlice(name string) []string { return f.Ctx.StringSlice(name) }
@@ -56,7 +132,7 @@ func (f V1) Parent() Flags {
 	if p == nil {
 		return nil
 	}
-	return V1{Ctx: p}
+	return V1{Ctx: p, draw: f.draw, undrawn: f.undrawn}
 }
 
 // Global walks up the parent chain and returns the root context's flags.
@@ -66,5 +142,5 @@ func (f V1) Global() Flags {
 	for cur.Parent() != nil {
 		cur = cur.Parent()
 	}
-	return V1{Ctx: cur}
+	return V1{Ctx: cur, draw: f.draw, undrawn: f.undrawn}
 }
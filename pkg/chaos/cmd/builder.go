@@ -11,7 +11,9 @@ import (
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/schedule"
 	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
 	"github.com/urfave/cli"
 )
 
@@ -54,21 +56,57 @@ func NewAction[P any](ctx context.Context, runtime chaos.Runtime, spec Spec[P])
 		Usage:       spec.Usage,
 		ArgsUsage:   spec.ArgsUsage,
 		Description: spec.Description,
-		Flags:       spec.Flags,
+		Flags:       cliflags.Ranges(spec.Flags),
 		Action: func(c *cli.Context) error {
 			if spec.RequireArgs && !c.Args().Present() {
 				return ErrContainerArgRequired
 			}
 			f := cliflags.NewV1(c)
 			gp := chaos.ParseGlobalParams(f)
-			p, err := spec.Parse(f, gp)
+			gp.Command = spec.Name
+			hooks, err := chaos.ParseHooks(f)
 			if err != nil {
 				return err
 			}
-			cmd, err := spec.Build(runtime(), gp, p)
+			if hooks != nil {
+				hooks.Command = spec.Name
+			}
+			gp.Hooks = hooks
+			if gp.Trigger, err = chaos.ParseTrigger(f); err != nil {
+				return err
+			}
+			if gp.Schedule, err = schedule.Parse(f); err != nil {
+				return err
+			}
+			if gp.Rollout, err = chaos.ParseRollout(f); err != nil {
+				return err
+			}
+			b, err := newBuilder(spec, f, gp)
+			if err != nil {
+				return err
+			}
+			if err := cliflags.Err(f); err != nil {
+				return err
+			}
+			client := runtime()
+			b.client = client
+			// a client recording a report learns where every run starts and ends
+			if o, ok := client.(chaos.Observer); ok {
+				gp.Observer = o
+			}
+			if gp.Hooks != nil {
+				gp.Hooks.Client = client
+			}
+			cmd, err := spec.Build(client, gp, b.p)
 			if err != nil {
 				return err
 			}
+			if gp.Trigger != nil && !gp.Plan {
+				return runTriggered(ctx, spec.Name, b, gp)
+			}
+			if b.ranged() {
+				cmd = &resampled[P]{b: b, gp: gp, perContainer: f.Global().Bool("sample-per-container")}
+			}
 			if err := chaos.RunChaosCommand(ctx, cmd, gp); err != nil {
 				return fmt.Errorf("running %s: %w", spec.Name, err)
 			}
@@ -76,3 +114,18 @@ func NewAction[P any](ctx context.Context, runtime chaos.Runtime, spec Spec[P])
 		},
 	}
 }
+
+// runTriggered runs the chaos action on every container triggering it,
+// building the command anew with the container as the sole target. The
+// changes come from the inventory the runtime client is or wraps.
+func runTriggered[P any](ctx context.Context, name string, b *builder[P], gp *chaos.GlobalParams) error {
+	inv, ok := inventory.Find(b.client)
+	if !ok {
+		return errors.New("--trigger: the container inventory is not running")
+	}
+	build := func(gp *chaos.GlobalParams) (chaos.Command, error) { return b.build(ctx, gp) }
+	if err := chaos.RunTriggered(ctx, inv.Subscribe(ctx), build, gp); err != nil {
+		return fmt.Errorf("running %s: %w", name, err)
+	}
+	return nil
+}
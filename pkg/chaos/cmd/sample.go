@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/tracing"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// builder builds the chaos command of a spec. When a flag holds a range (see
+// cliflags.Range), every build parses the flags again with values drawn
+// anew.
+type builder[P any] struct {
+	spec   Spec[P]
+	f      cliflags.Flags
+	client container.Client
+	// p are the params of the first parse; sampler is nil without ranges
+	p       P
+	sampler *cliflags.Sampler
+}
+
+// newBuilder parses the flags of spec, drawing range flag values from a
+// source seeded with --seed, or with the time when it is 0. A range is
+// checked up front at both of its bounds, so no draw fails the checks of the
+// parser (e.g. a --duration range reaching --interval) whatever the seed. The
+// caller sets the client.
+func newBuilder[P any](spec Spec[P], f cliflags.Flags, gp *chaos.GlobalParams) (*builder[P], error) {
+	highest := cliflags.Bound(true)
+	p, err := spec.Parse(cliflags.Sample(f, highest), gp)
+	if len(highest.Drawn()) == 0 {
+		// no ranges: the values are the only ones
+		if err != nil {
+			return nil, err
+		}
+		return &builder[P]{spec: spec, f: f, p: p}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (with ranges at their maximum)", err)
+	}
+	if _, err = spec.Parse(cliflags.Sample(f, cliflags.Bound(false)), gp); err != nil {
+		return nil, fmt.Errorf("%w (with ranges at their minimum)", err)
+	}
+	seed := int64(f.Global().Int("seed"))
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	b := &builder[P]{spec: spec, f: f, sampler: cliflags.NewSampler(seed)}
+	if b.p, err = spec.Parse(cliflags.Sample(f, b.sampler.Draw()), gp); err != nil {
+		return nil, err
+	}
+	log.WithField("seed", seed).Info("drawing chaos parameters from ranges; repeat the draws with --seed")
+	return b, nil
+}
+
+// ranged reports whether any flag holds a range.
+func (b *builder[P]) ranged() bool {
+	return b.sampler != nil
+}
+
+// build builds the command for gp, logging and tracing the values drawn.
+func (b *builder[P]) build(ctx context.Context, gp *chaos.GlobalParams) (chaos.Command, error) {
+	p := b.p
+	if b.ranged() {
+		draw := b.sampler.Draw()
+		var err error
+		if p, err = b.spec.Parse(cliflags.Sample(b.f, draw), gp); err != nil {
+			return nil, err
+		}
+		drawn := draw.Drawn()
+		fields := log.Fields{"command": b.spec.Name}
+		attrs := []attribute.KeyValue{tracing.AttrCommand.String(b.spec.Name)}
+		if len(gp.Names) == 1 {
+			fields["target"] = gp.Names[0]
+			attrs = append(attrs, tracing.AttrContainerID.String(gp.Names[0]))
+		}
+		for _, name := range slices.Sorted(maps.Keys(drawn)) {
+			fields[name] = drawn[name]
+			attrs = append(attrs, attribute.String("pumba.param."+name, drawn[name]))
+		}
+		log.WithFields(fields).Info("drew chaos parameters")
+		tracing.AddEvent(ctx, "pumba.draw", attrs...)
+	}
+	return b.spec.Build(b.client, gp, p)
+}
+
+// resampled is a chaos command drawing the range flag values anew for every
+// run, or with perContainer for every target container of a run.
+type resampled[P any] struct {
+	b            *builder[P]
+	gp           *chaos.GlobalParams
+	perContainer bool
+}
+
+func (r *resampled[P]) Run(ctx context.Context, random bool) error {
+	if !r.perContainer {
+		cmd, err := r.b.build(ctx, r.gp)
+		if err != nil {
+			return err
+		}
+		return cmd.Run(ctx, random)
+	}
+	containers, err := container.ListNContainers(ctx, r.b.client, r.gp.Names, r.gp.Pattern, r.gp.Labels, r.limit())
+	if err != nil {
+		return fmt.Errorf("listing containers: %w", err)
+	}
+	if random && len(containers) > 0 {
+		containers = []*container.Container{container.RandomContainer(containers)}
+	}
+	if len(containers) == 0 {
+		log.Warning("no containers found")
+		return nil
+	}
+	// a command per container, each with its own draw, running in parallel
+	// like the commands run on their targets; the draws are made up front in
+	// name order, so the same seed gives every container the same values
+	// whatever the order the commands are scheduled in
+	slices.SortFunc(containers, func(a, b *container.Container) int {
+		return cmp.Or(cmp.Compare(a.Name(), b.Name()), cmp.Compare(a.ID(), b.ID()))
+	})
+	cmds := make(map[*container.Container]chaos.Command, len(containers))
+	for _, c := range containers {
+		gp := *r.gp
+		gp.Names, gp.Pattern, gp.Labels, gp.Random = []string{c.ID()}, "", nil, false
+		cmd, err := r.b.build(ctx, &gp)
+		if err != nil {
+			return err
+		}
+		cmds[c] = cmd
+	}
+	return chaos.FanOut(ctx, r.gp, containers, true, func(ctx context.Context, c *container.Container) error {
+		return cmds[c].Run(ctx, false)
+	})
+}
+
+// limit returns the --limit of the command, declared on it or on its parent.
+func (r *resampled[P]) limit() int {
+	f := cliflags.Sample(r.b.f, r.b.sampler.Draw())
+	if limit := f.Int("limit"); limit != 0 || f.Parent() == nil {
+		return limit
+	}
+	return f.Parent().Int("limit")
+}
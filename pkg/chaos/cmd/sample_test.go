@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func rangedFlags(t *testing.T, args ...string) cliflags.Flags {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range append(cliflags.Ranges([]cli.Flag{cli.IntFlag{Name: "time"}}), cli.IntFlag{Name: "seed"}) {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cliflags.NewV1(cli.NewContext(cli.NewApp(), fs, nil))
+}
+
+// rangedSpec records the params and targets of every build.
+type rangedSpec struct {
+	mu    sync.Mutex
+	times []int
+	names []string
+}
+
+func (r *rangedSpec) spec() Spec[testParams] {
+	return Spec[testParams]{
+		Name: "test",
+		Parse: func(c cliflags.Flags, gp *chaos.GlobalParams) (testParams, error) {
+			return testParams{Limit: c.Int("time")}, nil
+		},
+		Build: func(client container.Client, gp *chaos.GlobalParams, p testParams) (chaos.Command, error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.times = append(r.times, p.Limit)
+			r.names = append(r.names, gp.Names...)
+			return &fakeChaos{}, nil
+		},
+	}
+}
+
+func TestBuilder_NotRanged(t *testing.T) {
+	b, err := newBuilder((&rangedSpec{}).spec(), rangedFlags(t, "--time", "7"), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.False(t, b.ranged())
+	assert.Equal(t, testParams{Limit: 7}, b.p)
+}
+
+func TestBuilder_RangeCheckedAtBounds(t *testing.T) {
+	spec := (&rangedSpec{}).spec()
+	spec.Parse = func(c cliflags.Flags, gp *chaos.GlobalParams) (testParams, error) {
+		if n := c.Int("time"); n == 0 || n >= 50 {
+			return testParams{}, errors.New("time must be shorter than interval")
+		}
+		return testParams{Limit: c.Int("time")}, nil
+	}
+	for _, seed := range []string{"1", "2", "3", "4", "5", "6"} {
+		_, err := newBuilder(spec, rangedFlags(t, "--time", "1..100", "--seed", seed), &chaos.GlobalParams{})
+		require.EqualError(t, err, "time must be shorter than interval (with ranges at their maximum)", "seed %s", seed)
+	}
+	_, err := newBuilder(spec, rangedFlags(t, "--time", "0|10"), &chaos.GlobalParams{})
+	require.EqualError(t, err, "time must be shorter than interval (with ranges at their minimum)")
+	b, err := newBuilder(spec, rangedFlags(t, "--time", "1..49"), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.True(t, b.ranged())
+}
+
+func TestResampled_PerRun(t *testing.T) {
+	draws := func() []int {
+		r := &rangedSpec{}
+		b, err := newBuilder(r.spec(), rangedFlags(t, "--time", "1..100", "--seed", "3"), &chaos.GlobalParams{})
+		require.NoError(t, err)
+		require.True(t, b.ranged())
+		cmd := &resampled[testParams]{b: b, gp: &chaos.GlobalParams{Names: []string{"c1", "c2"}}}
+		for range 10 {
+			require.NoError(t, cmd.Run(context.Background(), false))
+		}
+		return r.times
+	}
+	times := draws()
+	require.Len(t, times, 10)
+	for _, n := range times {
+		assert.True(t, n >= 1 && n <= 100, n)
+	}
+	assert.NotEqual(t, []int{times[0], times[0], times[0]}, times[:3], "every run draws anew")
+	assert.Equal(t, times, draws(), "the same seed draws the same values")
+}
+
+func TestResampled_PerContainer(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.On("ListContainers", mock.Anything, mock.Anything, mock.Anything).Return([]*container.Container{
+		{ContainerID: "id1", ContainerName: "c1"},
+		{ContainerID: "id2", ContainerName: "c2"},
+	}, nil)
+	r := &rangedSpec{}
+	b, err := newBuilder(r.spec(), rangedFlags(t, "--time", "1..100", "--seed", "3"), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	b.client = client
+	cmd := &resampled[testParams]{b: b, gp: &chaos.GlobalParams{Pattern: "^c"}, perContainer: true}
+	require.NoError(t, cmd.Run(context.Background(), false))
+	assert.ElementsMatch(t, []string{"id1", "id2"}, r.names, "a command per container")
+	assert.Len(t, r.times, 2)
+}
+
+func TestResampled_PerContainerStable(t *testing.T) {
+	var listed []*container.Container
+	for _, name := range []string{"c5", "c3", "c1", "c4", "c2", "c6"} {
+		listed = append(listed, &container.Container{ContainerID: "id-" + name, ContainerName: name})
+	}
+	draws := func(order []*container.Container) map[string]int {
+		client := container.NewMockClient(t)
+		client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return(order, nil)
+		r := &rangedSpec{}
+		b, err := newBuilder(r.spec(), rangedFlags(t, "--time", "1..1000", "--seed", "7"), &chaos.GlobalParams{})
+		require.NoError(t, err)
+		b.client = client
+		cmd := &resampled[testParams]{b: b, gp: &chaos.GlobalParams{Pattern: "^c"}, perContainer: true}
+		require.NoError(t, cmd.Run(context.Background(), false))
+		values := map[string]int{}
+		for i, name := range r.names {
+			values[name] = r.times[i]
+		}
+		return values
+	}
+	want := draws(listed)
+	require.Len(t, want, len(listed))
+	for range 20 {
+		// the parallel fan-out schedules the commands in any order, and the
+		// runtime may list the containers in any order
+		shuffled := slices.Clone(listed)
+		rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		assert.Equal(t, want, draws(shuffled), "the same seed gives every container the same value")
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/schedule"
 	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -27,6 +30,15 @@ type Command interface {
 	Run(ctx context.Context, random bool) error
 }
 
+// Observer is notified around every run of a chaos command, e.g. to report
+// what each run did.
+type Observer interface {
+	RunStarted()
+	// RunEnded receives the error of the run; skipped reports whether it was
+	// skipped with --skip-error.
+	RunEnded(err error, skipped bool)
+}
+
 // GlobalParams global parameters passed through CLI flags
 type GlobalParams struct {
 	Random     bool
@@ -36,6 +48,22 @@ type GlobalParams struct {
 	Interval   time.Duration
 	DryRun     bool
 	SkipErrors bool
+	// Plan runs the command once, in dry-run mode, so the runtime client can
+	// record the changes it would make.
+	Plan bool
+	// Observer, when set, is notified around every run.
+	Observer Observer
+	// Command names the chaos command in traces and hooks.
+	Command string
+	// Hooks, when set, run around every run and per-container action.
+	Hooks *Hooks
+	// Trigger, when set, runs the command on container events instead of on
+	// the interval.
+	Trigger *Trigger
+	// Schedule, when set, decides when runs are due instead of Interval.
+	Schedule *schedule.Schedule
+	// Rollout, when set, paces the actions of a run on its containers.
+	Rollout *Rollout
 }
 
 // splitLabels splits comma-separated label values into individual labels.
@@ -64,9 +92,10 @@ func ParseGlobalParams(c cliflags.Flags) *GlobalParams {
 		Labels:     splitLabels(g.StringSlice("label")),
 		Pattern:    pattern,
 		Names:      names,
-		DryRun:     g.Bool("dry-run"),
+		DryRun:     g.Bool("dry-run") || g.Bool("plan"),
 		SkipErrors: g.Bool("skip-error"),
 		Interval:   g.Duration("interval"),
+		Plan:       g.Bool("plan"),
 	}
 }
 
@@ -98,40 +127,105 @@ func getNamesOrPattern(c cliflags.Flags) ([]string, string) {
 
 // RunChaosCommand run chaos command in go routine
 func RunChaosCommand(topContext context.Context, command Command, params *GlobalParams) error {
-	// create Time channel for specified interval
-	var tick <-chan time.Time
-	if params.Interval == 0 {
-		timer := time.NewTimer(params.Interval)
-		defer timer.Stop()
-		tick = timer.C
-	} else {
-		ticker := time.NewTicker(params.Interval)
-		defer ticker.Stop()
-		tick = ticker.C
+	if params.Plan {
+		// a canceled context ends every wait phase at once, so a single run
+		// goes through both the inject and the cleanup path
+		ctx, cancel := context.WithCancel(topContext)
+		cancel()
+		if err := command.Run(ctx, params.Random); err != nil {
+			return fmt.Errorf("error planning chaos command: %w", err)
+		}
+		return nil
+	}
+	sched := params.Schedule
+	if sched == nil {
+		sched = &schedule.Schedule{Interval: params.Interval}
 	}
-
 	// handle the 'chaos' command
 	ctx, cancel := context.WithCancel(topContext)
 	// cancel current context on exit
 	defer cancel()
+	if sched.Deadline > 0 {
+		ctx, cancel = context.WithTimeout(ctx, sched.Deadline)
+		defer cancel()
+	}
 	// run chaos command
-	for {
-		// run chaos function
-		if err := command.Run(ctx, params.Random); err != nil {
-			if !params.SkipErrors {
-				return fmt.Errorf("error running chaos command: %w", err)
-			}
-			log.WithError(err).Warn("skipping error")
+	due := sched.First(time.Now())
+	for run := 1; ; {
+		if due.IsZero() {
+			log.WithField("cron", sched.Cron).Info("cron expression matches no more runs")
+			return nil
 		}
-		// wait for next timer tick or cancel
-		select {
-		case <-topContext.Done():
+		// wait for the run to be due or cancel
+		if !waitUntil(ctx, due) {
 			return nil // not to leak the goroutine
-		case <-tick:
-			if params.Interval == 0 {
-				return nil // not to leak the goroutine
+		}
+		if w := sched.Blackout(time.Now()); w != nil {
+			log.WithField("blackout", w.String()).Info("skipping chaos run in blackout window")
+		} else {
+			if err := runOnce(ctx, command, params, run, nil); err != nil {
+				return err
+			}
+			if run == sched.MaxRuns {
+				log.WithField("runs", run).Info("reached the maximum number of chaos runs")
+				return nil
 			}
-			log.Debug("next chaos execution (tick) ...")
+			run++
+		}
+		if !sched.Recurring() {
+			return nil
+		}
+		due = sched.Next(due, time.Now())
+		log.WithField("due", due).Debug("next chaos execution (tick) ...")
+	}
+}
+
+// waitUntil waits until t, reporting false when ctx is done first.
+func waitUntil(ctx context.Context, t time.Time) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// runOnce runs command as run number run, traced and surrounded by the
+// observer and the hooks; c is the container that triggered the run, if any.
+// A failed run is returned unless errors are skipped.
+func runOnce(ctx context.Context, command Command, params *GlobalParams, run int, c *container.Container) error {
+	if params.Observer != nil {
+		params.Observer.RunStarted()
+	}
+	attrs := []attribute.KeyValue{
+		tracing.AttrCommand.String(params.Command),
+		tracing.AttrDryRun.Bool(params.DryRun),
+		attribute.Int("pumba.run", run),
+		attribute.Bool("pumba.random", params.Random),
+		attribute.String("pumba.interval", params.Interval.String()),
+	}
+	if c != nil {
+		attrs = append(attrs, attribute.String("pumba.trigger", params.Trigger.Event),
+			tracing.AttrContainerID.String(c.ID()), tracing.AttrContainerName.String(c.Name()))
+	}
+	runCtx, span := tracing.Start(ctx, "chaos.run", attrs...)
+	err := params.Hooks.Run(runCtx, HookEnv{Scope: HookScopeRun, Run: run, Container: c}, func(ctx context.Context) error {
+		return command.Run(ctx, params.Random)
+	})
+	tracing.End(span, err)
+	if params.Observer != nil {
+		params.Observer.RunEnded(err, err != nil && params.SkipErrors)
+	}
+	if err != nil {
+		if !params.SkipErrors {
+			return fmt.Errorf("error running chaos command: %w", err)
 		}
+		log.WithError(err).Warn("skipping error")
 	}
+	return nil
 }
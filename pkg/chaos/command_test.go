@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/schedule"
 	"github.com/alexei-led/pumba/pkg/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -115,6 +117,82 @@ func TestRunChaosCommand_ContextCancel(t *testing.T) {
 	assert.Equal(t, 1, cmd.calls)
 }
 
+// firstWindows blacks out the first n runs due.
+type firstWindows struct {
+	n int
+}
+
+func (w *firstWindows) Contains(time.Time) bool {
+	w.n--
+	return w.n >= 0
+}
+
+func (w *firstWindows) String() string { return "first runs" }
+
+func TestRunChaosCommand_Schedule(t *testing.T) {
+	cmd := &mockCommand{}
+	params := &GlobalParams{Schedule: &schedule.Schedule{Interval: time.Millisecond, MaxRuns: 3,
+		Blackouts: []schedule.Window{&firstWindows{n: 2}}}}
+
+	require.NoError(t, RunChaosCommand(context.Background(), cmd, params))
+	assert.Equal(t, 3, cmd.calls, "runs due in a blackout window do not count")
+}
+
+func TestRunChaosCommand_Deadline(t *testing.T) {
+	cmd := &mockCommand{}
+	params := &GlobalParams{Schedule: &schedule.Schedule{Interval: time.Hour, Deadline: 20 * time.Millisecond}}
+
+	start := time.Now()
+	require.NoError(t, RunChaosCommand(context.Background(), cmd, params))
+	assert.Equal(t, 1, cmd.calls)
+	assert.Less(t, time.Since(start), time.Hour)
+}
+
+// recordingObserver records the runs it is notified of
+type recordingObserver struct {
+	runs []string
+}
+
+func (o *recordingObserver) RunStarted() { o.runs = append(o.runs, "start") }
+
+func (o *recordingObserver) RunEnded(err error, skipped bool) {
+	o.runs = append(o.runs, fmt.Sprintf("end %v %v", err, skipped))
+}
+
+func TestRunChaosCommand_Observer(t *testing.T) {
+	obs := &recordingObserver{}
+	cmd := &mockCommand{err: errors.New("chaos failed")}
+
+	require.NoError(t, RunChaosCommand(context.Background(), cmd, &GlobalParams{SkipErrors: true, Observer: obs}))
+	require.Error(t, RunChaosCommand(context.Background(), cmd, &GlobalParams{Observer: obs}))
+	assert.Equal(t, []string{"start", "end chaos failed true", "start", "end chaos failed false"}, obs.runs)
+}
+
+func TestRunChaosCommand_Plan(t *testing.T) {
+	var canceled bool
+	cmd := &mockCommand{}
+	params := &GlobalParams{Interval: time.Hour, Plan: true, SkipErrors: true}
+
+	err := RunChaosCommand(context.Background(), commandFunc(func(ctx context.Context) error {
+		canceled = ctx.Err() != nil
+		return cmd.Run(ctx, false)
+	}), params)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmd.calls, "plan runs once regardless of the interval")
+	assert.True(t, canceled, "plan runs on a canceled context so wait phases end at once")
+
+	cmd.err = errors.New("no containers")
+	err = RunChaosCommand(context.Background(), cmd, params)
+	require.EqualError(t, err, "error planning chaos command: no containers", "plan does not skip errors")
+}
+
+// commandFunc adapts a function to Command
+type commandFunc func(ctx context.Context) error
+
+func (f commandFunc) Run(ctx context.Context, _ bool) error {
+	return f(ctx)
+}
+
 func TestRuntime_ReturnsInjectedClient(t *testing.T) {
 	want := container.NewMockClient(t)
 
@@ -191,6 +269,7 @@ func TestParseGlobalParams(t *testing.T) {
 	globalFlags := []cli.Flag{
 		cli.BoolFlag{Name: "random"},
 		cli.BoolFlag{Name: "dry-run"},
+		cli.BoolFlag{Name: "plan"},
 		cli.BoolFlag{Name: "skip-error"},
 		cli.DurationFlag{Name: "interval"},
 		cli.StringSliceFlag{Name: "label"},
@@ -225,6 +304,15 @@ func TestParseGlobalParams(t *testing.T) {
 				DryRun: true,
 			},
 		},
+		{
+			name:       "plan implies dry-run",
+			globalArgs: []string{"--plan"},
+			childArgs:  nil,
+			want: &GlobalParams{
+				DryRun: true,
+				Plan:   true,
+			},
+		},
 		{
 			name:       "skip-error and interval",
 			globalArgs: []string{"--skip-error", "--interval", "30s"},
@@ -278,6 +366,7 @@ func TestParseGlobalParams(t *testing.T) {
 			if tt.want.DryRun {
 				assert.True(t, got.DryRun)
 			}
+			assert.Equal(t, tt.want.Plan, got.Plan)
 			if tt.want.SkipErrors {
 				assert.True(t, got.SkipErrors)
 			}
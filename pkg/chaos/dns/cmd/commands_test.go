@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func nilRuntime() chaos.Runtime {
+	return func() container.Client { return nil }
+}
+
+func newTestCLIContext(t *testing.T, flags []cli.Flag, args []string) *cli.Context {
+	t.Helper()
+	app := cli.NewApp()
+	fs := flag.NewFlagSet("dns", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(app, fs, nil)
+}
+
+func TestNewDNSCLICommand_Contract(t *testing.T) {
+	cmd := NewDNSCLICommand(context.Background(), nilRuntime())
+	require.NotNil(t, cmd)
+	assert.Equal(t, "dns", cmd.Name)
+	assert.NotNil(t, cmd.Action)
+}
+
+func TestParseDNSParams(t *testing.T) {
+	cmd := NewDNSCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "30s", "--action", "spoof",
+		"--domain", "example.com", "--domain", "*.internal", "--spoof-ip", "10.1.2.3", "--upstream", "10.0.0.2"})
+	got, err := parseDNSParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "spoof", got.Proxy.Action)
+	assert.Equal(t, []string{"example.com", "*.internal"}, got.Proxy.Domains)
+	assert.True(t, net.ParseIP("10.1.2.3").Equal(got.Proxy.SpoofIP))
+	assert.Equal(t, "10.0.0.2", got.Proxy.Upstream)
+	assert.Equal(t, 2*time.Second, got.Proxy.Delay)
+	assert.Equal(t, 30*time.Second, got.Duration)
+	assert.Equal(t, "ghcr.io/alexei-led/pumba:latest", got.Image)
+	assert.Equal(t, "ghcr.io/alexei-led/pumba-alpine-nettools:latest", got.IPTablesImage)
+	assert.True(t, got.Pull, "BoolT pull-image defaults true")
+}
+
+func TestParseDNSParams_InvalidSpoofIP(t *testing.T) {
+	cmd := NewDNSCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "30s", "--action", "spoof", "--spoof-ip", "nope"})
+	_, err := parseDNSParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	assert.ErrorContains(t, err, "invalid spoof IP address")
+}
+
+func TestBuildDNSCommand(t *testing.T) {
+	cmd := NewDNSCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "10s"})
+	p, err := parseDNSParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	built, err := buildDNSCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	require.NotNil(t, built)
+
+	c = newTestCLIContext(t, cmd.Flags, nil)
+	p, err = parseDNSParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	_, err = buildDNSCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	assert.ErrorContains(t, err, "duration")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/dns"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/dnsproxy"
+	"github.com/urfave/cli"
+)
+
+// NewDNSCLICommand initialize CLI dns command.
+func NewDNSCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[dns.Params]{
+		Name: "dns",
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "duration, d",
+				Usage: "dns chaos duration; should be smaller than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
+			},
+			cli.StringFlag{
+				Name:  "action, a",
+				Usage: "fault injected into matching queries: drop, delay, nxdomain, servfail or spoof",
+				Value: dnsproxy.ActionNXDomain,
+			},
+			cli.StringSliceFlag{
+				Name:  "domain",
+				Usage: "domain pattern to inject faults for; matches the domain and its subdomains, supports * and ? wildcards; supports multiple patterns (default: all domains)",
+			},
+			cli.DurationFlag{
+				Name:  "delay",
+				Usage: "time to hold matching queries before forwarding them, works only with delay action",
+				Value: 2 * time.Second, //nolint:mnd
+			},
+			cli.StringFlag{
+				Name:  "spoof-ip",
+				Usage: "IPv4 or IPv6 address returned for matching A or AAAA queries, works only with spoof action",
+			},
+			cli.StringFlag{
+				Name:  "upstream",
+				Usage: "upstream resolver address (host[:port]) for forwarded queries (default: first nameserver in the target's /etc/resolv.conf)",
+			},
+			cli.StringFlag{
+				Name:  "dns-image",
+				Usage: "Docker image running the DNS proxy (pumba dns-proxy)",
+				Value: "ghcr.io/alexei-led/pumba:latest",
+			},
+			cli.StringFlag{
+				Name:  "iptables-image",
+				Usage: "Docker image with iptables, used to redirect DNS traffic to the proxy",
+				Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+			},
+			cli.BoolTFlag{
+				Name:  "pull-image",
+				Usage: "force pull dns-image and iptables-image",
+			},
+			cli.IntFlag{
+				Name:  "limit",
+				Usage: "limit number of matching containers (0: target all)",
+				Value: 0,
+			},
+		},
+		Usage:       "inject DNS faults: drop, delay, fail or spoof name resolution",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "redirects the container's DNS traffic to a proxy sidecar that drops, delays, answers with NXDOMAIN/SERVFAIL or spoofs the address of queries matching the domain patterns, for the given duration",
+		Parse:       parseDNSParams,
+		Build:       buildDNSCommand,
+	})
+}
+
+func parseDNSParams(c cliflags.Flags, _ *chaos.GlobalParams) (dns.Params, error) {
+	var spoofIP net.IP
+	if s := c.String("spoof-ip"); s != "" {
+		if spoofIP = net.ParseIP(s); spoofIP == nil {
+			return dns.Params{}, fmt.Errorf("invalid spoof IP address %q", s)
+		}
+	}
+	return dns.Params{
+		Proxy: dnsproxy.Config{
+			Action:   c.String("action"),
+			Domains:  c.StringSlice("domain"),
+			Delay:    c.Duration("delay"),
+			SpoofIP:  spoofIP,
+			Upstream: c.String("upstream"),
+		},
+		Duration:      c.Duration("duration"),
+		Image:         c.String("dns-image"),
+		IPTablesImage: c.String("iptables-image"),
+		Pull:          c.BoolT("pull-image"),
+		Limit:         c.Int("limit"),
+	}, nil
+}
+
+func buildDNSCommand(client container.Client, gp *chaos.GlobalParams, p dns.Params) (chaos.Command, error) {
+	return dns.NewDNSCommand(client, gp, &p)
+}
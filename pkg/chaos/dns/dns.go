@@ -0,0 +1,133 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/dnsproxy"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// proxyName names the proxy kind in the sidecar name and in log messages.
+	proxyName = "dns"
+	// proxyBinary is the pumba binary path inside the proxy image.
+	proxyBinary = "/pumba"
+	// ProxyCommand is the hidden pumba command running the DNS proxy.
+	ProxyCommand = "dns-proxy"
+)
+
+// dnsClient is the narrow interface needed by the dns command.
+type dnsClient interface {
+	container.Lister
+	container.Proxy
+}
+
+// Params holds the parsed parameters of the dns command.
+type Params struct {
+	// Proxy describes the injected fault; Listen is set by the command.
+	Proxy         dnsproxy.Config
+	Duration      time.Duration
+	Image         string
+	IPTablesImage string
+	Pull          bool
+	Limit         int
+}
+
+// `dns` command
+type dnsCommand struct {
+	client dnsClient
+	gp     *chaos.GlobalParams
+	req    *container.ProxyRequest
+	p      *Params
+}
+
+// NewDNSCommand create new dns chaos command
+func NewDNSCommand(client dnsClient, gp *chaos.GlobalParams, p *Params) (chaos.Command, error) {
+	if p.Duration <= 0 {
+		return nil, errors.New("unset or invalid duration value")
+	}
+	if gp.Interval != 0 && p.Duration >= gp.Interval {
+		return nil, errors.New("duration must be shorter than interval")
+	}
+	if err := p.Proxy.Validate(); err != nil {
+		return nil, err
+	}
+	return &dnsCommand{
+		client: client,
+		gp:     gp,
+		p:      p,
+		req: &container.ProxyRequest{
+			Name:      proxyName,
+			Command:   proxyArgs(&p.Proxy),
+			Redirects: redirects(),
+			Proxy:     container.SidecarSpec{Image: p.Image, Pull: p.Pull},
+			Sidecar:   container.SidecarSpec{Image: p.IPTablesImage, Pull: p.Pull},
+			DryRun:    gp.DryRun,
+		},
+	}, nil
+}
+
+// proxyArgs builds the argv of the proxy sidecar.
+func proxyArgs(cfg *dnsproxy.Config) []string {
+	args := []string{proxyBinary, ProxyCommand,
+		"--listen", ":" + strconv.Itoa(dnsproxy.DefaultPort),
+		"--action", cfg.Action,
+	}
+	for _, d := range cfg.Domains {
+		args = append(args, "--domain", d)
+	}
+	if cfg.Action == dnsproxy.ActionDelay {
+		args = append(args, "--delay", cfg.Delay.String())
+	}
+	if cfg.Action == dnsproxy.ActionSpoof {
+		args = append(args, "--spoof-ip", cfg.SpoofIP.String())
+	}
+	if cfg.Upstream != "" {
+		args = append(args, "--upstream", cfg.Upstream)
+	}
+	return args
+}
+
+// redirects sends the target's outgoing DNS traffic (UDP and TCP port 53) to
+// the proxy. Traffic of the proxy user is excluded so forwarded queries reach
+// the real resolver instead of looping back.
+func redirects() [][]string {
+	rules := make([][]string, 0, 2) //nolint:mnd
+	for _, proto := range []string{"udp", "tcp"} {
+		rules = append(rules, []string{"OUTPUT", "-p", proto, "--dport", "53",
+			"-m", "owner", "!", "--uid-owner", container.ProxyUser,
+			"-j", "REDIRECT", "--to-ports", strconv.Itoa(dnsproxy.DefaultPort)})
+	}
+	return rules
+}
+
+// Run dns command
+func (n *dnsCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("injecting dns faults into all matching containers")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.p.Limit,
+		"random":  random,
+	}).Debug("listing matching containers")
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.p.Limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			log.WithFields(log.Fields{"container": *c, "action": n.p.Proxy.Action}).Debug("injecting dns faults for container")
+			dnsCtx, cancel := context.WithTimeout(ctx, n.p.Duration)
+			defer cancel()
+			req := *n.req
+			req.Container = c
+			if err := chaos.RunProxy(dnsCtx, n.client, &req, n.p.Duration); err != nil {
+				log.WithError(err).Warn("failed to inject dns faults for container")
+				return fmt.Errorf("failed to inject dns faults for one or more containers: %w", err)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/dnsproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDNSCommand_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		gp      chaos.GlobalParams
+		p       Params
+		wantErr string
+	}{
+		{"valid", chaos.GlobalParams{}, Params{Duration: time.Second, Proxy: dnsproxy.Config{Action: dnsproxy.ActionNXDomain}}, ""},
+		{"no duration", chaos.GlobalParams{}, Params{Proxy: dnsproxy.Config{Action: dnsproxy.ActionNXDomain}}, "invalid duration"},
+		{"duration over interval", chaos.GlobalParams{Interval: time.Second}, Params{Duration: time.Second, Proxy: dnsproxy.Config{Action: dnsproxy.ActionNXDomain}}, "shorter than interval"},
+		{"bad action", chaos.GlobalParams{}, Params{Duration: time.Second, Proxy: dnsproxy.Config{Action: "refuse"}}, "invalid dns action"},
+		{"spoof without ip", chaos.GlobalParams{}, Params{Duration: time.Second, Proxy: dnsproxy.Config{Action: dnsproxy.ActionSpoof}}, "spoof IP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewDNSCommand(container.NewMockClient(t), &tt.gp, &tt.p)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, cmd)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxyArgs(t *testing.T) {
+	assert.Equal(t, []string{"/pumba", "dns-proxy", "--listen", ":15353", "--action", "delay",
+		"--domain", "example.com", "--domain", "*.internal", "--delay", "2s", "--upstream", "10.0.0.2:53"},
+		proxyArgs(&dnsproxy.Config{Action: dnsproxy.ActionDelay, Domains: []string{"example.com", "*.internal"}, Delay: 2 * time.Second, Upstream: "10.0.0.2:53"}))
+	assert.Equal(t, []string{"/pumba", "dns-proxy", "--listen", ":15353", "--action", "spoof", "--spoof-ip", "10.1.2.3"},
+		proxyArgs(&dnsproxy.Config{Action: dnsproxy.ActionSpoof, SpoofIP: net.ParseIP("10.1.2.3")}))
+}
+
+func TestRedirects(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"OUTPUT", "-p", "udp", "--dport", "53", "-m", "owner", "!", "--uid-owner", "65534", "-j", "REDIRECT", "--to-ports", "15353"},
+		{"OUTPUT", "-p", "tcp", "--dport", "53", "-m", "owner", "!", "--uid-owner", "65534", "-j", "REDIRECT", "--to-ports", "15353"},
+	}, redirects())
+}
+
+func TestDNSCommand_Run(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	gp := &chaos.GlobalParams{Names: []string{"c1"}}
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return([]*container.Container{c}, nil)
+	matchReq := mock.MatchedBy(func(r *container.ProxyRequest) bool {
+		return r.Container == c && r.Name == "dns" && r.Proxy.Image == "pumba:test" && r.Sidecar.Image == "nettools:test"
+	})
+	mockClient.EXPECT().ProxyContainer(mock.Anything, matchReq).Return(nil).Once()
+	mockClient.EXPECT().StopProxyContainer(mock.Anything, matchReq).Return(nil).Once()
+
+	cmd, err := NewDNSCommand(mockClient, gp, &Params{
+		Proxy:         dnsproxy.Config{Action: dnsproxy.ActionServFail},
+		Duration:      10 * time.Millisecond,
+		Image:         "pumba:test",
+		IPTablesImage: "nettools:test",
+	})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
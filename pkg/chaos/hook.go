@@ -0,0 +1,222 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExecHookPrefix marks a hook exec'd in a container: exec:<container>:<command>.
+const ExecHookPrefix = "exec:"
+
+// Hook phases and scopes, passed to hooks as PUMBA_HOOK and PUMBA_SCOPE.
+const (
+	HookBefore = "before"
+	HookAfter  = "after"
+	// HookScopeRun hooks run around every run, once per --interval tick.
+	HookScopeRun = "run"
+	// HookScopeContainer hooks run around the action on every target container.
+	HookScopeContainer = "container"
+)
+
+// HookClient execs hooks in containers.
+type HookClient interface {
+	container.Lister
+	container.Executor
+}
+
+// Hook is a user command run before or after chaos.
+type Hook struct {
+	// Container names the running container the command is exec'd in; when
+	// empty the command runs locally.
+	Container string
+	// Command is run by sh -c.
+	Command string
+}
+
+// ParseHook parses a hook: a local shell command, or exec:<container>:<command>
+// for a command exec'd in a container. An empty s is no hook.
+func ParseHook(s string) (*Hook, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+	rest, found := strings.CutPrefix(s, ExecHookPrefix)
+	if !found {
+		return &Hook{Command: s}, nil
+	}
+	name, command, ok := strings.Cut(rest, ":")
+	if !ok || name == "" || command == "" {
+		return nil, fmt.Errorf("bad hook %q: expected %s<container>:<command>", s, ExecHookPrefix)
+	}
+	return &Hook{Container: name, Command: command}, nil
+}
+
+// Hooks are the user commands run around every run and every per-container
+// action of a chaos command.
+type Hooks struct {
+	Before *Hook
+	After  *Hook
+	// Abort makes a failed hook fail the run or action: a failed before hook
+	// skips the injection. Otherwise hook failures are only logged.
+	Abort bool
+	// Command is the chaos command the hooks run around.
+	Command string
+	// DryRun skips local hooks and execs container hooks in dry-run mode.
+	DryRun bool
+	// Client execs container hooks; set once the runtime client is resolved.
+	Client HookClient
+}
+
+// ParseHooks parses the --before-hook, --after-hook and --hook-abort flags,
+// returning nil when no hook is set.
+func ParseHooks(c cliflags.Flags) (*Hooks, error) {
+	g := c.Global()
+	before, err := ParseHook(g.String("before-hook"))
+	if err != nil {
+		return nil, err
+	}
+	after, err := ParseHook(g.String("after-hook"))
+	if err != nil {
+		return nil, err
+	}
+	if before == nil && after == nil {
+		return nil, nil //nolint:nilnil
+	}
+	return &Hooks{Before: before, After: after, Abort: g.Bool("hook-abort"), DryRun: g.Bool("dry-run") || g.Bool("plan")}, nil
+}
+
+// HookEnv describes what a hook runs around, passed to it as PUMBA_*
+// environment variables. Run fills in the phase, command and dry-run mode.
+type HookEnv struct {
+	Phase   string
+	Scope   string
+	Command string
+	DryRun  bool
+	// Run is the number of the run, for run hooks.
+	Run int
+	// Container is the target container, for container hooks, or the
+	// container that triggered the run.
+	Container *container.Container
+	// Err is the error of the run or action, for after hooks.
+	Err error
+}
+
+// Vars returns the environment variables of e.
+func (e HookEnv) Vars() []string {
+	vars := []string{
+		"PUMBA_HOOK=" + e.Phase,
+		"PUMBA_SCOPE=" + e.Scope,
+		"PUMBA_ACTION=" + e.Command,
+		"PUMBA_DRY_RUN=" + strconv.FormatBool(e.DryRun),
+	}
+	if e.Run > 0 {
+		vars = append(vars, "PUMBA_RUN="+strconv.Itoa(e.Run))
+	}
+	if e.Container != nil {
+		vars = append(vars,
+			"PUMBA_CONTAINER_ID="+e.Container.ID(),
+			"PUMBA_CONTAINER_NAME="+strings.TrimPrefix(e.Container.Name(), "/"),
+		)
+	}
+	if e.Err != nil {
+		vars = append(vars, "PUMBA_ERROR="+e.Err.Error())
+	}
+	return vars
+}
+
+// Run runs fn between the before and after hooks, described by env. A failed
+// before hook, with Abort, skips fn. Without hooks it just runs fn.
+func (h *Hooks) Run(ctx context.Context, env HookEnv, fn func(context.Context) error) error {
+	if h == nil {
+		return fn(ctx)
+	}
+	env.Command, env.DryRun = h.Command, h.DryRun
+	env.Phase = HookBefore
+	if err := h.run(ctx, env); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	env.Phase, env.Err = HookAfter, err
+	if hookErr := h.run(ctx, env); hookErr != nil {
+		return errors.Join(err, hookErr)
+	}
+	return err
+}
+
+// run runs the hook of env.Phase, if any. A failure is returned with Abort
+// and logged otherwise.
+func (h *Hooks) run(ctx context.Context, env HookEnv) error {
+	hook := h.Before
+	if env.Phase == HookAfter {
+		hook = h.After
+	}
+	if hook == nil {
+		return nil
+	}
+	logger := log.WithContext(ctx).WithFields(log.Fields{
+		"hook":    env.Phase,
+		"scope":   env.Scope,
+		"command": hook.Command,
+		"dryrun":  env.DryRun,
+	})
+	if env.Container != nil {
+		logger = logger.WithField("target", env.Container.Name())
+	}
+	err := h.exec(ctx, hook, env)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("%s hook failed: %w", env.Phase, err)
+	if h.Abort {
+		return err
+	}
+	logger.WithError(err).Warn("ignoring hook failure")
+	return nil
+}
+
+func (h *Hooks) exec(ctx context.Context, hook *Hook, env HookEnv) error {
+	if hook.Container != "" {
+		if h.Client == nil {
+			return errors.New("no runtime client to exec the hook")
+		}
+		containers, err := container.ListNContainers(ctx, h.Client, []string{hook.Container}, "", nil, 1)
+		if err != nil {
+			return fmt.Errorf("listing hook container %s: %w", hook.Container, err)
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("hook container %s not running", hook.Container)
+		}
+		args := append(env.Vars(), "sh", "-c", hook.Command)
+		result, err := h.Client.ExecContainer(ctx, containers[0], "env", args, env.DryRun)
+		if err != nil {
+			return err
+		}
+		return result.Err(hook.Command)
+	}
+	if env.DryRun {
+		log.WithContext(ctx).WithField("command", hook.Command).Info("dry run: skipping local hook")
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Env = append(os.Environ(), env.Vars()...)
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err := cmd.Run()
+	log.WithContext(ctx).WithField("command", hook.Command).WithField("output", strings.TrimSpace(out.String())).Debug("hook ran")
+	if err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHook(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *Hook
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{in: "curl -XPOST http://grafana/annotate", want: &Hook{Command: "curl -XPOST http://grafana/annotate"}},
+		{in: "exec:api:redis-cli flushall", want: &Hook{Container: "api", Command: "redis-cli flushall"}},
+		{in: "exec:api:echo a:b", want: &Hook{Container: "api", Command: "echo a:b"}},
+		{in: "exec:api", wantErr: `bad hook "exec:api": expected exec:<container>:<command>`},
+		{in: "exec::ls", wantErr: `bad hook "exec::ls": expected exec:<container>:<command>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHook(tt.in)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// hookLog returns a hook command appending its environment to a file, and a
+// func reading the lines back.
+func hookLog(t *testing.T) (string, func() []string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hooks.log")
+	cmd := `echo "$PUMBA_HOOK $PUMBA_SCOPE $PUMBA_ACTION run=$PUMBA_RUN c=$PUMBA_CONTAINER_NAME err=$PUMBA_ERROR" >> ` + path
+	return cmd, func() []string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+func TestRunChaosCommand_Hooks(t *testing.T) {
+	cmd, lines := hookLog(t)
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).
+		Return([]*container.Container{{ContainerID: "w1", ContainerName: "/web"}}, nil).Once()
+	gp := &GlobalParams{Names: []string{"web"}, Hooks: &Hooks{Before: &Hook{Command: cmd}, After: &Hook{Command: cmd}, Command: "pause"}}
+
+	err := RunChaosCommand(context.TODO(), commandFunc(func(ctx context.Context) error {
+		return RunOnContainers(ctx, client, gp, 0, false, false, func(context.Context, *container.Container) error {
+			return errors.New("conflict")
+		})
+	}), gp)
+	require.EqualError(t, err, "error running chaos command: conflict")
+	assert.Equal(t, []string{
+		"before run pause run=1 c= err=",
+		"before container pause run= c=web err=",
+		"after container pause run= c=web err=conflict",
+		"after run pause run=1 c= err=conflict",
+	}, lines())
+}
+
+func TestHooksRun_Abort(t *testing.T) {
+	var ran bool
+	fn := func(context.Context) error { ran = true; return nil }
+	hooks := &Hooks{Before: &Hook{Command: "echo not ready >&2; exit 3"}}
+
+	require.NoError(t, hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, fn))
+	assert.True(t, ran, "without abort a failed hook is only logged")
+
+	ran, hooks.Abort = false, true
+	err := hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, fn)
+	require.EqualError(t, err, "before hook failed: exit status 3: not ready")
+	assert.False(t, ran, "a failed before hook aborts the injection")
+
+	hooks.Before, hooks.After = nil, &Hook{Command: "exit 1"}
+	err = hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, fn)
+	require.EqualError(t, err, "after hook failed: exit status 1")
+	assert.True(t, ran)
+}
+
+func TestHooksRun_DryRunSkipsLocalHooks(t *testing.T) {
+	hooks := &Hooks{Before: &Hook{Command: "exit 1"}, Abort: true, DryRun: true}
+	require.NoError(t, hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, func(context.Context) error { return nil }))
+}
+
+func TestHooksRun_ExecHook(t *testing.T) {
+	grafana := &container.Container{ContainerID: "g1", ContainerName: "grafana"}
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*container.Container{grafana}, nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, grafana, "env", []string{
+		"PUMBA_HOOK=before", "PUMBA_SCOPE=container", "PUMBA_ACTION=kill", "PUMBA_DRY_RUN=false",
+		"PUMBA_CONTAINER_ID=w1", "PUMBA_CONTAINER_NAME=web",
+		"sh", "-c", "annotate",
+	}, false).Return(&container.ExecResult{}, nil).Once()
+
+	hooks := &Hooks{Before: &Hook{Container: "grafana", Command: "annotate"}, Command: "kill", Client: client}
+	require.NoError(t, hooks.Run(context.TODO(), HookEnv{Scope: HookScopeContainer, Container: web}, func(context.Context) error { return nil }))
+}
+
+func TestHooksRun_ExecHookContainerMissing(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+
+	hooks := &Hooks{Before: &Hook{Container: "grafana", Command: "annotate"}, Abort: true, Client: client}
+	err := hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, func(context.Context) error { return nil })
+	require.EqualError(t, err, "before hook failed: hook container grafana not running")
+}
+
+func TestHooksRun_NoHooks(t *testing.T) {
+	var hooks *Hooks
+	err := hooks.Run(context.TODO(), HookEnv{}, func(context.Context) error { return errors.New("boom") })
+	require.EqualError(t, err, "boom")
+}
+
+func TestHooksRun_ExecHookExitCode(t *testing.T) {
+	grafana := &container.Container{ContainerID: "g1", ContainerName: "grafana"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return([]*container.Container{grafana}, nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, grafana, "env", mock.Anything, false).
+		Return(&container.ExecResult{Stderr: "annotate: not found\n", ExitCode: 127}, nil).Once()
+
+	hooks := &Hooks{Before: &Hook{Container: "grafana", Command: "annotate"}, Abort: true, Client: client}
+	err := hooks.Run(context.TODO(), HookEnv{Scope: HookScopeRun}, func(context.Context) error { return nil })
+	require.EqualError(t, err, "before hook failed: annotate exited with code 127: annotate: not found")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func nilRuntime() chaos.Runtime {
+	return func() container.Client { return nil }
+}
+
+func newTestCLIContext(t *testing.T, flags []cli.Flag, args []string) *cli.Context {
+	t.Helper()
+	app := cli.NewApp()
+	fs := flag.NewFlagSet("http", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(app, fs, nil)
+}
+
+func TestNewHTTPCLICommand_Contract(t *testing.T) {
+	cmd := NewHTTPCLICommand(context.Background(), nilRuntime())
+	require.NotNil(t, cmd)
+	assert.Equal(t, "http", cmd.Name)
+	assert.NotNil(t, cmd.Action)
+}
+
+func TestParseHTTPParams(t *testing.T) {
+	cmd := NewHTTPCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "1m", "--port", "8080", "--path", "/checkout",
+		"--method", "POST", "--header", "x-canary=true", "--percent", "25", "--delay", "2s", "--abort-status", "503", "--grpc-status", "14"})
+	got, err := parseHTTPParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, 8080, got.Port)
+	assert.Equal(t, time.Minute, got.Duration)
+	assert.Equal(t, "/checkout", got.Proxy.Path)
+	assert.Equal(t, "POST", got.Proxy.Method)
+	assert.Equal(t, map[string]string{"X-Canary": "true"}, got.Proxy.Headers)
+	assert.InDelta(t, 25.0, got.Proxy.Percent, 0)
+	assert.Equal(t, 2*time.Second, got.Proxy.Delay)
+	assert.Equal(t, 503, got.Proxy.AbortStatus)
+	assert.Equal(t, 14, got.Proxy.GRPCStatus)
+	assert.Equal(t, "ghcr.io/alexei-led/pumba:latest", got.Image)
+	assert.True(t, got.Pull, "BoolT pull-image defaults true")
+}
+
+func TestParseHTTPParams_InvalidHeader(t *testing.T) {
+	cmd := NewHTTPCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "1m", "--port", "8080", "--header", "bad"})
+	_, err := parseHTTPParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	assert.ErrorContains(t, err, "invalid header matcher")
+}
+
+func TestBuildHTTPCommand(t *testing.T) {
+	cmd := NewHTTPCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--duration", "10s", "--port", "80", "--abort-status", "500"})
+	p, err := parseHTTPParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	built, err := buildHTTPCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	require.NotNil(t, built)
+
+	c = newTestCLIContext(t, cmd.Flags, []string{"--duration", "10s", "--port", "80"})
+	p, err = parseHTTPParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	_, err = buildHTTPCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	assert.ErrorContains(t, err, "no http fault configured")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/httpfault"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/httpproxy"
+	"github.com/urfave/cli"
+)
+
+// NewHTTPCLICommand initialize CLI http command.
+func NewHTTPCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[httpfault.Params]{
+		Name: "http",
+		Flags: []cli.Flag{
+			cli.DurationFlag{
+				Name:  "duration, d",
+				Usage: "http chaos duration; should be smaller than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
+			},
+			cli.IntFlag{
+				Name:  "port",
+				Usage: "container port serving HTTP or gRPC; incoming connections to it are redirected through the proxy",
+			},
+			cli.StringFlag{
+				Name:  "path",
+				Usage: "URL path prefix, or pattern with * and ? wildcards, of requests to inject faults into (default: all paths)",
+			},
+			cli.StringFlag{
+				Name:  "method",
+				Usage: "HTTP method of requests to inject faults into (default: all methods)",
+			},
+			cli.StringSliceFlag{
+				Name:  "header",
+				Usage: "inject faults only into requests carrying header <name>=<value>; supports multiple headers (all must match)",
+			},
+			cli.Float64Flag{
+				Name:  "percent",
+				Usage: "percentage of matching requests to inject faults into (0-100]",
+				Value: 100, //nolint:mnd
+			},
+			cli.DurationFlag{
+				Name:  "delay",
+				Usage: "delay matching requests before proxying or aborting them; use with optional unit suffix: 'ms/s/m/h'",
+			},
+			cli.IntFlag{
+				Name:  "abort-status",
+				Usage: "abort matching HTTP requests with this HTTP status code (e.g. 503)",
+			},
+			cli.IntFlag{
+				Name:  "grpc-status",
+				Usage: "abort matching gRPC calls with this gRPC status code (e.g. 14 for UNAVAILABLE)",
+			},
+			cli.StringFlag{
+				Name:  "http-image",
+				Usage: "Docker image running the HTTP proxy (pumba http-proxy)",
+				Value: "ghcr.io/alexei-led/pumba:latest",
+			},
+			cli.StringFlag{
+				Name:  "iptables-image",
+				Usage: "Docker image with iptables, used to redirect the port to the proxy",
+				Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+			},
+			cli.BoolTFlag{
+				Name:  "pull-image",
+				Usage: "force pull http-image and iptables-image",
+			},
+			cli.IntFlag{
+				Name:  "limit",
+				Usage: "limit number of matching containers (0: target all)",
+				Value: 0,
+			},
+		},
+		Usage:       "inject HTTP/gRPC faults: delays and aborts by path, method, header or percentage",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "redirects incoming connections to a container port through a transparent proxy sidecar that delays and/or aborts matching HTTP requests and gRPC calls, for the given duration",
+		Parse:       parseHTTPParams,
+		Build:       buildHTTPCommand,
+	})
+}
+
+func parseHTTPParams(c cliflags.Flags, _ *chaos.GlobalParams) (httpfault.Params, error) {
+	headers, err := httpproxy.ParseHeaders(c.StringSlice("header"))
+	if err != nil {
+		return httpfault.Params{}, err
+	}
+	return httpfault.Params{
+		Proxy: httpproxy.Config{
+			Path:        c.String("path"),
+			Method:      c.String("method"),
+			Headers:     headers,
+			Percent:     c.Float64("percent"),
+			Delay:       c.Duration("delay"),
+			AbortStatus: c.Int("abort-status"),
+			GRPCStatus:  c.Int("grpc-status"),
+		},
+		Port:          c.Int("port"),
+		Duration:      c.Duration("duration"),
+		Image:         c.String("http-image"),
+		IPTablesImage: c.String("iptables-image"),
+		Pull:          c.BoolT("pull-image"),
+		Limit:         c.Int("limit"),
+	}, nil
+}
+
+func buildHTTPCommand(client container.Client, gp *chaos.GlobalParams, p httpfault.Params) (chaos.Command, error) {
+	return httpfault.NewHTTPCommand(client, gp, &p)
+}
@@ -0,0 +1,148 @@
+// Package httpfault implements the `http` chaos command: application-level
+// fault injection through a transparent proxy sidecar (see pkg/httpproxy).
+package httpfault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/httpproxy"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// proxyName names the proxy kind in the sidecar name and in log messages.
+	proxyName = "http"
+	// proxyBinary is the pumba binary path inside the proxy image.
+	proxyBinary = "/pumba"
+	// ProxyCommand is the hidden pumba command running the HTTP proxy.
+	ProxyCommand = "http-proxy"
+
+	maxPort = 65535
+)
+
+// httpClient is the narrow interface needed by the http command.
+type httpClient interface {
+	container.Lister
+	container.Proxy
+}
+
+// Params holds the parsed parameters of the http command.
+type Params struct {
+	// Proxy describes the request rule and the injected fault; Listen and
+	// Upstream are set by the command.
+	Proxy         httpproxy.Config
+	Port          int
+	Duration      time.Duration
+	Image         string
+	IPTablesImage string
+	Pull          bool
+	Limit         int
+}
+
+// `http` command
+type httpCommand struct {
+	client httpClient
+	gp     *chaos.GlobalParams
+	req    *container.ProxyRequest
+	p      *Params
+}
+
+// NewHTTPCommand create new http chaos command
+func NewHTTPCommand(client httpClient, gp *chaos.GlobalParams, p *Params) (chaos.Command, error) {
+	if p.Duration <= 0 {
+		return nil, errors.New("unset or invalid duration value")
+	}
+	if gp.Interval != 0 && p.Duration >= gp.Interval {
+		return nil, errors.New("duration must be shorter than interval")
+	}
+	if p.Port <= 0 || p.Port > maxPort {
+		return nil, fmt.Errorf("invalid http port %d: must be between 1 and %d", p.Port, maxPort)
+	}
+	if p.Port == httpproxy.DefaultPort {
+		return nil, fmt.Errorf("invalid http port %d: reserved for the proxy", p.Port)
+	}
+	if err := p.Proxy.Validate(); err != nil {
+		return nil, err
+	}
+	return &httpCommand{
+		client: client,
+		gp:     gp,
+		p:      p,
+		req: &container.ProxyRequest{
+			Name:      proxyName,
+			Command:   proxyArgs(&p.Proxy),
+			Redirects: redirects(p.Port),
+			Proxy:     container.SidecarSpec{Image: p.Image, Pull: p.Pull},
+			Sidecar:   container.SidecarSpec{Image: p.IPTablesImage, Pull: p.Pull},
+			DryRun:    gp.DryRun,
+		},
+	}, nil
+}
+
+// proxyArgs builds the argv of the proxy sidecar. The proxy forwards each
+// redirected connection to its original destination, so services bound to
+// the container IP only are reached too.
+func proxyArgs(cfg *httpproxy.Config) []string {
+	args := []string{proxyBinary, ProxyCommand,
+		"--listen", ":" + strconv.Itoa(httpproxy.DefaultPort),
+		"--percent", strconv.FormatFloat(cfg.Percent, 'f', -1, 64),
+	}
+	if cfg.Path != "" {
+		args = append(args, "--path", cfg.Path)
+	}
+	if cfg.Method != "" {
+		args = append(args, "--method", cfg.Method)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Headers)) {
+		args = append(args, "--header", name+"="+cfg.Headers[name])
+	}
+	if cfg.Delay > 0 {
+		args = append(args, "--delay", cfg.Delay.String())
+	}
+	if cfg.AbortStatus != 0 {
+		args = append(args, "--abort-status", strconv.Itoa(cfg.AbortStatus))
+	}
+	if cfg.GRPCStatus != 0 {
+		args = append(args, "--grpc-status", strconv.Itoa(cfg.GRPCStatus))
+	}
+	return args
+}
+
+// redirects sends incoming TCP connections for port to the proxy.
+func redirects(port int) [][]string {
+	return [][]string{{"PREROUTING", "-p", "tcp", "--dport", strconv.Itoa(port),
+		"-j", "REDIRECT", "--to-ports", strconv.Itoa(httpproxy.DefaultPort)}}
+}
+
+// Run http command
+func (n *httpCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("injecting http faults into all matching containers")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.p.Limit,
+		"random":  random,
+	}).Debug("listing matching containers")
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.p.Limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			log.WithFields(log.Fields{"container": *c, "port": n.p.Port}).Debug("injecting http faults for container")
+			httpCtx, cancel := context.WithTimeout(ctx, n.p.Duration)
+			defer cancel()
+			req := *n.req
+			req.Container = c
+			if err := chaos.RunProxy(httpCtx, n.client, &req, n.p.Duration); err != nil {
+				log.WithError(err).Warn("failed to inject http faults for container")
+				return fmt.Errorf("failed to inject http faults for one or more containers: %w", err)
+			}
+			return nil
+		})
+}
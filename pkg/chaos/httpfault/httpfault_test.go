@@ -0,0 +1,81 @@
+package httpfault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/httpproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPCommand_Validation(t *testing.T) {
+	abort := httpproxy.Config{AbortStatus: 503, Percent: 100}
+	tests := []struct {
+		name    string
+		gp      chaos.GlobalParams
+		p       Params
+		wantErr string
+	}{
+		{"valid", chaos.GlobalParams{}, Params{Duration: time.Second, Port: 8080, Proxy: abort}, ""},
+		{"no duration", chaos.GlobalParams{}, Params{Port: 8080, Proxy: abort}, "invalid duration"},
+		{"duration over interval", chaos.GlobalParams{Interval: time.Second}, Params{Duration: time.Second, Port: 8080, Proxy: abort}, "shorter than interval"},
+		{"no port", chaos.GlobalParams{}, Params{Duration: time.Second, Proxy: abort}, "invalid http port"},
+		{"proxy port", chaos.GlobalParams{}, Params{Duration: time.Second, Port: httpproxy.DefaultPort, Proxy: abort}, "reserved for the proxy"},
+		{"no fault", chaos.GlobalParams{}, Params{Duration: time.Second, Port: 8080, Proxy: httpproxy.Config{Percent: 100}}, "no http fault configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewHTTPCommand(container.NewMockClient(t), &tt.gp, &tt.p)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, cmd)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxyArgs(t *testing.T) {
+	assert.Equal(t, []string{"/pumba", "http-proxy", "--listen", ":15080", "--percent", "50",
+		"--path", "/checkout", "--method", "POST", "--header", "X-A=1", "--header", "X-B=2",
+		"--delay", "2s", "--abort-status", "503", "--grpc-status", "14"},
+		proxyArgs(&httpproxy.Config{
+			Path: "/checkout", Method: "POST", Headers: map[string]string{"X-B": "2", "X-A": "1"},
+			Percent: 50, Delay: 2 * time.Second, AbortStatus: 503, GRPCStatus: 14,
+		}))
+	assert.Equal(t, []string{"/pumba", "http-proxy", "--listen", ":15080", "--percent", "12.5", "--abort-status", "500"},
+		proxyArgs(&httpproxy.Config{Percent: 12.5, AbortStatus: 500}))
+}
+
+func TestRedirects(t *testing.T) {
+	assert.Equal(t, [][]string{{"PREROUTING", "-p", "tcp", "--dport", "8080", "-j", "REDIRECT", "--to-ports", "15080"}}, redirects(8080))
+}
+
+func TestHTTPCommand_Run(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	gp := &chaos.GlobalParams{Names: []string{"c1"}}
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return([]*container.Container{c}, nil)
+	matchReq := mock.MatchedBy(func(r *container.ProxyRequest) bool {
+		return r.Container == c && r.Name == "http" && r.Proxy.Image == "pumba:test" && r.Sidecar.Image == "nettools:test"
+	})
+	mockClient.EXPECT().ProxyContainer(mock.Anything, matchReq).Return(nil).Once()
+	mockClient.EXPECT().StopProxyContainer(mock.Anything, matchReq).Return(nil).Once()
+
+	cmd, err := NewHTTPCommand(mockClient, gp, &Params{
+		Proxy:         httpproxy.Config{AbortStatus: 503, Percent: 100},
+		Port:          8080,
+		Duration:      10 * time.Millisecond,
+		Image:         "pumba:test",
+		IPTablesImage: "nettools:test",
+	})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
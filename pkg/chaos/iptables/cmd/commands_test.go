@@ -52,6 +52,7 @@ func iptablesParentFlags() []cli.Flag {
 		cli.StringFlag{Name: "dst-port, dport"},
 		cli.StringFlag{Name: "iptables-image", Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest"},
 		cli.BoolTFlag{Name: "pull-image"},
+		cli.StringFlag{Name: "backend", Value: "iptables"},
 	}
 }
 
@@ -150,3 +151,42 @@ func TestBuildLossCommand_BadMode(t *testing.T) {
 	_, err = buildLossCommand(client, defaultGlobalParams(), p)
 	assert.Error(t, err)
 }
+
+// ---- Throttle ------------------------------------------------------------
+
+func TestNewThrottleCLICommand_Contract(t *testing.T) {
+	rt, _, calls := fakeRuntime(t)
+	cmd := NewThrottleCLICommand(context.Background(), rt)
+	assertConstructorContract(t, cmd, "throttle")
+	assert.Equal(t, 0, *calls, "Runtime must not be resolved at construction time")
+}
+
+func TestParseThrottleParams(t *testing.T) {
+	cmd := NewThrottleCLICommand(context.Background(), nilRuntime())
+	parent := iptablesParentContext(t, []string{"--duration", "1s", "--protocol", "tcp"})
+	c := childContext(t, parent, cmd.Flags,
+		[]string{"--mode", "conn", "--max-connections", "25", "--per-source"})
+	got, err := parseThrottleParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, "conn", got.Mode)
+	assert.Equal(t, 25, got.MaxConnections)
+	assert.True(t, got.PerSource)
+	assert.Equal(t, "10/second", got.Rate)
+	assert.Equal(t, 5, got.Burst)
+	require.NotNil(t, got.Base)
+	assert.Equal(t, "tcp", got.Base.Protocol)
+
+	built, err := buildThrottleCommand(container.NewMockClient(t), defaultGlobalParams(), got)
+	require.NoError(t, err)
+	assert.NotNil(t, built)
+}
+
+func TestBuildThrottleCommand_BadRate(t *testing.T) {
+	cmd := NewThrottleCLICommand(context.Background(), nilRuntime())
+	parent := iptablesParentContext(t, nil)
+	c := childContext(t, parent, cmd.Flags, []string{"--rate", "fast"})
+	p, err := parseThrottleParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	_, err = buildThrottleCommand(container.NewMockClient(t), defaultGlobalParams(), p)
+	assert.ErrorContains(t, err, "invalid throttle rate")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/iptables"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// ThrottleParams holds the per-command parameters for the iptables throttle subcommand.
+type ThrottleParams struct {
+	Base           *iptables.RequestBase
+	Mode           string
+	Rate           string
+	Burst          int
+	MaxConnections int
+	PerSource      bool
+}
+
+// NewThrottleCLICommand initialize CLI throttle command.
+func NewThrottleCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[ThrottleParams]{
+		Name: "throttle",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "mode",
+				Usage: "throttling mode: rate (cap new connections per time unit) or conn (cap concurrent connections)",
+				Value: iptables.ThrottleModeRate,
+			},
+			cli.StringFlag{
+				Name:  "rate",
+				Usage: "maximum new connections in rate mode as <count>/<unit>, unit is one of second, minute, hour or day",
+				Value: "10/second",
+			},
+			cli.IntFlag{
+				Name:  "burst",
+				Usage: "number of new connections allowed above rate before dropping starts, works only with rate mode",
+				Value: 5, //nolint:mnd
+			},
+			cli.IntFlag{
+				Name:  "max-connections",
+				Usage: "maximum concurrent connections in conn mode",
+				Value: 0,
+			},
+			cli.BoolFlag{
+				Name:  "per-source",
+				Usage: "apply the cap to each source IP separately instead of to all traffic",
+			},
+		},
+		Usage:       "adds iptables rules to throttle new incoming connections",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "drops new incoming connections above a rate (hashlimit) or above a concurrent connection cap (connlimit), globally or per source IP\n \tsee:  https://www.man7.org/linux/man-pages/man8/iptables-extensions.8.html",
+		Parse:       parseThrottleParams,
+		Build:       buildThrottleCommand,
+	})
+}
+
+func parseThrottleParams(c cliflags.Flags, gp *chaos.GlobalParams) (ThrottleParams, error) {
+	base, err := iptables.ParseRequestBase(c.Parent(), gp)
+	if err != nil {
+		return ThrottleParams{}, fmt.Errorf("error parsing iptables parameters: %w", err)
+	}
+	return ThrottleParams{
+		Base:           base,
+		Mode:           c.String("mode"),
+		Rate:           c.String("rate"),
+		Burst:          c.Int("burst"),
+		MaxConnections: c.Int("max-connections"),
+		PerSource:      c.Bool("per-source"),
+	}, nil
+}
+
+func buildThrottleCommand(client container.Client, gp *chaos.GlobalParams, p ThrottleParams) (chaos.Command, error) {
+	return iptables.NewThrottleCommand(client, gp, p.Base, p.Mode, p.Rate, p.Burst, p.MaxConnections, p.PerSource)
+}
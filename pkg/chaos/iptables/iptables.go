@@ -27,11 +27,24 @@ type iptablesClient interface {
 // 1h chaos run does not give cleanup an hour to complete.
 const cleanupTimeout = 30 * time.Second
 
+// buildCmdPrefixes returns the rule installation (-I) and removal (-D)
+// prefixes matching ingress traffic on iface, narrowed to protocol unless it
+// is "any".
+func buildCmdPrefixes(iface, protocol string) (addCmdPrefix, delCmdPrefix []string) {
+	cmdPrefix := []string{"INPUT", "-i", iface}
+	if protocol != ProtocolAny {
+		cmdPrefix = append(cmdPrefix, "-p", protocol)
+	}
+	addCmdPrefix = append([]string{"-I"}, cmdPrefix...)
+	delCmdPrefix = append([]string{"-D"}, cmdPrefix...)
+	return addCmdPrefix, delCmdPrefix
+}
+
 // run iptables command, stop iptables on timeout or abort. The add/del prefix
 // pair distinguishes the rule installation command (-I/-A/-N) from its mirror
 // removal command (-D); both share the rest of the request fields.
 func runIPTables(ctx context.Context, client iptablesClient, addReq, delReq *container.IPTablesRequest) error {
-	logger := log.WithFields(log.Fields{
+	logger := log.WithContext(ctx).WithFields(log.Fields{
 		"id":           addReq.Container.ID(),
 		"name":         addReq.Container.Name(),
 		"addCmdPrefix": addReq.CmdPrefix,
@@ -44,34 +57,43 @@ func runIPTables(ctx context.Context, client iptablesClient, addReq, delReq *con
 		"duration":     addReq.Duration,
 		"image":        addReq.Sidecar.Image,
 		"pull":         addReq.Sidecar.Pull,
+		"backend":      addReq.Backend,
 	})
 	logger.Debug("running iptables command")
 	if err := client.IPTablesContainer(ctx, addReq); err != nil {
 		return fmt.Errorf("iptables failed: %w", err)
 	}
+	// the runtime resolved an auto backend on install: remove the rules with
+	// the same tool
+	delReq.Backend = addReq.Backend
 	logger.Debug("iptables command started")
+	if err := verifyIPTables(ctx, client, addReq, true); err != nil {
+		// do not leave partially applied rules behind a failed run
+		cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), cleanupTimeout)
+		defer cleanupCancel()
+		if stopErr := client.StopIPTablesContainer(cleanupCtx, delReq); stopErr != nil {
+			logger.WithError(stopErr).Warn("failed to stop iptables container after failed verification")
+		}
+		return err
+	}
 
 	// create new context with timeout for canceling
 	stopCtx, cancel := context.WithTimeout(context.Background(), addReq.Duration)
 	defer cancel()
 	// wait for specified duration and then stop iptables (where it applied) or stop on ctx.Done()
-	// use context.WithoutCancel so cleanup succeeds even if the parent ctx is canceled
-	// or if it inherited a deadline that has elapsed alongside stopCtx.
 	select {
 	case <-ctx.Done():
 		logger.Debug("stopping iptables command on abort")
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
-		defer cleanupCancel()
-		if err := client.StopIPTablesContainer(cleanupCtx, delReq); err != nil {
-			logger.WithError(err).Warn("failed to stop iptables container (container may have been removed)")
-		}
 	case <-stopCtx.Done():
 		logger.Debug("stopping iptables command on timeout")
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
-		defer cleanupCancel()
-		if err := client.StopIPTablesContainer(cleanupCtx, delReq); err != nil {
-			logger.WithError(err).Warn("failed to stop iptables container (container may have been removed)")
-		}
 	}
-	return nil
+	// the cleanup succeeds even if the parent ctx is canceled
+	// or if it inherited a deadline that has elapsed alongside stopCtx.
+	cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), cleanupTimeout)
+	defer cleanupCancel()
+	if err := client.StopIPTablesContainer(cleanupCtx, delReq); err != nil {
+		logger.WithError(err).Warn("failed to stop iptables container (container may have been removed)")
+		return nil
+	}
+	return verifyIPTables(cleanupCtx, client, addReq, false)
 }
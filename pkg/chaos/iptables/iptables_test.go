@@ -183,3 +183,20 @@ func Test_runIPTables(t *testing.T) {
 		})
 	}
 }
+
+func Test_runIPTables_RemovesWithResolvedBackend(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "c1"}
+	addReq := &container.IPTablesRequest{Container: c, CmdPrefix: []string{"-I", "INPUT"}, Backend: container.FilterBackendAuto, Duration: time.Millisecond}
+	delReq := &container.IPTablesRequest{Container: c, CmdPrefix: []string{"-D", "INPUT"}, Backend: container.FilterBackendAuto}
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().IPTablesContainer(mock.Anything, addReq).
+		Run(func(_ context.Context, req *container.IPTablesRequest) { req.Backend = container.FilterBackendNFTables }).
+		Return(nil).Once()
+	mockClient.EXPECT().StopIPTablesContainer(mock.Anything, mock.MatchedBy(func(req *container.IPTablesRequest) bool {
+		return req == delReq && req.Backend == container.FilterBackendNFTables
+	})).Return(nil).Once()
+
+	if err := runIPTables(context.TODO(), mockClient, addReq, delReq); err != nil {
+		t.Errorf("runIPTables() error = %v", err)
+	}
+}
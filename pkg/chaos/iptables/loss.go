@@ -104,12 +104,7 @@ func (n *lossCommand) Run(ctx context.Context, random bool) error {
 }
 
 func (n *lossCommand) buildIPTablesCmd() (addCmdPrefix, delCmdPrefix, cmdSuffix []string) {
-	cmdPrefix := []string{"INPUT", "-i", n.iface}
-	if n.protocol != "any" {
-		cmdPrefix = append(cmdPrefix, "-p", n.protocol)
-	}
-	addCmdPrefix = append([]string{"-I"}, cmdPrefix...)
-	delCmdPrefix = append([]string{"-D"}, cmdPrefix...)
+	addCmdPrefix, delCmdPrefix = buildCmdPrefixes(n.iface, n.protocol)
 	cmdSuffix = []string{"-m", "statistic", "--mode", n.mode}
 	if n.mode == ModeRandom {
 		cmdSuffix = append(cmdSuffix, "--probability", strconv.FormatFloat(n.probability, 'f', 2, 64))
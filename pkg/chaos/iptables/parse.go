@@ -27,7 +27,7 @@ type RequestBase struct {
 
 // ParseRequestBase reads the iptables-level flags (--duration, --interface,
 // --protocol, --source, --destination, --src-port, --dst-port,
-// --iptables-image, --pull-image, --limit) from c and returns a RequestBase
+// --iptables-image, --pull-image, --backend, --capture*, --verify, --limit) from c and returns a RequestBase
 // with the shared fields filled. Container, CmdPrefix and CmdSuffix on
 // Request are left zero — each per-action Run sets them per iteration.
 //
@@ -72,6 +72,22 @@ func ParseRequestBase(c cliflags.Flags, gp *chaos.GlobalParams) (*RequestBase, e
 			return nil, fmt.Errorf("using destination port is only supported for %s and %s protocol", ProtocolTCP, ProtocolUDP)
 		}
 	}
+	backend := c.String("backend")
+	if backend == "" {
+		backend = container.FilterBackendIPTables
+	}
+	if !slices.Contains([]string{container.FilterBackendIPTables, container.FilterBackendNFTables, container.FilterBackendAuto}, backend) {
+		return nil, errors.New("bad packet filter backend: must be one of iptables, nftables or auto")
+	}
+	sidecar := container.SidecarSpec{Image: c.String("iptables-image"), Pull: c.Bool("pull-image")}
+	capture, err := chaos.ParseCapture(c, iface, sidecar, slices.Concat(srcIPs, dstIPs), slices.Concat(sports, dports))
+	if err != nil {
+		return nil, err
+	}
+	verify, err := chaos.ParseVerify(c.String("verify"))
+	if err != nil {
+		return nil, err
+	}
 	return &RequestBase{
 		Request: &container.IPTablesRequest{
 			SrcIPs:   srcIPs,
@@ -79,7 +95,10 @@ func ParseRequestBase(c cliflags.Flags, gp *chaos.GlobalParams) (*RequestBase, e
 			SPorts:   sports,
 			DPorts:   dports,
 			Duration: duration,
-			Sidecar:  container.SidecarSpec{Image: c.String("iptables-image"), Pull: c.Bool("pull-image")},
+			Sidecar:  sidecar,
+			Capture:  capture,
+			Backend:  backend,
+			Verify:   verify,
 			DryRun:   gp.DryRun,
 		},
 		Iface:    iface,
@@ -7,6 +7,7 @@ import (
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
@@ -23,7 +24,12 @@ func iptablesParentFlags() []cli.Flag {
 		cli.StringFlag{Name: "dst-port, dport"},
 		cli.StringFlag{Name: "iptables-image", Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest"},
 		cli.BoolTFlag{Name: "pull-image"},
+		cli.StringFlag{Name: "backend", Value: "iptables"},
 		cli.IntFlag{Name: "limit"},
+		cli.StringFlag{Name: "capture"},
+		cli.IntFlag{Name: "capture-size", Value: 100},
+		cli.IntFlag{Name: "capture-files", Value: 5},
+		cli.StringFlag{Name: "verify", Value: "none"},
 	}
 }
 
@@ -112,6 +118,12 @@ func TestParseRequestBase(t *testing.T) {
 			gp:      &chaos.GlobalParams{},
 			wantErr: "using source port is only supported",
 		},
+		{
+			name:    "unknown backend rejected",
+			args:    []string{"--duration", "1s", "--interface", "eth0", "--backend", "pf"},
+			gp:      &chaos.GlobalParams{},
+			wantErr: "bad packet filter backend",
+		},
 		{
 			name:    "dst port with non-tcp/udp rejected",
 			args:    []string{"--duration", "1s", "--interface", "eth0", "--protocol", "icmp", "--dst-port", "80"},
@@ -166,3 +178,41 @@ func TestParseRequestBase_PortsAndIPsParsed(t *testing.T) {
 	assert.Equal(t, []string{"80", "443"}, base.Request.SPorts)
 	assert.Equal(t, []string{"8080"}, base.Request.DPorts)
 }
+
+func TestParseRequestBase_Backend(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--duration", "1s"}, container.FilterBackendIPTables},
+		{[]string{"--duration", "1s", "--backend", "nftables"}, container.FilterBackendNFTables},
+		{[]string{"--duration", "1s", "--backend", "auto"}, container.FilterBackendAuto},
+		{[]string{"--duration", "1s", "--backend", ""}, container.FilterBackendIPTables},
+	}
+	for _, tt := range tests {
+		base, err := ParseRequestBase(cliflags.NewV1(parentCtx(t, tt.args)), &chaos.GlobalParams{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, base.Request.Backend)
+	}
+}
+
+func TestParseRequestBase_Capture(t *testing.T) {
+	args := []string{"--duration", "1s", "--protocol", "tcp", "--source", "10.0.0.1", "--dst-port", "443", "--capture", "/var/pcap"}
+	base, err := ParseRequestBase(cliflags.NewV1(parentCtx(t, args)), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	require.NotNil(t, base.Request.Capture)
+	assert.Equal(t, "/var/pcap", base.Request.Capture.Dir)
+	assert.Equal(t, "net 10.0.0.1/32 and port 443", base.Request.Capture.Filter)
+
+	_, err = ParseRequestBase(cliflags.NewV1(parentCtx(t, []string{"--duration", "1s", "--capture", "pcap"})), &chaos.GlobalParams{})
+	assert.ErrorContains(t, err, "absolute path")
+}
+
+func TestParseRequestBase_Verify(t *testing.T) {
+	base, err := ParseRequestBase(cliflags.NewV1(parentCtx(t, []string{"--duration", "1s", "--verify", "warn"})), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, chaos.VerifyWarn, base.Request.Verify)
+
+	_, err = ParseRequestBase(cliflags.NewV1(parentCtx(t, []string{"--duration", "1s", "--verify", "strict"})), &chaos.GlobalParams{})
+	assert.ErrorContains(t, err, "bad verify mode")
+}
@@ -0,0 +1,188 @@
+package iptables
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ThrottleModeRate drops new connections above a rate (hashlimit).
+	ThrottleModeRate = "rate"
+	// ThrottleModeConn drops new connections above a concurrency cap (connlimit).
+	ThrottleModeConn = "conn"
+
+	// hashlimitPrefix starts the names of the hashlimit tables; with the 8
+	// hex digits of the run it stays within the 15 characters xt_hashlimit
+	// allows.
+	hashlimitPrefix = "pumba"
+	// connlimit masks: 32 counts connections per source IPv4 address, 0
+	// counts all connections together.
+	connlimitMaskPerSource = "32"
+	connlimitMaskGlobal    = "0"
+)
+
+// runID returns a random ID of a run; replaced in tests.
+var runID = rand.Uint32
+
+// hashlimitName matches the hashlimit table names of throttle runs, and the
+// fixed name of older releases.
+var hashlimitName = regexp.MustCompile(`^` + hashlimitPrefix + `([0-9a-f]{8})?$`)
+
+// IsHashlimitName reports whether name is the hashlimit table of a throttle
+// run.
+func IsHashlimitName(name string) bool {
+	return hashlimitName.MatchString(name)
+}
+
+// rateUnits maps the accepted --rate units onto the names iptables expects.
+var rateUnits = map[string]string{
+	"s": "second", "sec": "second", "second": "second",
+	"m": "minute", "min": "minute", "minute": "minute",
+	"h": "hour", "hour": "hour",
+	"d": "day", "day": "day",
+}
+
+// `iptables throttle` command
+type throttleCommand struct {
+	client         iptablesClient
+	gp             *chaos.GlobalParams
+	req            *container.IPTablesRequest
+	iface          string
+	protocol       string
+	limit          int
+	mode           string
+	rate           string
+	burst          int
+	maxConnections int
+	perSource      bool
+}
+
+// NewThrottleCommand create new iptables throttle command
+func NewThrottleCommand(client iptablesClient,
+	gp *chaos.GlobalParams,
+	base *RequestBase,
+	mode string, // throttle mode
+	rate string, // new connections rate for rate mode, e.g. 10/second
+	burst int, // burst allowance for rate mode
+	maxConnections int, // concurrent connections cap for conn mode
+	perSource bool, // apply the cap per source IP instead of globally
+) (chaos.Command, error) {
+	cmd := &throttleCommand{
+		client:         client,
+		gp:             gp,
+		req:            base.Request,
+		iface:          base.Iface,
+		protocol:       base.Protocol,
+		limit:          base.Limit,
+		mode:           mode,
+		burst:          burst,
+		maxConnections: maxConnections,
+		perSource:      perSource,
+	}
+	switch mode {
+	case ThrottleModeRate:
+		r, err := parseRate(rate)
+		if err != nil {
+			return nil, err
+		}
+		if burst <= 0 {
+			return nil, errors.New("invalid throttle burst: must be > 0")
+		}
+		cmd.rate = r
+	case ThrottleModeConn:
+		if maxConnections <= 0 {
+			return nil, errors.New("invalid throttle max connections: must be > 0")
+		}
+		if base.Protocol == ProtocolICMP {
+			return nil, errors.New("conn throttle mode requires a connection-oriented protocol: use tcp, udp or any")
+		}
+	default:
+		return nil, errors.New("invalid throttle mode: must be either rate or conn")
+	}
+	return cmd, nil
+}
+
+// parseRate validates a "<count>/<unit>" rate and returns it in the form
+// accepted by hashlimit (e.g. "10/second").
+func parseRate(rate string) (string, error) {
+	count, unit, found := strings.Cut(rate, "/")
+	if !found {
+		return "", fmt.Errorf("invalid throttle rate %q: must be <count>/<unit>, e.g. 10/second", rate)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return "", fmt.Errorf("invalid throttle rate %q: count must be a positive integer", rate)
+	}
+	u, ok := rateUnits[unit]
+	if !ok {
+		return "", fmt.Errorf("invalid throttle rate %q: unit must be one of second, minute, hour or day", rate)
+	}
+	return strconv.Itoa(n) + "/" + u, nil
+}
+
+// Run iptables throttle command
+func (n *throttleCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("adding connection throttling to all matching containers")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.limit,
+		"random":  random,
+	}).Debug("listing matching containers")
+	addCmdPrefix, delCmdPrefix, cmdSuffix := n.buildIPTablesCmd()
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			log.WithFields(log.Fields{"container": *c, "mode": n.mode}).Debug("adding connection throttling for container")
+			iptCtx, cancel := context.WithTimeout(ctx, n.req.Duration)
+			defer cancel()
+			addReq := *n.req
+			addReq.Container = c
+			addReq.CmdPrefix = addCmdPrefix
+			addReq.CmdSuffix = cmdSuffix
+			delReq := addReq
+			delReq.CmdPrefix = delCmdPrefix
+			if err := runIPTables(iptCtx, n.client, &addReq, &delReq); err != nil {
+				log.WithError(err).Warn("failed to throttle connections for container")
+				return fmt.Errorf("failed to throttle connections for one or more containers: %w", err)
+			}
+			return nil
+		})
+}
+
+func (n *throttleCommand) buildIPTablesCmd() (addCmdPrefix, delCmdPrefix, cmdSuffix []string) {
+	addCmdPrefix, delCmdPrefix = buildCmdPrefixes(n.iface, n.protocol)
+	// only new connections count against the cap; established flows keep working
+	cmdSuffix = []string{"-m", "conntrack", "--ctstate", "NEW"}
+	if n.mode == ThrottleModeRate {
+		cmdSuffix = append(cmdSuffix, "-m", "hashlimit",
+			"--hashlimit-above", n.rate,
+			"--hashlimit-burst", strconv.Itoa(n.burst))
+		if n.perSource {
+			cmdSuffix = append(cmdSuffix, "--hashlimit-mode", "srcip")
+		}
+		// a table per run: runs sharing one would share its buckets, and
+		// removing the rule of one would change the rate of the other; the
+		// -D rule reuses the suffix, so it names the same table
+		cmdSuffix = append(cmdSuffix, "--hashlimit-name", fmt.Sprintf("%s%08x", hashlimitPrefix, runID()))
+	} else { // mode == conn
+		mask := connlimitMaskGlobal
+		if n.perSource {
+			mask = connlimitMaskPerSource
+		}
+		cmdSuffix = append(cmdSuffix, "-m", "connlimit",
+			"--connlimit-above", strconv.Itoa(n.maxConnections),
+			"--connlimit-mask", mask)
+	}
+	cmdSuffix = append(cmdSuffix, "-j", "DROP")
+	return addCmdPrefix, delCmdPrefix, cmdSuffix
+}
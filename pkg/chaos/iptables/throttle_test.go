@@ -0,0 +1,157 @@
+package iptables
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThrottleCommand_Validation(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	gparams := &chaos.GlobalParams{Names: []string{"test"}}
+
+	tests := []struct {
+		name     string
+		protocol string
+		mode     string
+		rate     string
+		burst    int
+		maxConn  int
+		wantErr  string
+	}{
+		{"valid rate mode", "tcp", ThrottleModeRate, "10/second", 5, 0, ""},
+		{"valid rate short unit", "any", ThrottleModeRate, "100/m", 1, 0, ""},
+		{"valid conn mode", "tcp", ThrottleModeConn, "", 0, 20, ""},
+		{"invalid mode", "tcp", "bogus", "", 0, 0, "invalid throttle mode"},
+		{"rate: missing unit", "tcp", ThrottleModeRate, "10", 5, 0, "must be <count>/<unit>"},
+		{"rate: zero count", "tcp", ThrottleModeRate, "0/second", 5, 0, "count must be a positive integer"},
+		{"rate: bad unit", "tcp", ThrottleModeRate, "10/week", 5, 0, "unit must be one of"},
+		{"rate: zero burst", "tcp", ThrottleModeRate, "10/second", 0, 0, "invalid throttle burst"},
+		{"conn: zero max", "tcp", ThrottleModeConn, "", 0, 0, "invalid throttle max connections"},
+		{"conn: icmp rejected", "icmp", ThrottleModeConn, "", 0, 10, "connection-oriented protocol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newBase(&container.IPTablesRequest{Duration: time.Second}, "eth0", tt.protocol)
+			cmd, err := NewThrottleCommand(mockClient, gparams, base, tt.mode, tt.rate, tt.burst, tt.maxConn, false)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, cmd)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, cmd)
+			}
+		})
+	}
+}
+
+func TestThrottleCommand_BuildIPTablesCmd(t *testing.T) {
+	origRunID := runID
+	t.Cleanup(func() { runID = origRunID })
+	runID = func() uint32 { return 42 }
+	tests := []struct {
+		name       string
+		mode       string
+		rate       string
+		maxConn    int
+		perSource  bool
+		wantSuffix []string
+	}{
+		{
+			name: "global rate",
+			mode: ThrottleModeRate, rate: "10/s",
+			wantSuffix: []string{"-m", "conntrack", "--ctstate", "NEW", "-m", "hashlimit",
+				"--hashlimit-above", "10/second", "--hashlimit-burst", "5", "--hashlimit-name", "pumba0000002a", "-j", "DROP"},
+		},
+		{
+			name: "per-source rate",
+			mode: ThrottleModeRate, rate: "2/minute", perSource: true,
+			wantSuffix: []string{"-m", "conntrack", "--ctstate", "NEW", "-m", "hashlimit",
+				"--hashlimit-above", "2/minute", "--hashlimit-burst", "5", "--hashlimit-mode", "srcip", "--hashlimit-name", "pumba0000002a", "-j", "DROP"},
+		},
+		{
+			name: "global connections",
+			mode: ThrottleModeConn, maxConn: 50,
+			wantSuffix: []string{"-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit",
+				"--connlimit-above", "50", "--connlimit-mask", "0", "-j", "DROP"},
+		},
+		{
+			name: "per-source connections",
+			mode: ThrottleModeConn, maxConn: 3, perSource: true,
+			wantSuffix: []string{"-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit",
+				"--connlimit-above", "3", "--connlimit-mask", "32", "-j", "DROP"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := newBase(&container.IPTablesRequest{Duration: time.Second}, "eth0", "tcp")
+			cmd, err := NewThrottleCommand(container.NewMockClient(t), &chaos.GlobalParams{}, base, tt.mode, tt.rate, 5, tt.maxConn, tt.perSource)
+			require.NoError(t, err)
+			addPrefix, delPrefix, suffix := cmd.(*throttleCommand).buildIPTablesCmd()
+			assert.Equal(t, []string{"-I", "INPUT", "-i", "eth0", "-p", "tcp"}, addPrefix)
+			assert.Equal(t, []string{"-D", "INPUT", "-i", "eth0", "-p", "tcp"}, delPrefix)
+			assert.Equal(t, tt.wantSuffix, suffix)
+		})
+	}
+}
+
+func TestThrottleCommand_HashlimitNamePerRun(t *testing.T) {
+	base := newBase(&container.IPTablesRequest{Duration: time.Second}, "eth0", "tcp")
+	cmd, err := NewThrottleCommand(container.NewMockClient(t), &chaos.GlobalParams{}, base, ThrottleModeRate, "10/s", 5, 0, false)
+	require.NoError(t, err)
+	name := func() string {
+		_, _, suffix := cmd.(*throttleCommand).buildIPTablesCmd()
+		i := slices.Index(suffix, "--hashlimit-name")
+		require.GreaterOrEqual(t, i, 0)
+		return suffix[i+1]
+	}
+	first, second := name(), name()
+	assert.NotEqual(t, first, second, "runs do not share hashlimit buckets")
+	assert.LessOrEqual(t, len(first), 15, "xt_hashlimit name limit")
+	assert.True(t, strings.HasPrefix(first, "pumba"))
+}
+
+func TestThrottleCommand_Run(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	target := &container.Container{ContainerID: "abc123", ContainerName: "target"}
+	gparams := &chaos.GlobalParams{Names: []string{"target"}, DryRun: true}
+	base := newBase(&container.IPTablesRequest{
+		Duration: 100 * time.Millisecond,
+		Sidecar:  container.SidecarSpec{Image: "iptables-image"},
+		DryRun:   true,
+	}, "eth0", "tcp")
+
+	mockClient.EXPECT().ListContainers(mock.Anything,
+		mock.AnythingOfType("container.FilterFunc"),
+		container.ListOpts{All: false, Labels: nil}).
+		Return([]*container.Container{target}, nil)
+
+	cmdSuffix := []string{"-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit",
+		"--connlimit-above", "10", "--connlimit-mask", "32", "-j", "DROP"}
+	addReq := &container.IPTablesRequest{
+		Container: target,
+		CmdPrefix: []string{"-I", "INPUT", "-i", "eth0", "-p", "tcp"},
+		CmdSuffix: cmdSuffix,
+		Duration:  100 * time.Millisecond,
+		Sidecar:   container.SidecarSpec{Image: "iptables-image"},
+		DryRun:    true,
+	}
+	delReq := *addReq
+	delReq.CmdPrefix = []string{"-D", "INPUT", "-i", "eth0", "-p", "tcp"}
+	mockClient.EXPECT().IPTablesContainer(mock.Anything, addReq).Return(nil)
+	mockClient.EXPECT().StopIPTablesContainer(mock.Anything, &delReq).Return(nil)
+
+	cmd, err := NewThrottleCommand(mockClient, gparams, base, ThrottleModeConn, "", 0, 10, true)
+	require.NoError(t, err)
+	assert.NoError(t, cmd.Run(context.Background(), false))
+}
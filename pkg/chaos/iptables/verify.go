@@ -0,0 +1,86 @@
+package iptables
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
+)
+
+// verifyIPTables reads back the packet filter state of the target and checks
+// that the rules of addReq are present after apply (applied) or gone after
+// stop. The outcome is handled according to addReq.Verify.
+func verifyIPTables(ctx context.Context, client iptablesClient, addReq *container.IPTablesRequest, applied bool) error {
+	if !chaos.Verifying(addReq.Verify, addReq.DryRun) {
+		return nil
+	}
+	// the sidecar image is present from the injection being verified
+	status := *addReq
+	status.Sidecar.Pull = false
+	out, err := client.IPTablesStatus(ctx, &status)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to read back packet filter rules in %s: %w", addReq.Container.Name(), err)
+	case applied && !hasRules(out, addReq):
+		err = fmt.Errorf("packet filter rules not found in %s after injection", addReq.Container.Name())
+	case !applied && hasRules(out, addReq):
+		err = fmt.Errorf("packet filter rules still present in %s after removal", addReq.Container.Name())
+	}
+	return chaos.VerifyResult(addReq.Verify, err)
+}
+
+// hasRules reports whether the status output lists the rules of addReq:
+// either the dedicated Pumba table (`nft list tables`) or an `iptables -S`
+// rule in the request chain carrying its interface, protocol, match modules
+// and target. Option values are not compared since iptables prints them in
+// canonical form (e.g. --probability 0.20000000000).
+func hasRules(out string, addReq *container.IPTablesRequest) bool {
+	if len(addReq.CmdPrefix) < 2 { //nolint:mnd
+		return false
+	}
+	table := strings.Join([]string{"table", nftables.Family, nftables.Table}, " ")
+	want := ruleOptions(addReq)
+	for line := range strings.Lines(out) {
+		fields := strings.Fields(line)
+		if strings.Join(fields, " ") == table {
+			return true
+		}
+		if len(fields) < 2 || fields[0] != "-A" || fields[1] != addReq.CmdPrefix[1] { //nolint:mnd
+			continue
+		}
+		if !slices.ContainsFunc(want, func(opt [2]string) bool { return !hasOption(fields, opt) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleOptions returns the option/value pairs identifying the request's rules:
+// the interface and protocol of the prefix, the match modules and the jump
+// target of the suffix.
+func ruleOptions(addReq *container.IPTablesRequest) [][2]string {
+	var opts [][2]string
+	collect := func(args []string, names ...string) {
+		for i := 0; i+1 < len(args); i++ {
+			if slices.Contains(names, args[i]) {
+				opts = append(opts, [2]string{args[i], args[i+1]})
+			}
+		}
+	}
+	collect(addReq.CmdPrefix[2:], "-i", "-p")
+	collect(addReq.CmdSuffix, "-m", "-j")
+	return opts
+}
+
+func hasOption(fields []string, opt [2]string) bool {
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == opt[0] && fields[i+1] == opt[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package iptables
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	rulesDefault = "-P INPUT ACCEPT\n-P FORWARD ACCEPT\n-P OUTPUT ACCEPT\n"
+	rulesLoss    = rulesDefault +
+		"-A INPUT -i eth0 -p tcp -m tcp --dport 443 -m statistic --mode random --probability 0.20000000000 -j DROP\n"
+	nftTables = "table ip filter\ntable inet pumba\n"
+)
+
+func verifyRequests(mode string) (addReq, delReq *container.IPTablesRequest) {
+	addReq = &container.IPTablesRequest{
+		Container: &container.Container{ContainerID: "abc", ContainerName: "c1"},
+		CmdPrefix: []string{"-I", "INPUT", "-i", "eth0", "-p", "tcp"},
+		CmdSuffix: []string{"-m", "statistic", "--mode", "random", "--probability", "0.20", "-j", "DROP"},
+		DPorts:    []string{"443"},
+		Duration:  time.Millisecond,
+		Verify:    mode,
+	}
+	del := *addReq
+	del.CmdPrefix = []string{"-D", "INPUT", "-i", "eth0", "-p", "tcp"}
+	return addReq, &del
+}
+
+func TestHasRules(t *testing.T) {
+	addReq, _ := verifyRequests(chaos.VerifyFail)
+	assert.True(t, hasRules(rulesLoss, addReq))
+	assert.True(t, hasRules(nftTables, addReq))
+	assert.False(t, hasRules(rulesDefault, addReq))
+	assert.False(t, hasRules("table ip filter\n", addReq))
+	// same chain and target but another interface
+	assert.False(t, hasRules(rulesDefault+"-A INPUT -i eth1 -p tcp -m statistic --mode random -j DROP\n", addReq))
+	// same rule in another chain
+	assert.False(t, hasRules(rulesDefault+"-A OUTPUT -i eth0 -p tcp -m statistic --mode random -j DROP\n", addReq))
+}
+
+func Test_runIPTables_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		afterApply  string
+		afterStop   string
+		stopOnApply bool
+		wantErr     string
+	}{
+		{name: "applied and removed", mode: chaos.VerifyFail, afterApply: rulesLoss, afterStop: rulesDefault},
+		{name: "nft table applied and removed", mode: chaos.VerifyFail, afterApply: nftTables, afterStop: "table ip filter\n"},
+		{name: "missing after apply fails and cleans up", mode: chaos.VerifyFail, afterApply: rulesDefault, stopOnApply: true, wantErr: "not found in c1 after injection"},
+		{name: "left after stop warns", mode: chaos.VerifyWarn, afterApply: rulesLoss, afterStop: rulesLoss},
+		{name: "left after stop fails", mode: chaos.VerifyFail, afterApply: rulesLoss, afterStop: rulesLoss, wantErr: "still present in c1 after removal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addReq, delReq := verifyRequests(tt.mode)
+			mockClient := container.NewMockClient(t)
+			mockClient.EXPECT().IPTablesContainer(mock.Anything, addReq).Return(nil).Once()
+			mockClient.EXPECT().IPTablesStatus(mock.Anything, addReq).Return(tt.afterApply, nil).Once()
+			mockClient.EXPECT().StopIPTablesContainer(mock.Anything, delReq).Return(nil).Once()
+			if !tt.stopOnApply {
+				mockClient.EXPECT().IPTablesStatus(mock.Anything, addReq).Return(tt.afterStop, nil).Once()
+			}
+			err := runIPTables(context.Background(), mockClient, addReq, delReq)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@ package cmd
 import (
 	"context"
 	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/lifecycle"
 	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
@@ -100,6 +104,19 @@ func TestParseKillParams(t *testing.T) {
 	assert.Equal(t, KillParams{Signal: "SIGTERM", Limit: 3}, got)
 }
 
+func TestParseKillParams_Process(t *testing.T) {
+	cmd := NewKillCLICommand(context.Background(), nilRuntime())
+	flags := []cli.Flag{findFlag(t, cmd, "process"), findFlag(t, cmd, "process-image"), findFlag(t, cmd, "pull-image")}
+	c := newTestCLIContext(t, flags, []string{"--process", "^worker", "--process-image", "busybox"})
+	got, err := parseKillParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, &lifecycle.Processes{Regex: "^worker", Image: "busybox", Pull: true}, got.Processes)
+
+	c = newTestCLIContext(t, flags, []string{"--process", "worker("})
+	_, err = parseKillParams(cliflags.NewV1(c), defaultGlobalParams())
+	assert.ErrorContains(t, err, "bad --process regular expression")
+}
+
 func TestBuildKillCommand_ReturnsLifecycleKill(t *testing.T) {
 	client := container.NewMockClient(t)
 	cmd, err := buildKillCommand(client, defaultGlobalParams(), KillParams{Signal: "SIGTERM", Limit: 1})
@@ -113,6 +130,38 @@ func TestBuildKillCommand_RejectsInvalidSignal(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// ---- Crashloop -----------------------------------------------------------
+
+func TestNewCrashloopCLICommand_Contract(t *testing.T) {
+	rt, _, _ := fakeRuntime(t)
+	cmd := NewCrashloopCLICommand(context.Background(), rt)
+	assertConstructorContract(t, cmd, "crashloop")
+}
+
+func TestParseCrashloopParams(t *testing.T) {
+	cmd := NewCrashloopCLICommand(context.Background(), nilRuntime())
+	c := newTestCLIContext(t, cmd.Flags, []string{"--count", "5", "--delay", "2s", "--signal", "SIGTERM"})
+	got, err := parseCrashloopParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, CrashloopParams{Signal: "SIGTERM", Count: 5, Delay: 2 * time.Second, RestartTimeout: time.Minute}, got)
+}
+
+func TestBuildCrashloopCommand(t *testing.T) {
+	client := inventory.New(container.NewMockClient(t), nil)
+	cmd, err := buildCrashloopCommand(client, defaultGlobalParams(), CrashloopParams{Count: 1, RestartTimeout: time.Second})
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+	_, err = buildCrashloopCommand(client, defaultGlobalParams(), CrashloopParams{RestartTimeout: time.Second})
+	assert.EqualError(t, err, "count must be at least 1")
+
+	_, err = buildCrashloopCommand(container.NewMockClient(t), defaultGlobalParams(), CrashloopParams{Count: 1, RestartTimeout: time.Second})
+	assert.EqualError(t, err, "crashloop: the container inventory is not running", "restarts are seen on its changes")
+	gp := defaultGlobalParams()
+	gp.DryRun = true
+	_, err = buildCrashloopCommand(container.NewMockClient(t), gp, CrashloopParams{Count: 1, RestartTimeout: time.Second})
+	assert.NoError(t, err, "a dry run does not wait for restarts")
+}
+
 // ---- Stop ----------------------------------------------------------------
 
 func TestNewStopCLICommand_Contract(t *testing.T) {
@@ -181,12 +230,25 @@ func TestParsePauseParams_ZeroDurationErrors(t *testing.T) {
 	assert.EqualError(t, err, "unset or invalid duration value")
 }
 
+func TestParsePauseParams_Process(t *testing.T) {
+	cmd := NewPauseCLICommand(context.Background(), nilRuntime())
+	flags := []cli.Flag{findFlag(t, cmd, "duration"), findFlag(t, cmd, "process"), findFlag(t, cmd, "pull-image")}
+	c := newTestCLIContext(t, flags, []string{"--duration", "2s", "--process", "nginx: worker", "--pull-image=false"})
+	got, err := parsePauseParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, &lifecycle.Processes{Regex: "nginx: worker"}, got.Processes)
+}
+
 func TestBuildPauseCommand(t *testing.T) {
 	client := container.NewMockClient(t)
 	cmd, err := buildPauseCommand(client, defaultGlobalParams(),
 		PauseParams{Duration: time.Second, Limit: 0})
 	require.NoError(t, err)
 	require.NotNil(t, cmd)
+
+	_, err = buildPauseCommand(client, &chaos.GlobalParams{DryRun: true},
+		PauseParams{Duration: time.Second, Processes: &lifecycle.Processes{Regex: "worker("}})
+	require.ErrorContains(t, err, "bad process regular expression")
 }
 
 // ---- Restart -------------------------------------------------------------
@@ -263,6 +325,63 @@ func TestParseExecParams(t *testing.T) {
 	assert.Equal(t, ExecParams{Command: "touch", Args: []string{"/tmp/a", "/tmp/b"}, Limit: 2}, got)
 }
 
+func TestParseExecParams_Checks(t *testing.T) {
+	cmd := NewExecCLICommand(context.Background(), nilRuntime())
+	flags := []cli.Flag{
+		findFlag(t, cmd, "timeout"), findFlag(t, cmd, "expect-exit"), findFlag(t, cmd, "expect-output"), findFlag(t, cmd, "output-file"),
+	}
+	c := newTestCLIContext(t, flags,
+		[]string{"--timeout", "5s", "--expect-exit", "0, 1", "--expect-output", "^ok", "--output-file", "/tmp/exec.jsonl"})
+	got, err := parseExecParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, got.Options.Timeout)
+	assert.Equal(t, []int{0, 1}, got.Options.ExitCodes)
+	assert.Equal(t, "^ok", got.Options.Output.String())
+	assert.Equal(t, "/tmp/exec.jsonl", got.Options.OutputFile)
+
+	c = newTestCLIContext(t, flags, []string{"--expect-exit", "any"})
+	got, err = parseExecParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.True(t, got.Options.AnyExit)
+
+	for _, args := range [][]string{{"--expect-exit", "256"}, {"--expect-exit", "zero"}, {"--expect-output", "("}, {"--timeout", "-1s"}} {
+		c = newTestCLIContext(t, flags, args)
+		_, err = parseExecParams(cliflags.NewV1(c), defaultGlobalParams())
+		assert.Error(t, err, args)
+	}
+}
+
+func TestParseExecParams_Scripts(t *testing.T) {
+	dir := t.TempDir()
+	fault, undo := filepath.Join(dir, "fault.sh"), filepath.Join(dir, "undo.sh")
+	require.NoError(t, os.WriteFile(fault, []byte("tc qdisc add dev eth0 root netem loss 10%"), 0o600))
+	require.NoError(t, os.WriteFile(undo, []byte("tc qdisc del dev eth0 root"), 0o600))
+	cmd := NewExecCLICommand(context.Background(), nilRuntime())
+	flags := []cli.Flag{
+		findFlag(t, cmd, "script"), findFlag(t, cmd, "interpreter"), findFlag(t, cmd, "undo-script"), findFlag(t, cmd, "duration"),
+	}
+	c := newTestCLIContext(t, flags, []string{"--script", fault, "--undo-script", undo, "--duration", "30s"})
+	got, err := parseExecParams(cliflags.NewV1(c), defaultGlobalParams())
+	require.NoError(t, err)
+	assert.Equal(t, &lifecycle.Script{Name: "fault.sh", Content: []byte("tc qdisc add dev eth0 root netem loss 10%")}, got.Options.Script)
+	assert.Equal(t, &lifecycle.Script{Name: "undo.sh", Content: []byte("tc qdisc del dev eth0 root")}, got.Options.UndoScript)
+	assert.Equal(t, "sh", got.Options.Interpreter)
+	assert.Equal(t, 30*time.Second, got.Options.Duration)
+
+	for _, args := range [][]string{
+		{"--undo-script", undo, "--duration", "30s"},
+		{"--script", fault, "--duration", "30s"},
+		{"--script", fault, "--undo-script", undo},
+		{"--script", fault, "--undo-script", undo, "--duration", "1m"},
+		{"--script", fault, "--interpreter", ""},
+		{"--script", filepath.Join(dir, "missing.sh")},
+	} {
+		c = newTestCLIContext(t, flags, args)
+		_, err = parseExecParams(cliflags.NewV1(c), &chaos.GlobalParams{Interval: time.Minute})
+		assert.Error(t, err, args)
+	}
+}
+
 func TestBuildExecCommand(t *testing.T) {
 	client := container.NewMockClient(t)
 	cmd, err := buildExecCommand(client, defaultGlobalParams(),
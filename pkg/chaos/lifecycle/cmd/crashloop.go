@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/lifecycle"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	"github.com/urfave/cli"
+)
+
+// CrashloopParams holds the per-command parameters for the crashloop CLI subcommand.
+type CrashloopParams struct {
+	Signal         string
+	Count          int
+	Delay          time.Duration
+	RestartTimeout time.Duration
+	Limit          int
+}
+
+// NewCrashloopCLICommand initialize CLI crashloop command.
+func NewCrashloopCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[CrashloopParams]{
+		Name: "crashloop",
+		Flags: []cli.Flag{
+			cliflags.Choices(cli.StringFlag{
+				Name:  "signal, s",
+				Usage: "termination signal, that will be sent by Pumba to the main process inside target container(s); 'SIGTERM|SIGKILL' picks one per run",
+				Value: lifecycle.DefaultKillSignal,
+			}),
+			cli.IntFlag{
+				Name:  "count, c",
+				Usage: "number of kills of every target container",
+				Value: lifecycle.DefaultCrashloopCount,
+			},
+			cli.DurationFlag{
+				Name:  "delay, d",
+				Usage: "wait between a container coming back and the next kill; use with optional unit suffix: 'ms/s/m/h'",
+			},
+			cli.DurationFlag{
+				Name:  "restart-timeout, t",
+				Usage: "fail when a killed container does not come back within this time; use with optional unit suffix: 'ms/s/m/h'",
+				Value: lifecycle.DefaultRestartTimeout,
+			},
+			cli.IntFlag{
+				Name:  "limit, l",
+				Usage: "limit number of container to crash loop (0: crash loop all matching)",
+				Value: 0,
+			},
+		},
+		Usage:       "drive containers into a crash loop",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q)", chaos.Re2Prefix),
+		Description: "kill target container(s) again and again: after every kill, wait for the runtime or orchestrator to restart the container, then kill it again after a delay; tests restart policies, restart backoff and alerting",
+		RequireArgs: true,
+		Parse:       parseCrashloopParams,
+		Build:       buildCrashloopCommand,
+	})
+}
+
+func parseCrashloopParams(c cliflags.Flags, _ *chaos.GlobalParams) (CrashloopParams, error) {
+	return CrashloopParams{
+		Signal:         c.String("signal"),
+		Count:          c.Int("count"),
+		Delay:          c.Duration("delay"),
+		RestartTimeout: c.Duration("restart-timeout"),
+		Limit:          c.Int("limit"),
+	}, nil
+}
+
+func buildCrashloopCommand(client container.Client, gp *chaos.GlobalParams, p CrashloopParams) (chaos.Command, error) {
+	var subscribe lifecycle.Subscribe
+	if inv, ok := inventory.Find(client); ok {
+		subscribe = inv.Subscribe
+	} else if !gp.DryRun {
+		return nil, errors.New("crashloop: the container inventory is not running")
+	}
+	return lifecycle.NewCrashloopCommand(client, subscribe, gp, p.Signal, p.Count, p.Delay, p.RestartTimeout, p.Limit)
+}
@@ -2,7 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
@@ -16,6 +22,7 @@ import (
 type ExecParams struct {
 	Command string
 	Args    []string
+	Options lifecycle.ExecOptions
 	Limit   int
 }
 
@@ -38,23 +45,136 @@ func NewExecCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command
 				Usage: "limit number of container to exec (0: exec all matching)",
 				Value: 0,
 			},
+			cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "fail when the command runs longer; use with optional unit suffix: 'ms/s/m/h' (0: no timeout)",
+			},
+			cli.StringFlag{
+				Name:  "expect-exit",
+				Usage: "exit codes the command may end with, comma separated, or 'any'",
+				Value: "0",
+			},
+			cli.StringFlag{
+				Name:  "expect-output",
+				Usage: "RE2 regex the stdout of the command must match",
+			},
+			cli.StringFlag{
+				Name:  "output-file",
+				Usage: "append the stdout, stderr, exit code and duration of every run to this file, as JSON lines",
+			},
+			cli.StringFlag{
+				Name:  "script",
+				Usage: "local script to copy into the target container(s) and run with --interpreter instead of --command; --args go to the script",
+			},
+			cli.StringFlag{
+				Name:  "interpreter",
+				Usage: "interpreter running --script and --undo-script inside the target container(s)",
+				Value: "sh",
+			},
+			cli.StringFlag{
+				Name:  "undo-script",
+				Usage: "local script to run the same way after --duration, reverting --script",
+			},
+			cli.DurationFlag{
+				Name:  "duration, d",
+				Usage: "time to wait before running --undo-script; use with optional unit suffix: 'ms/s/m/h'",
+			},
 		},
 		Usage:       "exec specified containers",
 		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q)", chaos.Re2Prefix),
-		Description: "send command to target container(s)",
+		Description: "send command to target container(s); with --expect-exit and --expect-output, fail when the command does not end as expected, for exec as a verification probe; with --script, copy a local script into the target container(s) and run it, and with --undo-script, revert it after --duration",
 		Parse:       parseExecParams,
 		Build:       buildExecCommand,
 	})
 }
 
-func parseExecParams(c cliflags.Flags, _ *chaos.GlobalParams) (ExecParams, error) {
+func parseExecParams(c cliflags.Flags, gp *chaos.GlobalParams) (ExecParams, error) {
+	opts := lifecycle.ExecOptions{Timeout: c.Duration("timeout"), OutputFile: c.String("output-file")}
+	if opts.Timeout < 0 {
+		return ExecParams{}, errors.New("timeout must not be negative")
+	}
+	var err error
+	if opts.ExitCodes, opts.AnyExit, err = parseExitCodes(c.String("expect-exit")); err != nil {
+		return ExecParams{}, err
+	}
+	if expr := c.String("expect-output"); expr != "" {
+		if opts.Output, err = regexp.Compile(expr); err != nil {
+			return ExecParams{}, fmt.Errorf("bad --expect-output regular expression: %w", err)
+		}
+	}
+	if err = parseScripts(c, gp, &opts); err != nil {
+		return ExecParams{}, err
+	}
 	return ExecParams{
 		Command: c.String("command"),
 		Args:    c.StringSlice("args"),
+		Options: opts,
 		Limit:   c.Int("limit"),
 	}, nil
 }
 
+// parseExitCodes parses --expect-exit: comma separated exit codes, or "any";
+// empty leaves the default, 0.
+func parseExitCodes(s string) ([]int, bool, error) {
+	switch strings.TrimSpace(s) {
+	case "":
+		return nil, false, nil
+	case "any":
+		return nil, true, nil
+	}
+	var codes []int
+	for field := range strings.SplitSeq(s, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || code < 0 || code > 255 {
+			return nil, false, fmt.Errorf("bad --expect-exit %q: want exit codes 0-255, comma separated, or 'any'", s)
+		}
+		codes = append(codes, code)
+	}
+	return codes, false, nil
+}
+
+// parseScripts reads the --script and --undo-script files into opts.
+func parseScripts(c cliflags.Flags, gp *chaos.GlobalParams, opts *lifecycle.ExecOptions) error {
+	script, undo := c.String("script"), c.String("undo-script")
+	opts.Duration = c.Duration("duration")
+	switch {
+	case script == "" && (undo != "" || opts.Duration != 0):
+		return errors.New("--undo-script and --duration need --script")
+	case undo == "" && opts.Duration != 0:
+		return errors.New("--duration needs --undo-script")
+	case undo != "" && opts.Duration <= 0:
+		return errors.New("--undo-script needs a positive --duration")
+	case gp.Interval != 0 && opts.Duration >= gp.Interval:
+		return errors.New("duration must be shorter than interval")
+	}
+	if script == "" {
+		return nil
+	}
+	opts.Interpreter = c.String("interpreter")
+	if opts.Interpreter == "" {
+		return errors.New("--script needs an --interpreter")
+	}
+	var err error
+	if opts.Script, err = readScript(script); err != nil {
+		return err
+	}
+	if undo != "" {
+		if opts.UndoScript, err = readScript(undo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readScript reads a local script.
+func readScript(file string) (*lifecycle.Script, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return &lifecycle.Script{Name: filepath.Base(file), Content: content}, nil
+}
+
 func buildExecCommand(client container.Client, gp *chaos.GlobalParams, p ExecParams) (chaos.Command, error) {
-	return lifecycle.NewExecCommand(client, gp, p.Command, p.Args, p.Limit), nil
+	return lifecycle.NewExecCommand(client, gp, p.Command, p.Args, p.Options, p.Limit), nil
 }
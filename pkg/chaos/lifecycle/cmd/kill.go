@@ -14,29 +14,30 @@ import (
 
 // KillParams holds the per-command parameters for the kill CLI subcommand.
 type KillParams struct {
-	Signal string
-	Limit  int
+	Signal    string
+	Processes *lifecycle.Processes
+	Limit     int
 }
 
 // NewKillCLICommand initialize CLI kill command.
 func NewKillCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
 	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[KillParams]{
 		Name: "kill",
-		Flags: []cli.Flag{
-			cli.StringFlag{
+		Flags: append([]cli.Flag{
+			cliflags.Choices(cli.StringFlag{
 				Name:  "signal, s",
-				Usage: "termination signal, that will be sent by Pumba to the main process inside target container(s)",
+				Usage: "termination signal, that will be sent by Pumba to the main process inside target container(s); 'SIGTERM|SIGKILL' picks one per run",
 				Value: lifecycle.DefaultKillSignal,
-			},
+			}),
 			cli.IntFlag{
 				Name:  "limit, l",
 				Usage: "limit number of container to kill (0: kill all matching)",
 				Value: 0,
 			},
-		},
+		}, processFlags...),
 		Usage:       "kill specified containers",
 		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q)", chaos.Re2Prefix),
-		Description: "send termination signal to the main process inside target container(s)",
+		Description: "send termination signal to the main process inside target container(s) or, with --process, to the matching processes",
 		RequireArgs: true,
 		Parse:       parseKillParams,
 		Build:       buildKillCommand,
@@ -44,12 +45,17 @@ func NewKillCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command
 }
 
 func parseKillParams(c cliflags.Flags, _ *chaos.GlobalParams) (KillParams, error) {
+	processes, err := parseProcesses(c)
+	if err != nil {
+		return KillParams{}, err
+	}
 	return KillParams{
-		Signal: c.String("signal"),
-		Limit:  c.Int("limit"),
+		Signal:    c.String("signal"),
+		Processes: processes,
+		Limit:     c.Int("limit"),
 	}, nil
 }
 
 func buildKillCommand(client container.Client, gp *chaos.GlobalParams, p KillParams) (chaos.Command, error) {
-	return lifecycle.NewKillCommand(client, gp, p.Signal, p.Limit)
+	return lifecycle.NewKillCommand(client, gp, p.Signal, p.Processes, p.Limit)
 }
@@ -16,15 +16,16 @@ import (
 
 // PauseParams holds the per-command parameters for the pause CLI subcommand.
 type PauseParams struct {
-	Duration time.Duration
-	Limit    int
+	Duration  time.Duration
+	Processes *lifecycle.Processes
+	Limit     int
 }
 
 // NewPauseCLICommand initialize CLI pause command.
 func NewPauseCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
 	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[PauseParams]{
 		Name: "pause",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			cli.StringFlag{
 				Name:  "duration, d",
 				Usage: "pause duration: must be shorter than recurrent interval; use with optional unit suffix: 'ms/s/m/h'",
@@ -34,10 +35,10 @@ func NewPauseCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command
 				Usage: "limit number of container to pause (0: pause all matching)",
 				Value: 0,
 			},
-		},
+		}, processFlags...),
 		Usage:       "pause all processes",
 		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
-		Description: "pause all running processes within target containers",
+		Description: "pause all running processes within target containers or, with --process, stop the matching processes with SIGSTOP and resume them with SIGCONT",
 		Parse:       parsePauseParams,
 		Build:       buildPauseCommand,
 	})
@@ -48,12 +49,20 @@ func parsePauseParams(c cliflags.Flags, _ *chaos.GlobalParams) (PauseParams, err
 	if duration == 0 {
 		return PauseParams{}, errors.New("unset or invalid duration value")
 	}
+	processes, err := parseProcesses(c)
+	if err != nil {
+		return PauseParams{}, err
+	}
 	return PauseParams{
-		Duration: duration,
-		Limit:    c.Int("limit"),
+		Duration:  duration,
+		Processes: processes,
+		Limit:     c.Int("limit"),
 	}, nil
 }
 
 func buildPauseCommand(client container.Client, gp *chaos.GlobalParams, p PauseParams) (chaos.Command, error) {
-	return lifecycle.NewPauseCommand(client, gp, p.Duration, p.Limit), nil
+	if err := p.Processes.Validate(); err != nil {
+		return nil, err
+	}
+	return lifecycle.NewPauseCommand(client, gp, p.Duration, p.Processes, p.Limit), nil
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/chaos/lifecycle"
+	"github.com/urfave/cli"
+)
+
+// processFlags select processes inside the target containers for kill and
+// pause.
+var processFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "process",
+		Usage: "RE2 regex matching the name or command line of the processes to signal inside target container(s), instead of the container's main process",
+	},
+	cli.StringFlag{
+		Name:  "process-image",
+		Usage: "Docker image with a shell, run as a sidecar sharing the target's PID namespace to find and signal --process; empty: run the shell inside the target container",
+	},
+	cli.BoolTFlag{
+		Name:  "pull-image",
+		Usage: "force pull process-image",
+	},
+}
+
+// parseProcesses reads processFlags; it returns nil without --process.
+func parseProcesses(c cliflags.Flags) (*lifecycle.Processes, error) {
+	regex := c.String("process")
+	if regex == "" {
+		return nil, nil //nolint:nilnil
+	}
+	if _, err := regexp.Compile(regex); err != nil {
+		return nil, fmt.Errorf("bad --process regular expression: %w", err)
+	}
+	return &lifecycle.Processes{Regex: regex, Image: c.String("process-image"), Pull: c.BoolT("pull-image")}, nil
+}
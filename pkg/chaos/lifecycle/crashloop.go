@@ -0,0 +1,225 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCrashloopCount is the default number of kills of a crash loop.
+	DefaultCrashloopCount = 3
+	// DefaultRestartTimeout is the default wait for a killed container to
+	// come back.
+	DefaultRestartTimeout = time.Minute
+)
+
+// slotLabels are the label sets orchestrators give a container and the one
+// replacing it: the container of a Kubernetes pod, and the numbered
+// container of a Compose service.
+var slotLabels = [][]string{
+	{"io.kubernetes.pod.namespace", "io.kubernetes.pod.name", "io.kubernetes.container.name"},
+	{"com.docker.compose.project", "com.docker.compose.service", "com.docker.compose.container-number"},
+}
+
+// Subscribe returns the changes of the container inventory until ctx is
+// done.
+type Subscribe func(ctx context.Context) <-chan inventory.Change
+
+// crashloopClient is the narrow interface needed by the crashloop command.
+type crashloopClient interface {
+	container.Lister
+	KillContainer(context.Context, *container.Container, string, bool) error
+}
+
+// crashloop command: kill a container, wait for the runtime or orchestrator to
+// restart it, and kill it again
+type crashloopCommand struct {
+	client         crashloopClient
+	subscribe      Subscribe
+	names          []string
+	pattern        string
+	labels         []string
+	hooks          *chaos.Hooks
+	rollout        *chaos.Rollout
+	signal         string
+	count          int
+	delay          time.Duration
+	restartTimeout time.Duration
+	limit          int
+	dryRun         bool
+}
+
+// NewCrashloopCommand create new Crashloop Command instance; it waits for
+// restarts on the changes of subscribe.
+func NewCrashloopCommand(client crashloopClient, subscribe Subscribe, params *chaos.GlobalParams, signal string, count int, delay, restartTimeout time.Duration, limit int) (chaos.Command, error) {
+	loop := &crashloopCommand{
+		client:         client,
+		subscribe:      subscribe,
+		names:          params.Names,
+		pattern:        params.Pattern,
+		labels:         params.Labels,
+		hooks:          params.Hooks,
+		rollout:        params.Rollout,
+		signal:         signal,
+		count:          count,
+		delay:          delay,
+		restartTimeout: restartTimeout,
+		limit:          limit,
+		dryRun:         params.DryRun,
+	}
+	if loop.signal == "" {
+		loop.signal = DefaultKillSignal
+	}
+	if _, ok := linuxSignals[loop.signal]; !ok {
+		return nil, fmt.Errorf("undefined Linux signal: %s", signal)
+	}
+	if count < 1 {
+		return nil, errors.New("count must be at least 1")
+	}
+	if delay < 0 || restartTimeout <= 0 {
+		return nil, errors.New("delay must not be negative and restart timeout must be positive")
+	}
+	return loop, nil
+}
+
+// Run crashloop command: every target container runs its own crash loop
+func (k *crashloopCommand) Run(ctx context.Context, random bool) error {
+	log.WithFields(log.Fields{
+		"names":           k.names,
+		"pattern":         k.pattern,
+		"labels":          k.labels,
+		"signal":          k.signal,
+		"count":           k.count,
+		"delay":           k.delay,
+		"restart-timeout": k.restartTimeout,
+		"limit":           k.limit,
+		"random":          random,
+	}).Debug("crash looping all matching containers")
+	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels, Hooks: k.hooks, Rollout: k.rollout}
+	return chaos.RunOnContainers(ctx, k.client, gp, k.limit, random, true, k.crashloop)
+}
+
+// crashloop kills c count times, each time after it, or a container
+// replacing it, restarted and the delay passed; it ends early, without
+// error, when ctx is done.
+func (k *crashloopCommand) crashloop(ctx context.Context, c *container.Container) error {
+	for kill := 1; kill <= k.count; kill++ {
+		if kill > 1 && !sleep(ctx, k.delay) {
+			return nil
+		}
+		restarted, err := k.killAndWait(ctx, c, kill)
+		if err != nil || restarted == nil {
+			return err
+		}
+		c = restarted
+	}
+	return nil
+}
+
+// killAndWait kills c and returns it, or the container replacing it, once
+// it runs again; c itself in a dry run and nil when ctx is done.
+func (k *crashloopCommand) killAndWait(ctx context.Context, c *container.Container, kill int) (*container.Container, error) {
+	// subscribed before the kill, so the restart cannot be missed
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var changes <-chan inventory.Change
+	if !k.dryRun {
+		changes = k.subscribe(waitCtx)
+	}
+	killed := time.Now()
+	if err := k.client.KillContainer(ctx, c, k.signal, k.dryRun); err != nil {
+		return nil, fmt.Errorf("failed to kill container (kill %d of %d): %w", kill, k.count, err)
+	}
+	fields := log.Fields{"container": c, "signal": k.signal, "kill": kill, "count": k.count}
+	log.WithFields(fields).Info("crash loop: killed container")
+	if k.dryRun {
+		return c, nil
+	}
+	restarted, err := k.waitRestart(ctx, changes, c)
+	if restarted != nil {
+		log.WithFields(fields).WithFields(log.Fields{"after": time.Since(killed).Round(time.Millisecond), "id": restarted.ID()}).
+			Info("crash loop: container restarted")
+	}
+	return restarted, err
+}
+
+// waitRestart follows changes until the killed c runs again: started later
+// than c was or, with runtimes that do not report start times, after it
+// died. A container replacing c, newer and of the same name or slot labels,
+// counts as c restarted. It returns nil without error when ctx is done.
+func (k *crashloopCommand) waitRestart(ctx context.Context, changes <-chan inventory.Change, c *container.Container) (*container.Container, error) {
+	timeout := time.NewTimer(k.restartTimeout)
+	defer timeout.Stop()
+	died, removed := false, false
+	for {
+		var change inventory.Change
+		select {
+		case <-ctx.Done():
+			return nil, nil //nolint:nilnil
+		case <-timeout.C:
+			if removed {
+				return nil, fmt.Errorf("container %s was removed after the kill and not replaced within %s", c.Name(), k.restartTimeout)
+			}
+			return nil, fmt.Errorf("container %s did not restart within %s", c.Name(), k.restartTimeout)
+		case ch, ok := <-changes:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil, nil //nolint:nilnil
+				}
+				return nil, errors.New("the container event stream ended")
+			}
+			change = ch
+		}
+		x := change.Container
+		switch {
+		case change.ContainerID == c.ID() && x == nil:
+			removed = true
+		case change.ContainerID == c.ID() && x.State != container.StateRunning:
+			died = true
+		case change.ContainerID == c.ID():
+			if died || x.Started.After(c.Started) {
+				return x, nil
+			}
+		case x != nil && x.State == container.StateRunning && replaces(x, c):
+			return x, nil
+		}
+	}
+}
+
+// replaces reports whether x is a container created after c in its place:
+// of the same name, or with the same slot labels.
+func replaces(x, c *container.Container) bool {
+	if !x.Created.After(c.Created) {
+		return false
+	}
+	if x.Name() == c.Name() {
+		return true
+	}
+	for _, keys := range slotLabels {
+		if slices.ContainsFunc(keys, func(key string) bool { v, ok := c.Labels[key]; return !ok || x.Labels[key] != v }) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// sleep waits for d, reporting false when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,166 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// events is a subscription to the container inventory, replaying changes
+// to every subscriber as its subscription starts: per kill, the changes
+// after it.
+type events struct {
+	mu      sync.Mutex
+	changes [][]inventory.Change
+}
+
+func (e *events) subscribe(ctx context.Context) <-chan inventory.Change {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch := make(chan inventory.Change, 8)
+	if len(e.changes) > 0 {
+		for _, c := range e.changes[0] {
+			ch <- c
+		}
+		e.changes = e.changes[1:]
+	}
+	context.AfterFunc(ctx, func() { close(ch) })
+	return ch
+}
+
+func change(kind string, c *container.Container) inventory.Change {
+	return inventory.Change{Event: container.Event{Kind: kind, ContainerID: c.ID()}, Container: c}
+}
+
+func withState(c *container.Container, state string, started time.Time) *container.Container {
+	x := *c
+	x.State, x.Started = state, started
+	return &x
+}
+
+func TestCrashloopCommand_Run(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning, Started: time.Unix(1, 0)}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, mock.Anything, "SIGTERM", false).Return(nil).Times(2)
+	// the first restart is known from its start time, the second from the
+	// container seen stopped; a stale start is no restart
+	e := &events{changes: [][]inventory.Change{
+		{change(container.EventHealthy, c), change(container.EventStart, withState(c, container.StateRunning, time.Unix(2, 0)))},
+		{change(container.EventDie, withState(c, container.StateExited, time.Time{})), change(container.EventStart, withState(c, container.StateRunning, time.Time{}))},
+	}}
+
+	cmd, err := NewCrashloopCommand(client, e.subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "SIGTERM", 2, time.Millisecond, time.Second, 0)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.TODO(), false))
+}
+
+func TestCrashloopCommand_Replaced(t *testing.T) {
+	labels := map[string]string{
+		"io.kubernetes.pod.namespace": "shop", "io.kubernetes.pod.name": "api-0", "io.kubernetes.container.name": "api",
+	}
+	c := &container.Container{ContainerID: "id1", ContainerName: "/k8s_api_0", Labels: labels, State: container.StateRunning, Created: time.Unix(1, 0)}
+	other := &container.Container{ContainerID: "id9", ContainerName: "/k8s_api_9", State: container.StateRunning, Created: time.Unix(2, 0),
+		Labels: map[string]string{"io.kubernetes.pod.namespace": "shop", "io.kubernetes.pod.name": "api-1", "io.kubernetes.container.name": "api"}}
+	byLabels := &container.Container{ContainerID: "id2", ContainerName: "/k8s_api_1", Labels: labels, State: container.StateRunning, Created: time.Unix(2, 0)}
+	byName := &container.Container{ContainerID: "id3", ContainerName: "/k8s_api_1", State: container.StateRunning, Created: time.Unix(3, 0)}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", false).Return(nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, byLabels, "SIGKILL", false).Return(nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, byName, "SIGKILL", false).Return(nil).Once()
+	e := &events{changes: [][]inventory.Change{
+		{{Event: container.Event{Kind: container.EventDestroy, ContainerID: "id1"}}, change(container.EventStart, other), change(container.EventStart, byLabels)},
+		{change(container.EventStart, byName)},
+		{change(container.EventStart, withState(byName, container.StateRunning, time.Unix(4, 0)))},
+	}}
+
+	cmd, err := NewCrashloopCommand(client, e.subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "", 3, 0, time.Second, 0)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.TODO(), false), "the replacement of a killed container is crash looped on")
+}
+
+func TestCrashloopCommand_RestartTimeout(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", false).Return(nil).Once()
+	e := &events{changes: [][]inventory.Change{{change(container.EventDie, withState(c, container.StateExited, time.Time{}))}}}
+
+	cmd, err := NewCrashloopCommand(client, e.subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "", 3, 0, 20*time.Millisecond, 0)
+	require.NoError(t, err)
+	require.EqualError(t, cmd.Run(context.TODO(), false), "container /api did not restart within 20ms")
+}
+
+func TestCrashloopCommand_Removed(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", false).Return(nil).Once()
+	e := &events{changes: [][]inventory.Change{{{Event: container.Event{Kind: container.EventDestroy, ContainerID: "id1"}}}}}
+
+	cmd, err := NewCrashloopCommand(client, e.subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "", 3, 0, 20*time.Millisecond, 0)
+	require.NoError(t, err)
+	require.EqualError(t, cmd.Run(context.TODO(), false), "container /api was removed after the kill and not replaced within 20ms")
+}
+
+func TestCrashloopCommand_DryRun(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", true).Return(nil).Times(3)
+
+	cmd, err := NewCrashloopCommand(client, nil, &chaos.GlobalParams{Names: []string{"api"}, DryRun: true}, "", 3, 0, time.Second, 0)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.TODO(), false), "a dry run does not wait for restarts")
+}
+
+func TestCrashloopCommand_Canceled(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning}
+	ctx, cancel := context.WithCancel(context.TODO())
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", false).
+		RunAndReturn(func(context.Context, *container.Container, string, bool) error {
+			cancel()
+			return nil
+		}).Once()
+
+	cmd, err := NewCrashloopCommand(client, (&events{}).subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "", 3, 0, time.Second, 0)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(ctx, false), "a stopped crash loop is no failure")
+}
+
+func TestCrashloopCommand_KillError(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api", State: container.StateRunning}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().KillContainer(mock.Anything, c, "SIGKILL", false).Return(errors.New("no such container")).Once()
+
+	cmd, err := NewCrashloopCommand(client, (&events{}).subscribe, &chaos.GlobalParams{Names: []string{"api"}}, "", 3, 0, time.Second, 0)
+	require.NoError(t, err)
+	require.EqualError(t, cmd.Run(context.TODO(), false), "failed to kill container (kill 1 of 3): no such container")
+}
+
+func TestNewCrashloopCommand_Errors(t *testing.T) {
+	gp := &chaos.GlobalParams{}
+	_, err := NewCrashloopCommand(nil, nil, gp, "SIGNONE", 3, 0, time.Second, 0)
+	require.EqualError(t, err, "undefined Linux signal: SIGNONE")
+	_, err = NewCrashloopCommand(nil, nil, gp, "", 0, 0, time.Second, 0)
+	require.EqualError(t, err, "count must be at least 1")
+	_, err = NewCrashloopCommand(nil, nil, gp, "", 3, 0, 0, 0)
+	require.EqualError(t, err, "delay must not be negative and restart timeout must be positive")
+
+	cmd, err := NewCrashloopCommand(nil, nil, gp, "", 3, time.Second, time.Minute, 2)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKillSignal, cmd.(*crashloopCommand).signal)
+}
@@ -2,7 +2,16 @@ package lifecycle
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/container"
@@ -15,27 +24,86 @@ type execClient interface {
 	container.Executor
 }
 
+// ExecOptions bound and check the command run by the exec command, making it
+// a verification probe as well as a fault injector.
+type ExecOptions struct {
+	// Timeout bounds every run of the command; 0 leaves it unbounded.
+	Timeout time.Duration
+	// ExitCodes are the exit codes the command may end with: only 0 when
+	// empty, any with AnyExit.
+	ExitCodes []int
+	AnyExit   bool
+	// Output, when set, must match the stdout of the command.
+	Output *regexp.Regexp
+	// OutputFile, when set, gets a JSON line with the result of every run.
+	OutputFile string
+	// Script, when set, is copied into every target and run there with
+	// Interpreter in place of the command; the args go to the script.
+	Script      *Script
+	Interpreter string
+	// UndoScript, when set, is run the same way Duration after Script, or
+	// when the command is stopped first, in the targets Script ran in.
+	UndoScript *Script
+	Duration   time.Duration
+}
+
+// Script is a local script run inside the targets.
+type Script struct {
+	Name    string
+	Content []byte
+}
+
+// scriptDir is the directory of the targets scripts are copied to.
+const (
+	scriptDir  = "/tmp"
+	scriptMode = 0o700
+)
+
+// execRecord is a line of the --output-file of the exec command.
+type execRecord struct {
+	Time        time.Time `json:"time"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Command     []string  `json:"command"`
+	Stdout      string    `json:"stdout"`
+	Stderr      string    `json:"stderr"`
+	ExitCode    int       `json:"exit_code"`
+	Duration    string    `json:"duration"`
+	Error       string    `json:"error,omitempty"`
+	TimedOut    bool      `json:"timed_out,omitempty"`
+	CheckFailed string    `json:"check_failed,omitempty"`
+}
+
 // `docker exec` command
 type execCommand struct {
 	client  execClient
 	names   []string
 	pattern string
 	labels  []string
+	hooks   *chaos.Hooks
+	rollout *chaos.Rollout
 	command string
 	args    []string
+	opts    ExecOptions
 	limit   int
 	dryRun  bool
+	// mu serializes writes to opts.OutputFile and guards scripted
+	mu       sync.Mutex
+	scripted []*container.Container
 }
 
 // NewExecCommand create new Exec Command instance
-func NewExecCommand(client execClient, params *chaos.GlobalParams, command string, args []string, limit int) chaos.Command {
+func NewExecCommand(client execClient, params *chaos.GlobalParams, command string, args []string, opts ExecOptions, limit int) chaos.Command {
 	exec := &execCommand{
 		client:  client,
 		names:   params.Names,
 		pattern: params.Pattern,
 		labels:  params.Labels,
+		hooks:   params.Hooks,
+		rollout: params.Rollout,
 		command: command,
 		args:    args,
+		opts:    opts,
 		limit:   limit,
 		dryRun:  params.DryRun,
 	}
@@ -55,13 +123,168 @@ func (k *execCommand) Run(ctx context.Context, random bool) error {
 		"limit":   k.limit,
 		"random":  random,
 	}).Debug("listing matching containers")
-	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels}
-	return chaos.RunOnContainers(ctx, k.client, gp, k.limit, random, false,
-		func(ctx context.Context, c *container.Container) error {
-			log.WithFields(log.Fields{"c": *c, "command": k.command, "args": k.args}).Debug("execing c")
-			if err := k.client.ExecContainer(ctx, c, k.command, k.args, k.dryRun); err != nil {
-				return fmt.Errorf("failed to run exec command: %w", err)
-			}
-			return nil
-		})
+	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels, Hooks: k.hooks, Rollout: k.rollout}
+	k.scripted = nil
+	err := chaos.RunOnContainers(ctx, k.client, gp, k.limit, random, false, k.exec)
+	if k.opts.UndoScript == nil || len(k.scripted) == 0 {
+		return err
+	}
+	// wait for the duration and then undo the scripts, or undo them on ctx.Done()
+	durationTimer := time.NewTimer(k.opts.Duration)
+	defer durationTimer.Stop()
+	select {
+	case <-ctx.Done():
+		log.Debug("undo exec scripts by stop event")
+	case <-durationTimer.C:
+		log.WithField("duration", k.opts.Duration).Debug("undo exec scripts after duration")
+	}
+	// the cleanup succeeds even if the parent ctx is canceled
+	ctx = container.WithCleanup(ctx)
+	for _, c := range k.scripted {
+		if undoErr := k.undo(ctx, c); undoErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to undo exec script: %w", undoErr))
+		}
+	}
+	return err
+}
+
+// exec runs the command, or the script, in c and checks its result.
+func (k *execCommand) exec(ctx context.Context, c *container.Container) error {
+	if k.opts.Script == nil {
+		return k.run(ctx, c, k.command, k.args)
+	}
+	path, err := k.copyScript(ctx, c, k.opts.Script)
+	if err != nil {
+		return err
+	}
+	defer k.removeScript(ctx, c, path)
+	k.mu.Lock()
+	k.scripted = append(k.scripted, c)
+	k.mu.Unlock()
+	return k.run(ctx, c, k.opts.Interpreter, append([]string{path}, k.args...))
+}
+
+// undo runs the undo script in c; it must exit 0.
+func (k *execCommand) undo(ctx context.Context, c *container.Container) error {
+	path, err := k.copyScript(ctx, c, k.opts.UndoScript)
+	if err != nil {
+		return err
+	}
+	defer k.removeScript(ctx, c, path)
+	if k.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(container.WithExecTimeout(ctx, k.opts.Timeout), k.opts.Timeout)
+		defer cancel()
+	}
+	result, err := k.client.ExecContainer(ctx, c, k.opts.Interpreter, append([]string{path}, k.args...), k.dryRun)
+	if err != nil {
+		return err
+	}
+	return result.Err(k.opts.UndoScript.Name)
+}
+
+// copyScript copies script into c, returning its path there.
+func (k *execCommand) copyScript(ctx context.Context, c *container.Container, script *Script) (string, error) {
+	// a random name keeps concurrent runs and leftovers from clashing
+	path := fmt.Sprintf("%s/pumba-%08x-%s", scriptDir, rand.Uint32(), script.Name) //nolint:gosec
+	req := &container.CopyRequest{Container: c, Path: path, Content: script.Content, Mode: scriptMode, DryRun: k.dryRun}
+	if err := k.client.CopyToContainer(ctx, req); err != nil {
+		return "", fmt.Errorf("failed to copy script %s: %w", script.Name, err)
+	}
+	return path, nil
+}
+
+// removeScript removes a copied script from c, logging failures: it does not
+// change the outcome of the run.
+func (k *execCommand) removeScript(ctx context.Context, c *container.Container, path string) {
+	result, err := k.client.ExecContainer(container.WithCleanup(ctx), c, "rm", []string{"-f", path}, k.dryRun)
+	if err == nil {
+		err = result.Err("rm")
+	}
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"container": c, "path": path}).Warn("failed to remove exec script")
+	}
+}
+
+// run runs command in c and checks its result.
+func (k *execCommand) run(ctx context.Context, c *container.Container, command string, args []string) error {
+	log.WithFields(log.Fields{"c": *c, "command": command, "args": args}).Debug("execing c")
+	execCtx := ctx
+	if k.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(container.WithExecTimeout(ctx, k.opts.Timeout), k.opts.Timeout)
+		defer cancel()
+	}
+	result, err := k.client.ExecContainer(execCtx, c, command, args, k.dryRun)
+	timedOut := err != nil && ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded)
+	switch {
+	case timedOut:
+		err = fmt.Errorf("failed to run exec command: timed out after %s", k.opts.Timeout)
+	case err != nil:
+		err = fmt.Errorf("failed to run exec command: %w", err)
+	case result == nil:
+		// dry run: nothing ran, nothing to check
+		return nil
+	}
+	checkErr := k.check(command, result)
+	return errors.Join(err, checkErr, k.write(c, append([]string{command}, args...), result, err, timedOut, checkErr))
+}
+
+// check matches result against the expected exit codes and output.
+func (k *execCommand) check(command string, result *container.ExecResult) error {
+	if result == nil {
+		return nil
+	}
+	codes := k.opts.ExitCodes
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+	if !k.opts.AnyExit && !slices.Contains(codes, result.ExitCode) {
+		if msg := strings.TrimSpace(result.Stderr); msg != "" {
+			return fmt.Errorf("exec %s exited with code %d, expected %v: %s", command, result.ExitCode, codes, msg)
+		}
+		return fmt.Errorf("exec %s exited with code %d, expected %v", command, result.ExitCode, codes)
+	}
+	if k.opts.Output != nil && !k.opts.Output.MatchString(result.Stdout) {
+		return fmt.Errorf("exec %s output does not match %q", command, k.opts.Output.String())
+	}
+	return nil
+}
+
+// write appends the outcome of a run to the output file, when set.
+func (k *execCommand) write(c *container.Container, command []string, result *container.ExecResult, err error, timedOut bool, checkErr error) error {
+	if k.opts.OutputFile == "" {
+		return nil
+	}
+	rec := execRecord{
+		Time:     time.Now().UTC(),
+		ID:       c.ID(),
+		Name:     strings.TrimPrefix(c.Name(), "/"),
+		Command:  command,
+		TimedOut: timedOut,
+	}
+	if result != nil {
+		rec.Stdout, rec.Stderr, rec.ExitCode, rec.Duration = result.Stdout, result.Stderr, result.ExitCode, result.Duration.String()
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if checkErr != nil {
+		rec.CheckFailed = checkErr.Error()
+	}
+	line, mErr := json.Marshal(rec)
+	if mErr != nil {
+		return fmt.Errorf("failed to encode exec result: %w", mErr)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	f, oErr := os.OpenFile(k.opts.OutputFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) //nolint:mnd
+	if oErr != nil {
+		return fmt.Errorf("failed to open exec output file: %w", oErr)
+	}
+	if _, wErr := f.Write(append(line, '\n')); wErr != nil {
+		f.Close()
+		return fmt.Errorf("failed to write exec output file: %w", wErr)
+	}
+	return f.Close()
 }
@@ -2,9 +2,16 @@ package lifecycle
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/container"
@@ -146,15 +153,15 @@ func TestExecCommand_Run(t *testing.T) {
 				if tt.expected != nil {
 					execCall := mockClient.EXPECT().ExecContainer(mock.Anything, mock.AnythingOfType("*container.Container"), tt.fields.command, tt.fields.args, tt.fields.params.DryRun)
 					if tt.errs.execError {
-						execCall.Return(errors.New("ERROR")).Once()
+						execCall.Return(nil, errors.New("ERROR")).Once()
 					} else if tt.args.random {
-						execCall.Return(nil).Once()
+						execCall.Return(&container.ExecResult{}, nil).Once()
 					} else {
 						count := len(tt.expected)
 						if tt.fields.limit > 0 && tt.fields.limit < count {
 							count = tt.fields.limit
 						}
-						execCall.Return(nil).Times(count)
+						execCall.Return(&container.ExecResult{}, nil).Times(count)
 					}
 				}
 			}
@@ -206,7 +213,7 @@ func TestNewExecCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := container.NewMockClient(t)
-			got := NewExecCommand(mockClient, tt.params, tt.command, tt.args, tt.limit)
+			got := NewExecCommand(mockClient, tt.params, tt.command, tt.args, ExecOptions{}, tt.limit)
 			cmd, ok := got.(*execCommand)
 			require.True(t, ok)
 			tt.want.client = mockClient
@@ -214,3 +221,158 @@ func TestNewExecCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestExecCommand_Checks(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	tests := []struct {
+		name    string
+		opts    ExecOptions
+		result  *container.ExecResult
+		wantErr string
+	}{
+		{name: "exit 0 by default", result: &container.ExecResult{Stdout: "ok"}},
+		{
+			name:    "non-zero exit fails by default",
+			result:  &container.ExecResult{Stderr: "no such file\n", ExitCode: 2},
+			wantErr: "exec cat exited with code 2, expected [0]: no such file",
+		},
+		{name: "expected exit code", opts: ExecOptions{ExitCodes: []int{1, 2}}, result: &container.ExecResult{ExitCode: 2}},
+		{name: "any exit code", opts: ExecOptions{AnyExit: true}, result: &container.ExecResult{ExitCode: 137}},
+		{name: "output matches", opts: ExecOptions{Output: regexp.MustCompile(`status: (up|ok)`)}, result: &container.ExecResult{Stdout: "status: up\n"}},
+		{
+			name:    "output does not match",
+			opts:    ExecOptions{Output: regexp.MustCompile(`status: (up|ok)`)},
+			result:  &container.ExecResult{Stdout: "status: down\n"},
+			wantErr: `exec cat output does not match "status: (up|ok)"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := container.NewMockClient(t)
+			client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+			client.EXPECT().ExecContainer(mock.Anything, c, "cat", []string{"/status"}, false).Return(tt.result, nil).Once()
+			cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}}, "cat", []string{"/status"}, tt.opts, 0)
+			err := cmd.Run(context.TODO(), false)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestExecCommand_TimeoutAndOutputFile(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	file := filepath.Join(t.TempDir(), "exec.jsonl")
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Twice()
+	client.EXPECT().ExecContainer(mock.Anything, c, "curl", []string(nil), false).
+		Return(&container.ExecResult{Stdout: "pong", ExitCode: 0, Duration: time.Second}, nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "curl", []string(nil), false).
+		RunAndReturn(func(ctx context.Context, _ *container.Container, _ string, _ []string, _ bool) (*container.ExecResult, error) {
+			timeout, ok := container.ExecTimeout(ctx)
+			assert.True(t, ok, "the runtime learns the timeout, to kill the command")
+			assert.Equal(t, 10*time.Millisecond, timeout)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).Once()
+
+	cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}}, "curl", nil, ExecOptions{Timeout: 10 * time.Millisecond, OutputFile: file}, 0)
+	require.NoError(t, cmd.Run(context.TODO(), false))
+	require.EqualError(t, cmd.Run(context.TODO(), false), "failed to run exec command: timed out after 10ms")
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	var first, second execRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "api", first.Name)
+	assert.Equal(t, "pong", first.Stdout)
+	assert.Equal(t, "1s", first.Duration)
+	assert.True(t, second.TimedOut)
+	assert.Equal(t, "failed to run exec command: timed out after 10ms", second.Error)
+}
+
+func TestExecCommand_DryRunSkipsChecks(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "true", []string(nil), true).Return(nil, nil).Once()
+	opts := ExecOptions{Output: regexp.MustCompile("never"), OutputFile: filepath.Join(t.TempDir(), "exec.jsonl")}
+	cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}, DryRun: true}, "true", nil, opts, 0)
+	require.NoError(t, cmd.Run(context.TODO(), false))
+	assert.NoFileExists(t, opts.OutputFile)
+}
+
+// isScriptPath tells whether path is where a script called name is copied to.
+func isScriptPath(path, name string) bool {
+	return regexp.MustCompile(`^/tmp/pumba-[0-9a-f]{8}-` + regexp.QuoteMeta(name) + `$`).MatchString(path)
+}
+
+// copyOf matches the CopyRequest of script into c.
+func copyOf(c *container.Container, script *Script) any {
+	return mock.MatchedBy(func(req *container.CopyRequest) bool {
+		return req.Container == c && string(req.Content) == string(script.Content) && req.Mode == 0o700 && isScriptPath(req.Path, script.Name)
+	})
+}
+
+// runOf matches the args running a script called name, followed by args.
+func runOf(name string, args ...string) any {
+	return mock.MatchedBy(func(got []string) bool {
+		return len(got) == len(args)+1 && isScriptPath(got[0], name) && slices.Equal(got[1:], args)
+	})
+}
+
+func TestExecCommand_Script(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	script := &Script{Name: "fault.sh", Content: []byte("echo fault")}
+	undo := &Script{Name: "undo.sh", Content: []byte("echo undo")}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().CopyToContainer(mock.Anything, copyOf(c, script)).Return(nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "bash", runOf("fault.sh", "eth0"), false).Return(&container.ExecResult{}, nil).Once()
+	client.EXPECT().CopyToContainer(mock.Anything, copyOf(c, undo)).Return(nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "bash", runOf("undo.sh", "eth0"), false).Return(&container.ExecResult{}, nil).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "rm", mock.Anything, false).Return(&container.ExecResult{}, nil).Twice()
+
+	opts := ExecOptions{Script: script, UndoScript: undo, Interpreter: "bash", Duration: time.Millisecond}
+	cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}}, "", []string{"eth0"}, opts, 0)
+	require.NoError(t, cmd.Run(context.TODO(), false))
+}
+
+func TestExecCommand_ScriptUndoOnStop(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	undo := &Script{Name: "undo.sh", Content: []byte("exit 3")}
+	ctx, cancel := context.WithCancel(context.TODO())
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().CopyToContainer(mock.Anything, mock.Anything).Return(nil).Twice()
+	client.EXPECT().ExecContainer(mock.Anything, c, "sh", runOf("fault.sh"), false).RunAndReturn(func(context.Context, *container.Container, string, []string, bool) (*container.ExecResult, error) {
+		cancel()
+		return &container.ExecResult{}, nil
+	}).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "sh", runOf("undo.sh"), false).RunAndReturn(func(ctx context.Context, _ *container.Container, _ string, _ []string, _ bool) (*container.ExecResult, error) {
+		assert.NoError(t, ctx.Err(), "the undo script runs despite the stop")
+		return &container.ExecResult{ExitCode: 3}, nil
+	}).Once()
+	client.EXPECT().ExecContainer(mock.Anything, c, "rm", mock.Anything, false).Return(nil, errors.New("gone")).Twice()
+
+	opts := ExecOptions{Script: &Script{Name: "fault.sh"}, UndoScript: undo, Interpreter: "sh", Duration: time.Hour}
+	cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}}, "", nil, opts, 0)
+	require.EqualError(t, cmd.Run(ctx, false), "failed to undo exec script: undo.sh exited with code 3")
+}
+
+func TestExecCommand_ScriptCopyError(t *testing.T) {
+	c := &container.Container{ContainerID: "id1", ContainerName: "/api"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return([]*container.Container{c}, nil).Once()
+	client.EXPECT().CopyToContainer(mock.Anything, mock.Anything).Return(errors.New("read-only file system")).Once()
+
+	opts := ExecOptions{Script: &Script{Name: "fault.sh"}, UndoScript: &Script{Name: "undo.sh"}, Interpreter: "sh", Duration: time.Hour}
+	cmd := NewExecCommand(client, &chaos.GlobalParams{Names: []string{"api"}}, "", nil, opts, 0)
+	require.EqualError(t, cmd.Run(context.TODO(), false), "failed to copy script fault.sh: read-only file system",
+		"nothing to undo where the script did not run")
+}
@@ -52,29 +52,37 @@ var linuxSignals = map[string]syscall.Signal{
 type killClient interface {
 	container.Lister
 	KillContainer(context.Context, *container.Container, string, bool) error
+	processSignaler
 }
 
 // `docker kill` command
 type killCommand struct {
-	client  killClient
-	names   []string
-	pattern string
-	labels  []string
-	signal  string
-	limit   int
-	dryRun  bool
+	client    killClient
+	names     []string
+	pattern   string
+	labels    []string
+	hooks     *chaos.Hooks
+	rollout   *chaos.Rollout
+	signal    string
+	processes *Processes
+	limit     int
+	dryRun    bool
 }
 
-// NewKillCommand create new Kill Command instance
-func NewKillCommand(client killClient, params *chaos.GlobalParams, signal string, limit int) (chaos.Command, error) {
+// NewKillCommand create new Kill Command instance; with processes set, the
+// signal goes to the matching processes instead of the main process
+func NewKillCommand(client killClient, params *chaos.GlobalParams, signal string, processes *Processes, limit int) (chaos.Command, error) {
 	kill := &killCommand{
-		client:  client,
-		names:   params.Names,
-		pattern: params.Pattern,
-		labels:  params.Labels,
-		signal:  signal,
-		limit:   limit,
-		dryRun:  params.DryRun,
+		client:    client,
+		names:     params.Names,
+		pattern:   params.Pattern,
+		labels:    params.Labels,
+		hooks:     params.Hooks,
+		rollout:   params.Rollout,
+		signal:    signal,
+		processes: processes,
+		limit:     limit,
+		dryRun:    params.DryRun,
 	}
 	if kill.signal == "" {
 		kill.signal = DefaultKillSignal
@@ -82,6 +90,9 @@ func NewKillCommand(client killClient, params *chaos.GlobalParams, signal string
 	if _, ok := linuxSignals[kill.signal]; !ok {
 		return nil, fmt.Errorf("undefined Linux signal: %s", signal)
 	}
+	if err := processes.Validate(); err != nil {
+		return nil, err
+	}
 	return kill, nil
 }
 
@@ -95,9 +106,17 @@ func (k *killCommand) Run(ctx context.Context, random bool) error {
 		"limit":   k.limit,
 		"random":  random,
 	}).Debug("killing all matching containers")
-	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels}
+	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels, Hooks: k.hooks, Rollout: k.rollout}
 	return chaos.RunOnContainers(ctx, k.client, gp, k.limit, random, false,
 		func(ctx context.Context, c *container.Container) error {
+			if k.processes != nil {
+				pids, err := signalProcesses(ctx, k.client, c, k.processes, nil, k.signal, k.dryRun)
+				if err != nil {
+					return fmt.Errorf("failed to kill processes: %w", err)
+				}
+				log.WithFields(log.Fields{"ctr": c, "signal": k.signal, "pids": pids}).Debug("killed processes")
+				return nil
+			}
 			log.WithFields(log.Fields{"ctr": c, "signal": k.signal}).Debug("killing ctr")
 			if err := k.client.KillContainer(ctx, c, k.signal, k.dryRun); err != nil {
 				return fmt.Errorf("failed to kill ctr: %w", err)
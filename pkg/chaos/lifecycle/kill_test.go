@@ -196,7 +196,7 @@ func TestNewKillCommand(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewKillCommand(tt.args.client, tt.args.params, tt.args.signal, tt.args.limit)
+			got, err := NewKillCommand(tt.args.client, tt.args.params, tt.args.signal, nil, tt.args.limit)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
@@ -206,3 +206,41 @@ func TestNewKillCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestNewKillCommand_BadProcessRegex(t *testing.T) {
+	_, err := NewKillCommand(nil, &chaos.GlobalParams{Names: []string{"c0"}, DryRun: true}, "SIGTERM", &Processes{Regex: "worker("}, 0)
+	require.ErrorContains(t, err, "bad process regular expression", "a dry run rejects it too")
+}
+
+func TestKillCommand_RunInWaves(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	containers := container.CreateTestContainers(3)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return(containers, nil)
+	mockClient.EXPECT().KillContainer(mock.Anything, containers[0], "SIGKILL", false).Return(errors.New("kill failed"))
+	params := &chaos.GlobalParams{Names: []string{"c0", "c1", "c2"}, Rollout: &chaos.Rollout{WaveSize: 1}}
+	k, err := NewKillCommand(mockClient, params, "SIGKILL", nil, 0)
+	require.NoError(t, err)
+	require.Error(t, k.Run(context.TODO(), false), "a failed wave stops the rollout")
+}
+
+func TestKillCommand_RunProcesses(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	containers := container.CreateTestContainers(1)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return(containers, nil)
+	mockClient.EXPECT().SignalProcesses(mock.Anything, &container.SignalRequest{
+		Container: containers[0], Process: "^worker", Signal: "SIGTERM", Sidecar: container.SidecarSpec{Image: "busybox"},
+	}).Return([]int{7, 9}, nil).Once()
+	k, err := NewKillCommand(mockClient, &chaos.GlobalParams{Names: []string{"c0"}}, "SIGTERM", &Processes{Regex: "^worker", Image: "busybox"}, 0)
+	require.NoError(t, err)
+	require.NoError(t, k.Run(context.TODO(), false))
+}
+
+func TestKillCommand_RunProcessesNoMatch(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	containers := container.CreateTestContainers(1)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return(containers, nil)
+	mockClient.EXPECT().SignalProcesses(mock.Anything, mock.Anything).Return(nil, nil).Once()
+	k, err := NewKillCommand(mockClient, &chaos.GlobalParams{Names: []string{"c0"}}, "SIGKILL", &Processes{Regex: "^worker"}, 0)
+	require.NoError(t, err)
+	require.ErrorContains(t, k.Run(context.TODO(), false), `no process of container c0 matches "^worker"`)
+}
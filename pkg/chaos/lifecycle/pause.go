@@ -16,29 +16,45 @@ type pauseClient interface {
 	container.Lister
 	PauseContainer(context.Context, *container.Container, bool) error
 	UnpauseContainer(context.Context, *container.Container, bool) error
+	processSignaler
 }
 
 // `docker pause` command
 type pauseCommand struct {
-	client   pauseClient
-	names    []string
-	pattern  string
-	labels   []string
-	duration time.Duration
-	limit    int
-	dryRun   bool
+	client    pauseClient
+	names     []string
+	pattern   string
+	labels    []string
+	hooks     *chaos.Hooks
+	rollout   *chaos.Rollout
+	duration  time.Duration
+	processes *Processes
+	limit     int
+	dryRun    bool
 }
 
-// NewPauseCommand create new Pause Command instance
-func NewPauseCommand(client pauseClient, params *chaos.GlobalParams, duration time.Duration, limit int) chaos.Command {
+// paused is a paused container or, with processes set, the processes of it
+// stopped with SIGSTOP.
+type paused struct {
+	container *container.Container
+	pids      []int
+}
+
+// NewPauseCommand create new Pause Command instance; with processes set, the
+// matching processes get SIGSTOP and SIGCONT instead of the container being
+// paused
+func NewPauseCommand(client pauseClient, params *chaos.GlobalParams, duration time.Duration, processes *Processes, limit int) chaos.Command {
 	return &pauseCommand{
-		client:   client,
-		names:    params.Names,
-		pattern:  params.Pattern,
-		labels:   params.Labels,
-		duration: duration,
-		limit:    limit,
-		dryRun:   params.DryRun}
+		client:    client,
+		names:     params.Names,
+		pattern:   params.Pattern,
+		labels:    params.Labels,
+		hooks:     params.Hooks,
+		rollout:   params.Rollout,
+		duration:  duration,
+		processes: processes,
+		limit:     limit,
+		dryRun:    params.DryRun}
 }
 
 // Run pause command
@@ -52,16 +68,25 @@ func (p *pauseCommand) Run(ctx context.Context, random bool) error {
 		"limit":    p.limit,
 		"random":   random,
 	}).Debug("listing matching containers")
-	gp := &chaos.GlobalParams{Names: p.names, Pattern: p.pattern, Labels: p.labels}
-	pausedContainers := make([]*container.Container, 0)
+	gp := &chaos.GlobalParams{Names: p.names, Pattern: p.pattern, Labels: p.labels, Hooks: p.hooks, Rollout: p.rollout}
+	pausedContainers := make([]paused, 0)
 	err := chaos.RunOnContainers(ctx, p.client, gp, p.limit, random, false,
 		func(ctx context.Context, c *container.Container) error {
-			log.WithFields(log.Fields{"container": c, "duration": p.duration}).Debug("pausing container for duration")
+			log.WithContext(ctx).WithFields(log.Fields{"container": c, "duration": p.duration}).Debug("pausing container for duration")
+			if p.processes != nil {
+				pids, pErr := signalProcesses(ctx, p.client, c, p.processes, nil, "SIGSTOP", p.dryRun)
+				if pErr != nil {
+					log.WithContext(ctx).WithError(pErr).Warn("failed to stop container processes")
+					return pErr
+				}
+				pausedContainers = append(pausedContainers, paused{container: c, pids: pids})
+				return nil
+			}
 			if pErr := p.client.PauseContainer(ctx, c, p.dryRun); pErr != nil {
-				log.WithError(pErr).Warn("failed to pause container")
+				log.WithContext(ctx).WithError(pErr).Warn("failed to pause container")
 				return pErr
 			}
-			pausedContainers = append(pausedContainers, c)
+			pausedContainers = append(pausedContainers, paused{container: c})
 			return nil
 		})
 
@@ -74,26 +99,32 @@ func (p *pauseCommand) Run(ctx context.Context, random bool) error {
 		select {
 		case <-ctx.Done():
 			log.Debug("unpause containers by stop event")
-			// use context.WithoutCancel so cleanup succeeds even if the parent ctx is canceled
-			cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.duration)
+			// the cleanup succeeds even if the parent ctx is canceled
+			cleanupCtx, cancel := context.WithTimeout(container.WithCleanup(ctx), p.duration)
 			defer cancel()
 			unpauseErr = p.unpauseContainers(cleanupCtx, pausedContainers)
 		case <-durationTimer.C:
 			log.WithField("duration", p.duration).Debug("unpause containers after duration")
-			unpauseErr = p.unpauseContainers(ctx, pausedContainers)
+			unpauseErr = p.unpauseContainers(container.WithCleanup(ctx), pausedContainers)
 		}
 		err = errors.Join(err, unpauseErr)
 	}
 	return err
 }
 
-// unpause containers
-func (p *pauseCommand) unpauseContainers(ctx context.Context, containers []*container.Container) error {
+// unpause containers, or resume their stopped processes with SIGCONT
+func (p *pauseCommand) unpauseContainers(ctx context.Context, containers []paused) error {
 	var err error
-	for _, container := range containers {
-		log.WithField("container", container).Debug("unpause container")
-		c := container
-		if e := p.client.UnpauseContainer(ctx, c, p.dryRun); e != nil {
+	for _, pc := range containers {
+		if p.processes != nil {
+			log.WithFields(log.Fields{"container": pc.container, "pids": pc.pids}).Debug("resume container processes")
+			if _, e := signalProcesses(ctx, p.client, pc.container, p.processes, pc.pids, "SIGCONT", p.dryRun); e != nil {
+				err = errors.Join(err, fmt.Errorf("failed to resume container processes: %w", e))
+			}
+			continue
+		}
+		log.WithField("container", pc.container).Debug("unpause container")
+		if e := p.client.UnpauseContainer(ctx, pc.container, p.dryRun); e != nil {
 			err = errors.Join(err, fmt.Errorf("failed to unpause container: %w", e))
 		}
 	}
@@ -181,7 +181,7 @@ func TestNewPauseCommand(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := container.NewMockClient(t)
-			got := NewPauseCommand(mockClient, tt.params, tt.duration, tt.limit)
+			got := NewPauseCommand(mockClient, tt.params, tt.duration, nil, tt.limit)
 			cmd, ok := got.(*pauseCommand)
 			require.True(t, ok)
 			tt.want.client = mockClient
@@ -221,3 +221,26 @@ func TestPauseCommand_Run_CtxCancelUnpausesContainers(t *testing.T) {
 	err := p.Run(ctx, false)
 	assert.NoError(t, err)
 }
+
+func TestPauseCommand_RunProcesses(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	containers := container.CreateTestContainers(1)
+	processes := &Processes{Regex: "worker"}
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{}).Return(containers, nil)
+	mockClient.EXPECT().SignalProcesses(mock.Anything, &container.SignalRequest{
+		Container: containers[0], Process: "worker", Signal: "SIGSTOP",
+	}).Return([]int{7, 9}, nil).Once()
+	// only the stopped processes are resumed, even when the command is stopped
+	mockClient.EXPECT().SignalProcesses(mock.Anything, &container.SignalRequest{
+		Container: containers[0], Process: "worker", PIDs: []int{7, 9}, Signal: "SIGCONT",
+	}).Return([]int{7}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	p := NewPauseCommand(mockClient, &chaos.GlobalParams{Names: []string{"c0"}}, 10*time.Second, processes, 0)
+	require.NoError(t, p.Run(ctx, false))
+}
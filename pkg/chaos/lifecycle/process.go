@@ -0,0 +1,60 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/alexei-led/pumba/pkg/container"
+)
+
+// Processes selects the processes inside a target container that kill and
+// pause signal instead of the container itself.
+type Processes struct {
+	// Regex matches the name or the command line of the processes.
+	Regex string
+	// Image, when set, runs the shell listing and signaling the processes in
+	// a sidecar sharing the target's PID namespace; without an image the
+	// shell runs inside the target.
+	Image string
+	Pull  bool
+}
+
+// Validate checks the regular expression, so a dry run rejects it too;
+// nil Processes are valid.
+func (p *Processes) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if _, err := regexp.Compile(p.Regex); err != nil {
+		return fmt.Errorf("bad process regular expression: %w", err)
+	}
+	return nil
+}
+
+// processSignaler is the narrow interface needed to signal processes.
+type processSignaler interface {
+	SignalProcesses(context.Context, *container.SignalRequest) ([]int, error)
+}
+
+// signalProcesses sends signal to the processes of c selected by p or, when
+// set, to pids, returning the PIDs signaled. Outside dry runs, no process
+// matching is an error: the chaos would silently not happen.
+func signalProcesses(ctx context.Context, client processSignaler, c *container.Container, p *Processes, pids []int, signal string, dryRun bool) ([]int, error) {
+	req := &container.SignalRequest{
+		Container: c,
+		Process:   p.Regex,
+		PIDs:      pids,
+		Signal:    signal,
+		Sidecar:   container.SidecarSpec{Image: p.Image, Pull: p.Pull},
+		DryRun:    dryRun,
+	}
+	signaled, err := client.SignalProcesses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(signaled) == 0 && len(pids) == 0 && !dryRun {
+		return nil, fmt.Errorf("no process of container %s matches %q", c.Name(), p.Regex)
+	}
+	return signaled, nil
+}
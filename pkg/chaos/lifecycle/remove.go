@@ -21,6 +21,8 @@ type removeCommand struct {
 	names   []string
 	pattern string
 	labels  []string
+	hooks   *chaos.Hooks
+	rollout *chaos.Rollout
 	opts    container.RemoveOpts
 	limit   int
 }
@@ -32,6 +34,8 @@ func NewRemoveCommand(client removeClient, params *chaos.GlobalParams, force, li
 		names:   params.Names,
 		pattern: params.Pattern,
 		labels:  params.Labels,
+		hooks:   params.Hooks,
+		rollout: params.Rollout,
 		opts: container.RemoveOpts{
 			Force:   force,
 			Links:   links,
@@ -53,7 +57,7 @@ func (r *removeCommand) Run(ctx context.Context, random bool) error {
 		"limit":   r.limit,
 		"random":  random,
 	}).Debug("listing matching containers")
-	gp := &chaos.GlobalParams{Names: r.names, Pattern: r.pattern, Labels: r.labels}
+	gp := &chaos.GlobalParams{Names: r.names, Pattern: r.pattern, Labels: r.labels, Hooks: r.hooks, Rollout: r.rollout}
 	return chaos.RunOnContainersAll(ctx, r.client, gp, r.limit, random, false,
 		func(ctx context.Context, c *container.Container) error {
 			log.WithFields(log.Fields{
@@ -22,6 +22,8 @@ type restartCommand struct {
 	names   []string
 	pattern string
 	labels  []string
+	hooks   *chaos.Hooks
+	rollout *chaos.Rollout
 	timeout time.Duration
 	limit   int
 	dryRun  bool
@@ -34,6 +36,8 @@ func NewRestartCommand(client restartClient, params *chaos.GlobalParams, timeout
 		names:   params.Names,
 		pattern: params.Pattern,
 		labels:  params.Labels,
+		hooks:   params.Hooks,
+		rollout: params.Rollout,
 		timeout: timeout,
 		limit:   limit,
 		dryRun:  params.DryRun,
@@ -50,7 +54,7 @@ func (k *restartCommand) Run(ctx context.Context, random bool) error {
 		"limit":   k.limit,
 		"random":  random,
 	}).Debug("listing matching containers")
-	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels}
+	gp := &chaos.GlobalParams{Names: k.names, Pattern: k.pattern, Labels: k.labels, Hooks: k.hooks, Rollout: k.rollout}
 	return chaos.RunOnContainers(ctx, k.client, gp, k.limit, random, false,
 		func(ctx context.Context, c *container.Container) error {
 			log.WithFields(log.Fields{"container": c, "timeout": k.timeout}).Debug("restarting container")
@@ -29,6 +29,8 @@ type stopCommand struct {
 	names    []string
 	pattern  string
 	labels   []string
+	hooks    *chaos.Hooks
+	rollout  *chaos.Rollout
 	restart  bool
 	duration time.Duration
 	waitTime int
@@ -46,6 +48,8 @@ func NewStopCommand(client stopClient, params *chaos.GlobalParams, restart bool,
 		names:    params.Names,
 		pattern:  params.Pattern,
 		labels:   params.Labels,
+		hooks:    params.Hooks,
+		rollout:  params.Rollout,
 		dryRun:   params.DryRun,
 		restart:  restart,
 		duration: duration,
@@ -64,13 +68,13 @@ func (s *stopCommand) Run(ctx context.Context, random bool) error {
 		"limit":    s.limit,
 		"random":   random,
 	}).Debug("stopping all matching containers")
-	gp := &chaos.GlobalParams{Names: s.names, Pattern: s.pattern, Labels: s.labels}
+	gp := &chaos.GlobalParams{Names: s.names, Pattern: s.pattern, Labels: s.labels, Hooks: s.hooks, Rollout: s.rollout}
 	stoppedContainers := make([]*container.Container, 0)
 	err := chaos.RunOnContainers(ctx, s.client, gp, s.limit, random, false,
 		func(ctx context.Context, c *container.Container) error {
-			log.WithFields(log.Fields{"container": c, "waitTime": s.waitTime}).Debug("stopping container")
+			log.WithContext(ctx).WithFields(log.Fields{"container": c, "waitTime": s.waitTime}).Debug("stopping container")
 			if sErr := s.client.StopContainer(ctx, c, s.waitTime, s.dryRun); sErr != nil {
-				log.WithError(sErr).Warn("failed to stop container")
+				log.WithContext(ctx).WithError(sErr).Warn("failed to stop container")
 				return sErr
 			}
 			stoppedContainers = append(stoppedContainers, c)
@@ -86,13 +90,13 @@ func (s *stopCommand) Run(ctx context.Context, random bool) error {
 		select {
 		case <-ctx.Done():
 			log.Debug("start stopped containers by stop event")
-			// use context.WithoutCancel so cleanup succeeds even if the parent ctx is canceled
-			cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.duration)
+			// the cleanup succeeds even if the parent ctx is canceled
+			cleanupCtx, cancel := context.WithTimeout(container.WithCleanup(ctx), s.duration)
 			defer cancel()
 			restartErr = s.startStoppedContainers(cleanupCtx, stoppedContainers)
 		case <-durationTimer.C:
 			log.WithField("duration", s.duration).Debug("start stopped containers after duration")
-			restartErr = s.startStoppedContainers(ctx, stoppedContainers)
+			restartErr = s.startStoppedContainers(container.WithCleanup(ctx), stoppedContainers)
 		}
 		err = errors.Join(err, restartErr)
 	}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func nilRuntime() chaos.Runtime {
+	return func() container.Client { return nil }
+}
+
+// linkParentContext mirrors the parent "link" command flags declared in
+// cmd/commands.go so subcommand parsers can hydrate via c.Parent().
+func linkParentContext(t *testing.T, args []string) *cli.Context {
+	t.Helper()
+	fs := flag.NewFlagSet("link", flag.ContinueOnError)
+	for _, f := range []cli.Flag{
+		cli.DurationFlag{Name: "duration, d"},
+		cli.StringFlag{Name: "interface, i", Value: "eth0"},
+		cli.StringFlag{Name: "link-image", Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest"},
+		cli.BoolTFlag{Name: "pull-image"},
+		cli.IntFlag{Name: "limit"},
+	} {
+		f.Apply(fs)
+	}
+	if args == nil {
+		args = []string{"--duration", "1s"}
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(cli.NewApp(), fs, nil)
+}
+
+func childContext(t *testing.T, parent *cli.Context, subFlags []cli.Flag, args []string) *cli.Context {
+	t.Helper()
+	fs := flag.NewFlagSet("sub", flag.ContinueOnError)
+	for _, f := range subFlags {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(parent.App, fs, parent)
+}
+
+func TestNewLinkCLICommands_Contract(t *testing.T) {
+	for name, cmd := range map[string]*cli.Command{
+		"down":       NewDownCLICommand(context.Background(), nilRuntime()),
+		"mtu":        NewMTUCLICommand(context.Background(), nilRuntime()),
+		"disconnect": NewDisconnectCLICommand(context.Background(), nilRuntime()),
+	} {
+		assert.Equal(t, name, cmd.Name)
+		assert.NotNil(t, cmd.Action)
+	}
+}
+
+func TestParseDownParams(t *testing.T) {
+	c := childContext(t, linkParentContext(t, []string{"--duration", "5s", "--interface", "eth1"}), nil, nil)
+	p, err := parseDownParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "eth1", p.Interface)
+
+	built, err := buildDownCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	assert.NotNil(t, built)
+
+	c = childContext(t, linkParentContext(t, []string{}), nil, nil)
+	_, err = parseDownParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	assert.ErrorContains(t, err, "error parsing link parameters")
+}
+
+func TestParseMTUParams(t *testing.T) {
+	cmd := NewMTUCLICommand(context.Background(), nilRuntime())
+	c := childContext(t, linkParentContext(t, nil), cmd.Flags, nil)
+	p, err := parseMTUParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, 1200, p.MTU)
+
+	c = childContext(t, linkParentContext(t, nil), cmd.Flags, []string{"--mtu", "20"})
+	p, err = parseMTUParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	_, err = buildMTUCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	assert.ErrorContains(t, err, "invalid mtu 20")
+}
+
+func TestParseDisconnectParams(t *testing.T) {
+	cmd := NewDisconnectCLICommand(context.Background(), nilRuntime())
+	c := childContext(t, linkParentContext(t, nil), cmd.Flags, []string{"--network", "backend"})
+	p, err := parseDisconnectParams(cliflags.NewV1(c), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "backend", p.Network)
+
+	built, err := buildDisconnectCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	assert.NotNil(t, built)
+}
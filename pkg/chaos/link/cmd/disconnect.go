@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/link"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// DisconnectParams holds the per-command parameters for the link disconnect subcommand.
+type DisconnectParams struct {
+	Base    *link.Params
+	Network string
+}
+
+// NewDisconnectCLICommand initialize CLI link disconnect command.
+func NewDisconnectCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[DisconnectParams]{
+		Name: "disconnect",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "network, n",
+				Usage: "name of the runtime network to disconnect the container from",
+			},
+		},
+		Usage:       "temporarily disconnects containers from a network",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "disconnects the container from one of its networks through the runtime API and reconnects it, with the same aliases and static address, after the duration; not supported by containerd",
+		Parse:       parseDisconnectParams,
+		Build:       buildDisconnectCommand,
+	})
+}
+
+func parseDisconnectParams(c cliflags.Flags, gp *chaos.GlobalParams) (DisconnectParams, error) {
+	base, err := link.ParseParams(c.Parent(), gp)
+	if err != nil {
+		return DisconnectParams{}, fmt.Errorf("error parsing link parameters: %w", err)
+	}
+	return DisconnectParams{Base: base, Network: c.String("network")}, nil
+}
+
+func buildDisconnectCommand(client container.Client, gp *chaos.GlobalParams, p DisconnectParams) (chaos.Command, error) {
+	return link.NewDisconnectCommand(client, gp, p.Base, p.Network)
+}
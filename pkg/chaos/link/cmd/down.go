@@ -0,0 +1,39 @@
+// Package cmd wires link subcommands to the generic NewAction[P] CLI builder.
+// Per-action parsers delegate link-level flag parsing to link.ParseParams.
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/link"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// NewDownCLICommand initialize CLI link down command.
+func NewDownCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[*link.Params]{
+		Name:        "down",
+		Usage:       "takes the network interface down",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "sets the container network interface down (ip link set <iface> down) and brings it back up with its routes after the duration",
+		Parse:       parseDownParams,
+		Build:       buildDownCommand,
+	})
+}
+
+func parseDownParams(c cliflags.Flags, gp *chaos.GlobalParams) (*link.Params, error) {
+	p, err := link.ParseParams(c.Parent(), gp)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing link parameters: %w", err)
+	}
+	return p, nil
+}
+
+func buildDownCommand(client container.Client, gp *chaos.GlobalParams, p *link.Params) (chaos.Command, error) {
+	return link.NewDownCommand(client, gp, p)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/link"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// MTUParams holds the per-command parameters for the link mtu subcommand.
+type MTUParams struct {
+	Base *link.Params
+	MTU  int
+}
+
+// NewMTUCLICommand initialize CLI link mtu command.
+func NewMTUCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[MTUParams]{
+		Name: "mtu",
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "mtu, m",
+				Usage: "temporary MTU of the network interface (68-65535)",
+				Value: 1200, //nolint:mnd
+			},
+		},
+		Usage:       "temporarily changes the network interface MTU",
+		ArgsUsage:   fmt.Sprintf("containers (name, list of names, or RE2 regex if prefixed with %q", chaos.Re2Prefix),
+		Description: "sets the container network interface MTU and restores the original MTU after the duration; a lower MTU than the path expects emulates PMTU black holes",
+		Parse:       parseMTUParams,
+		Build:       buildMTUCommand,
+	})
+}
+
+func parseMTUParams(c cliflags.Flags, gp *chaos.GlobalParams) (MTUParams, error) {
+	base, err := link.ParseParams(c.Parent(), gp)
+	if err != nil {
+		return MTUParams{}, fmt.Errorf("error parsing link parameters: %w", err)
+	}
+	return MTUParams{Base: base, MTU: c.Int("mtu")}, nil
+}
+
+func buildMTUCommand(client container.Client, gp *chaos.GlobalParams, p MTUParams) (chaos.Command, error) {
+	return link.NewMTUCommand(client, gp, p.Base, p.MTU)
+}
@@ -0,0 +1,66 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// `link disconnect` command
+type disconnectCommand struct {
+	client  linkClient
+	gp      *chaos.GlobalParams
+	p       *Params
+	network string
+}
+
+// NewDisconnectCommand create new link disconnect command
+func NewDisconnectCommand(client linkClient, gp *chaos.GlobalParams, p *Params, network string) (chaos.Command, error) {
+	if network == "" {
+		return nil, errors.New("network name is required")
+	}
+	return &disconnectCommand{client: client, gp: gp, p: p, network: network}, nil
+}
+
+// Run link disconnect command
+func (n *disconnectCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("disconnecting all matching containers from network")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.p.Limit,
+		"random":  random,
+		"network": n.network,
+	}).Debug("listing matching containers")
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.p.Limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			if err := n.disconnect(ctx, c); err != nil {
+				log.WithError(err).Warn("failed to disconnect container from network")
+				return fmt.Errorf("failed to disconnect one or more containers from network: %w", err)
+			}
+			return nil
+		})
+}
+
+// disconnect detaches c from the network and reattaches it afterwards. The
+// container snapshot taken before the disconnect carries the aliases and
+// address the runtime restores on connect.
+func (n *disconnectCommand) disconnect(ctx context.Context, c *container.Container) error {
+	if _, ok := c.Networks[n.network]; !ok {
+		return fmt.Errorf("container %s is not connected to network %s", c.Name(), n.network)
+	}
+	logger := log.WithContext(ctx).WithFields(log.Fields{"id": c.ID(), "name": c.Name(), "network": n.network, "duration": n.p.Duration})
+	logger.Debug("disconnecting container from network")
+	req := &container.NetworkRequest{Container: c, Network: n.network, DryRun: n.gp.DryRun}
+	if err := n.client.DisconnectNetwork(ctx, req); err != nil {
+		return err
+	}
+	return waitAndRestore(ctx, n.p.Duration, logger, func(ctx context.Context) error {
+		return n.client.ConnectNetwork(ctx, req)
+	})
+}
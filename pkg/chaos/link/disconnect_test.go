@@ -0,0 +1,45 @@
+package link
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDisconnectCommand_RequiresNetwork(t *testing.T) {
+	_, err := NewDisconnectCommand(container.NewMockClient(t), &chaos.GlobalParams{}, testParams(), "")
+	assert.ErrorContains(t, err, "network name is required")
+}
+
+func TestDisconnectCommand_Run(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1", Networks: map[string]container.NetworkLink{
+		"backend":  {Aliases: []string{"api"}},
+		"frontend": {},
+	}}
+	expectList(client, c)
+	matchReq := mock.MatchedBy(func(r *container.NetworkRequest) bool {
+		return r.Container == c && r.Network == "backend"
+	})
+	client.EXPECT().DisconnectNetwork(mock.Anything, matchReq).Return(nil).Once()
+	client.EXPECT().ConnectNetwork(mock.Anything, matchReq).Return(nil).Once()
+
+	cmd, err := NewDisconnectCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, testParams(), "backend")
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
+
+func TestDisconnectCommand_Run_UnknownNetwork(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1", Networks: map[string]container.NetworkLink{"bridge": {}}}
+	expectList(client, c)
+
+	cmd, err := NewDisconnectCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, testParams(), "backend")
+	require.NoError(t, err)
+	assert.ErrorContains(t, cmd.Run(context.Background(), false), "not connected to network backend")
+}
@@ -0,0 +1,88 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// `link down` command
+type downCommand struct {
+	client linkClient
+	gp     *chaos.GlobalParams
+	p      *Params
+}
+
+// NewDownCommand create new link down command
+func NewDownCommand(client linkClient, gp *chaos.GlobalParams, p *Params) (chaos.Command, error) {
+	if p.Image == "" {
+		return nil, errors.New("link-image is required to change network interfaces")
+	}
+	return &downCommand{client: client, gp: gp, p: p}, nil
+}
+
+// Run link down command
+func (n *downCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("taking network interface down in all matching containers")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.p.Limit,
+		"random":  random,
+	}).Debug("listing matching containers")
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.p.Limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			if err := n.down(ctx, c); err != nil {
+				log.WithError(err).Warn("failed to take network interface down for container")
+				return fmt.Errorf("failed to take network interface down for one or more containers: %w", err)
+			}
+			return nil
+		})
+}
+
+// down takes the interface down, saving its routes first: the kernel drops
+// gateway routes of a downed interface and does not bring them back on up.
+func (n *downCommand) down(ctx context.Context, c *container.Container) error {
+	iface := n.p.Interface
+	logger := log.WithContext(ctx).WithFields(log.Fields{"id": c.ID(), "name": c.Name(), "iface": iface, "duration": n.p.Duration})
+	logger.Debug("taking network interface down")
+	routes, err := n.client.LinkContainer(ctx, n.p.linkRequest(c, [][]string{
+		{"route", "show", "dev", iface},
+		{"link", "set", "dev", iface, "down"},
+	}, n.gp.DryRun))
+	if err != nil {
+		return err
+	}
+	return waitAndRestore(ctx, n.p.Duration, logger, func(ctx context.Context) error {
+		_, err := n.client.LinkContainer(ctx, n.p.linkRequest(c, upCommands(iface, routes), n.gp.DryRun))
+		return err
+	})
+}
+
+// routeFlags are `ip route show` output flags that are not valid route
+// attributes.
+var routeFlags = []string{"linkdown", "dead", "offload", "trap", "rt_offload", "rt_trap", "rt_offload_failed"}
+
+// upCommands brings iface up and re-installs the routes listed in `ip route
+// show dev <iface>` output.
+func upCommands(iface, routes string) [][]string {
+	commands := [][]string{{"link", "set", "dev", iface, "up"}}
+	for line := range strings.Lines(routes) {
+		fields := slices.DeleteFunc(strings.Fields(line), func(f string) bool {
+			return slices.Contains(routeFlags, f)
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		cmd := append([]string{"route", "replace"}, fields...)
+		commands = append(commands, append(cmd, "dev", iface))
+	}
+	return commands
+}
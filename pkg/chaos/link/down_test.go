@@ -0,0 +1,68 @@
+package link
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testParams() *Params {
+	return &Params{Duration: 10 * time.Millisecond, Interface: "eth0", Image: "nettools"}
+}
+
+func expectList(client *container.MockClient, c *container.Container) {
+	client.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return([]*container.Container{c}, nil)
+}
+
+func linkCommands(commands ...[]string) any {
+	return mock.MatchedBy(func(r *container.LinkRequest) bool {
+		return r.Sidecar.Image == "nettools" && assert.ObjectsAreEqual(commands, r.Commands)
+	})
+}
+
+func TestNewDownCommand_RequiresImage(t *testing.T) {
+	_, err := NewDownCommand(container.NewMockClient(t), &chaos.GlobalParams{}, &Params{Duration: time.Second, Interface: "eth0"})
+	assert.ErrorContains(t, err, "link-image is required")
+}
+
+func TestDownCommand_Run_RestoresRoutes(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	expectList(client, c)
+	client.EXPECT().LinkContainer(mock.Anything, linkCommands(
+		[]string{"route", "show", "dev", "eth0"},
+		[]string{"link", "set", "dev", "eth0", "down"},
+	)).Return("default via 172.17.0.1 \n172.17.0.0/16 proto kernel scope link src 172.17.0.2 linkdown\n", nil).Once()
+	client.EXPECT().LinkContainer(mock.Anything, linkCommands(
+		[]string{"link", "set", "dev", "eth0", "up"},
+		[]string{"route", "replace", "default", "via", "172.17.0.1", "dev", "eth0"},
+		[]string{"route", "replace", "172.17.0.0/16", "proto", "kernel", "scope", "link", "src", "172.17.0.2", "dev", "eth0"},
+	)).Return("", nil).Once()
+
+	cmd, err := NewDownCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, testParams())
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
+
+func TestDownCommand_Run_RestoresOnAbort(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	expectList(client, c)
+	ctx, cancel := context.WithCancel(context.Background())
+	client.EXPECT().LinkContainer(mock.Anything, mock.Anything).Run(func(context.Context, *container.LinkRequest) { cancel() }).Return("", nil).Once()
+	client.EXPECT().LinkContainer(mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }),
+		linkCommands([]string{"link", "set", "dev", "eth0", "up"})).Return("", nil).Once()
+
+	p := testParams()
+	p.Duration = time.Hour
+	cmd, err := NewDownCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, p)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(ctx, false))
+}
@@ -0,0 +1,65 @@
+// Package link implements interface-level network chaos: taking a
+// container's network interface down, lowering its MTU and disconnecting the
+// container from a runtime network. Every change is reverted when the
+// duration elapses or the command is aborted.
+package link
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// cleanupTimeout caps how long restoring a link may take after abort or
+// scheduled stop, independent of --duration.
+const cleanupTimeout = 30 * time.Second
+
+// linkClient is the narrow interface needed by all link commands.
+type linkClient interface {
+	container.Lister
+	container.Link
+}
+
+// Params holds the link-level parameters shared by every link subcommand.
+// Interface, Image and Pull are used by the subcommands changing the
+// interface (down, mtu); disconnect ignores them.
+type Params struct {
+	Duration  time.Duration
+	Interface string
+	Image     string
+	Pull      bool
+	Limit     int
+}
+
+// linkRequest builds the request running commands on c.
+func (p *Params) linkRequest(c *container.Container, commands [][]string, dryRun bool) *container.LinkRequest {
+	return &container.LinkRequest{
+		Container: c,
+		Commands:  commands,
+		Sidecar:   container.SidecarSpec{Image: p.Image, Pull: p.Pull},
+		DryRun:    dryRun,
+	}
+}
+
+// waitAndRestore waits for the duration to elapse or ctx to be canceled and
+// then reverts the change with restore. Restore runs on a context detached
+// from ctx so an abort still brings the link back.
+func waitAndRestore(ctx context.Context, duration time.Duration, logger *log.Entry, restore func(context.Context) error) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		logger.Debug("restoring link on abort")
+	case <-timer.C:
+		logger.Debug("restoring link on timeout")
+	}
+	cleanupCtx, cancel := context.WithTimeout(container.WithCleanup(ctx), cleanupTimeout)
+	defer cancel()
+	if err := restore(cleanupCtx); err != nil {
+		return fmt.Errorf("failed to restore link: %w", err)
+	}
+	return nil
+}
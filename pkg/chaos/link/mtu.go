@@ -0,0 +1,97 @@
+package link
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// minMTU and maxMTU bound the MTU accepted by the kernel for IPv4.
+	minMTU = 68
+	maxMTU = 65535
+)
+
+var mtuRe = regexp.MustCompile(`\bmtu (\d+)\b`)
+
+// `link mtu` command
+type mtuCommand struct {
+	client linkClient
+	gp     *chaos.GlobalParams
+	p      *Params
+	mtu    int
+}
+
+// NewMTUCommand create new link mtu command
+func NewMTUCommand(client linkClient, gp *chaos.GlobalParams, p *Params, mtu int) (chaos.Command, error) {
+	if p.Image == "" {
+		return nil, errors.New("link-image is required to change network interfaces")
+	}
+	if mtu < minMTU || mtu > maxMTU {
+		return nil, fmt.Errorf("invalid mtu %d: must be between %d and %d", mtu, minMTU, maxMTU)
+	}
+	return &mtuCommand{client: client, gp: gp, p: p, mtu: mtu}, nil
+}
+
+// Run link mtu command
+func (n *mtuCommand) Run(ctx context.Context, random bool) error {
+	log.Debug("changing network interface mtu in all matching containers")
+	log.WithFields(log.Fields{
+		"names":   n.gp.Names,
+		"pattern": n.gp.Pattern,
+		"labels":  n.gp.Labels,
+		"limit":   n.p.Limit,
+		"random":  random,
+	}).Debug("listing matching containers")
+	return chaos.RunOnContainers(ctx, n.client, n.gp, n.p.Limit, random, true,
+		func(ctx context.Context, c *container.Container) error {
+			if err := n.setMTU(ctx, c); err != nil {
+				log.WithError(err).Warn("failed to change network interface mtu for container")
+				return fmt.Errorf("failed to change network interface mtu for one or more containers: %w", err)
+			}
+			return nil
+		})
+}
+
+// setMTU reads the current MTU and sets the new one in a single sidecar run,
+// then restores the original MTU.
+func (n *mtuCommand) setMTU(ctx context.Context, c *container.Container) error {
+	iface := n.p.Interface
+	logger := log.WithContext(ctx).WithFields(log.Fields{"id": c.ID(), "name": c.Name(), "iface": iface, "mtu": n.mtu, "duration": n.p.Duration})
+	logger.Debug("changing network interface mtu")
+	out, err := n.client.LinkContainer(ctx, n.p.linkRequest(c, [][]string{
+		{"-o", "link", "show", "dev", iface},
+		{"link", "set", "dev", iface, "mtu", strconv.Itoa(n.mtu)},
+	}, n.gp.DryRun))
+	if err != nil {
+		return err
+	}
+	var original int
+	if !n.gp.DryRun {
+		if original, err = parseMTU(out); err != nil {
+			return fmt.Errorf("failed to read %s mtu: %w", iface, err)
+		}
+	}
+	logger = logger.WithField("original-mtu", original)
+	return waitAndRestore(ctx, n.p.Duration, logger, func(ctx context.Context) error {
+		_, err := n.client.LinkContainer(ctx, n.p.linkRequest(c, [][]string{
+			{"link", "set", "dev", iface, "mtu", strconv.Itoa(original)},
+		}, n.gp.DryRun))
+		return err
+	})
+}
+
+// parseMTU extracts the MTU from `ip -o link show` output.
+func parseMTU(out string) (int, error) {
+	m := mtuRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("no mtu in %q", out)
+	}
+	return strconv.Atoi(m[1])
+}
@@ -0,0 +1,58 @@
+package link
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMTUCommand_Validation(t *testing.T) {
+	client := container.NewMockClient(t)
+	_, err := NewMTUCommand(client, &chaos.GlobalParams{}, testParams(), 67)
+	assert.ErrorContains(t, err, "invalid mtu 67")
+	_, err = NewMTUCommand(client, &chaos.GlobalParams{}, testParams(), 65536)
+	assert.ErrorContains(t, err, "invalid mtu")
+	_, err = NewMTUCommand(client, &chaos.GlobalParams{}, testParams(), 1200)
+	assert.NoError(t, err)
+}
+
+func TestParseMTU(t *testing.T) {
+	mtu, err := parseMTU("2: eth0@if7: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1450 qdisc noqueue state UP\\    link/ether 02:42:ac:11:00:02\n")
+	require.NoError(t, err)
+	assert.Equal(t, 1450, mtu)
+	_, err = parseMTU("")
+	assert.Error(t, err)
+}
+
+func TestMTUCommand_Run_RestoresOriginal(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	expectList(client, c)
+	client.EXPECT().LinkContainer(mock.Anything, linkCommands(
+		[]string{"-o", "link", "show", "dev", "eth0"},
+		[]string{"link", "set", "dev", "eth0", "mtu", "1200"},
+	)).Return("2: eth0@if7: <UP> mtu 1500 qdisc noqueue\n", nil).Once()
+	client.EXPECT().LinkContainer(mock.Anything, linkCommands(
+		[]string{"link", "set", "dev", "eth0", "mtu", "1500"},
+	)).Return("", nil).Once()
+
+	cmd, err := NewMTUCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, testParams(), 1200)
+	require.NoError(t, err)
+	require.NoError(t, cmd.Run(context.Background(), false))
+}
+
+func TestMTUCommand_Run_UnreadableMTU(t *testing.T) {
+	client := container.NewMockClient(t)
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	expectList(client, c)
+	client.EXPECT().LinkContainer(mock.Anything, mock.Anything).Return("garbage", nil).Once()
+
+	cmd, err := NewMTUCommand(client, &chaos.GlobalParams{Names: []string{"c1"}}, testParams(), 1200)
+	require.NoError(t, err)
+	assert.ErrorContains(t, cmd.Run(context.Background(), false), "failed to read eth0 mtu")
+}
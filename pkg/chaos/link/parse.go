@@ -0,0 +1,34 @@
+package link
+
+import (
+	"errors"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/util"
+)
+
+// ParseParams reads the link-level flags (--duration, --interface,
+// --link-image, --pull-image, --limit) from c.
+//
+// c must be the link parent context. Per-action parsers pass c.Parent().
+func ParseParams(c cliflags.Flags, gp *chaos.GlobalParams) (*Params, error) {
+	duration := c.Duration("duration")
+	if duration <= 0 {
+		return nil, errors.New("unset or invalid duration value")
+	}
+	if gp.Interval != 0 && duration >= gp.Interval {
+		return nil, errors.New("duration must be shorter than interval")
+	}
+	iface := c.String("interface")
+	if err := util.ValidateInterfaceName(iface); err != nil {
+		return nil, err
+	}
+	return &Params{
+		Duration:  duration,
+		Interface: iface,
+		Image:     c.String("link-image"),
+		Pull:      c.Bool("pull-image"),
+		Limit:     c.Int("limit"),
+	}, nil
+}
@@ -0,0 +1,59 @@
+package link
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func parentCtx(t *testing.T, args []string) *cli.Context {
+	t.Helper()
+	fs := flag.NewFlagSet("link", flag.ContinueOnError)
+	for _, f := range []cli.Flag{
+		cli.DurationFlag{Name: "duration, d"},
+		cli.StringFlag{Name: "interface, i", Value: "eth0"},
+		cli.StringFlag{Name: "link-image", Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest"},
+		cli.BoolTFlag{Name: "pull-image"},
+		cli.IntFlag{Name: "limit"},
+	} {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cli.NewContext(cli.NewApp(), fs, nil)
+}
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		gp      chaos.GlobalParams
+		want    *Params
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			args: []string{"--duration", "10s", "--limit", "2"},
+			want: &Params{Duration: 10 * time.Second, Interface: "eth0", Image: "ghcr.io/alexei-led/pumba-alpine-nettools:latest", Pull: true, Limit: 2},
+		},
+		{name: "no duration", args: nil, wantErr: "invalid duration"},
+		{name: "duration over interval", args: []string{"--duration", "1m"}, gp: chaos.GlobalParams{Interval: time.Minute}, wantErr: "shorter than interval"},
+		{name: "bad interface", args: []string{"--duration", "1s", "--interface", "eth0;reboot"}, wantErr: "interface"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseParams(cliflags.NewV1(parentCtx(t, tt.args)), &tt.gp)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -22,7 +22,7 @@ const cleanupTimeout = 30 * time.Second
 
 // run network emulation command, stop netem on timeout or abort
 func runNetem(ctx context.Context, client netemClient, req *container.NetemRequest) error {
-	logger := log.WithFields(log.Fields{
+	logger := log.WithContext(ctx).WithFields(log.Fields{
 		"id":       req.Container.ID(),
 		"name":     req.Container.Name(),
 		"iface":    req.Interface,
@@ -39,28 +39,33 @@ func runNetem(ctx context.Context, client netemClient, req *container.NetemReque
 		return fmt.Errorf("netem failed: %w", err)
 	}
 	logger.Debug("netem command started")
+	if err := verifyNetem(ctx, client, req, true); err != nil {
+		// do not leave a partially applied fault behind a failed run
+		cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), cleanupTimeout)
+		defer cleanupCancel()
+		if stopErr := client.StopNetemContainer(cleanupCtx, req); stopErr != nil {
+			logger.WithError(stopErr).Warn("failed to stop netem container after failed verification")
+		}
+		return err
+	}
 
 	// create new context with timeout for canceling
 	stopCtx, cancel := context.WithTimeout(context.Background(), req.Duration)
 	defer cancel()
 	// wait for specified duration and then stop netem (where it applied) or stop on ctx.Done()
-	// use context.WithoutCancel so cleanup succeeds even if the parent ctx is canceled
-	// or if it inherited a deadline that has elapsed alongside stopCtx.
 	select {
 	case <-ctx.Done():
 		logger.Debug("stopping netem command on abort")
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
-		defer cleanupCancel()
-		if err := client.StopNetemContainer(cleanupCtx, req); err != nil {
-			logger.WithError(err).Warn("failed to stop netem container (container may have been removed)")
-		}
 	case <-stopCtx.Done():
 		logger.Debug("stopping netem command on timeout")
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
-		defer cleanupCancel()
-		if err := client.StopNetemContainer(cleanupCtx, req); err != nil {
-			logger.WithError(err).Warn("failed to stop netem container (container may have been removed)")
-		}
 	}
-	return nil
+	// the cleanup succeeds even if the parent ctx is canceled
+	// or if it inherited a deadline that has elapsed alongside stopCtx.
+	cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), cleanupTimeout)
+	defer cleanupCancel()
+	if err := client.StopNetemContainer(cleanupCtx, req); err != nil {
+		logger.WithError(err).Warn("failed to stop netem container (container may have been removed)")
+		return nil
+	}
+	return verifyNetem(cleanupCtx, client, req, false)
 }
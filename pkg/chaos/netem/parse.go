@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
@@ -12,8 +13,8 @@ import (
 )
 
 // ParseRequestBase reads the netem-level flags (--duration, --interface,
-// --target, --egress-port, --ingress-port, --tc-image, --pull-image, --limit)
-// from c and returns a *container.NetemRequest with the shared base fields
+// --target, --egress-port, --ingress-port, --tc-image, --pull-image,
+// --capture*, --verify, --limit) from c and returns a *container.NetemRequest with the shared base fields
 // filled, plus the --limit value (consumed by per-action ListNContainers calls
 // rather than by the runtime). Container and Command are left zero — each
 // per-action Run sets them per iteration.
@@ -48,13 +49,24 @@ func ParseRequestBase(c cliflags.Flags, gp *chaos.GlobalParams) (*container.Nete
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get destination ports: %w", err)
 	}
+	sidecar := container.SidecarSpec{Image: c.String("tc-image"), Pull: c.Bool("pull-image")}
+	capture, err := chaos.ParseCapture(c, iface, sidecar, ips, slices.Concat(sports, dports))
+	if err != nil {
+		return nil, 0, err
+	}
+	verify, err := chaos.ParseVerify(c.String("verify"))
+	if err != nil {
+		return nil, 0, err
+	}
 	return &container.NetemRequest{
 		Interface: iface,
 		IPs:       ips,
 		SPorts:    sports,
 		DPorts:    dports,
 		Duration:  duration,
-		Sidecar:   container.SidecarSpec{Image: c.String("tc-image"), Pull: c.Bool("pull-image")},
+		Sidecar:   sidecar,
+		Capture:   capture,
+		Verify:    verify,
 		DryRun:    gp.DryRun,
 	}, c.Int("limit"), nil
 }
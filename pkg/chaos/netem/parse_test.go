@@ -22,6 +22,10 @@ func netemParentFlags() []cli.Flag {
 		cli.StringFlag{Name: "tc-image", Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest"},
 		cli.BoolTFlag{Name: "pull-image"},
 		cli.IntFlag{Name: "limit"},
+		cli.StringFlag{Name: "capture"},
+		cli.IntFlag{Name: "capture-size", Value: 100},
+		cli.IntFlag{Name: "capture-files", Value: 5},
+		cli.StringFlag{Name: "verify", Value: "none"},
 	}
 }
 
@@ -90,6 +94,12 @@ func TestParseRequestBase(t *testing.T) {
 			gp:      &chaos.GlobalParams{},
 			wantErr: "failed to get destination ports",
 		},
+		{
+			name:    "invalid verify mode rejected",
+			args:    []string{"--duration", "1s", "--interface", "eth0", "--verify", "strict"},
+			gp:      &chaos.GlobalParams{},
+			wantErr: "bad verify mode",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -118,6 +128,13 @@ func TestParseRequestBase_DryRunPropagates(t *testing.T) {
 	assert.True(t, req.DryRun)
 }
 
+func TestParseRequestBase_Verify(t *testing.T) {
+	c := cliflags.NewV1(parentCtx(t, []string{"--duration", "1s", "--interface", "eth0", "--verify", "fail"}))
+	req, _, err := ParseRequestBase(c, &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Equal(t, chaos.VerifyFail, req.Verify)
+}
+
 func TestParseRequestBase_PortsAndIPsParsed(t *testing.T) {
 	c := cliflags.NewV1(parentCtx(t, []string{
 		"--duration", "1s", "--interface", "eth0",
@@ -132,3 +149,25 @@ func TestParseRequestBase_PortsAndIPsParsed(t *testing.T) {
 	assert.Equal(t, []string{"80", "443"}, req.SPorts)
 	assert.Equal(t, []string{"8080"}, req.DPorts)
 }
+
+func TestParseRequestBase_Capture(t *testing.T) {
+	c := cliflags.NewV1(parentCtx(t, []string{
+		"--duration", "1s", "--interface", "eth0",
+		"--target", "10.0.0.0/24",
+		"--egress-port", "80",
+		"--ingress-port", "8080",
+		"--capture", "/var/pcap",
+	}))
+	req, _, err := ParseRequestBase(c, &chaos.GlobalParams{})
+	require.NoError(t, err)
+	require.NotNil(t, req.Capture)
+	assert.Equal(t, "/var/pcap", req.Capture.Dir)
+	assert.Equal(t, "eth0", req.Capture.Interface)
+	assert.Equal(t, "net 10.0.0.0/24 and (port 80 or port 8080)", req.Capture.Filter)
+	assert.Equal(t, req.Sidecar.Image, req.Capture.Image)
+
+	c = cliflags.NewV1(parentCtx(t, []string{"--duration", "1s", "--interface", "eth0"}))
+	req, _, err = ParseRequestBase(c, &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.Nil(t, req.Capture)
+}
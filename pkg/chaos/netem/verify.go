@@ -0,0 +1,45 @@
+package netem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+)
+
+// verifyNetem reads back the qdiscs of the request interface and checks that
+// a netem qdisc is present after apply (applied) or gone after stop. The
+// outcome is handled according to req.Verify.
+func verifyNetem(ctx context.Context, client netemClient, req *container.NetemRequest, applied bool) error {
+	if !chaos.Verifying(req.Verify, req.DryRun) {
+		return nil
+	}
+	// the sidecar image is present from the injection being verified
+	status := *req
+	status.Sidecar.Pull = false
+	out, err := client.NetemStatus(ctx, &status)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to read back qdiscs of %s in %s: %w", req.Interface, req.Container.Name(), err)
+	case applied && !hasNetemQdisc(out):
+		err = fmt.Errorf("netem qdisc not found on %s in %s after injection; qdiscs: %q",
+			req.Interface, req.Container.Name(), strings.TrimSpace(out))
+	case !applied && hasNetemQdisc(out):
+		err = fmt.Errorf("netem qdisc still present on %s in %s after removal; qdiscs: %q",
+			req.Interface, req.Container.Name(), strings.TrimSpace(out))
+	}
+	return chaos.VerifyResult(req.Verify, err)
+}
+
+// hasNetemQdisc reports whether `tc qdisc show` output lists a netem qdisc,
+// either at the root or under the prio qdisc of a filtered injection.
+func hasNetemQdisc(out string) bool {
+	for line := range strings.Lines(out) {
+		if strings.HasPrefix(line, "qdisc netem ") {
+			return true
+		}
+	}
+	return false
+}
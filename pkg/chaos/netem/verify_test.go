@@ -0,0 +1,91 @@
+package netem
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	qdiscsWithNetem = "qdisc netem 8001: root refcnt 2 limit 1000 delay 100ms\n Sent 0 bytes 0 pkt (dropped 0, overlimits 0 requeues 0)\n"
+	qdiscsFiltered  = "qdisc prio 1: root refcnt 2 bands 4\nqdisc netem 30: parent 1:3 limit 1000 delay 100ms\n"
+	qdiscsDefault   = "qdisc noqueue 0: root refcnt 2\n"
+)
+
+func TestHasNetemQdisc(t *testing.T) {
+	assert.True(t, hasNetemQdisc(qdiscsWithNetem))
+	assert.True(t, hasNetemQdisc(qdiscsFiltered))
+	assert.False(t, hasNetemQdisc(qdiscsDefault))
+	assert.False(t, hasNetemQdisc(""))
+}
+
+func verifyRequest(mode string) *container.NetemRequest {
+	return &container.NetemRequest{
+		Container: &container.Container{ContainerID: "abc", ContainerName: "c1"},
+		Interface: "eth0",
+		Command:   []string{"delay", "100ms"},
+		Duration:  time.Millisecond,
+		Verify:    mode,
+	}
+}
+
+func Test_runNetem_Verify(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		afterApply  string
+		afterStop   string
+		statusErr   error
+		stopOnApply bool
+		wantErr     string
+	}{
+		{name: "applied and removed", mode: chaos.VerifyFail, afterApply: qdiscsWithNetem, afterStop: qdiscsDefault},
+		{name: "missing after apply fails and cleans up", mode: chaos.VerifyFail, afterApply: qdiscsDefault, stopOnApply: true, wantErr: "not found on eth0 in c1 after injection"},
+		{name: "missing after apply warns", mode: chaos.VerifyWarn, afterApply: qdiscsDefault, afterStop: qdiscsDefault},
+		{name: "left after stop fails", mode: chaos.VerifyFail, afterApply: qdiscsFiltered, afterStop: qdiscsFiltered, wantErr: "still present on eth0 in c1 after removal"},
+		{name: "read back error fails", mode: chaos.VerifyFail, statusErr: errors.New("no tc"), stopOnApply: true, wantErr: "failed to read back qdiscs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := verifyRequest(tt.mode)
+			mockClient := container.NewMockClient(t)
+			mockClient.EXPECT().NetemContainer(mock.Anything, req).Return(nil).Once()
+			if tt.statusErr != nil {
+				mockClient.EXPECT().NetemStatus(mock.Anything, req).Return("", tt.statusErr).Once()
+			} else {
+				mockClient.EXPECT().NetemStatus(mock.Anything, req).Return(tt.afterApply, nil).Once()
+			}
+			mockClient.EXPECT().StopNetemContainer(mock.Anything, req).Return(nil).Once()
+			if !tt.stopOnApply {
+				mockClient.EXPECT().NetemStatus(mock.Anything, req).Return(tt.afterStop, nil).Once()
+			}
+			err := runNetem(context.Background(), mockClient, req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_runNetem_VerifySkipped(t *testing.T) {
+	for _, req := range []*container.NetemRequest{verifyRequest(chaos.VerifyNone), verifyRequest("")} {
+		mockClient := container.NewMockClient(t)
+		mockClient.EXPECT().NetemContainer(mock.Anything, req).Return(nil).Once()
+		mockClient.EXPECT().StopNetemContainer(mock.Anything, req).Return(nil).Once()
+		assert.NoError(t, runNetem(context.Background(), mockClient, req))
+	}
+	req := verifyRequest(chaos.VerifyFail)
+	req.DryRun = true
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().NetemContainer(mock.Anything, req).Return(nil).Once()
+	mockClient.EXPECT().StopNetemContainer(mock.Anything, req).Return(nil).Once()
+	assert.NoError(t, runNetem(context.Background(), mockClient, req))
+}
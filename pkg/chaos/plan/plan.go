@@ -0,0 +1,200 @@
+// Package plan implements the global --plan mode: the chaos command runs its
+// target selection and request building against a Recorder, which records
+// every runtime change as a Step, with the exact tc, iptables, nft, ip or
+// stress-ng argument lists, instead of performing it. The resulting Plan is
+// printed as JSON so it can be reviewed, e.g. attached to a change-approval
+// ticket, before the experiment runs.
+package plan
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
+)
+
+// Phases of a step.
+const (
+	// PhaseInject marks the steps applying the chaos.
+	PhaseInject = "inject"
+	// PhaseCleanup marks the steps reverting it after the duration or on
+	// abort.
+	PhaseCleanup = "cleanup"
+)
+
+// Target is a container resolved by target selection.
+type Target struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Step is a runtime change the command would make. Commands run through
+// Tool, in a sidecar of Image or, without an image, inside the container.
+type Step struct {
+	Phase    string            `json:"phase"`
+	Action   string            `json:"action"`
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Tool     string            `json:"tool,omitempty"`
+	Image    string            `json:"image,omitempty"`
+	Commands [][]string        `json:"commands,omitempty"`
+	Duration string            `json:"duration,omitempty"`
+	Params   map[string]string `json:"params,omitempty"`
+}
+
+// Plan lists the targets of a command and the steps run on them.
+type Plan struct {
+	Runtime string   `json:"runtime,omitempty"`
+	Command []string `json:"command,omitempty"`
+	Targets []Target `json:"targets"`
+	Steps   []Step   `json:"steps"`
+}
+
+// Recorder is a container.Client intercepting its calls to record runtime
+// changes instead of making them. Reads (listing, status) go to the wrapped
+// client.
+//
+// Plan mode runs the command on a canceled context so every wait phase ends
+// at once; the Recorder detaches reads from that cancellation. Calls on the
+// contexts the commands mark with container.WithCleanup clean the chaos up,
+// all others inject it.
+type Recorder struct {
+	*intercept.Client
+	mu      sync.Mutex
+	targets map[string]Target
+	steps   []Step
+}
+
+// NewRecorder returns a Recorder reading through client.
+func NewRecorder(client container.Client) *Recorder {
+	r := &Recorder{targets: map[string]Target{}}
+	r.Client = intercept.New(client, r)
+	return r
+}
+
+// Plan returns the recorded targets, ordered by name, and their steps, in
+// the order they were recorded per target.
+func (r *Recorder) Plan() *Plan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := &Plan{Targets: []Target{}, Steps: slices.Clone(r.steps)}
+	for _, t := range r.targets {
+		p.Targets = append(p.Targets, t)
+	}
+	slices.SortFunc(p.Targets, func(a, b Target) int { return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID)) })
+	slices.SortStableFunc(p.Steps, func(a, b Step) int { return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID)) })
+	if p.Steps == nil {
+		p.Steps = []Step{}
+	}
+	return p
+}
+
+// Write prints p as indented JSON.
+func (p *Plan) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// record adds a step on c; the phase is marked on ctx.
+func (r *Recorder) record(ctx context.Context, c *container.Container, step Step) {
+	step.Phase = PhaseInject
+	if container.IsCleanup(ctx) {
+		step.Phase = PhaseCleanup
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c != nil {
+		step.ID, step.Name = c.ID(), intercept.Name(c)
+		r.targets[c.ID()] = Target{ID: c.ID(), Name: step.Name, Labels: c.Labels}
+	}
+	r.steps = append(r.steps, step)
+}
+
+func duration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// Intercept reads through the wrapped client and records the changes.
+func (r *Recorder) Intercept(ctx context.Context, call *intercept.Call, next func(context.Context) error) error {
+	if call.Read {
+		return next(context.WithoutCancel(ctx))
+	}
+	switch req := call.Request.(type) {
+	case *container.IPTablesRequest:
+		r.record(ctx, call.Container, filterStep(call, req))
+	case *container.StressRequest:
+		// the plan runs in dry-run mode, where the command does not wait to
+		// stop the sidecar
+		r.record(ctx, call.Container, step(call))
+		r.record(container.WithCleanup(ctx), call.Container, Step{Action: "stop-sidecar", Params: map[string]string{"sidecar": "stress"}})
+		call.Result = &container.StressResult{}
+	case *container.ProxyRequest:
+		r.recordProxy(ctx, call, req)
+	default:
+		r.record(ctx, call.Container, step(call))
+	}
+	return nil
+}
+
+// step describes call.
+func step(call *intercept.Call) Step {
+	return Step{
+		Action: call.Action, Tool: call.Tool, Image: call.Image, Commands: call.Commands,
+		Duration: duration(call.Duration), Params: call.Params,
+	}
+}
+
+// recordProxy records the proxy sidecar and its nat redirects, or their
+// removal.
+func (r *Recorder) recordProxy(ctx context.Context, call *intercept.Call, req *container.ProxyRequest) {
+	if call.Cleanup {
+		r.record(ctx, req.Container, Step{Action: req.Name, Tool: "iptables", Image: req.Sidecar.Image, Commands: req.RedirectArgs("-D")})
+		r.record(ctx, req.Container, Step{Action: "stop-sidecar", Params: map[string]string{"sidecar": req.SidecarName()}})
+		return
+	}
+	r.record(ctx, req.Container, step(call))
+	r.record(ctx, req.Container, Step{Action: req.Name, Tool: "iptables", Image: req.Sidecar.Image, Commands: req.RedirectArgs("-I")})
+}
+
+// filterStep describes the rules of req with the backend it selects. The
+// auto backend is resolved by probing the target at run time, so the plan
+// shows the iptables rules with the backend noted. The packet capture params
+// of call are kept.
+func filterStep(call *intercept.Call, req *container.IPTablesRequest) Step {
+	step := Step{
+		Action: "iptables", Tool: "iptables", Image: req.Sidecar.Image, Commands: req.Commands(), Duration: duration(req.Duration),
+	}
+	params := map[string]string{}
+	for k, v := range call.Params {
+		if strings.HasPrefix(k, "capture") {
+			params[k] = v
+		}
+	}
+	switch req.Backend {
+	case container.FilterBackendNFTables:
+		if cmds, err := nftables.Translate(step.Commands); err == nil {
+			step.Tool, step.Commands = nftables.Tool, cmds
+		} else {
+			params["error"] = err.Error()
+		}
+	case container.FilterBackendAuto:
+		params["backend"] = "auto: nft when it works in the target network namespace, iptables otherwise"
+	}
+	if len(params) > 0 {
+		step.Params = params
+	}
+	return step
+}
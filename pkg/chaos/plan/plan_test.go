@@ -0,0 +1,135 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func canceled() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestRecorder_ReadsThroughDetached(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), mock.Anything, mock.Anything).
+		Return([]*container.Container{{ContainerID: "abc"}}, nil).Once()
+	client.EXPECT().NetemStatus(mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), mock.Anything).
+		Return("qdisc noqueue 0: root", nil).Once()
+
+	r := NewRecorder(client)
+	containers, err := r.ListContainers(canceled(), nil, container.ListOpts{})
+	require.NoError(t, err)
+	assert.Len(t, containers, 1)
+	out, err := r.NetemStatus(canceled(), &container.NetemRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "qdisc noqueue 0: root", out)
+}
+
+func TestRecorder_Plan(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web", Labels: map[string]string{"tier": "front"}}
+	db := &container.Container{ContainerID: "d1", ContainerName: "db"}
+	r := NewRecorder(container.NewMockClient(t))
+
+	netem := &container.NetemRequest{
+		Container: web, Interface: "eth0", Command: []string{"delay", "100ms"},
+		Sidecar: container.SidecarSpec{Image: "nettools"}, Duration: time.Minute,
+	}
+	require.NoError(t, r.NetemContainer(canceled(), netem))
+	_, err := r.StressContainer(canceled(), &container.StressRequest{
+		Container: db, Stressors: []string{"--cpu", "2"}, Sidecar: container.SidecarSpec{Image: "stress-ng"}, Duration: time.Minute,
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.StopNetemContainer(container.WithCleanup(canceled()), netem))
+
+	p := r.Plan()
+	assert.Equal(t, []Target{{ID: "d1", Name: "db"}, {ID: "w1", Name: "web", Labels: map[string]string{"tier": "front"}}}, p.Targets)
+	assert.Equal(t, []Step{
+		{Phase: PhaseInject, Action: "stress", ID: "d1", Name: "db", Tool: "stress-ng", Image: "stress-ng",
+			Commands: [][]string{{"--cpu", "2"}}, Duration: "1m0s"},
+		{Phase: PhaseCleanup, Action: "stop-sidecar", ID: "d1", Name: "db", Params: map[string]string{"sidecar": "stress"}},
+		{Phase: PhaseInject, Action: "netem", ID: "w1", Name: "web", Tool: "tc", Image: "nettools",
+			Commands: [][]string{{"qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms"}}, Duration: "1m0s"},
+		{Phase: PhaseCleanup, Action: "netem", ID: "w1", Name: "web", Tool: "tc", Image: "nettools",
+			Commands: [][]string{{"qdisc", "del", "dev", "eth0", "root", "netem"}}},
+	}, p.Steps)
+
+	var out bytes.Buffer
+	require.NoError(t, p.Write(&out))
+	assert.Contains(t, out.String(), `"phase": "inject"`)
+}
+
+func TestRecorder_Proxy(t *testing.T) {
+	r := NewRecorder(container.NewMockClient(t))
+	req := &container.ProxyRequest{
+		Container: &container.Container{ContainerID: "abc", ContainerName: "api"},
+		Name:      "dns", Command: []string{"dns-proxy", "--port", "5353"},
+		Redirects: [][]string{{"-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "5353"}},
+		Proxy:     container.SidecarSpec{Image: "pumba"}, Sidecar: container.SidecarSpec{Image: "nettools"},
+	}
+	require.NoError(t, r.ProxyContainer(canceled(), req))
+	require.NoError(t, r.StopProxyContainer(container.WithCleanup(canceled()), req))
+
+	p := r.Plan()
+	require.Len(t, p.Steps, 4)
+	assert.Equal(t, req.Command, p.Steps[0].Commands[0])
+	assert.Equal(t, "pumba-dns-proxy-abc", p.Steps[0].Params["sidecar"])
+	assert.Equal(t, req.RedirectArgs("-I"), p.Steps[1].Commands)
+	assert.Equal(t, req.RedirectArgs("-D"), p.Steps[2].Commands)
+	assert.Equal(t, PhaseCleanup, p.Steps[3].Phase)
+}
+
+func TestRecorder_IPTablesBackends(t *testing.T) {
+	r := NewRecorder(container.NewMockClient(t))
+	req := &container.IPTablesRequest{
+		Container: &container.Container{ContainerID: "abc", ContainerName: "api"},
+		CmdPrefix: []string{"-A", "INPUT", "-i", "eth0"}, CmdSuffix: []string{"-j", "DROP"},
+		Backend: container.FilterBackendNFTables,
+	}
+	require.NoError(t, r.IPTablesContainer(canceled(), req))
+	req.Backend = container.FilterBackendAuto
+	require.NoError(t, r.IPTablesContainer(canceled(), req))
+
+	p := r.Plan()
+	assert.Equal(t, "nft", p.Steps[0].Tool)
+	assert.Equal(t, "iptables", p.Steps[1].Tool)
+	assert.Equal(t, [][]string{{"-A", "INPUT", "-i", "eth0", "-j", "DROP"}}, p.Steps[1].Commands)
+	assert.Contains(t, p.Steps[1].Params["backend"], "auto")
+}
+
+func TestRecorder_Capture(t *testing.T) {
+	r := NewRecorder(container.NewMockClient(t))
+	target := &container.Container{ContainerID: "abc", ContainerName: "api"}
+	capture := &container.CaptureSpec{Dir: "/tmp/pcap", Interface: "eth0", Filter: "port 80", FileSize: 10, FileCount: 3, Image: "tcpdump"}
+	require.NoError(t, r.NetemContainer(canceled(), &container.NetemRequest{
+		Container: target, Interface: "eth0", Command: []string{"delay", "100ms"}, Capture: capture,
+	}))
+	require.NoError(t, r.IPTablesContainer(canceled(), &container.IPTablesRequest{
+		Container: target, CmdPrefix: []string{"-A", "INPUT"}, CmdSuffix: []string{"-j", "DROP"},
+		Backend: container.FilterBackendAuto, Capture: capture,
+	}))
+	require.NoError(t, r.IPTablesContainer(canceled(), &container.IPTablesRequest{
+		Container: target, CmdPrefix: []string{"-A", "INPUT"}, CmdSuffix: []string{"-j", "DROP"},
+	}))
+
+	p := r.Plan()
+	require.Len(t, p.Steps, 3)
+	want := map[string]string{
+		"capture": "/tmp/pcap", "capture-sidecar": "pumba-capture-abc", "capture-image": "tcpdump",
+		"capture-interface": "eth0", "capture-filter": "port 80", "capture-size": "10", "capture-files": "3",
+	}
+	assert.Equal(t, want, p.Steps[0].Params)
+	for k, v := range want {
+		assert.Equal(t, v, p.Steps[1].Params[k], k)
+	}
+	assert.Contains(t, p.Steps[1].Params["backend"], "auto")
+	assert.Nil(t, p.Steps[2].Params)
+}
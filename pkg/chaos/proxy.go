@@ -0,0 +1,52 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// proxyCleanupTimeout caps how long removing the redirects and the proxy
+// sidecar may take after abort or scheduled stop, independent of duration.
+const proxyCleanupTimeout = 30 * time.Second
+
+// RunProxy starts the proxy sidecar described by req, waits for duration or
+// abort and then removes the redirects and the proxy. A failed stop is only
+// logged: the target may be gone already. Shared by the proxy-based commands
+// (dns, http).
+func RunProxy(ctx context.Context, client container.Proxy, req *container.ProxyRequest, duration time.Duration) error {
+	logger := log.WithContext(ctx).WithFields(log.Fields{
+		"id":        req.Container.ID(),
+		"name":      req.Container.Name(),
+		"proxy":     req.Name,
+		"command":   req.Command,
+		"redirects": req.Redirects,
+		"duration":  duration,
+		"image":     req.Proxy.Image,
+		"pull":      req.Proxy.Pull,
+	})
+	logger.Debug("starting proxy")
+	if err := client.ProxyContainer(ctx, req); err != nil {
+		return fmt.Errorf("%s proxy failed: %w", req.Name, err)
+	}
+	logger.Debug("proxy started")
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		logger.Debug("stopping proxy on abort")
+	case <-stopCtx.Done():
+		logger.Debug("stopping proxy on timeout")
+	}
+	// the cleanup succeeds even if the parent ctx is canceled
+	cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), proxyCleanupTimeout)
+	defer cleanupCancel()
+	if err := client.StopProxyContainer(cleanupCtx, req); err != nil {
+		logger.WithError(err).Warn("failed to stop proxy (container may have been removed)")
+	}
+	return nil
+}
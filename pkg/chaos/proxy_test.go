@@ -0,0 +1,39 @@
+package chaos_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunProxy(t *testing.T) {
+	c := &container.Container{ContainerID: "abc", ContainerName: "c1"}
+	t.Run("stops after duration", func(t *testing.T) {
+		mockClient := container.NewMockProxy(t)
+		req := &container.ProxyRequest{Container: c, Name: "dns"}
+		mockClient.EXPECT().ProxyContainer(mock.Anything, req).Return(nil).Once()
+		mockClient.EXPECT().StopProxyContainer(mock.Anything, req).Return(nil).Once()
+		assert.NoError(t, chaos.RunProxy(context.Background(), mockClient, req, time.Millisecond))
+	})
+	t.Run("start failure skips stop", func(t *testing.T) {
+		mockClient := container.NewMockProxy(t)
+		req := &container.ProxyRequest{Container: c, Name: "dns"}
+		mockClient.EXPECT().ProxyContainer(mock.Anything, req).Return(errors.New("boom"))
+		err := chaos.RunProxy(context.Background(), mockClient, req, time.Second)
+		assert.ErrorContains(t, err, "dns proxy failed")
+	})
+	t.Run("abort stops proxy", func(t *testing.T) {
+		mockClient := container.NewMockProxy(t)
+		req := &container.ProxyRequest{Container: c, Name: "dns"}
+		ctx, cancel := context.WithCancel(context.Background())
+		mockClient.EXPECT().ProxyContainer(mock.Anything, req).Run(func(context.Context, *container.ProxyRequest) { cancel() }).Return(nil)
+		mockClient.EXPECT().StopProxyContainer(mock.Anything, req).Return(errors.New("gone"))
+		assert.NoError(t, chaos.RunProxy(ctx, mockClient, req, time.Hour))
+	})
+}
@@ -0,0 +1,207 @@
+// Package report records what a chaos command did, run by run, so CI can
+// track chaos results alongside test results. The Recorder wraps the runtime
+// client and records every runtime change and every state read-back; the
+// command runner marks the runs through the chaos.Observer methods. The
+// Report is written as JSON or as JUnit XML.
+package report
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+)
+
+// Report formats.
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// ParseFormat validates a --report-format value; empty selects FormatJSON.
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatJUnit:
+		return format, nil
+	default:
+		return "", fmt.Errorf("bad report format %q: must be json or junit", format)
+	}
+}
+
+// Report summarizes a chaos command run.
+type Report struct {
+	Name    string    `json:"name"`
+	Runtime string    `json:"runtime,omitempty"`
+	Command []string  `json:"command,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Ticks   []*Tick   `json:"ticks"`
+}
+
+// Tick is a single run of the command: once, or every --interval.
+type Tick struct {
+	Number     int       `json:"number"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Containers []string  `json:"containers"`
+	Actions    []Action  `json:"actions"`
+	Probes     []Probe   `json:"probes,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// Skipped marks an error skipped with --skip-error.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// Action is a runtime change made on a container.
+type Action struct {
+	Action string            `json:"action"`
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+	Start  time.Time         `json:"start"`
+	End    time.Time         `json:"end"`
+	// Cleanup marks the changes reverting an earlier one.
+	Cleanup bool   `json:"cleanup,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Probe is a read-back of the state of a container, e.g. by --verify.
+type Probe struct {
+	Probe  string    `json:"probe"`
+	ID     string    `json:"id"`
+	Name   string    `json:"name"`
+	Time   time.Time `json:"time"`
+	Output string    `json:"output,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Failed reports whether the run failed: a tick ended with an error that was
+// not skipped, or a cleanup failed and left chaos behind.
+func (r *Report) Failed() bool {
+	for _, t := range r.Ticks {
+		if t.Error != "" && !t.Skipped {
+			return true
+		}
+		for _, a := range t.Actions {
+			if a.Cleanup && a.Error != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Recorder is a container.Client intercepting its calls to record them.
+type Recorder struct {
+	*intercept.Client
+	mu    sync.Mutex
+	start time.Time
+	ticks []*Tick
+	now   func() time.Time
+}
+
+// NewRecorder returns a Recorder passing calls to client.
+func NewRecorder(client container.Client) *Recorder {
+	r := &Recorder{now: time.Now, start: time.Now()}
+	r.Client = intercept.New(client, r)
+	return r
+}
+
+// RunStarted opens a tick.
+func (r *Recorder) RunStarted() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ticks = append(r.ticks, &Tick{Number: len(r.ticks) + 1, Start: r.now(), Containers: []string{}, Actions: []Action{}})
+}
+
+// RunEnded closes the current tick with the error of the run; skipped
+// reports whether the error was skipped with --skip-error.
+func (r *Recorder) RunEnded(err error, skipped bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.tick()
+	t.End = r.now()
+	if err != nil {
+		t.Error, t.Skipped = err.Error(), skipped
+	}
+}
+
+// Report returns the ticks recorded so far.
+func (r *Recorder) Report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := &Report{Start: r.start, End: r.now(), Ticks: []*Tick{}}
+	for _, t := range r.ticks {
+		c := *t
+		c.Actions, c.Probes = slices.Clone(t.Actions), slices.Clone(t.Probes)
+		rep.Ticks = append(rep.Ticks, &c)
+	}
+	return rep
+}
+
+// tick returns the open tick; calls made outside a run open one.
+func (r *Recorder) tick() *Tick {
+	if len(r.ticks) == 0 {
+		r.ticks = append(r.ticks, &Tick{Number: 1, Start: r.now(), Containers: []string{}, Actions: []Action{}})
+	}
+	return r.ticks[len(r.ticks)-1]
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// reverts lists the actions that are a cleanup only when they follow
+// another one on the container in the same tick, e.g. the start of
+// `stop --restart`.
+var reverts = map[string]string{"start": "stop", "link": "link"}
+
+// Intercept runs the call and records it: a change as an action, a state
+// read-back as a probe.
+func (r *Recorder) Intercept(ctx context.Context, call *intercept.Call, next func(context.Context) error) error {
+	if call.Read {
+		err := next(ctx)
+		if call.Action != "list" {
+			out, _ := call.Result.(string)
+			r.probe(call.Container, call.Action, out, err)
+		}
+		return err
+	}
+	start := r.now()
+	err := next(ctx)
+	a := Action{Action: call.Action, Params: call.Fields(), Start: start, End: r.now(), Cleanup: call.Cleanup, Error: errString(err)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.tick()
+	if c := call.Container; c != nil {
+		a.ID, a.Name = c.ID(), intercept.Name(c)
+		if !slices.Contains(t.Containers, a.Name) {
+			t.Containers = append(t.Containers, a.Name)
+		}
+		if prior, ok := reverts[call.Action]; ok {
+			a.Cleanup = slices.ContainsFunc(t.Actions, func(p Action) bool { return p.ID == a.ID && p.Action == prior })
+		}
+	}
+	t.Actions = append(t.Actions, a)
+	return err
+}
+
+// probe records a read-back of the state of c.
+func (r *Recorder) probe(c *container.Container, probe, out string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := Probe{Probe: probe, Time: r.now(), Output: out, Error: errString(err)}
+	if c != nil {
+		p.ID, p.Name = c.ID(), intercept.Name(c)
+	}
+	t := r.tick()
+	t.Probes = append(t.Probes, p)
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRecorder returns a Recorder whose clock advances a second per read.
+func newTestRecorder(client container.Client) *Recorder {
+	r := NewRecorder(client)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.start = now
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return r
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatJSON, "json": FormatJSON, "junit": FormatJUnit} {
+		got, err := ParseFormat(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFormat("xml")
+	require.EqualError(t, err, `bad report format "xml": must be json or junit`)
+}
+
+func TestRecorder_Ticks(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().StopContainer(mock.Anything, web, 10, false).Return(nil).Once()
+	client.EXPECT().StartContainer(mock.Anything, web, false).Return(errors.New("no such container")).Once()
+	client.EXPECT().StartContainer(mock.Anything, web, false).Return(nil).Once()
+
+	r := newTestRecorder(client)
+	r.RunStarted()
+	require.NoError(t, r.StopContainer(context.TODO(), web, 10, false))
+	require.Error(t, r.StartContainer(context.TODO(), web, false))
+	r.RunEnded(errors.New("no such container"), true)
+	r.RunStarted()
+	require.NoError(t, r.StartContainer(context.TODO(), web, false))
+	r.RunEnded(nil, false)
+
+	rep := r.Report()
+	require.Len(t, rep.Ticks, 2)
+	first := rep.Ticks[0]
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, []string{"web"}, first.Containers)
+	assert.Equal(t, []Action{
+		{Action: "stop", ID: "w1", Name: "web", Params: map[string]string{"timeout": "10s"},
+			Start: r.start.Add(2 * time.Second), End: r.start.Add(3 * time.Second)},
+		{Action: "start", ID: "w1", Name: "web", Cleanup: true, Error: "no such container",
+			Start: r.start.Add(4 * time.Second), End: r.start.Add(5 * time.Second)},
+	}, first.Actions)
+	assert.Equal(t, "no such container", first.Error)
+	assert.True(t, first.Skipped)
+	assert.False(t, rep.Ticks[1].Actions[0].Cleanup, "a start without a prior stop is no cleanup")
+	assert.True(t, rep.Failed(), "a failed cleanup fails the run")
+}
+
+func TestRecorder_ProbesAndCleanup(t *testing.T) {
+	req := &container.NetemRequest{
+		Container: &container.Container{ContainerID: "a1", ContainerName: "api"},
+		Interface: "eth0", Command: []string{"delay", "100ms"}, Duration: time.Minute,
+	}
+	client := container.NewMockClient(t)
+	client.EXPECT().NetemContainer(mock.Anything, req).Return(nil).Once()
+	client.EXPECT().NetemStatus(mock.Anything, mock.Anything).Return("qdisc netem 8001: root", nil).Once()
+	client.EXPECT().StopNetemContainer(mock.Anything, req).Return(nil).Once()
+
+	r := newTestRecorder(client)
+	require.NoError(t, r.NetemContainer(context.TODO(), req))
+	_, err := r.NetemStatus(context.TODO(), req)
+	require.NoError(t, err)
+	require.NoError(t, r.StopNetemContainer(context.TODO(), req))
+
+	rep := r.Report()
+	require.Len(t, rep.Ticks, 1, "calls outside a run open a tick")
+	tick := rep.Ticks[0]
+	assert.Equal(t, map[string]string{"duration": "1m0s", "tc": "qdisc add dev eth0 root netem delay 100ms"}, tick.Actions[0].Params)
+	assert.False(t, tick.Actions[0].Cleanup)
+	assert.True(t, tick.Actions[1].Cleanup)
+	assert.Equal(t, []Probe{{Probe: "netem-status", ID: "a1", Name: "api", Time: r.start.Add(4 * time.Second), Output: "qdisc netem 8001: root"}}, tick.Probes)
+	assert.False(t, rep.Failed())
+}
+
+func TestRecorder_SignalCleanup(t *testing.T) {
+	c := &container.Container{ContainerID: "a1", ContainerName: "api"}
+	stop := &container.SignalRequest{Container: c, Process: "worker", Signal: "SIGSTOP"}
+	cont := &container.SignalRequest{Container: c, Process: "worker", PIDs: []int{7}, Signal: "SIGCONT"}
+	client := container.NewMockClient(t)
+	client.EXPECT().SignalProcesses(mock.Anything, stop).Return([]int{7}, nil).Once()
+	client.EXPECT().SignalProcesses(mock.Anything, cont).Return([]int{7}, nil).Once()
+
+	r := newTestRecorder(client)
+	pids, err := r.SignalProcesses(context.TODO(), stop)
+	require.NoError(t, err)
+	assert.Equal(t, []int{7}, pids)
+	_, err = r.SignalProcesses(context.TODO(), cont)
+	require.NoError(t, err)
+
+	actions := r.Report().Ticks[0].Actions
+	require.Len(t, actions, 2)
+	assert.Equal(t, map[string]string{"signal": "SIGSTOP", "process": "worker"}, actions[0].Params)
+	assert.False(t, actions[0].Cleanup)
+	assert.True(t, actions[1].Cleanup, "resuming stopped processes reverts the stop")
+}
+
+func TestReport_Failed(t *testing.T) {
+	assert.False(t, (&Report{Ticks: []*Tick{{Error: "skipped", Skipped: true}}}).Failed())
+	assert.True(t, (&Report{Ticks: []*Tick{{Error: "fatal"}}}).Failed())
+}
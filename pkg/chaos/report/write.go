@@ -0,0 +1,137 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write prints r in format.
+func (r *Report) Write(w io.Writer, format string) error {
+	if format == FormatJUnit {
+		return r.WriteJUnit(w)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func seconds(start, end time.Time) string {
+	if end.Before(start) {
+		return "0.000"
+	}
+	return strconv.FormatFloat(end.Sub(start).Seconds(), 'f', 3, 64)
+}
+
+func params(p map[string]string) string {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(p)) {
+		fmt.Fprintf(&b, "%s: %s\n", k, p[k])
+	}
+	return b.String()
+}
+
+// WriteJUnit prints r as JUnit XML: a test suite per tick with a test case
+// per action and probe, and a "run" test case carrying the tick error, as a
+// failure or, when skipped with --skip-error, as skipped.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	class := "pumba." + r.Name
+	suites := junitSuites{Name: "pumba " + r.Name, Time: seconds(r.Start, r.End)}
+	for _, t := range r.Ticks {
+		suite := junitSuite{
+			Name: fmt.Sprintf("%s tick %d", r.Name, t.Number), Time: seconds(t.Start, t.End),
+			Timestamp: t.Start.UTC().Format(time.RFC3339),
+		}
+		for _, a := range t.Actions {
+			name := a.Action + " " + a.Name
+			if a.Name == "" {
+				name = a.Action + " " + a.Params["sidecar"]
+			}
+			if a.Cleanup {
+				name += " (cleanup)"
+			}
+			tc := junitCase{Name: name, Classname: class, Time: seconds(a.Start, a.End), SystemOut: params(a.Params)}
+			if a.Error != "" {
+				tc.Failure = &junitMessage{Message: a.Error}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		for _, p := range t.Probes {
+			tc := junitCase{Name: p.Probe + " " + p.Name, Classname: class, Time: "0.000", SystemOut: p.Output}
+			if p.Error != "" {
+				tc.Failure = &junitMessage{Message: p.Error}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		run := junitCase{Name: "run", Classname: class, Time: seconds(t.Start, t.End)}
+		switch {
+		case t.Error != "" && t.Skipped:
+			run.Skipped = &junitMessage{Message: t.Error}
+		case t.Error != "":
+			run.Failure = &junitMessage{Message: t.Error}
+		}
+		suite.Cases = append(suite.Cases, run)
+		for _, tc := range suite.Cases {
+			suite.Tests++
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *Report {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &Report{
+		Name: "pause", Start: start, End: start.Add(time.Minute),
+		Ticks: []*Tick{
+			{
+				Number: 1, Start: start, End: start.Add(10 * time.Second), Containers: []string{"web"},
+				Actions: []Action{
+					{Action: "pause", ID: "w1", Name: "web", Start: start, End: start.Add(time.Second)},
+					{Action: "unpause", ID: "w1", Name: "web", Cleanup: true, Error: "not paused", Start: start.Add(9 * time.Second), End: start.Add(10 * time.Second)},
+				},
+			},
+			{Number: 2, Start: start.Add(30 * time.Second), End: start.Add(30 * time.Second), Containers: []string{}, Actions: []Action{}, Error: "no containers", Skipped: true},
+		},
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, testReport().Write(&out, FormatJUnit))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="pumba pause" tests="4" failures="1" skipped="1" time="60.000">
+  <testsuite name="pause tick 1" tests="3" failures="1" skipped="0" time="10.000" timestamp="2026-01-02T03:04:05Z">
+    <testcase name="pause web" classname="pumba.pause" time="1.000"></testcase>
+    <testcase name="unpause web (cleanup)" classname="pumba.pause" time="1.000">
+      <failure message="not paused"></failure>
+    </testcase>
+    <testcase name="run" classname="pumba.pause" time="10.000"></testcase>
+  </testsuite>
+  <testsuite name="pause tick 2" tests="1" failures="0" skipped="1" time="0.000" timestamp="2026-01-02T03:04:35Z">
+    <testcase name="run" classname="pumba.pause" time="0.000">
+      <skipped message="no containers"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, out.String())
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, testReport().Write(&out, FormatJSON))
+	var got Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, testReport(), &got)
+	assert.Contains(t, out.String(), `"skipped": true`)
+}
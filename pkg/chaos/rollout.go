@@ -0,0 +1,40 @@
+package chaos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+)
+
+// Rollout paces the actions of a run on its target containers.
+type Rollout struct {
+	// MaxParallel caps the actions of a parallel command running at once; 0
+	// leaves them unbounded.
+	MaxParallel int
+	// WaveSize, when set, applies the action to this many containers at a
+	// time: a wave starts once the one before it ended and WavePause passed.
+	WaveSize  int
+	WavePause time.Duration
+}
+
+// ParseRollout parses the global --max-parallel, --wave-size and --wave-pause
+// flags, dropping the pause in --plan mode. It returns nil without them.
+func ParseRollout(c cliflags.Flags) (*Rollout, error) {
+	g := c.Global()
+	r := &Rollout{MaxParallel: g.Int("max-parallel"), WaveSize: g.Int("wave-size"), WavePause: g.Duration("wave-pause")}
+	if r.MaxParallel < 0 || r.WaveSize < 0 || r.WavePause < 0 {
+		return nil, errors.New("--max-parallel, --wave-size and --wave-pause must not be negative")
+	}
+	if r.WavePause != 0 && r.WaveSize == 0 {
+		return nil, errors.New("--wave-pause needs --wave-size")
+	}
+	if g.Bool("plan") {
+		// a plan records every wave at once
+		r.WavePause = 0
+	}
+	if *r == (Rollout{}) {
+		return nil, nil //nolint:nilnil
+	}
+	return r, nil
+}
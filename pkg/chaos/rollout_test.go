@@ -0,0 +1,43 @@
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestParseRollout(t *testing.T) {
+	flags := []cli.Flag{
+		cli.IntFlag{Name: "max-parallel"},
+		cli.IntFlag{Name: "wave-size"},
+		cli.DurationFlag{Name: "wave-pause"},
+		cli.BoolFlag{Name: "plan"},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    *Rollout
+		wantErr string
+	}{
+		{name: "none", want: nil},
+		{name: "max parallel", args: []string{"--max-parallel", "10"}, want: &Rollout{MaxParallel: 10}},
+		{name: "waves", args: []string{"--wave-size", "5", "--wave-pause", "30s"}, want: &Rollout{WaveSize: 5, WavePause: 30 * time.Second}},
+		{name: "plan", args: []string{"--wave-size", "5", "--wave-pause", "30s", "--plan"}, want: &Rollout{WaveSize: 5}},
+		{name: "negative", args: []string{"--max-parallel", "-1"}, wantErr: "--max-parallel, --wave-size and --wave-pause must not be negative"},
+		{name: "pause without waves", args: []string{"--wave-pause", "30s"}, wantErr: "--wave-pause needs --wave-size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRollout(buildFlags(t, flags, tt.args, nil, nil))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,9 +3,14 @@ package chaos
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -18,7 +23,8 @@ type ContainerAction func(ctx context.Context, c *container.Container) error
 // RunOnContainers lists running containers matching gp.{Names,Pattern,Labels}
 // (capped by limit), optionally narrows to a single random pick when random
 // is true, then invokes fn for each container. parallel selects between
-// errgroup fanout (true) and a sequential for-loop (false). Returns nil when
+// errgroup fanout (true) and a sequential for-loop (false), both paced by
+// gp.Rollout (see FanOut). Returns nil when
 // no containers match — same warning the per-action loops used to log.
 //
 // The helper takes container.Lister rather than the per-action narrow client
@@ -81,6 +87,53 @@ func runOnContainers(
 			containers = []*container.Container{c}
 		}
 	}
+	if err := intercept.Targets(ctx, lister, containers); err != nil {
+		return err
+	}
+	return FanOut(ctx, gp, containers, parallel, func(ctx context.Context, c *container.Container) error {
+		return traced(ctx, gp, c, fn)
+	})
+}
+
+// FanOut invokes fn for each container, in parallel or one after another,
+// paced by gp.Rollout: at most MaxParallel parallel invocations at once, and
+// with a WaveSize, wave after wave of containers, WavePause apart. A failed
+// wave stops the rollout; so does ctx ending during a pause.
+func FanOut(ctx context.Context, gp *GlobalParams, containers []*container.Container, parallel bool, fn ContainerAction) error {
+	if len(containers) == 0 {
+		return nil
+	}
+	r := gp.Rollout
+	if r == nil {
+		r = &Rollout{}
+	}
+	size := r.WaveSize
+	if size == 0 || size > len(containers) {
+		size = len(containers)
+	}
+	waves := (len(containers) + size - 1) / size
+	for i := range waves {
+		wave := containers[i*size : min((i+1)*size, len(containers))]
+		if waves > 1 {
+			if i > 0 && r.WavePause > 0 {
+				select {
+				case <-ctx.Done():
+					log.WithField("wave", i+1).Info("chaos rollout stopped before the next wave")
+					return nil
+				case <-time.After(r.WavePause):
+				}
+			}
+			log.WithFields(log.Fields{"wave": i + 1, "waves": waves, "containers": len(wave)}).Info("rolling out chaos wave")
+			tracing.AddEvent(ctx, "pumba.wave", attribute.Int("pumba.wave", i+1), attribute.Int("pumba.wave.containers", len(wave)))
+		}
+		if err := fanOutWave(ctx, wave, parallel, r.MaxParallel, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fanOutWave(ctx context.Context, containers []*container.Container, parallel bool, maxParallel int, fn ContainerAction) error {
 	if !parallel {
 		for _, c := range containers {
 			if err := fn(ctx, c); err != nil {
@@ -90,8 +143,25 @@ func runOnContainers(
 		return nil
 	}
 	var eg errgroup.Group
+	if maxParallel > 0 {
+		eg.SetLimit(maxParallel)
+	}
 	for _, c := range containers {
 		eg.Go(func() error { return fn(ctx, c) })
 	}
 	return eg.Wait()
 }
+
+// traced runs fn on c, between the container hooks, in a span, the parent of
+// the runtime calls fn makes.
+func traced(ctx context.Context, gp *GlobalParams, c *container.Container, fn ContainerAction) error {
+	ctx, span := tracing.Start(ctx, "chaos.container",
+		tracing.AttrContainerID.String(c.ID()),
+		tracing.AttrContainerName.String(strings.TrimPrefix(c.Name(), "/")),
+	)
+	err := gp.Hooks.Run(ctx, HookEnv{Scope: HookScopeContainer, Container: c}, func(ctx context.Context) error {
+		return fn(ctx, c)
+	})
+	tracing.End(span, err)
+	return err
+}
@@ -3,8 +3,10 @@ package chaos_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/alexei-led/pumba/pkg/chaos"
 	"github.com/alexei-led/pumba/pkg/container"
@@ -238,3 +240,69 @@ func TestRunOnContainersAll_EmptyList(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, called, "fn must not run when no containers match")
 }
+
+func TestFanOut_MaxParallel(t *testing.T) {
+	gp := &chaos.GlobalParams{Rollout: &chaos.Rollout{MaxParallel: 2}}
+	var running, peak atomic.Int32
+	err := chaos.FanOut(context.Background(), gp, makeContainers("a", "b", "c", "d", "e"), true,
+		func(_ context.Context, _ *container.Container) error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), peak.Load(), "at most --max-parallel actions at once")
+}
+
+func TestFanOut_Waves(t *testing.T) {
+	gp := &chaos.GlobalParams{Rollout: &chaos.Rollout{WaveSize: 2, WavePause: 20 * time.Millisecond}}
+	var mu sync.Mutex
+	var done []time.Time
+	start := time.Now()
+	err := chaos.FanOut(context.Background(), gp, makeContainers("a", "b", "c", "d", "e"), true,
+		func(_ context.Context, _ *container.Container) error {
+			mu.Lock()
+			defer mu.Unlock()
+			done = append(done, time.Now())
+			return nil
+		})
+	require.NoError(t, err)
+	require.Len(t, done, 5)
+	assert.Less(t, done[1].Sub(start), 20*time.Millisecond, "the first wave starts at once")
+	assert.GreaterOrEqual(t, done[2].Sub(start), 20*time.Millisecond, "the second wave waits for the pause")
+	assert.GreaterOrEqual(t, done[4].Sub(start), 40*time.Millisecond, "the third wave waits for two pauses")
+}
+
+func TestFanOut_FailedWaveStopsRollout(t *testing.T) {
+	gp := &chaos.GlobalParams{Rollout: &chaos.Rollout{WaveSize: 1}}
+	wantErr := errors.New("fail-a")
+	var counter atomic.Int32
+	err := chaos.FanOut(context.Background(), gp, makeContainers("a", "b", "c"), false,
+		func(_ context.Context, _ *container.Container) error {
+			counter.Add(1)
+			return wantErr
+		})
+	require.ErrorIs(t, err, wantErr)
+	assert.Equal(t, int32(1), counter.Load())
+}
+
+func TestFanOut_CanceledDuringPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	gp := &chaos.GlobalParams{Rollout: &chaos.Rollout{WaveSize: 1, WavePause: time.Hour}}
+	var counter atomic.Int32
+	err := chaos.FanOut(ctx, gp, makeContainers("a", "b"), true,
+		func(_ context.Context, _ *container.Container) error {
+			counter.Add(1)
+			cancel()
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), counter.Load(), "no wave starts once the context ended")
+}
@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a standard five-field cron expression: minute, hour, day of month,
+// month and day of week, evaluated in the local time zone unless it starts
+// with TZ=<zone> or CRON_TZ=<zone>.
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	// like cron(8), restricted day of month and day of week fields match
+	// when either does
+	anyDay bool
+	loc    *time.Location
+}
+
+// cronField is the range of values, and their names, one field accepts.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is Sunday too
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronHorizon bounds the search for the next matching time.
+const cronHorizon = 5 // years
+
+// ParseCron parses a cron expression: five fields of values, names (jan,
+// mon), ranges, steps and lists, like "*/15 10-15 * * mon-fri", or one of
+// @yearly, @monthly, @weekly, @daily and @hourly.
+func ParseCron(expr string) (*Cron, error) {
+	c := &Cron{expr: expr, loc: time.Local}
+	spec := strings.TrimSpace(expr)
+	if zone, rest, ok := cutZone(spec); ok {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("bad cron expression %q: %w", expr, err)
+		}
+		c.loc, spec = loc, rest
+	}
+	if d, ok := cronDescriptors[spec]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("bad cron expression %q: expected 5 fields: minute hour day-of-month month day-of-week", expr)
+	}
+	for i, dst := range []*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow} {
+		bits, err := parseCronField(fields[i], cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("bad cron expression %q: %w", expr, err)
+		}
+		*dst = bits
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("bad cron expression %q: never matches", expr)
+	}
+	return c, nil
+}
+
+func cutZone(spec string) (string, string, bool) {
+	for _, prefix := range []string{"TZ=", "CRON_TZ="} {
+		if rest, ok := strings.CutPrefix(spec, prefix); ok {
+			zone, rest, _ := strings.Cut(rest, " ")
+			return zone, strings.TrimSpace(rest), true
+		}
+	}
+	return "", spec, false
+}
+
+// parseCronField returns the values s selects as a bit set.
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(s, ",") {
+		rng, stepStr, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad %s step %q", f.name, part)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" && rng != "?" {
+			from, to, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			case !stepped:
+				hi = lo
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("bad %s range %q", f.name, rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or name of the field.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad %s %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
+
+// Matches reports whether the minute of t is one the expression selects.
+func (c *Cron) Matches(t time.Time) bool {
+	t = t.In(c.loc)
+	return has(c.minute, t.Minute()) && has(c.hour, t.Hour()) && has(c.month, int(t.Month())) && c.matchesDay(t)
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	if c.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first minute after t the expression selects, or the zero
+// time when there is none within five years.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.In(c.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronHorizon, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+		case !has(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// String returns the expression as given.
+func (c *Cron) String() string {
+	return c.expr
+}
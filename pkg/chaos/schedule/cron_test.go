@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04 Mon", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCron_Next(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{expr: "* * * * *", from: "2026-10-16 10:07 Fri", want: "2026-10-16 10:08 Fri"},
+		{expr: "*/15 10-15 * * mon-fri", from: "2026-10-16 10:07 Fri", want: "2026-10-16 10:15 Fri"},
+		{expr: "*/15 10-15 * * mon-fri", from: "2026-10-16 15:45 Fri", want: "2026-10-19 10:00 Mon"},
+		{expr: "0 9 * * 7", from: "2026-10-16 10:07 Fri", want: "2026-10-18 09:00 Sun"},
+		{expr: "30 4 1,15 * 5", from: "2026-10-16 10:07 Fri", want: "2026-10-23 04:30 Fri"},
+		{expr: "0 0 29 feb *", from: "2026-10-16 10:07 Fri", want: "2028-02-29 00:00 Tue"},
+		{expr: "@monthly", from: "2026-12-16 10:07 Wed", want: "2027-01-01 00:00 Fri"},
+		{expr: "5/20 * * * *", from: "2026-10-16 10:07 Fri", want: "2026-10-16 10:25 Fri"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron("TZ=UTC " + tt.expr)
+			require.NoError(t, err)
+			next := c.Next(date(tt.from))
+			assert.Equal(t, date(tt.want), next)
+			assert.True(t, c.Matches(next))
+			assert.False(t, c.Matches(next.Add(-time.Minute)) && tt.expr != "* * * * *")
+		})
+	}
+}
+
+func TestCron_TimeZone(t *testing.T) {
+	c, err := ParseCron("CRON_TZ=Asia/Tokyo 0 9 * * *")
+	require.NoError(t, err)
+	assert.Equal(t, date("2026-10-17 00:00 Sat"), c.Next(date("2026-10-16 10:07 Fri")).UTC(), "09:00 in Tokyo")
+	assert.Equal(t, "CRON_TZ=Asia/Tokyo 0 9 * * *", c.String())
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := map[string]string{
+		"* * * *":        `bad cron expression "* * * *": expected 5 fields: minute hour day-of-month month day-of-week`,
+		"60 * * * *":     `bad cron expression "60 * * * *": minute 60 out of range 0-59`,
+		"* * * foo *":    `bad cron expression "* * * foo *": bad month "foo"`,
+		"*/0 * * * *":    `bad cron expression "*/0 * * * *": bad minute step "*/0"`,
+		"* 5-2 * * *":    `bad cron expression "* 5-2 * * *": bad hour range "5-2"`,
+		"0 0 30 feb *":   `bad cron expression "0 0 30 feb *": never matches`,
+		"TZ=Mars/Base *": `bad cron expression "TZ=Mars/Base *": unknown time zone Mars/Base`,
+	}
+	for expr, want := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			require.EqualError(t, err, want)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package schedule decides when recurring chaos runs: on a fixed interval or
+// a cron expression, with a random jitter, skipping blackout windows, for a
+// bounded number of runs or time.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+)
+
+// Schedule decides when the runs of a chaos command are due.
+type Schedule struct {
+	// Interval is the wait between runs, without Cron.
+	Interval time.Duration
+	// Cron, when set, makes runs due at the minutes it selects.
+	Cron *Cron
+	// Jitter bounds a random wait added to every wait for a run.
+	Jitter time.Duration
+	// Blackouts are the windows during which due runs are skipped.
+	Blackouts []Window
+	// MaxRuns, when positive, ends the chaos after that many runs.
+	MaxRuns int
+	// Deadline, when positive, ends the chaos that long after it started.
+	Deadline time.Duration
+}
+
+// Parse parses the global schedule flags: --interval, --cron, --jitter,
+// --blackout, --max-runs and --deadline.
+func Parse(c cliflags.Flags) (*Schedule, error) {
+	g := c.Global()
+	s := &Schedule{
+		Interval: g.Duration("interval"),
+		Jitter:   g.Duration("jitter"),
+		MaxRuns:  g.Int("max-runs"),
+		Deadline: g.Duration("deadline"),
+	}
+	if expr := g.String("cron"); expr != "" {
+		if s.Interval != 0 {
+			return nil, errors.New("--cron cannot be used with --interval")
+		}
+		var err error
+		if s.Cron, err = ParseCron(expr); err != nil {
+			return nil, err
+		}
+	}
+	for _, spec := range g.StringSlice("blackout") {
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		s.Blackouts = append(s.Blackouts, w)
+	}
+	if s.Jitter < 0 || s.MaxRuns < 0 || s.Deadline < 0 {
+		return nil, errors.New("--jitter, --max-runs and --deadline must not be negative")
+	}
+	if (s.Jitter != 0 || s.MaxRuns != 0 || s.Deadline != 0) && !s.Recurring() {
+		return nil, errors.New("--jitter, --max-runs and --deadline need --interval or --cron")
+	}
+	return s, nil
+}
+
+// Recurring reports whether more than one run is due.
+func (s *Schedule) Recurring() bool {
+	return s.Interval > 0 || s.Cron != nil
+}
+
+// First returns when the first run is due: at once, or at the first minute
+// Cron selects. It is the zero time when Cron never matches again.
+func (s *Schedule) First(now time.Time) time.Time {
+	if s.Cron == nil {
+		return now
+	}
+	return s.jitter(s.Cron.Next(now))
+}
+
+// Next returns when the run after the one due at prev is due, as of now: an
+// Interval after prev, or the next minute Cron selects after now, plus a
+// jitter. Runs missed while the previous one went on are dropped, like a
+// time.Ticker drops ticks. It is the zero time when Cron never matches again.
+func (s *Schedule) Next(prev, now time.Time) time.Time {
+	if s.Cron != nil {
+		return s.jitter(s.Cron.Next(now))
+	}
+	next := s.jitter(prev.Add(s.Interval))
+	if next.Before(now) {
+		return now
+	}
+	return next
+}
+
+func (s *Schedule) jitter(t time.Time) time.Time {
+	if s.Jitter <= 0 || t.IsZero() {
+		return t
+	}
+	return t.Add(time.Duration(rand.Int63n(int64(s.Jitter)))) //nolint:gosec
+}
+
+// Blackout returns the blackout window t falls in, or nil.
+func (s *Schedule) Blackout(t time.Time) Window {
+	for _, w := range s.Blackouts {
+		if w.Contains(t) {
+			return w
+		}
+	}
+	return nil
+}
+
+// Window is a period during which no chaos runs.
+type Window interface {
+	Contains(t time.Time) bool
+	String() string
+}
+
+// ParseWindow parses a blackout window: a cron expression, blacking out the
+// minutes it selects, like "* * * * sat,sun", or an absolute period
+// "<RFC3339 start>/<RFC3339 end>".
+func ParseWindow(spec string) (Window, error) {
+	if from, to, ok := strings.Cut(spec, "/"); ok && !strings.ContainsAny(spec, " \t") {
+		start, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("bad blackout window %q: %w", spec, err)
+		}
+		end, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("bad blackout window %q: %w", spec, err)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("bad blackout window %q: ends before it starts", spec)
+		}
+		return period{start: start, end: end, spec: spec}, nil
+	}
+	c, err := ParseCron(spec)
+	if err != nil {
+		return nil, fmt.Errorf("bad blackout window: %w", err)
+	}
+	return cronWindow{c}, nil
+}
+
+// period is a blackout window from start until end.
+type period struct {
+	start, end time.Time
+	spec       string
+}
+
+func (p period) Contains(t time.Time) bool {
+	return !t.Before(p.start) && t.Before(p.end)
+}
+
+func (p period) String() string {
+	return p.spec
+}
+
+// cronWindow blacks out the minutes a cron expression selects.
+type cronWindow struct {
+	*Cron
+}
+
+func (w cronWindow) Contains(t time.Time) bool {
+	return w.Matches(t)
+}
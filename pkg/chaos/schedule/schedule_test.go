@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func globalFlags(t *testing.T, args ...string) cliflags.Flags {
+	t.Helper()
+	fs := flag.NewFlagSet("pumba", flag.ContinueOnError)
+	for _, f := range []cli.Flag{
+		cli.DurationFlag{Name: "interval"},
+		cli.StringFlag{Name: "cron"},
+		cli.DurationFlag{Name: "jitter"},
+		cli.StringSliceFlag{Name: "blackout"},
+		cli.IntFlag{Name: "max-runs"},
+		cli.DurationFlag{Name: "deadline"},
+	} {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse(args))
+	return cliflags.NewV1(cli.NewContext(cli.NewApp(), fs, nil))
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse(globalFlags(t, "--cron", "0 * * * *", "--jitter", "1m", "--max-runs", "3", "--deadline", "8h",
+		"--blackout", "* * * * sat,sun", "--blackout", "2026-12-20T00:00:00Z/2027-01-04T00:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, "0 * * * *", s.Cron.String())
+	assert.Equal(t, time.Minute, s.Jitter)
+	assert.Equal(t, 3, s.MaxRuns)
+	assert.Equal(t, 8*time.Hour, s.Deadline)
+	require.Len(t, s.Blackouts, 2)
+	assert.True(t, s.Recurring())
+
+	s, err = Parse(globalFlags(t))
+	require.NoError(t, err)
+	assert.False(t, s.Recurring(), "a single run")
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--cron", "@hourly", "--interval", "1m"}, want: "--cron cannot be used with --interval"},
+		{args: []string{"--max-runs", "3"}, want: "--jitter, --max-runs and --deadline need --interval or --cron"},
+		{args: []string{"--interval", "1m", "--jitter", "-1s"}, want: "--jitter, --max-runs and --deadline must not be negative"},
+		{args: []string{"--blackout", "weekends"}, want: `bad blackout window: bad cron expression "weekends": expected 5 fields: minute hour day-of-month month day-of-week`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			_, err := Parse(globalFlags(t, tt.args...))
+			require.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	start := date("2026-10-16 10:00 Fri")
+	s := &Schedule{Interval: time.Minute}
+	assert.Equal(t, start, s.First(start), "interval runs start at once")
+	assert.Equal(t, start.Add(time.Minute), s.Next(start, start.Add(10*time.Second)))
+	late := start.Add(90 * time.Second)
+	assert.Equal(t, late, s.Next(start, late), "a run taking longer than the interval is followed at once")
+
+	s.Jitter = 10 * time.Second
+	for range 100 {
+		next := s.Next(start, start)
+		assert.False(t, next.Before(start.Add(time.Minute)))
+		assert.True(t, next.Before(start.Add(time.Minute+10*time.Second)))
+	}
+
+	c, err := ParseCron("TZ=UTC 0 * * * *")
+	require.NoError(t, err)
+	s = &Schedule{Cron: c}
+	assert.Equal(t, date("2026-10-16 11:00 Fri"), s.First(start.Add(time.Second)))
+	assert.Equal(t, date("2026-10-16 13:00 Fri"), s.Next(date("2026-10-16 11:00 Fri"), date("2026-10-16 12:30 Fri")),
+		"missed cron times are dropped")
+}
+
+func TestSchedule_Blackout(t *testing.T) {
+	weekend, err := ParseWindow("TZ=UTC * * * * sat,sun")
+	require.NoError(t, err)
+	freeze, err := ParseWindow("2026-12-20T00:00:00Z/2027-01-04T00:00:00Z")
+	require.NoError(t, err)
+	s := &Schedule{Blackouts: []Window{weekend, freeze}}
+
+	assert.Nil(t, s.Blackout(date("2026-10-16 10:00 Fri")))
+	assert.Equal(t, weekend, s.Blackout(date("2026-10-17 10:00 Sat")))
+	assert.Equal(t, freeze, s.Blackout(date("2026-12-22 10:00 Tue")))
+	assert.Nil(t, s.Blackout(date("2027-01-04 00:00 Mon")), "the end of a period is not blacked out")
+	assert.Equal(t, "2026-12-20T00:00:00Z/2027-01-04T00:00:00Z", freeze.String())
+}
+
+func TestParseWindow_Errors(t *testing.T) {
+	_, err := ParseWindow("2026-12-20/2027-01-04")
+	require.ErrorContains(t, err, `bad blackout window "2026-12-20/2027-01-04": parsing time`)
+	_, err = ParseWindow("2027-01-04T00:00:00Z/2026-12-20T00:00:00Z")
+	require.EqualError(t, err, `bad blackout window "2027-01-04T00:00:00Z/2026-12-20T00:00:00Z": ends before it starts`)
+}
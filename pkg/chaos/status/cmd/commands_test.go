@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestParseStatusParams(t *testing.T) {
+	cmd := NewStatusCLICommand(context.Background(), func() container.Client { return nil })
+	assert.Equal(t, "status", cmd.Name)
+
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	for _, f := range cmd.Flags {
+		f.Apply(fs)
+	}
+	require.NoError(t, fs.Parse([]string{"--json", "--stopped"}))
+	p, err := parseStatusParams(cliflags.NewV1(cli.NewContext(cli.NewApp(), fs, nil)), &chaos.GlobalParams{})
+	require.NoError(t, err)
+	assert.True(t, p.JSON)
+	assert.True(t, p.Stopped)
+	assert.True(t, p.Pull, "BoolT pull-image defaults true")
+	assert.Equal(t, "ghcr.io/alexei-led/pumba-alpine-nettools:latest", p.Image)
+
+	built, err := buildStatusCommand(container.NewMockClient(t), &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	assert.NotNil(t, built)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	chaoscmd "github.com/alexei-led/pumba/pkg/chaos/cmd"
+	"github.com/alexei-led/pumba/pkg/chaos/status"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/urfave/cli"
+)
+
+// NewStatusCLICommand initialize CLI status command.
+func NewStatusCLICommand(ctx context.Context, runtime chaos.Runtime) *cli.Command {
+	return chaoscmd.NewAction(ctx, runtime, chaoscmd.Spec[status.Params]{
+		Name: "status",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "print a JSON document instead of a table",
+			},
+			cli.BoolFlag{
+				Name:  "stopped",
+				Usage: "also report exited containers; the runtime does not record who stopped a container, so they may not have been stopped by Pumba",
+			},
+			cli.StringFlag{
+				Name:  "image",
+				Usage: "Docker image with tc, iptables and nft, used to inspect container network namespaces (empty: run the tools in the container)",
+				Value: "ghcr.io/alexei-led/pumba-alpine-nettools:latest",
+			},
+			cli.BoolTFlag{
+				Name:  "pull-image",
+				Usage: "force pull image",
+			},
+		},
+		Usage:       "show the chaos currently active on containers",
+		ArgsUsage:   fmt.Sprintf("containers to inspect (name, list of names, or RE2 regex if prefixed with %q; default: all)", chaos.Re2Prefix),
+		Description: "lists, per container, active netem qdiscs, Pumba iptables rules and nftables table, paused containers and running Pumba sidecars (e.g. stress-ng), detected from the runtime and network namespace state",
+		Parse:       parseStatusParams,
+		Build:       buildStatusCommand,
+	})
+}
+
+func parseStatusParams(c cliflags.Flags, _ *chaos.GlobalParams) (status.Params, error) {
+	return status.Params{
+		Image:   c.String("image"),
+		Pull:    c.BoolT("pull-image"),
+		Stopped: c.Bool("stopped"),
+		JSON:    c.Bool("json"),
+	}, nil
+}
+
+func buildStatusCommand(client container.Client, gp *chaos.GlobalParams, p status.Params) (chaos.Command, error) {
+	return status.NewStatusCommand(client, gp, &p)
+}
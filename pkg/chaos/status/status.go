@@ -0,0 +1,208 @@
+// Package status implements the `status` command reporting the chaos that
+// is currently active on a host: netem qdiscs, Pumba iptables rules and
+// nftables table in container network namespaces, paused (and optionally
+// stopped) containers and running Pumba sidecars such as stress-ng. It
+// answers "is this degradation self-inflicted?" without reading logs.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/chaos/artifacts"
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// Kinds of active chaos.
+const (
+	KindNetem    = "netem"
+	KindIPTables = "iptables"
+	KindNFTables = "nftables"
+	KindPaused   = "paused"
+	// KindPausedUnknown is a paused container without Pumba evidence:
+	// runtimes do not record who paused a container.
+	KindPausedUnknown = "paused-unknown"
+	KindStopped       = "stopped"
+	KindSidecar       = "sidecar"
+)
+
+const shortIDLen = 12
+
+// statusClient is the narrow interface needed by the status command.
+type statusClient interface {
+	container.Lister
+	container.Netem
+	container.IPTables
+}
+
+// Params holds the parsed parameters of the status command.
+type Params struct {
+	// Image is the nettools image used to inspect the target network
+	// namespaces; empty runs tc and iptables in the target itself.
+	Image string
+	Pull  bool
+	// Stopped also reports exited containers. Runtimes do not record who
+	// stopped a container, so these may not have been stopped by Pumba.
+	Stopped bool
+	// JSON prints a JSON document instead of a table.
+	JSON bool
+}
+
+// Activity is a piece of chaos active on a container.
+type Activity struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ContainerStatus lists the chaos active on a container.
+type ContainerStatus struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	State      string     `json:"state"`
+	Activities []Activity `json:"activities"`
+}
+
+// `status` command
+type statusCommand struct {
+	client statusClient
+	gp     *chaos.GlobalParams
+	p      *Params
+	// pull is cleared once the nettools image has been pulled
+	pull bool
+	out  io.Writer
+}
+
+// NewStatusCommand create new status command
+func NewStatusCommand(client statusClient, gp *chaos.GlobalParams, p *Params) (chaos.Command, error) {
+	return &statusCommand{client: client, gp: gp, p: p, pull: p.Pull, out: os.Stdout}, nil
+}
+
+// Run status command
+func (n *statusCommand) Run(ctx context.Context, _ bool) error {
+	statuses, err := n.collect(ctx)
+	if err != nil && statuses == nil {
+		return err
+	}
+	if werr := n.write(statuses); werr != nil {
+		return errors.Join(err, fmt.Errorf("failed to write status: %w", werr))
+	}
+	return err
+}
+
+// sidecar returns the nettools sidecar of the next runtime call, pulling the
+// image only the first time.
+func (n *statusCommand) sidecar() container.SidecarSpec {
+	spec := container.SidecarSpec{Image: n.p.Image, Pull: n.pull}
+	if n.p.Image != "" {
+		n.pull = false
+	}
+	return spec
+}
+
+// collect returns the matching containers with active chaos, in listing
+// order. Failed namespace scans are returned as errors next to the
+// statuses found elsewhere.
+func (n *statusCommand) collect(ctx context.Context) ([]ContainerStatus, error) {
+	targets, err := container.ListNContainersAll(ctx, n.client, n.gp.Names, n.gp.Pattern, n.gp.Labels, 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	sidecars, err := n.client.ListContainers(ctx, (*container.Container).IsPumbaSidecar, container.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sidecar containers: %w", err)
+	}
+	statuses := []ContainerStatus{}
+	var errs []error
+	for _, c := range targets {
+		var activities []Activity
+		own := ownSidecars(sidecars, c)
+		switch {
+		// a live sidecar created for a paused container, such as the
+		// stress-ng sidecar of a container paused while stressed, shows
+		// Pumba is at work on it
+		case c.State == container.StatePaused && len(own) > 0:
+			activities = append(activities, Activity{Kind: KindPaused})
+		case c.State == container.StatePaused:
+			activities = append(activities, Activity{Kind: KindPausedUnknown, Detail: "origin unknown"})
+		case c.State == container.StateExited && n.p.Stopped:
+			activities = append(activities, Activity{Kind: KindStopped})
+		case c.State == container.StateRunning:
+			log.WithFields(log.Fields{"name": c.Name(), "id": c.ID()}).Debug("scanning container network namespace")
+			ns, err := artifacts.ScanNetNS(ctx, n.client, c, n.sidecar)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			activities = append(activities, netnsActivities(ns)...)
+		}
+		for _, s := range own {
+			activities = append(activities, Activity{Kind: KindSidecar, Detail: s.SidecarKind() + " " + strings.TrimPrefix(s.Name(), "/")})
+		}
+		if len(activities) > 0 {
+			statuses = append(statuses, ContainerStatus{ID: c.ID(), Name: strings.TrimPrefix(c.Name(), "/"), State: c.State, Activities: activities})
+		}
+	}
+	return statuses, errors.Join(errs...)
+}
+
+// ownSidecars returns the running Pumba sidecars created for c.
+func ownSidecars(sidecars []*container.Container, c *container.Container) []*container.Container {
+	var own []*container.Container
+	for _, s := range sidecars {
+		if s.SidecarTarget() == c.ID() {
+			own = append(own, s)
+		}
+	}
+	return own
+}
+
+// netnsActivities describes the artifacts found in a network namespace.
+func netnsActivities(ns *artifacts.NetNS) []Activity {
+	var activities []Activity
+	for _, q := range ns.Netem {
+		detail := q.Dev + ": " + q.Options
+		if q.Filtered {
+			detail += " (filtered)"
+		}
+		activities = append(activities, Activity{Kind: KindNetem, Detail: detail})
+	}
+	for _, rule := range ns.Rules {
+		activities = append(activities, Activity{Kind: KindIPTables, Detail: strings.Join(rule, " ")})
+	}
+	if ns.NFTable {
+		activities = append(activities, Activity{Kind: KindNFTables, Detail: "table inet pumba"})
+	}
+	return activities
+}
+
+// write prints statuses as a table, one row per activity, or as JSON.
+func (n *statusCommand) write(statuses []ContainerStatus) error {
+	if n.p.JSON {
+		enc := json.NewEncoder(n.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	}
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(n.out, "no active chaos found")
+		return err
+	}
+	w := tabwriter.NewWriter(n.out, 0, 0, 2, ' ', 0) //nolint:mnd
+	fmt.Fprintln(w, "CONTAINER\tID\tSTATE\tCHAOS\tDETAIL")
+	for _, s := range statuses {
+		id := s.ID
+		if len(id) > shortIDLen {
+			id = id[:shortIDLen]
+		}
+		for _, a := range s.Activities {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, id, s.State, a.Kind, a.Detail)
+		}
+	}
+	return w.Flush()
+}
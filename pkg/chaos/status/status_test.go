@@ -0,0 +1,126 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/chaos"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectListings sets up the target listing (all states) and the running
+// sidecar listing.
+func expectListings(mockClient *container.MockClient, targets, sidecars []*container.Container) {
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{All: true}).
+		Return(targets, nil).Once()
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.AnythingOfType("container.FilterFunc"), container.ListOpts{}).
+		Return(sidecars, nil).Once()
+}
+
+func newTestCommand(t *testing.T, mockClient *container.MockClient, p *Params) (*statusCommand, *bytes.Buffer) {
+	t.Helper()
+	cmd, err := NewStatusCommand(mockClient, &chaos.GlobalParams{}, p)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	cmd.(*statusCommand).out = &out
+	return cmd.(*statusCommand), &out
+}
+
+func TestStatusCommand_Run(t *testing.T) {
+	web := &container.Container{ContainerID: "0123456789abcdef", ContainerName: "/web", State: container.StateRunning}
+	db := &container.Container{ContainerID: "db1", ContainerName: "/db", State: container.StatePaused}
+	worker := &container.Container{ContainerID: "w1", ContainerName: "/worker", State: container.StateRunning}
+	old := &container.Container{ContainerID: "o1", ContainerName: "/old", State: container.StateExited}
+	stress := &container.Container{ContainerID: "s1", ContainerName: "/pumba-stress-1", Labels: container.SidecarLabels(container.SidecarKindStress, "w1")}
+
+	mockClient := container.NewMockClient(t)
+	expectListings(mockClient, []*container.Container{web, db, worker, old}, []*container.Container{stress})
+	mockClient.EXPECT().NetemStatus(mock.Anything, mock.MatchedBy(func(r *container.NetemRequest) bool { return r.Container == web })).
+		Return("qdisc netem 8001: dev eth0 root refcnt 2 limit 1000 delay 100ms\n", nil).Once()
+	mockClient.EXPECT().NetemStatus(mock.Anything, mock.MatchedBy(func(r *container.NetemRequest) bool { return r.Container == worker })).
+		Return("qdisc noqueue 0: dev eth0 root refcnt 2\n", nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Container == web && r.Backend == container.FilterBackendIPTables
+	})).Return("-A INPUT -i eth0 -m statistic --mode random --probability 0.10000000000 -j DROP\n", nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Container == worker && r.Backend == container.FilterBackendIPTables
+	})).Return("", nil).Once()
+	mockClient.EXPECT().IPTablesStatus(mock.Anything, mock.MatchedBy(func(r *container.IPTablesRequest) bool {
+		return r.Backend == container.FilterBackendNFTables
+	})).Return("", errors.New("nft: not found")).Twice()
+
+	cmd, out := newTestCommand(t, mockClient, &Params{JSON: true})
+	require.NoError(t, cmd.Run(context.Background(), false))
+
+	var got []ContainerStatus
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, []ContainerStatus{
+		{ID: "0123456789abcdef", Name: "web", State: container.StateRunning, Activities: []Activity{
+			{Kind: KindNetem, Detail: "eth0: limit 1000 delay 100ms"},
+			{Kind: KindIPTables, Detail: "-A INPUT -i eth0 -m statistic --mode random --probability 0.10000000000 -j DROP"},
+		}},
+		{ID: "db1", Name: "db", State: container.StatePaused, Activities: []Activity{{Kind: KindPausedUnknown, Detail: "origin unknown"}}},
+		{ID: "w1", Name: "worker", State: container.StateRunning, Activities: []Activity{{Kind: KindSidecar, Detail: "stress pumba-stress-1"}}},
+	}, got, "exited containers are only reported with Stopped")
+}
+
+func TestStatusCommand_Table(t *testing.T) {
+	db := &container.Container{ContainerID: "db1", ContainerName: "/db", State: container.StatePaused}
+	old := &container.Container{ContainerID: "o1", ContainerName: "/old", State: container.StateExited}
+	mockClient := container.NewMockClient(t)
+	expectListings(mockClient, []*container.Container{db, old}, nil)
+
+	cmd, out := newTestCommand(t, mockClient, &Params{Stopped: true})
+	require.NoError(t, cmd.Run(context.Background(), false))
+	assert.Equal(t, "CONTAINER  ID   STATE   CHAOS           DETAIL\n"+
+		"db         db1  paused  paused-unknown  origin unknown\n"+
+		"old        o1   exited  stopped         \n", out.String())
+}
+
+func TestStatusCommand_PausedByPumba(t *testing.T) {
+	db := &container.Container{ContainerID: "db1", ContainerName: "/db", State: container.StatePaused}
+	stress := &container.Container{ContainerID: "s1", ContainerName: "/pumba-stress-1", Labels: container.SidecarLabels(container.SidecarKindStress, "db1")}
+	mockClient := container.NewMockClient(t)
+	expectListings(mockClient, []*container.Container{db}, []*container.Container{stress})
+
+	cmd, out := newTestCommand(t, mockClient, &Params{JSON: true})
+	require.NoError(t, cmd.Run(context.Background(), false))
+
+	var got []ContainerStatus
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, []ContainerStatus{
+		{ID: "db1", Name: "db", State: container.StatePaused, Activities: []Activity{
+			{Kind: KindPaused},
+			{Kind: KindSidecar, Detail: "stress pumba-stress-1"},
+		}},
+	}, got, "a live sidecar is Pumba evidence")
+}
+
+func TestStatusCommand_Empty(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	expectListings(mockClient, nil, nil)
+	cmd, out := newTestCommand(t, mockClient, &Params{})
+	require.NoError(t, cmd.Run(context.Background(), false))
+	assert.Equal(t, "no active chaos found\n", out.String())
+
+	mockClient = container.NewMockClient(t)
+	expectListings(mockClient, nil, nil)
+	cmd, out = newTestCommand(t, mockClient, &Params{JSON: true})
+	require.NoError(t, cmd.Run(context.Background(), false))
+	assert.JSONEq(t, "[]", out.String())
+}
+
+func TestStatusCommand_ListError(t *testing.T) {
+	mockClient := container.NewMockClient(t)
+	mockClient.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		Return(nil, errors.New("daemon down")).Once()
+	cmd, out := newTestCommand(t, mockClient, &Params{})
+	assert.ErrorContains(t, cmd.Run(context.Background(), false), "daemon down")
+	assert.Empty(t, out.String())
+}
@@ -24,6 +24,8 @@ type stressCommand struct {
 	names        []string
 	pattern      string
 	labels       []string
+	hooks        *chaos.Hooks
+	rollout      *chaos.Rollout
 	image        string
 	pull         bool
 	stressors    []string
@@ -44,6 +46,8 @@ func NewStressCommand(client stressClient, globalParams *chaos.GlobalParams, ima
 		names:        globalParams.Names,
 		pattern:      globalParams.Pattern,
 		labels:       globalParams.Labels,
+		hooks:        globalParams.Hooks,
+		rollout:      globalParams.Rollout,
 		image:        image,
 		pull:         pull,
 		stressors:    strings.Fields(stressors),
@@ -66,7 +70,7 @@ func (s *stressCommand) Run(ctx context.Context, random bool) error {
 		"limit":     s.limit,
 		"random":    random,
 	}).Debug("stress testing all matching containers")
-	gp := &chaos.GlobalParams{Names: s.names, Pattern: s.pattern, Labels: s.labels}
+	gp := &chaos.GlobalParams{Names: s.names, Pattern: s.pattern, Labels: s.labels, Hooks: s.hooks, Rollout: s.rollout}
 	if err := chaos.RunOnContainers(ctx, s.client, gp, s.limit, random, true, s.stressContainer); err != nil {
 		return fmt.Errorf("one or more stress test failed: %w", err)
 	}
@@ -74,7 +78,7 @@ func (s *stressCommand) Run(ctx context.Context, random bool) error {
 }
 
 func (s *stressCommand) stressContainer(ctx context.Context, c *container.Container) error {
-	log.WithFields(log.Fields{
+	log.WithContext(ctx).WithFields(log.Fields{
 		"container":       c.ID(),
 		"duration":        s.duration,
 		"stressors":       s.stressors,
@@ -105,8 +109,8 @@ func (s *stressCommand) stressContainer(ctx context.Context, c *container.Contai
 		return fmt.Errorf("stress-ng failed with error: %w", e)
 	case <-ctx.Done():
 		log.Debug("stop stress test on containers by stop event")
-		// cleanup must run even when parent ctx is canceled; preserve values but strip cancellation
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), defaultStopTimeout)
+		// cleanup must run even when parent ctx is canceled
+		cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), defaultStopTimeout)
 		defer cleanupCancel()
 		err = s.client.StopContainerWithID(cleanupCtx, result.SidecarID, defaultStopTimeout, s.dryRun)
 		if err != nil {
@@ -114,8 +118,8 @@ func (s *stressCommand) stressContainer(ctx context.Context, c *container.Contai
 		}
 	case <-timer.C:
 		log.WithField("duration", s.duration).Debug("stop stress containers after duration")
-		// parent ctx may cancel simultaneously with the timer; strip cancellation for cleanup
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.WithoutCancel(ctx), defaultStopTimeout)
+		// parent ctx may cancel simultaneously with the timer; cleanup must still run
+		cleanupCtx, cleanupCancel := context.WithTimeout(container.WithCleanup(ctx), defaultStopTimeout)
 		defer cleanupCancel()
 		err = s.client.StopContainerWithID(cleanupCtx, result.SidecarID, defaultStopTimeout, s.dryRun)
 		if err != nil {
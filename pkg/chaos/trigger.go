@@ -0,0 +1,134 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/chaos/cliflags"
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// Trigger runs a chaos command on every target container reaching an event,
+// rather than on the interval.
+type Trigger struct {
+	// Event is container.EventStart, restarts included, or
+	// container.EventHealthy.
+	Event string
+	// Delay is the wait between the event and the chaos.
+	Delay time.Duration
+}
+
+// ParseTrigger parses the global --trigger and --trigger-delay flags. It
+// returns nil without --trigger.
+func ParseTrigger(c cliflags.Flags) (*Trigger, error) {
+	g := c.Global()
+	event := g.String("trigger")
+	if event == "" {
+		return nil, nil //nolint:nilnil
+	}
+	if event != container.EventStart && event != container.EventHealthy {
+		return nil, fmt.Errorf("bad trigger %q: expected %s or %s", event, container.EventStart, container.EventHealthy)
+	}
+	if g.Duration("interval") != 0 || g.String("cron") != "" {
+		return nil, errors.New("--trigger cannot be used with --interval or --cron")
+	}
+	return &Trigger{Event: event, Delay: g.Duration("trigger-delay")}, nil
+}
+
+// RunTriggered runs a command on every container of changes that is a target
+// of params and reaches the event of params.Trigger, after its delay, until
+// topContext is done, changes are closed or a run failed. build returns the
+// command for global params naming the triggering container as the sole
+// target. Containers run concurrently, at most params.Rollout.MaxParallel at
+// once; a container pumba acts on ignores its events until the run ended, so
+// chaos restarting the container does not trigger itself.
+func RunTriggered(topContext context.Context, changes <-chan inventory.Change, build func(*GlobalParams) (Command, error), params *GlobalParams) error {
+	eg, ctx := errgroup.WithContext(topContext)
+	var slots chan struct{}
+	if params.Rollout != nil && params.Rollout.MaxParallel > 0 {
+		slots = make(chan struct{}, params.Rollout.MaxParallel)
+	}
+	isTarget := container.TargetFilter(params.Names, params.Pattern)
+	acting := &acting{ended: map[string]time.Time{}}
+	var runs atomic.Int64
+	for {
+		var change inventory.Change
+		select {
+		case <-ctx.Done():
+			return eg.Wait()
+		case ch, ok := <-changes:
+			if !ok {
+				return eg.Wait()
+			}
+			change = ch
+		}
+		c := change.Container
+		if change.Kind != params.Trigger.Event || c == nil || !isTarget(c) || !c.HasLabels(params.Labels) {
+			continue
+		}
+		fields := log.Fields{"event": change.Kind, "id": c.ID(), "name": c.Name(), "delay": params.Trigger.Delay}
+		if !acting.begin(c.ID(), change.Time) {
+			log.WithFields(fields).Debug("ignoring the event of a container chaos acts on")
+			continue
+		}
+		log.WithFields(fields).Info("container triggered chaos")
+		eg.Go(func() error {
+			defer acting.end(c.ID())
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(params.Trigger.Delay):
+			}
+			if slots != nil {
+				select {
+				case <-ctx.Done():
+					return nil
+				case slots <- struct{}{}:
+				}
+				defer func() { <-slots }()
+			}
+			gp := *params
+			gp.Names, gp.Pattern, gp.Labels, gp.Random = []string{c.ID()}, "", nil, false
+			command, err := build(&gp)
+			if err != nil {
+				return err
+			}
+			return runOnce(ctx, command, &gp, int(runs.Add(1)), c)
+		})
+	}
+}
+
+// acting tracks the containers triggered chaos acts on: from the trigger to
+// the end of the run, and for the events the runtime stamped before that end,
+// which the chaos itself may have caused.
+type acting struct {
+	mu sync.Mutex
+	// ended is zero while a run is due or on, and its end after.
+	ended map[string]time.Time
+}
+
+// begin marks the container acted on, unless it is already or the event at
+// the time at happened during its last run.
+func (a *acting) begin(id string, at time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ended, ok := a.ended[id]; ok && (ended.IsZero() || !at.IsZero() && !at.After(ended)) {
+		return false
+	}
+	a.ended[id] = time.Time{}
+	return true
+}
+
+// end marks the run on the container ended.
+func (a *acting) end(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ended[id] = time.Now()
+}
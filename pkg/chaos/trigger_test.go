@@ -0,0 +1,160 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/inventory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestParseTrigger(t *testing.T) {
+	flags := []cli.Flag{
+		cli.StringFlag{Name: "trigger"},
+		cli.DurationFlag{Name: "trigger-delay"},
+		cli.DurationFlag{Name: "interval"},
+		cli.StringFlag{Name: "cron"},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    *Trigger
+		wantErr string
+	}{
+		{name: "none", want: nil},
+		{name: "start", args: []string{"--trigger", "start"}, want: &Trigger{Event: container.EventStart}},
+		{name: "healthy with delay", args: []string{"--trigger", "healthy", "--trigger-delay", "2s"},
+			want: &Trigger{Event: container.EventHealthy, Delay: 2 * time.Second}},
+		{name: "bad event", args: []string{"--trigger", "die"}, wantErr: `bad trigger "die": expected start or healthy`},
+		{name: "with interval", args: []string{"--trigger", "start", "--interval", "1m"}, wantErr: "--trigger cannot be used with --interval or --cron"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTrigger(buildFlags(t, flags, tt.args, nil, nil))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunTriggered(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web", Labels: map[string]string{"app": "web"}}
+	db := &container.Container{ContainerID: "d1", ContainerName: "/db", Labels: map[string]string{"app": "db"}}
+	pumba := &container.Container{ContainerID: "p1", ContainerName: "/web-pumba", Labels: map[string]string{"app": "web", "com.gaiaadm.pumba": "true"}}
+	changes := make(chan inventory.Change, 4)
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventDie, ContainerID: "w1"}, Container: web}
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventStart, ContainerID: "d1"}, Container: db}
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventStart, ContainerID: "p1"}, Container: pumba}
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventStart, ContainerID: "w1"}, Container: web}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	params := &GlobalParams{Pattern: "web", Labels: []string{"app=web"}, Random: true,
+		Trigger: &Trigger{Event: container.EventStart, Delay: time.Millisecond}}
+	var built []*GlobalParams
+	build := func(gp *GlobalParams) (Command, error) {
+		built = append(built, gp)
+		cancel()
+		return &mockCommand{}, nil
+	}
+
+	require.NoError(t, RunTriggered(ctx, changes, build, params))
+	require.Len(t, built, 1, "only the start of a target container triggers chaos")
+	assert.Equal(t, []string{"w1"}, built[0].Names, "the triggering container is the sole target")
+	assert.Empty(t, built[0].Pattern)
+	assert.Empty(t, built[0].Labels)
+	assert.False(t, built[0].Random)
+	assert.Equal(t, "web", params.Pattern, "the global params are not changed")
+}
+
+func TestRunTriggered_Error(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	changes := make(chan inventory.Change, 2)
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventHealthy, ContainerID: "w1"}, Container: web}
+	changes <- inventory.Change{Event: container.Event{Kind: container.EventHealthy, ContainerID: "w1"}, Container: web}
+	build := func(*GlobalParams) (Command, error) { return &mockCommand{err: errors.New("kill failed")}, nil }
+
+	observer := &recordingObserver{}
+	err := RunTriggered(context.TODO(), changes, build, &GlobalParams{Observer: observer, Trigger: &Trigger{Event: container.EventHealthy}})
+	require.EqualError(t, err, "error running chaos command: kill failed")
+	assert.Equal(t, []string{"start", "end kill failed false"}, observer.runs, "a failed run stops triggered chaos")
+}
+
+func TestRunTriggered_Concurrent(t *testing.T) {
+	changes := make(chan inventory.Change, 3)
+	for _, id := range []string{"a", "b", "c"} {
+		changes <- inventory.Change{Event: container.Event{Kind: container.EventStart, ContainerID: id},
+			Container: &container.Container{ContainerID: id, ContainerName: "/" + id}}
+	}
+	close(changes)
+
+	var mu sync.Mutex
+	runs, running, peak := 0, 0, 0
+	build := func(*GlobalParams) (Command, error) {
+		return commandFunc(func(context.Context) error {
+			mu.Lock()
+			runs++
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		}), nil
+	}
+	params := &GlobalParams{Rollout: &Rollout{MaxParallel: 2}, Trigger: &Trigger{Event: container.EventStart}}
+
+	require.NoError(t, RunTriggered(context.TODO(), changes, build, params))
+	assert.Equal(t, 3, runs, "every triggering container runs")
+	assert.Equal(t, 2, peak, "containers run concurrently, up to the rollout limit")
+}
+
+func TestRunTriggered_IgnoresOwnEvents(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	start := func(at time.Time) inventory.Change {
+		return inventory.Change{Event: container.Event{Kind: container.EventStart, ContainerID: "w1", Time: at}, Container: web}
+	}
+	changes := make(chan inventory.Change)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	runs := make(chan struct{})
+	build := func(*GlobalParams) (Command, error) {
+		return commandFunc(func(context.Context) error {
+			runs <- struct{}{}
+			// the chaos restarts the container, while acting on it
+			changes <- start(time.Now())
+			return nil
+		}), nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- RunTriggered(ctx, changes, build, &GlobalParams{Trigger: &Trigger{Event: container.EventStart}})
+	}()
+
+	changes <- start(time.Now())
+	<-runs
+	// the restart caused by the run, delivered after the run ended
+	changes <- start(time.Now().Add(-time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	changes <- start(time.Now().Add(time.Second))
+	<-runs
+	select {
+	case <-runs:
+		t.Fatal("the events of the chaos triggered it again")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	require.NoError(t, <-done)
+}
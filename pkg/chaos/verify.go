@@ -0,0 +1,50 @@
+package chaos
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Modes of the --verify flag of the netem and iptables commands, which read
+// back the target's network namespace after a fault is applied and after it
+// is removed.
+const (
+	// VerifyNone skips the read-back.
+	VerifyNone = "none"
+	// VerifyWarn logs a warning when the read-back does not match.
+	VerifyWarn = "warn"
+	// VerifyFail fails the run when the read-back does not match.
+	VerifyFail = "fail"
+)
+
+// ParseVerify validates a --verify value; empty selects VerifyNone.
+func ParseVerify(mode string) (string, error) {
+	switch mode {
+	case "", VerifyNone:
+		return VerifyNone, nil
+	case VerifyWarn, VerifyFail:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("bad verify mode %q: must be one of none, warn or fail", mode)
+	}
+}
+
+// Verifying reports whether a request with the given verify mode and dry-run
+// flag should be read back.
+func Verifying(mode string, dryRun bool) bool {
+	return !dryRun && mode != "" && mode != VerifyNone
+}
+
+// VerifyResult applies mode to the outcome of a read-back: a nil err passes,
+// otherwise VerifyWarn logs err and VerifyFail returns it.
+func VerifyResult(mode string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if mode == VerifyFail {
+		return err
+	}
+	log.WithError(err).Warn("fault verification failed")
+	return nil
+}
@@ -0,0 +1,34 @@
+package chaos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVerify(t *testing.T) {
+	for in, want := range map[string]string{"": VerifyNone, "none": VerifyNone, "warn": VerifyWarn, "fail": VerifyFail} {
+		got, err := ParseVerify(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseVerify("strict")
+	assert.ErrorContains(t, err, "bad verify mode")
+}
+
+func TestVerifying(t *testing.T) {
+	assert.True(t, Verifying(VerifyWarn, false))
+	assert.True(t, Verifying(VerifyFail, false))
+	assert.False(t, Verifying(VerifyFail, true), "dry-run applies nothing to read back")
+	assert.False(t, Verifying(VerifyNone, false))
+	assert.False(t, Verifying("", false))
+}
+
+func TestVerifyResult(t *testing.T) {
+	mismatch := errors.New("qdisc not found")
+	assert.NoError(t, VerifyResult(VerifyFail, nil))
+	assert.NoError(t, VerifyResult(VerifyWarn, mismatch))
+	assert.ErrorIs(t, VerifyResult(VerifyFail, mismatch), mismatch)
+}
@@ -0,0 +1,96 @@
+package container
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CaptureMountPath is where the capture sidecar sees CaptureSpec.Dir.
+const CaptureMountPath = "/capture"
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// CaptureSpec describes a tcpdump packet capture running in a sidecar that
+// shares the target's network namespace for the lifetime of a netem or
+// iptables injection. The pcap is written to Dir on the runtime host and
+// rotated every FileSize megabytes, keeping at most FileCount files. Image
+// must provide tcpdump.
+type CaptureSpec struct {
+	Dir       string
+	Interface string
+	Filter    string
+	FileSize  int
+	FileCount int
+	Image     string
+	Pull      bool
+}
+
+// SidecarName returns the deterministic name of the capture sidecar, so stop
+// can find it without extra state.
+func (s *CaptureSpec) SidecarName(c *Container) string {
+	id := c.ID()
+	if len(id) > 12 { //nolint:mnd
+		id = id[:12]
+	}
+	return "pumba-capture-" + id
+}
+
+// Args returns the tcpdump argv writing a rotating pcap named after the
+// container and the capture start time.
+func (s *CaptureSpec) Args(c *Container, start time.Time) []string {
+	name := unsafeFileChars.ReplaceAllString(strings.TrimPrefix(c.Name(), "/"), "_")
+	file := path.Join(CaptureMountPath, fmt.Sprintf("%s-%s.pcap", name, start.UTC().Format("20060102T150405Z")))
+	args := []string{"tcpdump", "-i", s.Interface, "-n", "-U", "-Z", "root", "-w", file}
+	if s.FileSize > 0 {
+		args = append(args, "-C", strconv.Itoa(s.FileSize))
+	}
+	if s.FileCount > 0 {
+		args = append(args, "-W", strconv.Itoa(s.FileCount))
+	}
+	if s.Filter != "" {
+		args = append(args, s.Filter)
+	}
+	return args
+}
+
+// CaptureFilter builds a BPF filter matching traffic to or from any of nets
+// on any of ports, a port being a number or a range "min:max" (or
+// "min-max"); an empty list does not restrict the capture.
+func CaptureFilter(nets []*net.IPNet, ports []string) string {
+	var parts []string
+	if len(nets) > 0 {
+		terms := make([]string, 0, len(nets))
+		for _, n := range nets {
+			terms = append(terms, "net "+n.String())
+		}
+		parts = append(parts, anyOf(terms))
+	}
+	if len(ports) > 0 {
+		terms := make([]string, 0, len(ports))
+		for _, p := range ports {
+			terms = append(terms, portTerm(p))
+		}
+		parts = append(parts, anyOf(terms))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// portTerm returns the BPF term matching port p, or the range p.
+func portTerm(p string) string {
+	if i := strings.IndexAny(p, ":-"); i >= 0 {
+		return "portrange " + p[:i] + "-" + p[i+1:]
+	}
+	return "port " + p
+}
+
+func anyOf(terms []string) string {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " or ") + ")"
+}
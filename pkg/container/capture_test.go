@@ -0,0 +1,40 @@
+package container
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureSpec_SidecarName(t *testing.T) {
+	s := &CaptureSpec{}
+	assert.Equal(t, "pumba-capture-0123456789ab", s.SidecarName(&Container{ContainerID: "0123456789abcdef"}))
+	assert.Equal(t, "pumba-capture-abc", s.SidecarName(&Container{ContainerID: "abc"}))
+}
+
+func TestCaptureSpec_Args(t *testing.T) {
+	start := time.Date(2026, 5, 4, 3, 2, 1, 0, time.UTC)
+	c := &Container{ContainerID: "abc", ContainerName: "/web/api 1"}
+	s := &CaptureSpec{Interface: "eth0", Filter: "port 80", FileSize: 100, FileCount: 5}
+	assert.Equal(t, []string{"tcpdump", "-i", "eth0", "-n", "-U", "-Z", "root",
+		"-w", "/capture/web_api_1-20260504T030201Z.pcap", "-C", "100", "-W", "5", "port 80"}, s.Args(c, start))
+
+	s = &CaptureSpec{Interface: "eth1"}
+	assert.Equal(t, []string{"tcpdump", "-i", "eth1", "-n", "-U", "-Z", "root",
+		"-w", "/capture/web_api_1-20260504T030201Z.pcap"}, s.Args(c, start))
+}
+
+func TestCaptureFilter(t *testing.T) {
+	_, n1, _ := net.ParseCIDR("10.0.0.0/24")
+	_, n2, _ := net.ParseCIDR("192.168.1.5/32")
+	assert.Empty(t, CaptureFilter(nil, nil))
+	assert.Equal(t, "net 10.0.0.0/24", CaptureFilter([]*net.IPNet{n1}, nil))
+	assert.Equal(t, "port 53", CaptureFilter(nil, []string{"53"}))
+	assert.Equal(t, "(net 10.0.0.0/24 or net 192.168.1.5/32) and (port 80 or port 443)",
+		CaptureFilter([]*net.IPNet{n1, n2}, []string{"80", "443"}))
+	assert.Equal(t, "portrange 8000-8100", CaptureFilter(nil, []string{"8000:8100"}))
+	assert.Equal(t, "net 10.0.0.0/24 and (port 53 or portrange 8000-8100)",
+		CaptureFilter([]*net.IPNet{n1}, []string{"53", "8000-8100"}))
+}
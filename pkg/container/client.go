@@ -7,6 +7,9 @@ import (
 
 // A FilterFunc is a prototype for a function that can be used to filter the
 // results from a call to the ListContainers() method on the Client.
+// Runtimes may call it first on a container holding only the ID, name, image,
+// state, creation time and labels, to skip inspecting containers it rejects,
+// so a FilterFunc must decide on those fields.
 type FilterFunc func(*Container) bool
 
 // --- Focused Interfaces ---
@@ -26,11 +29,19 @@ type Lifecycle interface {
 	PauseContainer(context.Context, *Container, bool) error
 	UnpauseContainer(context.Context, *Container, bool) error
 	StopContainerWithID(context.Context, string, time.Duration, bool) error
+	// SignalProcesses signals processes inside a container rather than its
+	// main process, and returns their PIDs.
+	SignalProcesses(context.Context, *SignalRequest) ([]int, error)
 }
 
 // Executor executes commands in containers.
 type Executor interface {
-	ExecContainer(context.Context, *Container, string, []string, bool) error
+	// ExecContainer runs a command in a container and returns its result, nil
+	// in dry-run mode. A command that ran and exited non-zero is no error:
+	// callers check ExecResult.ExitCode. The context bounds the command.
+	ExecContainer(context.Context, *Container, string, []string, bool) (*ExecResult, error)
+	// CopyToContainer writes a file into a container's filesystem.
+	CopyToContainer(context.Context, *CopyRequest) error
 }
 
 // Netem manages network emulation on containers. Requests are passed by
@@ -39,6 +50,10 @@ type Executor interface {
 type Netem interface {
 	NetemContainer(context.Context, *NetemRequest) error
 	StopNetemContainer(context.Context, *NetemRequest) error
+	// NetemStatus returns the output of `tc -s qdisc show dev <Interface>`
+	// (every interface when Interface is empty) run in the target's network
+	// namespace.
+	NetemStatus(context.Context, *NetemRequest) (string, error)
 }
 
 // IPTables manages iptables rules on containers. Requests are passed by
@@ -46,6 +61,10 @@ type Netem interface {
 type IPTables interface {
 	IPTablesContainer(context.Context, *IPTablesRequest) error
 	StopIPTablesContainer(context.Context, *IPTablesRequest) error
+	// IPTablesStatus returns the packet filter state of the target's network
+	// namespace: `iptables -S` of the filter and nat tables, or
+	// `nft list tables` when Backend resolves to nftables.
+	IPTablesStatus(context.Context, *IPTablesRequest) (string, error)
 }
 
 // Stressor manages stress testing on containers. Requests are passed by
@@ -55,6 +74,38 @@ type Stressor interface {
 	StressContainer(context.Context, *StressRequest) (*StressResult, error)
 }
 
+// Proxy runs a transparent proxy sidecar in the target's network namespace
+// and redirects selected traffic to it with iptables nat rules. Requests are
+// passed by pointer for the same size reason as Netem.
+type Proxy interface {
+	ProxyContainer(context.Context, *ProxyRequest) error
+	StopProxyContainer(context.Context, *ProxyRequest) error
+}
+
+// Link changes the network interfaces of containers and their attachment to
+// runtime networks. Requests are passed by pointer for the same size reason
+// as Netem.
+type Link interface {
+	LinkContainer(context.Context, *LinkRequest) (string, error)
+	DisconnectNetwork(context.Context, *NetworkRequest) error
+	ConnectNetwork(context.Context, *NetworkRequest) error
+}
+
+// Diagnoser runs the preflight checks of `pumba doctor` against the runtime.
+// Problems are reported as failed checks rather than errors so a single run
+// lists all of them.
+type Diagnoser interface {
+	Diagnose(context.Context, *DiagnoseRequest) []Check
+}
+
+// Watcher streams the changes of the runtime's containers, for an inventory
+// kept up to date without listing. It is optional: runtimes implement it
+// besides Client. The event channel is closed, and an error sent, when the
+// stream fails; both end when the context is done.
+type Watcher interface {
+	WatchContainers(context.Context) (<-chan Event, <-chan error)
+}
+
 // Client is the full container runtime interface, combining all focused interfaces.
 type Client interface {
 	Lister
@@ -63,5 +114,8 @@ type Client interface {
 	Netem
 	IPTables
 	Stressor
+	Proxy
+	Link
+	Diagnoser
 	Close() error
 }
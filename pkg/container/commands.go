@@ -0,0 +1,107 @@
+package container
+
+// TCCommands returns the tc argument lists applying the request's netem
+// command. When IP/port filters are specified, it creates a priority-based
+// queueing hierarchy:
+//
+//	       1:   root qdisc (prio)
+//	      / | \
+//	    1:1 1:2 1:3    classes
+//	     |   |   |
+//	   10:  20:  30:   qdiscs
+//	   sfq  sfq  netem
+//	band 0   1    2
+//
+// Matching traffic is routed to band 2 (netem), all other traffic flows
+// through sfq. See http://man7.org/linux/man-pages/man8/tc-netem.8.html
+func (r *NetemRequest) TCCommands() [][]string {
+	if !r.filtered() {
+		// simple case: apply netem directly on root qdisc
+		args := make([]string, 0, len(r.Command)+6) //nolint:mnd
+		args = append(args, "qdisc", "add", "dev", r.Interface, "root", "netem")
+		args = append(args, r.Command...)
+		return [][]string{args}
+	}
+
+	// IP/port filter case: prio qdisc + sfq + netem + u32 filters
+	netemArgs := make([]string, 0, len(r.Command)+9) //nolint:mnd
+	netemArgs = append(netemArgs, "qdisc", "add", "dev", r.Interface, "parent", "1:3", "handle", "30:", "netem")
+	netemArgs = append(netemArgs, r.Command...)
+
+	commands := [][]string{
+		{"qdisc", "add", "dev", r.Interface, "root", "handle", "1:", "prio"},
+		{"qdisc", "add", "dev", r.Interface, "parent", "1:1", "handle", "10:", "sfq"},
+		{"qdisc", "add", "dev", r.Interface, "parent", "1:2", "handle", "20:", "sfq"},
+		netemArgs,
+	}
+
+	for _, ip := range r.IPs {
+		commands = append(commands, []string{
+			"filter", "add", "dev", r.Interface, "protocol", "ip", "parent", "1:0", "prio", "1",
+			"u32", "match", "ip", "dst", ip.String(), "flowid", "1:3",
+		})
+	}
+	for _, sport := range r.SPorts {
+		commands = append(commands, []string{
+			"filter", "add", "dev", r.Interface, "protocol", "ip", "parent", "1:0", "prio", "1",
+			"u32", "match", "ip", "sport", sport, "0xffff", "flowid", "1:3",
+		})
+	}
+	for _, dport := range r.DPorts {
+		commands = append(commands, []string{
+			"filter", "add", "dev", r.Interface, "protocol", "ip", "parent", "1:0", "prio", "1",
+			"u32", "match", "ip", "dport", dport, "0xffff", "flowid", "1:3",
+		})
+	}
+
+	return commands
+}
+
+// StopTCCommands returns the tc argument lists removing the request's netem
+// command: the priority qdisc hierarchy when filters were used, otherwise
+// the root netem qdisc.
+func (r *NetemRequest) StopTCCommands() [][]string {
+	if !r.filtered() {
+		return [][]string{{"qdisc", "del", "dev", r.Interface, "root", "netem"}}
+	}
+	return [][]string{
+		{"qdisc", "del", "dev", r.Interface, "parent", "1:1", "handle", "10:"},
+		{"qdisc", "del", "dev", r.Interface, "parent", "1:2", "handle", "20:"},
+		{"qdisc", "del", "dev", r.Interface, "parent", "1:3", "handle", "30:"},
+		{"qdisc", "del", "dev", r.Interface, "root", "handle", "1:", "prio"},
+	}
+}
+
+func (r *NetemRequest) filtered() bool {
+	return len(r.IPs) != 0 || len(r.SPorts) != 0 || len(r.DPorts) != 0
+}
+
+// Commands returns the iptables argument lists of the request, one rule per
+// IP/port filter element, or a single rule without filters. CmdPrefix
+// selects whether the rules are inserted or deleted.
+func (r *IPTablesRequest) Commands() [][]string {
+	var commands [][]string
+	rule := func(match ...string) []string {
+		cmd := make([]string, 0, len(r.CmdPrefix)+len(match)+len(r.CmdSuffix))
+		cmd = append(cmd, r.CmdPrefix...)
+		cmd = append(cmd, match...)
+		return append(cmd, r.CmdSuffix...)
+	}
+	for _, ip := range r.SrcIPs {
+		commands = append(commands, rule("-s", ip.String()))
+	}
+	for _, ip := range r.DstIPs {
+		commands = append(commands, rule("-d", ip.String()))
+	}
+	for _, sport := range r.SPorts {
+		commands = append(commands, rule("--sport", sport))
+	}
+	for _, dport := range r.DPorts {
+		commands = append(commands, rule("--dport", dport))
+	}
+	// no filters: single command with just prefix + suffix
+	if len(commands) == 0 {
+		commands = append(commands, rule())
+	}
+	return commands
+}
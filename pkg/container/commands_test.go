@@ -0,0 +1,158 @@
+package container
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetemRequest_TCCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		iface    string
+		cmds     []string
+		ips      []*net.IPNet
+		sports   []string
+		dports   []string
+		wantCmds [][]string
+	}{
+		{
+			name:     "basic_delay",
+			iface:    "eth0",
+			cmds:     []string{"delay", "100ms"},
+			wantCmds: [][]string{{"qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms"}},
+		},
+		{
+			name:     "multiple_commands",
+			iface:    "eth0",
+			cmds:     []string{"delay", "100ms", "loss", "10%"},
+			wantCmds: [][]string{{"qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms", "loss", "10%"}},
+		},
+		{
+			name:  "ip_filtering_creates_prio_qdisc",
+			iface: "eth0",
+			cmds:  []string{"delay", "100ms"},
+			ips:   func() []*net.IPNet { _, n, _ := net.ParseCIDR("10.0.0.0/8"); return []*net.IPNet{n} }(),
+			wantCmds: [][]string{
+				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
+				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "dst", "10.0.0.0/8", "flowid", "1:3"},
+			},
+		},
+		{
+			name:   "sport_filtering",
+			iface:  "eth0",
+			cmds:   []string{"delay", "100ms"},
+			sports: []string{"80"},
+			wantCmds: [][]string{
+				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
+				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "sport", "80", "0xffff", "flowid", "1:3"},
+			},
+		},
+		{
+			name:   "dport_filtering",
+			iface:  "eth0",
+			cmds:   []string{"delay", "100ms"},
+			dports: []string{"443"},
+			wantCmds: [][]string{
+				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
+				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
+				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "dport", "443", "0xffff", "flowid", "1:3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := &NetemRequest{Interface: tt.iface, Command: tt.cmds, IPs: tt.ips, SPorts: tt.sports, DPorts: tt.dports}
+			cmds := req.TCCommands()
+			assert.Equal(t, tt.wantCmds, cmds)
+		})
+	}
+}
+
+func TestNetemRequest_StopTCCommands(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without_filters", func(t *testing.T) {
+		t.Parallel()
+		cmds := (&NetemRequest{Interface: "eth0"}).StopTCCommands()
+		assert.Equal(t, [][]string{{"qdisc", "del", "dev", "eth0", "root", "netem"}}, cmds)
+	})
+
+	t.Run("with_filters", func(t *testing.T) {
+		t.Parallel()
+		cmds := (&NetemRequest{Interface: "eth0", DPorts: []string{"80"}}).StopTCCommands()
+		assert.Len(t, cmds, 4)
+		assert.Equal(t, []string{"qdisc", "del", "dev", "eth0", "parent", "1:1", "handle", "10:"}, cmds[0])
+		assert.Equal(t, []string{"qdisc", "del", "dev", "eth0", "root", "handle", "1:", "prio"}, cmds[3])
+	})
+}
+
+func TestIPTablesRequest_Commands(t *testing.T) {
+	t.Parallel()
+
+	_, srcNet, _ := net.ParseCIDR("10.0.0.0/8")
+	_, dstNet, _ := net.ParseCIDR("192.168.1.0/24")
+
+	tests := []struct {
+		name     string
+		flags    []string
+		target   []string
+		srcIPs   []*net.IPNet
+		dstIPs   []*net.IPNet
+		srcPorts []string
+		dstPorts []string
+		want     [][]string
+	}{
+		{
+			name:   "basic_rule",
+			flags:  []string{"-A", "INPUT"},
+			target: []string{"-j", "DROP"},
+			want:   [][]string{{"-A", "INPUT", "-j", "DROP"}},
+		},
+		{
+			name:   "with_src_and_dst_ips",
+			flags:  []string{"-A", "INPUT"},
+			target: []string{"-j", "DROP"},
+			srcIPs: []*net.IPNet{srcNet},
+			dstIPs: []*net.IPNet{dstNet},
+			want: [][]string{
+				{"-A", "INPUT", "-s", "10.0.0.0/8", "-j", "DROP"},
+				{"-A", "INPUT", "-d", "192.168.1.0/24", "-j", "DROP"},
+			},
+		},
+		{
+			name:     "with_src_and_dst_ports",
+			flags:    []string{"-A", "INPUT"},
+			target:   []string{"-j", "DROP"},
+			srcPorts: []string{"80", "443"},
+			dstPorts: []string{"8080"},
+			want: [][]string{
+				{"-A", "INPUT", "--sport", "80", "-j", "DROP"},
+				{"-A", "INPUT", "--sport", "443", "-j", "DROP"},
+				{"-A", "INPUT", "--dport", "8080", "-j", "DROP"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := &IPTablesRequest{CmdPrefix: tt.flags, CmdSuffix: tt.target, SrcIPs: tt.srcIPs, DstIPs: tt.dstIPs, SPorts: tt.srcPorts, DPorts: tt.dstPorts}
+			cmds := req.Commands()
+			assert.Equal(t, tt.want, cmds)
+		})
+	}
+}
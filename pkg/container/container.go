@@ -2,24 +2,39 @@ package container
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 )
 
 const (
-	pumbaLabel     = "com.gaiaadm.pumba"
-	pumbaSkipLabel = "com.gaiaadm.pumba.skip"
-	signalLabel    = "com.gaiaadm.pumba.stop-signal"
-	trueValue      = "true"
+	pumbaLabel        = "com.gaiaadm.pumba"
+	pumbaSkipLabel    = "com.gaiaadm.pumba.skip"
+	pumbaSidecarLabel = "com.gaiaadm.pumba.sidecar"
+	pumbaTargetLabel  = "com.gaiaadm.pumba.target"
+	signalLabel       = "com.gaiaadm.pumba.stop-signal"
+	trueValue         = "true"
 
 	// StateRunning represents a running container state.
 	StateRunning = "running"
 	// StateExited represents an exited container state.
 	StateExited = "exited"
+	// StatePaused represents a paused container state.
+	StatePaused = "paused"
+
+	// SidecarKindStress labels stress-ng sidecars.
+	SidecarKindStress = "stress"
+	// SidecarKindCapture labels tcpdump capture sidecars.
+	SidecarKindCapture = "capture"
 )
 
 // NetworkLink represents a link from one container network endpoint.
+// Aliases and IPv4Address (the statically assigned address, if any) are
+// needed to reconnect the container to the network as it was.
 type NetworkLink struct {
-	Links []string
+	Links       []string
+	Aliases     []string
+	IPv4Address string
 }
 
 // Container represents a running container, decoupled from any specific runtime.
@@ -31,6 +46,12 @@ type Container struct {
 	State         string
 	Labels        map[string]string
 	Networks      map[string]NetworkLink
+	// Created is the container creation time; zero when the runtime does
+	// not report it.
+	Created time.Time
+	// Started is when the main process last started; zero when the runtime
+	// does not report it (containerd).
+	Started time.Time
 }
 
 // ID returns the container ID.
@@ -70,6 +91,19 @@ func (c *Container) Links() []string {
 	return links
 }
 
+// HasLabels reports whether the container carries every label of labels,
+// given as "key" or "key=value", as the runtimes filter them.
+func (c *Container) HasLabels(labels []string) bool {
+	for _, label := range labels {
+		key, value, withValue := strings.Cut(label, "=")
+		v, ok := c.Labels[key]
+		if !ok || (withValue && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
 // IsPumba returns a boolean flag indicating whether or not the current
 // container is the Pumba container itself. The Pumba container is
 // identified by the presence of the "com.gaiaadm.pumba" label in
@@ -88,6 +122,48 @@ func (c *Container) IsPumbaSkip() bool {
 	return ok && val == trueValue
 }
 
+// legacySidecarName matches the names Pumba generated for its containerd
+// network tools and stress sidecars before they carried the sidecar label.
+// Those sidecars carried the skip label, so a name alone is not enough: a
+// user container may be named the same.
+var legacySidecarName = regexp.MustCompile(`^/?pumba-(sidecar|stress)-\d+$`)
+
+// SidecarLabels returns the labels set on every helper container Pumba
+// creates for the target container. The skip label keeps sidecars out of
+// target lists; the sidecar label, holding the sidecar kind (e.g. "tc" or
+// "stress"), tells them apart from user containers carrying the skip label,
+// so `pumba cleanup` can remove orphaned ones; the target label lets
+// `pumba status` attribute them.
+func SidecarLabels(kind, targetID string) map[string]string {
+	return map[string]string{pumbaSkipLabel: trueValue, pumbaSidecarLabel: kind, pumbaTargetLabel: targetID}
+}
+
+// IsPumbaSidecar returns a boolean flag indicating whether or not the
+// current container is a helper container created by Pumba: it carries the
+// "com.gaiaadm.pumba.sidecar" label or, for sidecars of older releases, the
+// skip label and a name Pumba generated.
+func (c *Container) IsPumbaSidecar() bool {
+	if c.IsPumba() {
+		return false
+	}
+	if c.Labels[pumbaSidecarLabel] != "" {
+		return true
+	}
+	return c.IsPumbaSkip() && legacySidecarName.MatchString(c.ContainerName)
+}
+
+// SidecarKind returns the kind of a labelled Pumba sidecar, or "" for other
+// containers.
+func (c *Container) SidecarKind() string {
+	return c.Labels[pumbaSidecarLabel]
+}
+
+// SidecarTarget returns the ID of the container a labelled Pumba sidecar was
+// created for, or "" when unknown.
+func (c *Container) SidecarTarget() string {
+	return c.Labels[pumbaTargetLabel]
+}
+
 // StopSignal returns the custom stop signal (if any) that is encoded in the
 // container's metadata. If the container has not specified a custom stop
 // signal, the empty string "" is returned.
@@ -181,3 +181,42 @@ func TestIsPumbaSkip_NoLabel(t *testing.T) {
 	}
 	assert.False(t, c.IsPumbaSkip())
 }
+
+func TestIsPumbaSidecar(t *testing.T) {
+	skip := map[string]string{"com.gaiaadm.pumba.skip": "true"}
+	tests := []struct {
+		name string
+		c    Container
+		want bool
+	}{
+		{"sidecar label", Container{ContainerName: "/vibrant_turing", Labels: SidecarLabels("tc", "abc")}, true},
+		{"legacy netem sidecar", Container{ContainerName: "/pumba-sidecar-17", Labels: skip}, true},
+		{"legacy stress sidecar", Container{ContainerName: "pumba-stress-3", Labels: skip}, true},
+		{"legacy name without skip label", Container{ContainerName: "/pumba-sidecar-17"}, false},
+		{"capture name", Container{ContainerName: "/pumba-capture-db", Labels: skip}, false},
+		{"proxy name", Container{ContainerName: "/pumba-dns-proxy-abc123", Labels: skip}, false},
+		{"pumba itself", Container{ContainerName: "/pumba-sidecar-1", Labels: map[string]string{"com.gaiaadm.pumba": "true"}}, false},
+		{"skip label only", Container{ContainerName: "/monitor", Labels: map[string]string{"com.gaiaadm.pumba.skip": "true"}}, false},
+		{"regular container", Container{ContainerName: "/pumba-app"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.c.IsPumbaSidecar())
+		})
+	}
+}
+
+func TestSidecarKindAndTarget(t *testing.T) {
+	c := Container{Labels: SidecarLabels(SidecarKindStress, "abc")}
+	assert.Equal(t, SidecarKindStress, c.SidecarKind())
+	assert.Equal(t, "abc", c.SidecarTarget())
+	assert.Empty(t, (&Container{ContainerName: "/pumba-sidecar-1"}).SidecarTarget(), "legacy sidecars have no target")
+}
+
+func TestHasLabels(t *testing.T) {
+	c := Container{Labels: map[string]string{"app": "web", "tier": "front"}}
+	assert.True(t, c.HasLabels(nil))
+	assert.True(t, c.HasLabels([]string{"app", "tier=front"}))
+	assert.False(t, c.HasLabels([]string{"app=db"}))
+	assert.False(t, c.HasLabels([]string{"app", "env"}))
+}
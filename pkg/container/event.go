@@ -0,0 +1,31 @@
+package container
+
+import "time"
+
+// Kinds of container events.
+const (
+	// EventCreate is a new container.
+	EventCreate = "create"
+	// EventStart is a container started, or restarted.
+	EventStart = "start"
+	// EventHealthy is a container whose health check turned healthy; only
+	// docker and podman report it.
+	EventHealthy = "healthy"
+	// EventDie is a container whose main process exited.
+	EventDie = "die"
+	// EventPause and EventUnpause are a container paused and resumed.
+	EventPause   = "pause"
+	EventUnpause = "unpause"
+	// EventUpdate is a container renamed, updated, or connected to or
+	// disconnected from a network.
+	EventUpdate = "update"
+	// EventDestroy is a container removed.
+	EventDestroy = "destroy"
+)
+
+// Event is a change of a container reported by the runtime.
+type Event struct {
+	Kind        string
+	ContainerID string
+	Time        time.Time
+}
@@ -34,11 +34,17 @@ func matchPattern(pattern, containerName string) bool {
 	return matched
 }
 
+// TargetFilter returns the FilterFunc selecting the chaos targets named by
+// names, or matching pattern without names; Pumba's own containers never are.
+func TargetFilter(names []string, pattern string) FilterFunc {
+	return applyContainerFilter(filter{Names: names, Pattern: pattern})
+}
+
 // applyContainerFilter creates a FilterFunc from a filter config.
 func applyContainerFilter(flt filter) FilterFunc {
 	return func(c *Container) bool {
-		// skip Pumba label
-		if c.IsPumba() || c.IsPumbaSkip() {
+		// skip Pumba label and Pumba sidecars
+		if c.IsPumba() || c.IsPumbaSkip() || c.IsPumbaSidecar() {
 			return false
 		}
 		// match names
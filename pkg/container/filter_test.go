@@ -148,6 +148,26 @@ func TestApplyContainerFilter(t *testing.T) {
 			filter:   filter{Names: []string{"skip-container"}, Opts: ListOpts{All: false}},
 			expected: false,
 		},
+		{
+			name: "skips legacy pumba sidecar",
+			container: &Container{
+				ContainerName: "/pumba-sidecar-4",
+				Labels:        map[string]string{"com.gaiaadm.pumba.skip": "true"},
+				Networks:      map[string]NetworkLink{},
+			},
+			filter:   filter{Pattern: "^/pumba", Opts: ListOpts{All: false}},
+			expected: false,
+		},
+		{
+			name: "matches user container named like a sidecar",
+			container: &Container{
+				ContainerName: "/pumba-capture-db",
+				Labels:        map[string]string{},
+				Networks:      map[string]NetworkLink{},
+			},
+			filter:   filter{Names: []string{"pumba-capture-db"}, Opts: ListOpts{All: false}},
+			expected: true,
+		},
 		{
 			name: "matches by name",
 			container: &Container{
@@ -67,22 +67,224 @@ func (_c *MockClient_Close_Call) RunAndReturn(run func() error) *MockClient_Clos
 	return _c
 }
 
+// ConnectNetwork provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) ConnectNetwork(_a0 context.Context, _a1 *NetworkRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConnectNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetworkRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClient_ConnectNetwork_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConnectNetwork'
+type MockClient_ConnectNetwork_Call struct {
+	*mock.Call
+}
+
+// ConnectNetwork is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetworkRequest
+func (_e *MockClient_Expecter) ConnectNetwork(_a0 interface{}, _a1 interface{}) *MockClient_ConnectNetwork_Call {
+	return &MockClient_ConnectNetwork_Call{Call: _e.mock.On("ConnectNetwork", _a0, _a1)}
+}
+
+func (_c *MockClient_ConnectNetwork_Call) Run(run func(_a0 context.Context, _a1 *NetworkRequest)) *MockClient_ConnectNetwork_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetworkRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_ConnectNetwork_Call) Return(_a0 error) *MockClient_ConnectNetwork_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_ConnectNetwork_Call) RunAndReturn(run func(context.Context, *NetworkRequest) error) *MockClient_ConnectNetwork_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CopyToContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) CopyToContainer(_a0 context.Context, _a1 *CopyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyToContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *CopyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClient_CopyToContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CopyToContainer'
+type MockClient_CopyToContainer_Call struct {
+	*mock.Call
+}
+
+// CopyToContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *CopyRequest
+func (_e *MockClient_Expecter) CopyToContainer(_a0 interface{}, _a1 interface{}) *MockClient_CopyToContainer_Call {
+	return &MockClient_CopyToContainer_Call{Call: _e.mock.On("CopyToContainer", _a0, _a1)}
+}
+
+func (_c *MockClient_CopyToContainer_Call) Run(run func(_a0 context.Context, _a1 *CopyRequest)) *MockClient_CopyToContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*CopyRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_CopyToContainer_Call) Return(_a0 error) *MockClient_CopyToContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_CopyToContainer_Call) RunAndReturn(run func(context.Context, *CopyRequest) error) *MockClient_CopyToContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Diagnose provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) Diagnose(_a0 context.Context, _a1 *DiagnoseRequest) []Check {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Diagnose")
+	}
+
+	var r0 []Check
+	if rf, ok := ret.Get(0).(func(context.Context, *DiagnoseRequest) []Check); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Check)
+		}
+	}
+
+	return r0
+}
+
+// MockClient_Diagnose_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Diagnose'
+type MockClient_Diagnose_Call struct {
+	*mock.Call
+}
+
+// Diagnose is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *DiagnoseRequest
+func (_e *MockClient_Expecter) Diagnose(_a0 interface{}, _a1 interface{}) *MockClient_Diagnose_Call {
+	return &MockClient_Diagnose_Call{Call: _e.mock.On("Diagnose", _a0, _a1)}
+}
+
+func (_c *MockClient_Diagnose_Call) Run(run func(_a0 context.Context, _a1 *DiagnoseRequest)) *MockClient_Diagnose_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*DiagnoseRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_Diagnose_Call) Return(_a0 []Check) *MockClient_Diagnose_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_Diagnose_Call) RunAndReturn(run func(context.Context, *DiagnoseRequest) []Check) *MockClient_Diagnose_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisconnectNetwork provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) DisconnectNetwork(_a0 context.Context, _a1 *NetworkRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisconnectNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetworkRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClient_DisconnectNetwork_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisconnectNetwork'
+type MockClient_DisconnectNetwork_Call struct {
+	*mock.Call
+}
+
+// DisconnectNetwork is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetworkRequest
+func (_e *MockClient_Expecter) DisconnectNetwork(_a0 interface{}, _a1 interface{}) *MockClient_DisconnectNetwork_Call {
+	return &MockClient_DisconnectNetwork_Call{Call: _e.mock.On("DisconnectNetwork", _a0, _a1)}
+}
+
+func (_c *MockClient_DisconnectNetwork_Call) Run(run func(_a0 context.Context, _a1 *NetworkRequest)) *MockClient_DisconnectNetwork_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetworkRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_DisconnectNetwork_Call) Return(_a0 error) *MockClient_DisconnectNetwork_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_DisconnectNetwork_Call) RunAndReturn(run func(context.Context, *NetworkRequest) error) *MockClient_DisconnectNetwork_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExecContainer provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *MockClient) ExecContainer(_a0 context.Context, _a1 *Container, _a2 string, _a3 []string, _a4 bool) error {
+func (_m *MockClient) ExecContainer(_a0 context.Context, _a1 *Container, _a2 string, _a3 []string, _a4 bool) (*ExecResult, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ExecContainer")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) error); ok {
+	var r0 *ExecResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) (*ExecResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) *ExecResult); ok {
 		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ExecResult)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *Container, string, []string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // MockClient_ExecContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecContainer'
@@ -107,12 +309,12 @@ func (_c *MockClient_ExecContainer_Call) Run(run func(_a0 context.Context, _a1 *
 	return _c
 }
 
-func (_c *MockClient_ExecContainer_Call) Return(_a0 error) *MockClient_ExecContainer_Call {
-	_c.Call.Return(_a0)
+func (_c *MockClient_ExecContainer_Call) Return(_a0 *ExecResult, _a1 error) *MockClient_ExecContainer_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockClient_ExecContainer_Call) RunAndReturn(run func(context.Context, *Container, string, []string, bool) error) *MockClient_ExecContainer_Call {
+func (_c *MockClient_ExecContainer_Call) RunAndReturn(run func(context.Context, *Container, string, []string, bool) (*ExecResult, error)) *MockClient_ExecContainer_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -164,6 +366,63 @@ func (_c *MockClient_IPTablesContainer_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// IPTablesStatus provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) IPTablesStatus(_a0 context.Context, _a1 *IPTablesRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IPTablesStatus")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *IPTablesRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *IPTablesRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *IPTablesRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_IPTablesStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IPTablesStatus'
+type MockClient_IPTablesStatus_Call struct {
+	*mock.Call
+}
+
+// IPTablesStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *IPTablesRequest
+func (_e *MockClient_Expecter) IPTablesStatus(_a0 interface{}, _a1 interface{}) *MockClient_IPTablesStatus_Call {
+	return &MockClient_IPTablesStatus_Call{Call: _e.mock.On("IPTablesStatus", _a0, _a1)}
+}
+
+func (_c *MockClient_IPTablesStatus_Call) Run(run func(_a0 context.Context, _a1 *IPTablesRequest)) *MockClient_IPTablesStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*IPTablesRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_IPTablesStatus_Call) Return(_a0 string, _a1 error) *MockClient_IPTablesStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_IPTablesStatus_Call) RunAndReturn(run func(context.Context, *IPTablesRequest) (string, error)) *MockClient_IPTablesStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // KillContainer provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *MockClient) KillContainer(_a0 context.Context, _a1 *Container, _a2 string, _a3 bool) error {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -213,6 +472,63 @@ func (_c *MockClient_KillContainer_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// LinkContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) LinkContainer(_a0 context.Context, _a1 *LinkRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkContainer")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *LinkRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *LinkRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *LinkRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_LinkContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkContainer'
+type MockClient_LinkContainer_Call struct {
+	*mock.Call
+}
+
+// LinkContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *LinkRequest
+func (_e *MockClient_Expecter) LinkContainer(_a0 interface{}, _a1 interface{}) *MockClient_LinkContainer_Call {
+	return &MockClient_LinkContainer_Call{Call: _e.mock.On("LinkContainer", _a0, _a1)}
+}
+
+func (_c *MockClient_LinkContainer_Call) Run(run func(_a0 context.Context, _a1 *LinkRequest)) *MockClient_LinkContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*LinkRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_LinkContainer_Call) Return(_a0 string, _a1 error) *MockClient_LinkContainer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_LinkContainer_Call) RunAndReturn(run func(context.Context, *LinkRequest) (string, error)) *MockClient_LinkContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListContainers provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockClient) ListContainers(_a0 context.Context, _a1 FilterFunc, _a2 ListOpts) ([]*Container, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -320,6 +636,63 @@ func (_c *MockClient_NetemContainer_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// NetemStatus provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) NetemStatus(_a0 context.Context, _a1 *NetemRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NetemStatus")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetemRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *NetemRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *NetemRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_NetemStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NetemStatus'
+type MockClient_NetemStatus_Call struct {
+	*mock.Call
+}
+
+// NetemStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetemRequest
+func (_e *MockClient_Expecter) NetemStatus(_a0 interface{}, _a1 interface{}) *MockClient_NetemStatus_Call {
+	return &MockClient_NetemStatus_Call{Call: _e.mock.On("NetemStatus", _a0, _a1)}
+}
+
+func (_c *MockClient_NetemStatus_Call) Run(run func(_a0 context.Context, _a1 *NetemRequest)) *MockClient_NetemStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetemRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_NetemStatus_Call) Return(_a0 string, _a1 error) *MockClient_NetemStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_NetemStatus_Call) RunAndReturn(run func(context.Context, *NetemRequest) (string, error)) *MockClient_NetemStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PauseContainer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockClient) PauseContainer(_a0 context.Context, _a1 *Container, _a2 bool) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -368,6 +741,53 @@ func (_c *MockClient_PauseContainer_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// ProxyContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) ProxyContainer(_a0 context.Context, _a1 *ProxyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProxyContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ProxyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClient_ProxyContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProxyContainer'
+type MockClient_ProxyContainer_Call struct {
+	*mock.Call
+}
+
+// ProxyContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *ProxyRequest
+func (_e *MockClient_Expecter) ProxyContainer(_a0 interface{}, _a1 interface{}) *MockClient_ProxyContainer_Call {
+	return &MockClient_ProxyContainer_Call{Call: _e.mock.On("ProxyContainer", _a0, _a1)}
+}
+
+func (_c *MockClient_ProxyContainer_Call) Run(run func(_a0 context.Context, _a1 *ProxyRequest)) *MockClient_ProxyContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*ProxyRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_ProxyContainer_Call) Return(_a0 error) *MockClient_ProxyContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_ProxyContainer_Call) RunAndReturn(run func(context.Context, *ProxyRequest) error) *MockClient_ProxyContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RemoveContainer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockClient) RemoveContainer(_a0 context.Context, _a1 *Container, _a2 RemoveOpts) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -465,6 +885,65 @@ func (_c *MockClient_RestartContainer_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// SignalProcesses provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) SignalProcesses(_a0 context.Context, _a1 *SignalRequest) ([]int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignalProcesses")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SignalRequest) ([]int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *SignalRequest) []int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *SignalRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockClient_SignalProcesses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SignalProcesses'
+type MockClient_SignalProcesses_Call struct {
+	*mock.Call
+}
+
+// SignalProcesses is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *SignalRequest
+func (_e *MockClient_Expecter) SignalProcesses(_a0 interface{}, _a1 interface{}) *MockClient_SignalProcesses_Call {
+	return &MockClient_SignalProcesses_Call{Call: _e.mock.On("SignalProcesses", _a0, _a1)}
+}
+
+func (_c *MockClient_SignalProcesses_Call) Run(run func(_a0 context.Context, _a1 *SignalRequest)) *MockClient_SignalProcesses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*SignalRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_SignalProcesses_Call) Return(_a0 []int, _a1 error) *MockClient_SignalProcesses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockClient_SignalProcesses_Call) RunAndReturn(run func(context.Context, *SignalRequest) ([]int, error)) *MockClient_SignalProcesses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartContainer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockClient) StartContainer(_a0 context.Context, _a1 *Container, _a2 bool) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -705,6 +1184,53 @@ func (_c *MockClient_StopNetemContainer_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// StopProxyContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockClient) StopProxyContainer(_a0 context.Context, _a1 *ProxyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopProxyContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ProxyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClient_StopProxyContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StopProxyContainer'
+type MockClient_StopProxyContainer_Call struct {
+	*mock.Call
+}
+
+// StopProxyContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *ProxyRequest
+func (_e *MockClient_Expecter) StopProxyContainer(_a0 interface{}, _a1 interface{}) *MockClient_StopProxyContainer_Call {
+	return &MockClient_StopProxyContainer_Call{Call: _e.mock.On("StopProxyContainer", _a0, _a1)}
+}
+
+func (_c *MockClient_StopProxyContainer_Call) Run(run func(_a0 context.Context, _a1 *ProxyRequest)) *MockClient_StopProxyContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*ProxyRequest))
+	})
+	return _c
+}
+
+func (_c *MockClient_StopProxyContainer_Call) Return(_a0 error) *MockClient_StopProxyContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClient_StopProxyContainer_Call) RunAndReturn(run func(context.Context, *ProxyRequest) error) *MockClient_StopProxyContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StressContainer provides a mock function with given fields: _a0, _a1
 func (_m *MockClient) StressContainer(_a0 context.Context, _a1 *StressRequest) (*StressResult, error) {
 	ret := _m.Called(_a0, _a1)
@@ -0,0 +1,85 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package container
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDiagnoser is an autogenerated mock type for the Diagnoser type
+type MockDiagnoser struct {
+	mock.Mock
+}
+
+type MockDiagnoser_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDiagnoser) EXPECT() *MockDiagnoser_Expecter {
+	return &MockDiagnoser_Expecter{mock: &_m.Mock}
+}
+
+// Diagnose provides a mock function with given fields: _a0, _a1
+func (_m *MockDiagnoser) Diagnose(_a0 context.Context, _a1 *DiagnoseRequest) []Check {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Diagnose")
+	}
+
+	var r0 []Check
+	if rf, ok := ret.Get(0).(func(context.Context, *DiagnoseRequest) []Check); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Check)
+		}
+	}
+
+	return r0
+}
+
+// MockDiagnoser_Diagnose_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Diagnose'
+type MockDiagnoser_Diagnose_Call struct {
+	*mock.Call
+}
+
+// Diagnose is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *DiagnoseRequest
+func (_e *MockDiagnoser_Expecter) Diagnose(_a0 interface{}, _a1 interface{}) *MockDiagnoser_Diagnose_Call {
+	return &MockDiagnoser_Diagnose_Call{Call: _e.mock.On("Diagnose", _a0, _a1)}
+}
+
+func (_c *MockDiagnoser_Diagnose_Call) Run(run func(_a0 context.Context, _a1 *DiagnoseRequest)) *MockDiagnoser_Diagnose_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*DiagnoseRequest))
+	})
+	return _c
+}
+
+func (_c *MockDiagnoser_Diagnose_Call) Return(_a0 []Check) *MockDiagnoser_Diagnose_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDiagnoser_Diagnose_Call) RunAndReturn(run func(context.Context, *DiagnoseRequest) []Check) *MockDiagnoser_Diagnose_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDiagnoser creates a new instance of MockDiagnoser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDiagnoser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDiagnoser {
+	mock := &MockDiagnoser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
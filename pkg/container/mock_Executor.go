@@ -21,22 +21,81 @@ func (_m *MockExecutor) EXPECT() *MockExecutor_Expecter {
 	return &MockExecutor_Expecter{mock: &_m.Mock}
 }
 
+// CopyToContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockExecutor) CopyToContainer(_a0 context.Context, _a1 *CopyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyToContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *CopyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockExecutor_CopyToContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CopyToContainer'
+type MockExecutor_CopyToContainer_Call struct {
+	*mock.Call
+}
+
+// CopyToContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *CopyRequest
+func (_e *MockExecutor_Expecter) CopyToContainer(_a0 interface{}, _a1 interface{}) *MockExecutor_CopyToContainer_Call {
+	return &MockExecutor_CopyToContainer_Call{Call: _e.mock.On("CopyToContainer", _a0, _a1)}
+}
+
+func (_c *MockExecutor_CopyToContainer_Call) Run(run func(_a0 context.Context, _a1 *CopyRequest)) *MockExecutor_CopyToContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*CopyRequest))
+	})
+	return _c
+}
+
+func (_c *MockExecutor_CopyToContainer_Call) Return(_a0 error) *MockExecutor_CopyToContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockExecutor_CopyToContainer_Call) RunAndReturn(run func(context.Context, *CopyRequest) error) *MockExecutor_CopyToContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExecContainer provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *MockExecutor) ExecContainer(_a0 context.Context, _a1 *Container, _a2 string, _a3 []string, _a4 bool) error {
+func (_m *MockExecutor) ExecContainer(_a0 context.Context, _a1 *Container, _a2 string, _a3 []string, _a4 bool) (*ExecResult, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ExecContainer")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) error); ok {
+	var r0 *ExecResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) (*ExecResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *Container, string, []string, bool) *ExecResult); ok {
 		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ExecResult)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *Container, string, []string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // MockExecutor_ExecContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecContainer'
@@ -61,12 +120,12 @@ func (_c *MockExecutor_ExecContainer_Call) Run(run func(_a0 context.Context, _a1
 	return _c
 }
 
-func (_c *MockExecutor_ExecContainer_Call) Return(_a0 error) *MockExecutor_ExecContainer_Call {
-	_c.Call.Return(_a0)
+func (_c *MockExecutor_ExecContainer_Call) Return(_a0 *ExecResult, _a1 error) *MockExecutor_ExecContainer_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockExecutor_ExecContainer_Call) RunAndReturn(run func(context.Context, *Container, string, []string, bool) error) *MockExecutor_ExecContainer_Call {
+func (_c *MockExecutor_ExecContainer_Call) RunAndReturn(run func(context.Context, *Container, string, []string, bool) (*ExecResult, error)) *MockExecutor_ExecContainer_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -68,6 +68,63 @@ func (_c *MockIPTables_IPTablesContainer_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// IPTablesStatus provides a mock function with given fields: _a0, _a1
+func (_m *MockIPTables) IPTablesStatus(_a0 context.Context, _a1 *IPTablesRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IPTablesStatus")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *IPTablesRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *IPTablesRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *IPTablesRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIPTables_IPTablesStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IPTablesStatus'
+type MockIPTables_IPTablesStatus_Call struct {
+	*mock.Call
+}
+
+// IPTablesStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *IPTablesRequest
+func (_e *MockIPTables_Expecter) IPTablesStatus(_a0 interface{}, _a1 interface{}) *MockIPTables_IPTablesStatus_Call {
+	return &MockIPTables_IPTablesStatus_Call{Call: _e.mock.On("IPTablesStatus", _a0, _a1)}
+}
+
+func (_c *MockIPTables_IPTablesStatus_Call) Run(run func(_a0 context.Context, _a1 *IPTablesRequest)) *MockIPTables_IPTablesStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*IPTablesRequest))
+	})
+	return _c
+}
+
+func (_c *MockIPTables_IPTablesStatus_Call) Return(_a0 string, _a1 error) *MockIPTables_IPTablesStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIPTables_IPTablesStatus_Call) RunAndReturn(run func(context.Context, *IPTablesRequest) (string, error)) *MockIPTables_IPTablesStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StopIPTablesContainer provides a mock function with given fields: _a0, _a1
 func (_m *MockIPTables) StopIPTablesContainer(_a0 context.Context, _a1 *IPTablesRequest) error {
 	ret := _m.Called(_a0, _a1)
@@ -216,6 +216,65 @@ func (_c *MockLifecycle_RestartContainer_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// SignalProcesses provides a mock function with given fields: _a0, _a1
+func (_m *MockLifecycle) SignalProcesses(_a0 context.Context, _a1 *SignalRequest) ([]int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignalProcesses")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SignalRequest) ([]int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *SignalRequest) []int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *SignalRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLifecycle_SignalProcesses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SignalProcesses'
+type MockLifecycle_SignalProcesses_Call struct {
+	*mock.Call
+}
+
+// SignalProcesses is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *SignalRequest
+func (_e *MockLifecycle_Expecter) SignalProcesses(_a0 interface{}, _a1 interface{}) *MockLifecycle_SignalProcesses_Call {
+	return &MockLifecycle_SignalProcesses_Call{Call: _e.mock.On("SignalProcesses", _a0, _a1)}
+}
+
+func (_c *MockLifecycle_SignalProcesses_Call) Run(run func(_a0 context.Context, _a1 *SignalRequest)) *MockLifecycle_SignalProcesses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*SignalRequest))
+	})
+	return _c
+}
+
+func (_c *MockLifecycle_SignalProcesses_Call) Return(_a0 []int, _a1 error) *MockLifecycle_SignalProcesses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLifecycle_SignalProcesses_Call) RunAndReturn(run func(context.Context, *SignalRequest) ([]int, error)) *MockLifecycle_SignalProcesses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartContainer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockLifecycle) StartContainer(_a0 context.Context, _a1 *Container, _a2 bool) error {
 	ret := _m.Called(_a0, _a1, _a2)
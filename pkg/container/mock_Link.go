@@ -0,0 +1,187 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package container
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockLink is an autogenerated mock type for the Link type
+type MockLink struct {
+	mock.Mock
+}
+
+type MockLink_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLink) EXPECT() *MockLink_Expecter {
+	return &MockLink_Expecter{mock: &_m.Mock}
+}
+
+// ConnectNetwork provides a mock function with given fields: _a0, _a1
+func (_m *MockLink) ConnectNetwork(_a0 context.Context, _a1 *NetworkRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConnectNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetworkRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLink_ConnectNetwork_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConnectNetwork'
+type MockLink_ConnectNetwork_Call struct {
+	*mock.Call
+}
+
+// ConnectNetwork is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetworkRequest
+func (_e *MockLink_Expecter) ConnectNetwork(_a0 interface{}, _a1 interface{}) *MockLink_ConnectNetwork_Call {
+	return &MockLink_ConnectNetwork_Call{Call: _e.mock.On("ConnectNetwork", _a0, _a1)}
+}
+
+func (_c *MockLink_ConnectNetwork_Call) Run(run func(_a0 context.Context, _a1 *NetworkRequest)) *MockLink_ConnectNetwork_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetworkRequest))
+	})
+	return _c
+}
+
+func (_c *MockLink_ConnectNetwork_Call) Return(_a0 error) *MockLink_ConnectNetwork_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLink_ConnectNetwork_Call) RunAndReturn(run func(context.Context, *NetworkRequest) error) *MockLink_ConnectNetwork_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisconnectNetwork provides a mock function with given fields: _a0, _a1
+func (_m *MockLink) DisconnectNetwork(_a0 context.Context, _a1 *NetworkRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisconnectNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetworkRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLink_DisconnectNetwork_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisconnectNetwork'
+type MockLink_DisconnectNetwork_Call struct {
+	*mock.Call
+}
+
+// DisconnectNetwork is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetworkRequest
+func (_e *MockLink_Expecter) DisconnectNetwork(_a0 interface{}, _a1 interface{}) *MockLink_DisconnectNetwork_Call {
+	return &MockLink_DisconnectNetwork_Call{Call: _e.mock.On("DisconnectNetwork", _a0, _a1)}
+}
+
+func (_c *MockLink_DisconnectNetwork_Call) Run(run func(_a0 context.Context, _a1 *NetworkRequest)) *MockLink_DisconnectNetwork_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetworkRequest))
+	})
+	return _c
+}
+
+func (_c *MockLink_DisconnectNetwork_Call) Return(_a0 error) *MockLink_DisconnectNetwork_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLink_DisconnectNetwork_Call) RunAndReturn(run func(context.Context, *NetworkRequest) error) *MockLink_DisconnectNetwork_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockLink) LinkContainer(_a0 context.Context, _a1 *LinkRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkContainer")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *LinkRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *LinkRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *LinkRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLink_LinkContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkContainer'
+type MockLink_LinkContainer_Call struct {
+	*mock.Call
+}
+
+// LinkContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *LinkRequest
+func (_e *MockLink_Expecter) LinkContainer(_a0 interface{}, _a1 interface{}) *MockLink_LinkContainer_Call {
+	return &MockLink_LinkContainer_Call{Call: _e.mock.On("LinkContainer", _a0, _a1)}
+}
+
+func (_c *MockLink_LinkContainer_Call) Run(run func(_a0 context.Context, _a1 *LinkRequest)) *MockLink_LinkContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*LinkRequest))
+	})
+	return _c
+}
+
+func (_c *MockLink_LinkContainer_Call) Return(_a0 string, _a1 error) *MockLink_LinkContainer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLink_LinkContainer_Call) RunAndReturn(run func(context.Context, *LinkRequest) (string, error)) *MockLink_LinkContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLink creates a new instance of MockLink. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLink(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLink {
+	mock := &MockLink{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -68,6 +68,63 @@ func (_c *MockNetem_NetemContainer_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// NetemStatus provides a mock function with given fields: _a0, _a1
+func (_m *MockNetem) NetemStatus(_a0 context.Context, _a1 *NetemRequest) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NetemStatus")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *NetemRequest) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *NetemRequest) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *NetemRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNetem_NetemStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NetemStatus'
+type MockNetem_NetemStatus_Call struct {
+	*mock.Call
+}
+
+// NetemStatus is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *NetemRequest
+func (_e *MockNetem_Expecter) NetemStatus(_a0 interface{}, _a1 interface{}) *MockNetem_NetemStatus_Call {
+	return &MockNetem_NetemStatus_Call{Call: _e.mock.On("NetemStatus", _a0, _a1)}
+}
+
+func (_c *MockNetem_NetemStatus_Call) Run(run func(_a0 context.Context, _a1 *NetemRequest)) *MockNetem_NetemStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*NetemRequest))
+	})
+	return _c
+}
+
+func (_c *MockNetem_NetemStatus_Call) Return(_a0 string, _a1 error) *MockNetem_NetemStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNetem_NetemStatus_Call) RunAndReturn(run func(context.Context, *NetemRequest) (string, error)) *MockNetem_NetemStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StopNetemContainer provides a mock function with given fields: _a0, _a1
 func (_m *MockNetem) StopNetemContainer(_a0 context.Context, _a1 *NetemRequest) error {
 	ret := _m.Called(_a0, _a1)
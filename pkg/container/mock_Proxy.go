@@ -0,0 +1,130 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package container
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockProxy is an autogenerated mock type for the Proxy type
+type MockProxy struct {
+	mock.Mock
+}
+
+type MockProxy_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockProxy) EXPECT() *MockProxy_Expecter {
+	return &MockProxy_Expecter{mock: &_m.Mock}
+}
+
+// ProxyContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockProxy) ProxyContainer(_a0 context.Context, _a1 *ProxyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProxyContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ProxyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProxy_ProxyContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProxyContainer'
+type MockProxy_ProxyContainer_Call struct {
+	*mock.Call
+}
+
+// ProxyContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *ProxyRequest
+func (_e *MockProxy_Expecter) ProxyContainer(_a0 interface{}, _a1 interface{}) *MockProxy_ProxyContainer_Call {
+	return &MockProxy_ProxyContainer_Call{Call: _e.mock.On("ProxyContainer", _a0, _a1)}
+}
+
+func (_c *MockProxy_ProxyContainer_Call) Run(run func(_a0 context.Context, _a1 *ProxyRequest)) *MockProxy_ProxyContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*ProxyRequest))
+	})
+	return _c
+}
+
+func (_c *MockProxy_ProxyContainer_Call) Return(_a0 error) *MockProxy_ProxyContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProxy_ProxyContainer_Call) RunAndReturn(run func(context.Context, *ProxyRequest) error) *MockProxy_ProxyContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StopProxyContainer provides a mock function with given fields: _a0, _a1
+func (_m *MockProxy) StopProxyContainer(_a0 context.Context, _a1 *ProxyRequest) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StopProxyContainer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ProxyRequest) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProxy_StopProxyContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StopProxyContainer'
+type MockProxy_StopProxyContainer_Call struct {
+	*mock.Call
+}
+
+// StopProxyContainer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *ProxyRequest
+func (_e *MockProxy_Expecter) StopProxyContainer(_a0 interface{}, _a1 interface{}) *MockProxy_StopProxyContainer_Call {
+	return &MockProxy_StopProxyContainer_Call{Call: _e.mock.On("StopProxyContainer", _a0, _a1)}
+}
+
+func (_c *MockProxy_StopProxyContainer_Call) Run(run func(_a0 context.Context, _a1 *ProxyRequest)) *MockProxy_StopProxyContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*ProxyRequest))
+	})
+	return _c
+}
+
+func (_c *MockProxy_StopProxyContainer_Call) Return(_a0 error) *MockProxy_StopProxyContainer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProxy_StopProxyContainer_Call) RunAndReturn(run func(context.Context, *ProxyRequest) error) *MockProxy_StopProxyContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockProxy creates a new instance of MockProxy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProxy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProxy {
+	mock := &MockProxy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,113 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// listProcessesScript prints a "<pid>\t<name>\t<command line>" line for every
+// process in the PID namespace but the listing shell and, when it runs in a
+// sidecar sharing the namespace, the processes of the sidecar's cgroup. The
+// target's processes are kept whatever their cgroup, so workers a supervisor
+// moved into child cgroups are listed. Only the shell, cat and tr are needed.
+const listProcessesScript = `proc=/proc; self=$$; own=$(cat "$proc/self/cgroup"); ` +
+	`[ "$own" = "$(cat "$proc/1/cgroup")" ] && own=; for p in "$proc"/[0-9]*; do ` +
+	`pid=${p##*/}; [ "$pid" = "$self" ] && continue; ` +
+	`[ -n "$own" ] && [ "$(cat "$p/cgroup" 2>/dev/null)" = "$own" ] && continue; ` +
+	`printf '%s\t%s\t' "$pid" "$(cat "$p/comm" 2>/dev/null)"; tr '\0' ' ' < "$p/cmdline" 2>/dev/null; echo; done`
+
+// Process is a process of a container.
+type Process struct {
+	PID     int
+	Name    string
+	Command string
+}
+
+// ListProcessesArgs returns the sh arguments listing the processes of a
+// container; see ParseProcesses.
+func ListProcessesArgs() []string {
+	return []string{"-c", listProcessesScript}
+}
+
+// ParseProcesses parses the output of the ListProcessesArgs shell. Zombies
+// and other processes without a command line are left out.
+func ParseProcesses(out string) []Process {
+	var procs []Process
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3) //nolint:mnd
+		if len(fields) != 3 {                   //nolint:mnd
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		command := strings.TrimSpace(fields[2])
+		if err != nil || command == "" {
+			continue
+		}
+		procs = append(procs, Process{PID: pid, Name: fields[1], Command: command})
+	}
+	return procs
+}
+
+// SignalArgs returns the sh arguments sending signal, e.g. SIGSTOP, to
+// pids; the shell prints the PIDs it signaled, see ParsePIDs.
+func SignalArgs(signal string, pids []int) []string {
+	var list strings.Builder
+	for i, pid := range pids {
+		if i > 0 {
+			list.WriteByte(' ')
+		}
+		list.WriteString(strconv.Itoa(pid))
+	}
+	return []string{"-c", fmt.Sprintf("for pid in %s; do kill -s %s $pid 2>/dev/null && echo $pid; done; true",
+		list.String(), strings.TrimPrefix(signal, "SIG"))}
+}
+
+// ParsePIDs parses the PIDs printed one per line.
+func ParsePIDs(out string) []int {
+	var pids []int
+	for field := range strings.FieldsSeq(out) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// signalName matches the signal names SignalArgs passes to the shell.
+var signalName = regexp.MustCompile(`^(SIG)?[A-Z0-9]+$`)
+
+// SignalWithShell carries out req, running sh with args through sh: the
+// runtimes differ only in where the shell runs. It returns the PIDs
+// signaled, none when no process matches.
+func SignalWithShell(ctx context.Context, req *SignalRequest, sh func(ctx context.Context, args []string) (string, error)) ([]int, error) {
+	if !signalName.MatchString(req.Signal) {
+		return nil, fmt.Errorf("bad signal %q", req.Signal)
+	}
+	pids := req.PIDs
+	if len(pids) == 0 {
+		re, err := regexp.Compile(req.Process)
+		if err != nil {
+			return nil, fmt.Errorf("bad process regular expression: %w", err)
+		}
+		out, err := sh(ctx, ListProcessesArgs())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+		for _, p := range ParseProcesses(out) {
+			if re.MatchString(p.Name) || re.MatchString(p.Command) {
+				pids = append(pids, p.PID)
+			}
+		}
+		if len(pids) == 0 {
+			return nil, nil
+		}
+	}
+	out, err := sh(ctx, SignalArgs(req.Signal, pids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s to processes %v: %w", req.Signal, pids, err)
+	}
+	return ParsePIDs(out), nil
+}
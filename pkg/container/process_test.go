@@ -0,0 +1,109 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcesses(t *testing.T) {
+	out := "1\ts6-svscan\ts6-svscan /etc/s6 \n" +
+		"17\tnginx\tnginx: worker process \n" +
+		"23\tdefunct\t\n" +
+		"garbage\n"
+	assert.Equal(t, []Process{
+		{PID: 1, Name: "s6-svscan", Command: "s6-svscan /etc/s6"},
+		{PID: 17, Name: "nginx", Command: "nginx: worker process"},
+	}, ParseProcesses(out))
+}
+
+// fakeProc writes a /proc tree with a process per entry of cgroups, keyed by
+// PID or "self", running its PID as command.
+func fakeProc(t *testing.T, cgroups map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for pid, cgroup := range cgroups {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, pid), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "cgroup"), []byte(cgroup+"\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "comm"), []byte("p"+pid+"\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, pid, "cmdline"), []byte("p"+pid+"\x00-v\x00"), 0o600))
+	}
+	return dir
+}
+
+func listFakeProcesses(t *testing.T, proc string) []Process {
+	t.Helper()
+	args := ListProcessesArgs()
+	script := strings.Replace(args[1], "proc=/proc;", "proc="+proc+";", 1)
+	out, err := exec.CommandContext(context.Background(), "sh", "-c", script).Output()
+	require.NoError(t, err)
+	return ParseProcesses(string(out))
+}
+
+func TestListProcessesScript(t *testing.T) {
+	proc := fakeProc(t, map[string]string{
+		"1":    "0::/target",
+		"10":   "0::/target/worker",
+		"20":   "0::/sidecar",
+		"self": "0::/sidecar",
+	})
+	assert.Equal(t, []Process{{PID: 1, Name: "p1", Command: "p1 -v"}, {PID: 10, Name: "p10", Command: "p10 -v"}},
+		listFakeProcesses(t, proc), "workers in child cgroups are kept, the sidecar's processes are not")
+
+	proc = fakeProc(t, map[string]string{
+		"1":    "0::/target",
+		"10":   "0::/target/worker",
+		"self": "0::/target",
+	})
+	assert.Equal(t, []Process{{PID: 1, Name: "p1", Command: "p1 -v"}, {PID: 10, Name: "p10", Command: "p10 -v"}},
+		listFakeProcesses(t, proc), "inside the target every process is kept")
+}
+
+func TestSignalArgs(t *testing.T) {
+	assert.Equal(t, []string{"-c", "for pid in 7 9; do kill -s STOP $pid 2>/dev/null && echo $pid; done; true"},
+		SignalArgs("SIGSTOP", []int{7, 9}))
+	assert.Equal(t, []int{7, 9}, ParsePIDs("7\n9\n"))
+}
+
+func TestSignalWithShell(t *testing.T) {
+	var calls [][]string
+	sh := func(_ context.Context, args []string) (string, error) {
+		calls = append(calls, args)
+		if len(calls) == 1 {
+			return "1\ttini\ttini -- supervisord\n12\tpython\tpython worker.py\n13\tpython\tpython web.py\n", nil
+		}
+		return "12\n", nil
+	}
+	pids, err := SignalWithShell(context.TODO(), &SignalRequest{Process: "worker", Signal: "SIGTERM"}, sh)
+	require.NoError(t, err)
+	assert.Equal(t, []int{12}, pids)
+	assert.Equal(t, [][]string{ListProcessesArgs(), SignalArgs("SIGTERM", []int{12})}, calls)
+
+	calls = nil
+	pids, err = SignalWithShell(context.TODO(), &SignalRequest{Process: "^nginx$", Signal: "SIGTERM"}, sh)
+	require.NoError(t, err)
+	assert.Empty(t, pids, "no process matches")
+	assert.Len(t, calls, 1)
+
+	calls = nil
+	_, err = SignalWithShell(context.TODO(), &SignalRequest{PIDs: []int{12}, Signal: "SIGCONT"}, sh)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{SignalArgs("SIGCONT", []int{12})}, calls, "known PIDs are not listed again")
+}
+
+func TestSignalWithShell_Errors(t *testing.T) {
+	sh := func(context.Context, []string) (string, error) { return "", errors.New("no sh") }
+	_, err := SignalWithShell(context.TODO(), &SignalRequest{Process: "x", Signal: "STOP; rm -rf /"}, sh)
+	require.EqualError(t, err, `bad signal "STOP; rm -rf /"`)
+	_, err = SignalWithShell(context.TODO(), &SignalRequest{Process: "(", Signal: "SIGSTOP"}, sh)
+	require.ErrorContains(t, err, "bad process regular expression")
+	_, err = SignalWithShell(context.TODO(), &SignalRequest{Process: "x", Signal: "SIGSTOP"}, sh)
+	require.EqualError(t, err, "failed to list processes: no sh")
+}
@@ -1,7 +1,11 @@
 package container
 
 import (
+	"context"
+	"fmt"
 	"net"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -16,7 +20,10 @@ type SidecarSpec struct {
 // NetemRequest carries every parameter required to apply or stop a netem rule
 // on a target container. Stop operations reuse the same struct; Duration is
 // ignored on stop. Zero values are safe — slices may be nil and Sidecar may
-// be left empty when the runtime does not need it.
+// be left empty when the runtime does not need it. A non-nil Capture records
+// the traffic from before the rule is applied until after it is removed.
+// Verify selects the chaos-side read-back of the qdisc after apply and stop
+// (none, warn or fail); runtimes ignore it.
 type NetemRequest struct {
 	Container *Container
 	Interface string
@@ -26,12 +33,30 @@ type NetemRequest struct {
 	DPorts    []string
 	Duration  time.Duration
 	Sidecar   SidecarSpec
+	Capture   *CaptureSpec
+	Verify    string
 	DryRun    bool
 }
 
+// Packet filter backends accepted in IPTablesRequest.Backend. The empty
+// string is treated as FilterBackendIPTables.
+const (
+	// FilterBackendIPTables applies rules with the iptables binary.
+	FilterBackendIPTables = "iptables"
+	// FilterBackendNFTables translates rules into a dedicated nft table.
+	FilterBackendNFTables = "nftables"
+	// FilterBackendAuto probes the target netns and prefers nftables when
+	// `nft` is usable there, falling back to iptables otherwise. The probe
+	// runs once, on install: IPTablesContainer replaces it in the request
+	// with the backend resolved, and removals must pass that backend.
+	FilterBackendAuto = "auto"
+)
+
 // IPTablesRequest carries every parameter required to apply or stop an
 // iptables rule on a target container. Stop operations reuse the same
-// struct; Duration is ignored on stop. Zero values are safe.
+// struct; Duration is ignored on stop. Zero values are safe. CmdPrefix and
+// CmdSuffix are always expressed in iptables syntax; runtimes translate them
+// when Backend selects nftables. Capture and Verify work as in NetemRequest.
 type IPTablesRequest struct {
 	Container *Container
 	CmdPrefix []string
@@ -42,6 +67,9 @@ type IPTablesRequest struct {
 	DPorts    []string
 	Duration  time.Duration
 	Sidecar   SidecarSpec
+	Capture   *CaptureSpec
+	Backend   string
+	Verify    string
 	DryRun    bool
 }
 
@@ -70,6 +98,66 @@ type StressResult struct {
 	Errors    <-chan error
 }
 
+// ExecResult is the outcome of a command run by Executor.ExecContainer.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Err reports a non-zero exit of command as an error carrying its stderr.
+func (r *ExecResult) Err(command string) error {
+	if r == nil || r.ExitCode == 0 {
+		return nil
+	}
+	if msg := strings.TrimSpace(r.Stderr); msg != "" {
+		return fmt.Errorf("%s exited with code %d: %s", command, r.ExitCode, msg)
+	}
+	return fmt.Errorf("%s exited with code %d", command, r.ExitCode)
+}
+
+type execTimeoutKey struct{}
+
+// WithExecTimeout returns ctx bounding the ExecContainer calls made with it
+// by d, which the caller also sets as the ctx deadline. Runtimes that cannot
+// stop an exec when ctx ends have the command killed in the container after
+// d instead of leaving it running.
+func WithExecTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, execTimeoutKey{}, d)
+}
+
+// ExecTimeout returns the timeout set by WithExecTimeout, if any.
+func ExecTimeout(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(execTimeoutKey{}).(time.Duration)
+	return d, ok
+}
+
+type cleanupKey struct{}
+
+// WithCleanup returns a context for reverting chaos: detached from the
+// cancellation of ctx, so an abort still reverts it, and marked, so clients
+// recording the calls made with it tell the cleanup from the injection.
+func WithCleanup(ctx context.Context) context.Context {
+	return context.WithValue(context.WithoutCancel(ctx), cleanupKey{}, true)
+}
+
+// IsCleanup reports whether ctx comes from WithCleanup.
+func IsCleanup(ctx context.Context) bool {
+	cleanup, _ := ctx.Value(cleanupKey{}).(bool)
+	return cleanup
+}
+
+// CopyRequest writes Content to the absolute Path in the filesystem of a
+// container, as a file of Mode owned by root. Parent directories must exist.
+type CopyRequest struct {
+	Container *Container
+	Path      string
+	Content   []byte
+	Mode      os.FileMode
+	DryRun    bool
+}
+
 // RemoveOpts bundles the boolean flags that govern Lifecycle.RemoveContainer.
 // Force translates to the runtime's force-removal flag (typically SIGKILL +
 // teardown). Links and Volumes opt into removing linked containers and
@@ -82,3 +170,109 @@ type RemoveOpts struct {
 	Volumes bool
 	DryRun  bool
 }
+
+// ProxyUser is the numeric user the proxy process runs as. Redirect rules
+// exclude its traffic with `-m owner ! --uid-owner` so the proxy's own
+// upstream connections are not looped back into it.
+const ProxyUser = "65534"
+
+// ProxyRequest carries every parameter required to start or stop a
+// transparent proxy sidecar in a target container's network namespace.
+// Proxy is the image running Command (the full proxy argv, executed as
+// ProxyUser); Sidecar is the iptables image used to install and remove the
+// Redirects. Each redirect is an iptables nat rule spec starting with its
+// chain, e.g. {"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT",
+// "--to-ports", "15353"}; runtimes prepend `-t nat -I` to install it and
+// `-t nat -D` to remove it. Stop operations reuse the same struct.
+type ProxyRequest struct {
+	Container *Container
+	Name      string
+	Command   []string
+	Redirects [][]string
+	Proxy     SidecarSpec
+	Sidecar   SidecarSpec
+	DryRun    bool
+}
+
+// SidecarName returns the deterministic name of the proxy sidecar, so stop
+// can find it without extra state and a second proxy of the same kind on the
+// same target fails fast on the name conflict.
+func (r *ProxyRequest) SidecarName() string {
+	id := r.Container.ID()
+	if len(id) > 12 { //nolint:mnd
+		id = id[:12]
+	}
+	return "pumba-" + r.Name + "-proxy-" + id
+}
+
+// RedirectArgs returns the iptables argument lists that install (op "-I") or
+// remove (op "-D") the request's nat redirects.
+func (r *ProxyRequest) RedirectArgs(op string) [][]string {
+	argsList := make([][]string, 0, len(r.Redirects))
+	for _, redirect := range r.Redirects {
+		args := make([]string, 0, 3+len(redirect)) //nolint:mnd
+		args = append(args, "-t", "nat", op)
+		argsList = append(argsList, append(args, redirect...))
+	}
+	return argsList
+}
+
+// LinkRequest carries the `ip` commands run in a target container's network
+// namespace to change one of its interfaces, e.g. {"link", "set", "dev",
+// "eth0", "down"}. Commands run in order in a single sidecar; the combined
+// stdout is returned so callers can read state (MTU, routes) to restore later.
+type LinkRequest struct {
+	Container *Container
+	Commands  [][]string
+	Sidecar   SidecarSpec
+	DryRun    bool
+}
+
+// NetworkRequest names a runtime network a container is disconnected from
+// or reconnected to. Reconnect restores the aliases and static address
+// recorded in Container.Networks before the disconnect.
+type NetworkRequest struct {
+	Container *Container
+	Network   string
+	DryRun    bool
+}
+
+// SignalRequest sends Signal, e.g. SIGSTOP, to the processes of a container
+// whose name or command line match the regular expression Process or, when
+// set, to PIDs. A shell lists and signals the processes: in a sidecar of
+// Sidecar.Image sharing the target's PID namespace or, without an image, in
+// the target itself. See SignalWithShell.
+type SignalRequest struct {
+	Container *Container
+	Process   string
+	PIDs      []int
+	Signal    string
+	Sidecar   SidecarSpec
+	DryRun    bool
+}
+
+// Check statuses reported by Diagnoser.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Check is the outcome of a single preflight check. Hint tells the user how
+// to fix a failed or warned check.
+type Check struct {
+	Name   string
+	Status string
+	Detail string
+	Hint   string
+}
+
+// DiagnoseRequest selects the sidecar images `pumba doctor` checks. Sidecar
+// is the nettools image the capability and kernel module probes run in;
+// Images are further sidecar images (e.g. stress-ng) that only need to be
+// available. Pull pulls every image instead of looking it up locally.
+type DiagnoseRequest struct {
+	Sidecar string
+	Images  []string
+	Pull    bool
+}
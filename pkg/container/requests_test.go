@@ -99,3 +99,23 @@ func TestIPTablesRequest_Hydration(t *testing.T) {
 	assert.Equal(t, SidecarSpec{Image: "img", Pull: true}, r.Sidecar)
 	assert.True(t, r.DryRun)
 }
+
+func TestProxyRequest_SidecarName(t *testing.T) {
+	r := ProxyRequest{Container: &Container{ContainerID: "0123456789abcdef"}, Name: "dns"}
+	assert.Equal(t, "pumba-dns-proxy-0123456789ab", r.SidecarName())
+	r.Container = &Container{ContainerID: "short"}
+	assert.Equal(t, "pumba-dns-proxy-short", r.SidecarName())
+}
+
+func TestProxyRequest_RedirectArgs(t *testing.T) {
+	r := ProxyRequest{Redirects: [][]string{
+		{"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"},
+		{"OUTPUT", "-p", "tcp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"},
+	}}
+	assert.Equal(t, [][]string{
+		{"-t", "nat", "-I", "OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"},
+		{"-t", "nat", "-I", "OUTPUT", "-p", "tcp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"},
+	}, r.RedirectArgs("-I"))
+	assert.Equal(t, "-D", r.RedirectArgs("-D")[1][2])
+	assert.Empty(t, (&ProxyRequest{}).RedirectArgs("-I"))
+}
@@ -0,0 +1,368 @@
+// Package dnsproxy implements the DNS fault-injection proxy behind the `dns`
+// chaos command. The proxy runs as the hidden `pumba dns-proxy` command in a
+// sidecar sharing the target's network namespace; the target's DNS traffic is
+// redirected to it with iptables and every query whose name matches one of the
+// configured domain patterns is dropped, delayed, answered with NXDOMAIN or
+// SERVFAIL, or answered with a spoofed address. Other queries are forwarded to
+// the upstream resolver unchanged.
+package dnsproxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Fault actions applied to matching queries.
+const (
+	// ActionDrop swallows the query; the client times out.
+	ActionDrop = "drop"
+	// ActionDelay forwards the query after Config.Delay.
+	ActionDelay = "delay"
+	// ActionNXDomain answers with NXDOMAIN (name does not exist).
+	ActionNXDomain = "nxdomain"
+	// ActionServFail answers with SERVFAIL (resolver failure).
+	ActionServFail = "servfail"
+	// ActionSpoof answers A or AAAA queries with Config.SpoofIP.
+	ActionSpoof = "spoof"
+)
+
+const (
+	// DefaultPort is the port the proxy listens on inside the target netns.
+	DefaultPort = 15353
+
+	upstreamTimeout = 5 * time.Second
+	maxMessageSize  = 65535
+	spoofTTL        = 30
+)
+
+// Actions lists every supported fault action.
+var Actions = []string{ActionDrop, ActionDelay, ActionNXDomain, ActionServFail, ActionSpoof}
+
+// resolvConfPath is where the default upstream resolver is read from; a var
+// so tests can point it elsewhere.
+var resolvConfPath = "/etc/resolv.conf"
+
+// Config describes the faults the proxy injects.
+type Config struct {
+	// Listen is the UDP and TCP address the proxy serves on.
+	Listen string
+	// Upstream is the resolver (host:port) non-dropped queries are forwarded
+	// to; empty selects the first nameserver from /etc/resolv.conf.
+	Upstream string
+	// Action is one of Actions.
+	Action string
+	// Domains are the name patterns the action applies to; empty matches
+	// every query. See MatchDomain.
+	Domains []string
+	// Delay is how long ActionDelay holds a query before forwarding it.
+	Delay time.Duration
+	// SpoofIP is the address returned by ActionSpoof.
+	SpoofIP net.IP
+}
+
+// Validate checks that the action and its parameters are consistent.
+func (c *Config) Validate() error {
+	if !slices.Contains(Actions, c.Action) {
+		return fmt.Errorf("invalid dns action %q: must be one of %s", c.Action, strings.Join(Actions, ", "))
+	}
+	if c.Action == ActionDelay && c.Delay <= 0 {
+		return errors.New("invalid dns delay: must be > 0 for the delay action")
+	}
+	if c.Action == ActionSpoof && c.SpoofIP == nil {
+		return errors.New("spoof action requires a valid spoof IP address")
+	}
+	for _, d := range c.Domains {
+		if _, err := path.Match(normalize(d), ""); err != nil {
+			return fmt.Errorf("invalid domain pattern %q: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// MatchDomain reports whether name matches any of the patterns; an empty
+// pattern list matches everything. A plain pattern matches the domain itself
+// and all of its subdomains ("example.com" matches "api.example.com"); a
+// pattern containing wildcards is matched as a whole with path.Match
+// semantics ("*.example.com", "db-?.internal"). Matching ignores case and the
+// trailing root dot.
+func MatchDomain(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	name = normalize(name)
+	for _, p := range patterns {
+		p = normalize(p)
+		if strings.ContainsAny(p, "*?[") {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+			continue
+		}
+		if name == p || strings.HasSuffix(name, "."+p) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// Proxy answers or forwards DNS messages according to its Config.
+type Proxy struct {
+	cfg Config
+	// exchange sends a query upstream over network ("udp" or "tcp") and
+	// returns the raw response; replaced in tests.
+	exchange func(ctx context.Context, network string, query []byte) ([]byte, error)
+}
+
+// New validates cfg, resolves the default upstream and returns a Proxy.
+func New(cfg Config) (*Proxy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Upstream == "" {
+		upstream, err := systemResolver(resolvConfPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Upstream = upstream
+	} else if _, _, err := net.SplitHostPort(cfg.Upstream); err != nil {
+		cfg.Upstream = net.JoinHostPort(cfg.Upstream, "53")
+	}
+	p := &Proxy{cfg: cfg}
+	p.exchange = p.forward
+	return p, nil
+}
+
+// systemResolver returns the first nameserver of a resolv.conf file as host:port.
+func systemResolver(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("no upstream resolver configured and failed to read %s: %w", file, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" { //nolint:mnd
+			return net.JoinHostPort(fields[1], "53"), nil
+		}
+	}
+	return "", fmt.Errorf("no upstream resolver configured and no nameserver found in %s", file)
+}
+
+// Handle applies the configured fault to a raw DNS query received over
+// network and returns the raw response. A nil response with a nil error means
+// the query is dropped.
+func (p *Proxy) Handle(ctx context.Context, network string, query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return p.exchange(ctx, network, query)
+	}
+	question, err := parser.Question()
+	if err != nil || !MatchDomain(p.cfg.Domains, question.Name.String()) {
+		return p.exchange(ctx, network, query)
+	}
+	logger := log.WithFields(log.Fields{"name": question.Name.String(), "type": question.Type, "action": p.cfg.Action})
+	logger.Debug("injecting dns fault")
+	switch p.cfg.Action {
+	case ActionDrop:
+		return nil, nil
+	case ActionDelay:
+		timer := time.NewTimer(p.cfg.Delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		return p.exchange(ctx, network, query)
+	case ActionNXDomain:
+		return reply(header, question, dnsmessage.RCodeNameError, nil)
+	case ActionServFail:
+		return reply(header, question, dnsmessage.RCodeServerFailure, nil)
+	default: // ActionSpoof
+		return reply(header, question, dnsmessage.RCodeSuccess, p.cfg.SpoofIP)
+	}
+}
+
+// reply builds a response to question with rcode. When ip is set and matches
+// the question type (A for IPv4, AAAA for IPv6) it is returned as the answer;
+// other question types get an empty NOERROR answer.
+func reply(query dnsmessage.Header, question dnsmessage.Question, rcode dnsmessage.RCode, ip net.IP) ([]byte, error) {
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ //nolint:mnd
+		ID:                 query.ID,
+		Response:           true,
+		OpCode:             query.OpCode,
+		RecursionDesired:   query.RecursionDesired,
+		RecursionAvailable: true,
+		RCode:              rcode,
+	})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(question); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	rh := dnsmessage.ResourceHeader{Name: question.Name, Class: question.Class, TTL: spoofTTL}
+	if ip4 := ip.To4(); ip4 != nil && question.Type == dnsmessage.TypeA {
+		if err := b.AResource(rh, dnsmessage.AResource{A: [4]byte(ip4)}); err != nil {
+			return nil, err
+		}
+	} else if ip != nil && ip.To4() == nil && question.Type == dnsmessage.TypeAAAA {
+		if err := b.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())}); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+// forward sends query to the upstream resolver and returns its response.
+func (p *Proxy) forward(ctx context.Context, network string, query []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, p.cfg.Upstream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach upstream resolver %s: %w", p.cfg.Upstream, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if network == "tcp" {
+		if err := writeTCPMessage(conn, query); err != nil {
+			return nil, err
+		}
+		return readTCPMessage(conn)
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, maxMessageSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// ListenAndServe serves DNS over UDP and TCP on Config.Listen until ctx is
+// canceled.
+func (p *Proxy) ListenAndServe(ctx context.Context) error {
+	var lc net.ListenConfig
+	pc, err := lc.ListenPacket(ctx, "udp", p.cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on udp %s: %w", p.cfg.Listen, err)
+	}
+	ln, err := lc.Listen(ctx, "tcp", p.cfg.Listen)
+	if err != nil {
+		pc.Close()
+		return fmt.Errorf("failed to listen on tcp %s: %w", p.cfg.Listen, err)
+	}
+	log.WithFields(log.Fields{"listen": p.cfg.Listen, "upstream": p.cfg.Upstream, "action": p.cfg.Action, "domains": p.cfg.Domains}).Info("dns proxy started")
+	go func() {
+		<-ctx.Done()
+		pc.Close()
+		ln.Close()
+	}()
+	go p.serveTCP(ctx, ln)
+	p.serveUDP(ctx, pc)
+	return nil
+}
+
+func (p *Proxy) serveUDP(ctx context.Context, pc net.PacketConn) {
+	for {
+		buf := make([]byte, maxMessageSize)
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithError(err).Error("dns proxy udp read failed")
+			}
+			return
+		}
+		go func() {
+			resp, err := p.Handle(ctx, "udp", buf[:n])
+			if err != nil {
+				log.WithError(err).Debug("dns proxy failed to handle udp query")
+				return
+			}
+			if resp != nil {
+				_, _ = pc.WriteTo(resp, addr)
+			}
+		}()
+	}
+}
+
+func (p *Proxy) serveTCP(ctx context.Context, ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.WithError(err).Error("dns proxy tcp accept failed")
+			}
+			return
+		}
+		go func() {
+			defer conn.Close()
+			for {
+				query, err := readTCPMessage(conn)
+				if err != nil {
+					return
+				}
+				resp, err := p.Handle(ctx, "tcp", query)
+				if err != nil || resp == nil {
+					return
+				}
+				if err := writeTCPMessage(conn, resp); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// readTCPMessage reads a DNS message with its two-byte length prefix.
+func readTCPMessage(r io.Reader) ([]byte, error) {
+	var size uint16
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// writeTCPMessage writes a DNS message with its two-byte length prefix.
+func writeTCPMessage(w io.Writer, msg []byte) error {
+	if len(msg) > maxMessageSize {
+		return errors.New("dns message too large")
+	}
+	buf := make([]byte, 2+len(msg)) //nolint:mnd
+	binary.BigEndian.PutUint16(buf, uint16(len(msg)))
+	copy(buf[2:], msg)
+	_, err := w.Write(buf)
+	return err
+}
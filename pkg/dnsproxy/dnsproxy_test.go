@@ -0,0 +1,249 @@
+package dnsproxy
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func buildQuery(t *testing.T, name string, qtype dnsmessage.Type) []byte {
+	t.Helper()
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  qtype,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	b, err := msg.Pack()
+	require.NoError(t, err)
+	return b
+}
+
+func parseResponse(t *testing.T, b []byte) dnsmessage.Message {
+	t.Helper()
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(b))
+	return msg
+}
+
+// newTestProxy returns a proxy whose upstream echoes the query back.
+func newTestProxy(t *testing.T, cfg Config) (*Proxy, *int) {
+	t.Helper()
+	cfg.Upstream = "127.0.0.1:53"
+	p, err := New(cfg)
+	require.NoError(t, err)
+	forwarded := 0
+	p.exchange = func(_ context.Context, _ string, query []byte) ([]byte, error) {
+		forwarded++
+		return query, nil
+	}
+	return p, &forwarded
+}
+
+func TestMatchDomain(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{nil, "anything.com.", true},
+		{[]string{"example.com"}, "example.com.", true},
+		{[]string{"example.com"}, "API.Example.com.", true},
+		{[]string{"example.com"}, "badexample.com.", false},
+		{[]string{"*.example.com"}, "api.example.com.", true},
+		{[]string{"*.example.com"}, "example.com.", false},
+		{[]string{"db-?.internal"}, "db-1.internal.", true},
+		{[]string{"other.org", "example.com."}, "www.example.com", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchDomain(tt.patterns, tt.name), "%v %s", tt.patterns, tt.name)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid nxdomain", Config{Action: ActionNXDomain}, ""},
+		{"unknown action", Config{Action: "refuse"}, "invalid dns action"},
+		{"delay without duration", Config{Action: ActionDelay}, "invalid dns delay"},
+		{"spoof without ip", Config{Action: ActionSpoof}, "requires a valid spoof IP"},
+		{"bad pattern", Config{Action: ActionDrop, Domains: []string{"[a-"}}, "invalid domain pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_DefaultUpstreamFromResolvConf(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "resolv.conf")
+	require.NoError(t, os.WriteFile(file, []byte("search local\nnameserver 127.0.0.11\nnameserver 8.8.8.8\n"), 0o600))
+	old := resolvConfPath
+	resolvConfPath = file
+	t.Cleanup(func() { resolvConfPath = old })
+
+	p, err := New(Config{Action: ActionNXDomain})
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.11:53", p.cfg.Upstream)
+
+	p, err = New(Config{Action: ActionNXDomain, Upstream: "10.0.0.2"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:53", p.cfg.Upstream)
+
+	resolvConfPath = filepath.Join(dir, "missing")
+	_, err = New(Config{Action: ActionNXDomain})
+	assert.ErrorContains(t, err, "no upstream resolver configured")
+}
+
+func TestHandle_NonMatchingQueryIsForwarded(t *testing.T) {
+	p, forwarded := newTestProxy(t, Config{Action: ActionNXDomain, Domains: []string{"example.com"}})
+	query := buildQuery(t, "other.org.", dnsmessage.TypeA)
+	resp, err := p.Handle(context.Background(), "udp", query)
+	require.NoError(t, err)
+	assert.Equal(t, query, resp)
+	assert.Equal(t, 1, *forwarded)
+}
+
+func TestHandle_Actions(t *testing.T) {
+	t.Run("drop", func(t *testing.T) {
+		p, forwarded := newTestProxy(t, Config{Action: ActionDrop})
+		resp, err := p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeA))
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+		assert.Zero(t, *forwarded)
+	})
+	t.Run("delay", func(t *testing.T) {
+		p, forwarded := newTestProxy(t, Config{Action: ActionDelay, Delay: 20 * time.Millisecond})
+		start := time.Now()
+		resp, err := p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeA))
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+		assert.Equal(t, 1, *forwarded)
+	})
+	t.Run("delay aborted", func(t *testing.T) {
+		p, forwarded := newTestProxy(t, Config{Action: ActionDelay, Delay: time.Hour})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := p.Handle(ctx, "udp", buildQuery(t, "example.com.", dnsmessage.TypeA))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, *forwarded)
+	})
+	for action, rcode := range map[string]dnsmessage.RCode{
+		ActionNXDomain: dnsmessage.RCodeNameError,
+		ActionServFail: dnsmessage.RCodeServerFailure,
+	} {
+		t.Run(action, func(t *testing.T) {
+			p, _ := newTestProxy(t, Config{Action: action})
+			resp, err := p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeA))
+			require.NoError(t, err)
+			msg := parseResponse(t, resp)
+			assert.Equal(t, uint16(42), msg.ID)
+			assert.True(t, msg.Response)
+			assert.True(t, msg.RecursionDesired)
+			assert.Equal(t, rcode, msg.RCode)
+			assert.Empty(t, msg.Answers)
+		})
+	}
+	t.Run("spoof A", func(t *testing.T) {
+		p, _ := newTestProxy(t, Config{Action: ActionSpoof, SpoofIP: net.ParseIP("10.1.2.3")})
+		msg := parseResponse(t, must(p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeA))))
+		require.Len(t, msg.Answers, 1)
+		assert.Equal(t, &dnsmessage.AResource{A: [4]byte{10, 1, 2, 3}}, msg.Answers[0].Body)
+	})
+	t.Run("spoof AAAA", func(t *testing.T) {
+		p, _ := newTestProxy(t, Config{Action: ActionSpoof, SpoofIP: net.ParseIP("fd00::1")})
+		msg := parseResponse(t, must(p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeAAAA))))
+		require.Len(t, msg.Answers, 1)
+		assert.Equal(t, dnsmessage.TypeAAAA, msg.Answers[0].Header.Type)
+	})
+	t.Run("spoof other type", func(t *testing.T) {
+		p, _ := newTestProxy(t, Config{Action: ActionSpoof, SpoofIP: net.ParseIP("10.1.2.3")})
+		msg := parseResponse(t, must(p.Handle(context.Background(), "udp", buildQuery(t, "example.com.", dnsmessage.TypeAAAA))))
+		assert.Equal(t, dnsmessage.RCodeSuccess, msg.RCode)
+		assert.Empty(t, msg.Answers)
+	})
+}
+
+func must(b []byte, err error) []byte {
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestTCPMessageFraming(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTCPMessage(&buf, []byte("hello")))
+	assert.Equal(t, []byte{0, 5}, buf.Bytes()[:2])
+	msg, err := readTCPMessage(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), msg)
+}
+
+func TestListenAndServe_UDPAndTCP(t *testing.T) {
+	p, _ := newTestProxy(t, Config{Action: ActionNXDomain, Listen: "127.0.0.1:0"})
+	// pick a free port shared by udp and tcp
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p.cfg.Listen = ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.ListenAndServe(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	query := buildQuery(t, "example.com.", dnsmessage.TypeA)
+	var resp []byte
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("udp", p.cfg.Listen)
+		if err != nil {
+			return false
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(200 * time.Millisecond))
+		if _, err = conn.Write(query); err != nil {
+			return false
+		}
+		buf := make([]byte, 512)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return false
+		}
+		resp = buf[:n]
+		return true
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, dnsmessage.RCodeNameError, parseResponse(t, resp).RCode)
+
+	conn, err := net.Dial("tcp", p.cfg.Listen)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, writeTCPMessage(conn, query))
+	resp, err = readTCPMessage(conn)
+	require.NoError(t, err)
+	assert.Equal(t, dnsmessage.RCodeNameError, parseResponse(t, resp).RCode)
+}
@@ -0,0 +1,269 @@
+// Package httpproxy implements the application-level fault-injection proxy
+// behind the `http` chaos command. The proxy runs as the hidden
+// `pumba http-proxy` command in a sidecar sharing the target's network
+// namespace; incoming connections to the target's service port are redirected
+// to it with iptables and every request matching the configured rule is
+// delayed and/or aborted, with an HTTP status or, for gRPC calls, a gRPC
+// status. Other requests are proxied to the service unchanged. Both HTTP/1.1
+// and cleartext HTTP/2 (h2c, as used by gRPC without TLS) are served.
+package httpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPort is the port the proxy listens on inside the target netns.
+	DefaultPort = 15080
+
+	shutdownTimeout = 5 * time.Second
+	grpcContentType = "application/grpc"
+	faultMessage    = "fault injected by pumba"
+	maxGRPCStatus   = 16
+	maxPercent      = 100
+)
+
+// Config describes the rule selecting requests and the fault applied to them.
+type Config struct {
+	// Listen is the address the proxy serves on.
+	Listen string
+	// Upstream is the service address (host:port) requests are proxied to
+	// when their connection was not redirected to the proxy; redirected
+	// connections go to their original destination, so services bound to
+	// the container IP only are reached.
+	Upstream string
+	// Path selects requests by URL path: a prefix of whole segments ("/api"
+	// matches /api/users, not /apiv2) or, when it contains wildcards, a
+	// path.Match pattern ("/users/*/orders"). Empty matches every path.
+	Path string
+	// Method selects requests by HTTP method; empty matches every method.
+	Method string
+	// Headers select requests carrying every listed header with the given
+	// value (canonical names).
+	Headers map[string]string
+	// Percent is the share (0-100] of matching requests that get the fault.
+	Percent float64
+	// Delay holds matching requests before they are aborted or proxied.
+	Delay time.Duration
+	// AbortStatus answers matching HTTP requests with this status code.
+	AbortStatus int
+	// GRPCStatus answers matching gRPC calls with this gRPC status code.
+	GRPCStatus int
+}
+
+// ParseHeaders parses "Name=value" (or "Name:value") header matchers.
+func ParseHeaders(list []string) (map[string]string, error) {
+	headers := make(map[string]string, len(list))
+	for _, h := range list {
+		i := strings.IndexAny(h, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid header matcher %q: must be <name>=<value>", h)
+		}
+		headers[http.CanonicalHeaderKey(strings.TrimSpace(h[:i]))] = strings.TrimSpace(h[i+1:])
+	}
+	return headers, nil
+}
+
+// Validate checks that the rule selects something and injects a fault.
+func (c *Config) Validate() error {
+	if c.Delay < 0 {
+		return errors.New("invalid http delay: must be >= 0")
+	}
+	if c.AbortStatus != 0 && (c.AbortStatus < 200 || c.AbortStatus > 599) {
+		return fmt.Errorf("invalid http abort status %d: must be between 200 and 599", c.AbortStatus)
+	}
+	if c.GRPCStatus < 0 || c.GRPCStatus > maxGRPCStatus {
+		return fmt.Errorf("invalid grpc status %d: must be between 1 and %d", c.GRPCStatus, maxGRPCStatus)
+	}
+	if c.Delay == 0 && c.AbortStatus == 0 && c.GRPCStatus == 0 {
+		return errors.New("no http fault configured: set a delay, an abort status or a grpc status")
+	}
+	if c.Percent <= 0 || c.Percent > maxPercent {
+		return fmt.Errorf("invalid http fault percent %v: must be in (0, 100]", c.Percent)
+	}
+	if _, err := path.Match(c.Path, ""); err != nil {
+		return fmt.Errorf("invalid path pattern %q: %w", c.Path, err)
+	}
+	return nil
+}
+
+// Proxy is a reverse proxy injecting faults into matching requests.
+type Proxy struct {
+	cfg   Config
+	proxy http.Handler
+	// roll returns a number in [0, 100); replaced in tests.
+	roll func() float64
+}
+
+// New validates cfg and returns a Proxy forwarding to the original
+// destination of each connection, or to cfg.Upstream.
+func New(cfg Config) (*Proxy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	http1 := http.DefaultTransport.(*http.Transport).Clone()
+	h2c := http.DefaultTransport.(*http.Transport).Clone()
+	h2c.Protocols = new(http.Protocols)
+	h2c.Protocols.SetUnencryptedHTTP2(true)
+	p := &Proxy{cfg: cfg, roll: func() float64 { return rand.Float64() * maxPercent }} //nolint:gosec
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(&url.URL{Scheme: "http", Host: p.upstream(r.In)})
+			r.Out.Host = r.In.Host
+		},
+		// stream responses (gRPC, server-sent events) without buffering
+		FlushInterval: -1,
+		Transport:     protoTransport{http1: http1, h2c: h2c},
+	}
+	return p, nil
+}
+
+type originalDstKey struct{}
+
+// withOriginalDst records the address a redirected connection was sent to.
+func withOriginalDst(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, originalDstKey{}, addr)
+}
+
+// upstream returns the address r is proxied to: the original destination of
+// its connection or, when the connection was not redirected, Upstream.
+func (p *Proxy) upstream(r *http.Request) string {
+	if addr, ok := r.Context().Value(originalDstKey{}).(string); ok {
+		return addr
+	}
+	return p.cfg.Upstream
+}
+
+// connContext records the original destination of a connection redirected
+// to the proxy; connections made to the proxy itself have none.
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	dst, err := originalDst(c)
+	if err != nil {
+		log.WithError(err).Debug("no original destination of the connection")
+		return ctx
+	}
+	if local, ok := c.LocalAddr().(*net.TCPAddr); ok && local.Port == dst.Port && local.IP.Equal(dst.IP) {
+		return ctx
+	}
+	return withOriginalDst(ctx, dst.String())
+}
+
+// protoTransport talks to the service in the protocol the client used, so
+// gRPC (HTTP/2) calls stay HTTP/2 end to end.
+type protoTransport struct {
+	http1, h2c http.RoundTripper
+}
+
+func (t protoTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.ProtoMajor == 2 { //nolint:mnd
+		return t.h2c.RoundTrip(r)
+	}
+	return t.http1.RoundTrip(r)
+}
+
+// Matches reports whether r is selected by the rule (ignoring Percent).
+func (p *Proxy) Matches(r *http.Request) bool {
+	if p.cfg.Method != "" && !strings.EqualFold(p.cfg.Method, r.Method) {
+		return false
+	}
+	if p.cfg.Path != "" {
+		if strings.ContainsAny(p.cfg.Path, "*?[") {
+			if ok, _ := path.Match(p.cfg.Path, r.URL.Path); !ok {
+				return false
+			}
+		} else if !hasPathPrefix(r.URL.Path, p.cfg.Path) {
+			return false
+		}
+	}
+	for name, value := range p.cfg.Headers {
+		if r.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPathPrefix reports whether p is prefix or lies below it: /api matches
+// /api and /api/users, not /apiv2.
+func hasPathPrefix(p, prefix string) bool {
+	return p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+func isGRPC(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), grpcContentType)
+}
+
+// ServeHTTP applies the fault to matching requests and proxies the rest.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.Matches(r) || p.roll() >= p.cfg.Percent {
+		p.proxy.ServeHTTP(w, r)
+		return
+	}
+	logger := log.WithFields(log.Fields{"method": r.Method, "path": r.URL.Path, "proto": r.Proto})
+	if p.cfg.Delay > 0 {
+		logger.WithField("delay", p.cfg.Delay).Debug("delaying request")
+		timer := time.NewTimer(p.cfg.Delay)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+	switch {
+	case isGRPC(r) && p.cfg.GRPCStatus != 0:
+		logger.WithField("grpc-status", p.cfg.GRPCStatus).Debug("aborting grpc call")
+		// trailers-only response: the status travels in the headers
+		w.Header().Set("Content-Type", grpcContentType)
+		w.Header().Set("Grpc-Status", strconv.Itoa(p.cfg.GRPCStatus))
+		w.Header().Set("Grpc-Message", faultMessage)
+		w.WriteHeader(http.StatusOK)
+	case !isGRPC(r) && p.cfg.AbortStatus != 0:
+		logger.WithField("status", p.cfg.AbortStatus).Debug("aborting request")
+		http.Error(w, faultMessage, p.cfg.AbortStatus)
+	default:
+		p.proxy.ServeHTTP(w, r)
+	}
+}
+
+// ListenAndServe serves HTTP/1.1 and h2c on Config.Listen until ctx is canceled.
+func (p *Proxy) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              p.cfg.Listen,
+		Handler:           p,
+		ReadHeaderTimeout: time.Minute,
+		ConnContext:       connContext,
+		Protocols:         new(http.Protocols),
+	}
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", p.cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", p.cfg.Listen, err)
+	}
+	log.WithFields(log.Fields{"listen": p.cfg.Listen, "upstream": p.cfg.Upstream}).Info("http proxy started")
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
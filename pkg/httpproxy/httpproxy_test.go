@@ -0,0 +1,243 @@
+package httpproxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProxy returns a proxy in front of a backend answering "ok" with the
+// request protocol in the X-Proto header.
+func newTestProxy(t *testing.T, cfg Config) *Proxy {
+	t.Helper()
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	backend.Config.Protocols = new(http.Protocols)
+	backend.Config.Protocols.SetHTTP1(true)
+	backend.Config.Protocols.SetUnencryptedHTTP2(true)
+	backend.Start()
+	t.Cleanup(backend.Close)
+	cfg.Upstream = strings.TrimPrefix(backend.URL, "http://")
+	if cfg.Percent == 0 {
+		cfg.Percent = 100
+	}
+	p, err := New(cfg)
+	require.NoError(t, err)
+	return p
+}
+
+func serve(p *Proxy, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, r)
+	return w
+}
+
+func TestParseHeaders(t *testing.T) {
+	got, err := ParseHeaders([]string{"x-user=test", "X-Canary: true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-User": "test", "X-Canary": "true"}, got)
+	_, err = ParseHeaders([]string{"novalue"})
+	assert.ErrorContains(t, err, "invalid header matcher")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"abort", Config{AbortStatus: 503, Percent: 100}, ""},
+		{"delay", Config{Delay: time.Second, Percent: 50}, ""},
+		{"grpc", Config{GRPCStatus: 14, Percent: 100}, ""},
+		{"no fault", Config{Percent: 100}, "no http fault configured"},
+		{"bad status", Config{AbortStatus: 99, Percent: 100}, "invalid http abort status"},
+		{"bad grpc status", Config{GRPCStatus: 17, Percent: 100}, "invalid grpc status"},
+		{"negative delay", Config{Delay: -time.Second, Percent: 100}, "invalid http delay"},
+		{"zero percent", Config{AbortStatus: 503}, "invalid http fault percent"},
+		{"percent over 100", Config{AbortStatus: 503, Percent: 101}, "invalid http fault percent"},
+		{"bad path", Config{AbortStatus: 503, Percent: 100, Path: "/[a-"}, "invalid path pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProxy_Matches(t *testing.T) {
+	p := &Proxy{cfg: Config{Path: "/checkout", Method: "post", Headers: map[string]string{"X-User": "test"}}}
+	r := httptest.NewRequest(http.MethodPost, "/checkout/cart", nil)
+	r.Header.Set("X-User", "test")
+	assert.True(t, p.Matches(r))
+
+	r.Header.Set("X-User", "other")
+	assert.False(t, p.Matches(r), "header mismatch")
+	assert.False(t, p.Matches(httptest.NewRequest(http.MethodGet, "/checkout", nil)), "method mismatch")
+
+	p = &Proxy{cfg: Config{Path: "/api"}}
+	assert.True(t, p.Matches(httptest.NewRequest(http.MethodGet, "/api", nil)))
+	assert.True(t, p.Matches(httptest.NewRequest(http.MethodGet, "/api/users", nil)))
+	assert.False(t, p.Matches(httptest.NewRequest(http.MethodGet, "/apiv2", nil)), "prefix ends at a path segment")
+	p = &Proxy{cfg: Config{Path: "/api/"}}
+	assert.True(t, p.Matches(httptest.NewRequest(http.MethodGet, "/api/users", nil)))
+	assert.False(t, p.Matches(httptest.NewRequest(http.MethodGet, "/apiv2", nil)))
+
+	p = &Proxy{cfg: Config{Path: "/users/*/orders"}}
+	assert.True(t, p.Matches(httptest.NewRequest(http.MethodGet, "/users/42/orders", nil)))
+	assert.False(t, p.Matches(httptest.NewRequest(http.MethodGet, "/users/42/profile", nil)))
+	assert.True(t, (&Proxy{}).Matches(httptest.NewRequest(http.MethodGet, "/anything", nil)))
+}
+
+// nonLoopbackIP returns an IPv4 address of a host interface other than
+// loopback.
+func nonLoopbackIP(t *testing.T) net.IP {
+	t.Helper()
+	addrs, err := net.InterfaceAddrs()
+	require.NoError(t, err)
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && !n.IP.IsLoopback() && n.IP.To4() != nil {
+			return n.IP
+		}
+	}
+	t.Skip("no non-loopback IPv4 address")
+	return nil
+}
+
+func TestProxy_ForwardsToOriginalDestination(t *testing.T) {
+	// the service binds the container IP only, as with --bind <ip>
+	ln, err := net.Listen("tcp", net.JoinHostPort(nonLoopbackIP(t).String(), "0"))
+	require.NoError(t, err)
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	backend.Listener = ln
+	backend.Start()
+	t.Cleanup(backend.Close)
+
+	p, err := New(Config{AbortStatus: http.StatusServiceUnavailable, Percent: 50})
+	require.NoError(t, err)
+	p.roll = func() float64 { return 50 }
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(withOriginalDst(r.Context(), ln.Addr().String()))
+	w := serve(p, r)
+	assert.Equal(t, http.StatusOK, w.Code, "requests without the fault reach the service")
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestConnContext_NotRedirected(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		if c, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+			defer c.Close()
+		}
+	}()
+	c, err := ln.Accept()
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := connContext(context.Background(), c)
+	assert.Nil(t, ctx.Value(originalDstKey{}), "a connection made to the proxy itself keeps Upstream")
+}
+
+func TestProxy_AbortMatchingRequest(t *testing.T) {
+	p := newTestProxy(t, Config{Path: "/checkout", AbortStatus: http.StatusServiceUnavailable})
+
+	w := serve(p, httptest.NewRequest(http.MethodGet, "/checkout", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), faultMessage)
+
+	w = serve(p, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestProxy_Percent(t *testing.T) {
+	p := newTestProxy(t, Config{AbortStatus: http.StatusBadGateway, Percent: 30})
+	p.roll = func() float64 { return 29.9 }
+	assert.Equal(t, http.StatusBadGateway, serve(p, httptest.NewRequest(http.MethodGet, "/", nil)).Code)
+	p.roll = func() float64 { return 30 }
+	assert.Equal(t, http.StatusOK, serve(p, httptest.NewRequest(http.MethodGet, "/", nil)).Code)
+}
+
+func TestProxy_DelayThenProxy(t *testing.T) {
+	p := newTestProxy(t, Config{Delay: 20 * time.Millisecond})
+	start := time.Now()
+	w := serve(p, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestProxy_DelayAbortedByClient(t *testing.T) {
+	p := newTestProxy(t, Config{Delay: time.Hour, AbortStatus: http.StatusServiceUnavailable})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := serve(p, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestProxy_GRPCAbort(t *testing.T) {
+	p := newTestProxy(t, Config{GRPCStatus: 14})
+	r := httptest.NewRequest(http.MethodPost, "/pkg.Service/Method", nil)
+	r.Header.Set("Content-Type", "application/grpc+proto")
+	w := serve(p, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "14", w.Header().Get("Grpc-Status"))
+	assert.Equal(t, grpcContentType, w.Header().Get("Content-Type"))
+
+	// plain HTTP requests are not affected by a gRPC-only fault
+	w = serve(p, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestListenAndServe_HTTP1AndH2C(t *testing.T) {
+	p := newTestProxy(t, Config{Path: "/fail", AbortStatus: http.StatusTeapot})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p.cfg.Listen = ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.ListenAndServe(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	h2c := &http.Transport{Protocols: new(http.Protocols)}
+	h2c.Protocols.SetUnencryptedHTTP2(true)
+	for name, client := range map[string]*http.Client{"http1": http.DefaultClient, "h2c": {Transport: h2c}} {
+		t.Run(name, func(t *testing.T) {
+			var resp *http.Response
+			require.Eventually(t, func() bool {
+				resp, err = client.Get("http://" + p.cfg.Listen + "/ok")
+				return err == nil
+			}, 5*time.Second, 20*time.Millisecond)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, resp.Proto, resp.Header.Get("X-Proto"), "backend sees the client protocol")
+
+			resp, err = client.Get("http://" + p.cfg.Listen + "/fail")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package httpproxy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// originalDst returns the destination of an IPv4 connection before an
+// iptables REDIRECT sent it to the proxy, as recorded by conntrack.
+func originalDst(c net.Conn) (*net.TCPAddr, error) {
+	tc, ok := c.(*net.TCPConn)
+	if !ok {
+		return nil, errors.New("not a TCP connection")
+	}
+	raw, err := tc.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	var mreq *unix.IPv6Mreq
+	var optErr error
+	err = raw.Control(func(fd uintptr) {
+		// SO_ORIGINAL_DST fills a sockaddr_in, which fits in an IPv6Mreq
+		mreq, optErr = unix.GetsockoptIPv6Mreq(int(fd), unix.SOL_IP, unix.SO_ORIGINAL_DST)
+	})
+	if err == nil {
+		err = optErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get original destination: %w", err)
+	}
+	addr := mreq.Multiaddr
+	return &net.TCPAddr{
+		IP:   net.IPv4(addr[4], addr[5], addr[6], addr[7]),
+		Port: int(addr[2])<<8 | int(addr[3]),
+	}, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package httpproxy
+
+import (
+	"errors"
+	"net"
+)
+
+// originalDst is only known on Linux, where the proxy runs.
+func originalDst(net.Conn) (*net.TCPAddr, error) {
+	return nil, errors.New("original destination is only supported on linux")
+}
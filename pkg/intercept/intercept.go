@@ -0,0 +1,440 @@
+// Package intercept wraps a runtime client so every call it makes goes
+// through an Interceptor, described as a Call. The tracing, audit, plan and
+// report clients are Interceptors: a runtime call is described once, here,
+// rather than once per wrapper.
+package intercept
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+)
+
+// Call describes a runtime call.
+type Call struct {
+	// Action names the call, e.g. kill, netem or netem-status.
+	Action string
+	// Container is the target of the call; nil for listings and sidecars.
+	Container *container.Container
+	// Read marks the calls reading state rather than changing it.
+	Read bool
+	// Cleanup marks the calls reverting an earlier change.
+	Cleanup bool
+	DryRun  bool
+	// Tool runs Commands, in a sidecar of Image or, without an image, inside
+	// the container; without a tool the commands run themselves.
+	Tool     string
+	Image    string
+	Commands [][]string
+	Duration time.Duration
+	Params   map[string]string
+	// Request is the request of the call, for calls taking one.
+	Request any
+	// Result is what the call returned: an *container.ExecResult, a
+	// *container.StressResult, or the output of a status read or link change.
+	// An Interceptor not running the call may set it.
+	Result any
+}
+
+// Fields returns the params of the call with its commands, under the name
+// of their tool, its duration and what it returned: the exit code of a
+// command, or the stress-ng sidecar. It returns nil without any.
+func (c *Call) Fields() map[string]string {
+	fields := maps.Clone(c.Params)
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	if len(c.Commands) > 0 {
+		fields[cmp.Or(c.Tool, "command")] = Join(c.Commands)
+	}
+	if c.Duration != 0 {
+		fields["duration"] = c.Duration.String()
+	}
+	switch result := c.Result.(type) {
+	case *container.ExecResult:
+		fields["exit-code"] = strconv.Itoa(result.ExitCode)
+	case *container.StressResult:
+		fields["sidecar"] = result.SidecarID
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// Interceptor sees every call of a Client; next makes the call. An
+// Interceptor not calling next leaves the call unmade.
+type Interceptor interface {
+	Intercept(ctx context.Context, call *Call, next func(context.Context) error) error
+}
+
+// TargetsInterceptor is an Interceptor learning the containers a command
+// selected as its targets.
+type TargetsInterceptor interface {
+	Targets(ctx context.Context, containers []*container.Container) error
+}
+
+// Client is a container.Client passing every runtime call through its
+// Interceptor.
+//
+// Embedding invariant: when adding a method to container.Client, override it
+// here, or tracing, audit, plan and report will not see its calls.
+type Client struct {
+	container.Client
+	in Interceptor
+}
+
+// New returns a Client passing the calls to client through in.
+func New(client container.Client, in Interceptor) *Client {
+	return &Client{Client: client, in: in}
+}
+
+// Unwrap returns the wrapped runtime client.
+func (c *Client) Unwrap() container.Client {
+	return c.Client
+}
+
+// Targets tells the TargetsInterceptors among client and the clients it
+// wraps the containers a command selected.
+func Targets(ctx context.Context, client container.Lister, containers []*container.Container) error {
+	for client != nil {
+		if c, ok := client.(*Client); ok {
+			if in, ok := c.in.(TargetsInterceptor); ok {
+				if err := in.Targets(ctx, containers); err != nil {
+					return err
+				}
+			}
+		}
+		w, ok := client.(interface{ Unwrap() container.Client })
+		if !ok {
+			return nil
+		}
+		client = w.Unwrap()
+	}
+	return nil
+}
+
+// Name returns the name of c without the leading slash of Docker.
+func Name(c *container.Container) string {
+	return strings.TrimPrefix(c.Name(), "/")
+}
+
+// Join joins commands into a line, separated by semicolons.
+func Join(cmds [][]string) string {
+	lines := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		lines = append(lines, strings.Join(cmd, " "))
+	}
+	return strings.Join(lines, "; ")
+}
+
+func (c *Client) intercept(ctx context.Context, call *Call, next func(context.Context) error) error {
+	return c.in.Intercept(ctx, call, next)
+}
+
+// ListContainers lists containers.
+func (c *Client) ListContainers(ctx context.Context, fn container.FilterFunc, opts container.ListOpts) ([]*container.Container, error) {
+	var containers []*container.Container
+	call := &Call{Action: "list", Read: true, Params: map[string]string{"all": strconv.FormatBool(opts.All)}}
+	err := c.intercept(ctx, call, func(ctx context.Context) error {
+		var err error
+		containers, err = c.Client.ListContainers(ctx, fn, opts)
+		return err
+	})
+	return containers, err
+}
+
+// StopContainer stops ctr.
+func (c *Client) StopContainer(ctx context.Context, ctr *container.Container, timeout int, dryrun bool) error {
+	call := &Call{Action: "stop", Container: ctr, DryRun: dryrun, Params: map[string]string{"timeout": strconv.Itoa(timeout) + "s"}}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.StopContainer(ctx, ctr, timeout, dryrun)
+	})
+}
+
+// KillContainer kills ctr.
+func (c *Client) KillContainer(ctx context.Context, ctr *container.Container, signal string, dryrun bool) error {
+	call := &Call{Action: "kill", Container: ctr, DryRun: dryrun, Params: map[string]string{"signal": signal}}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.KillContainer(ctx, ctr, signal, dryrun)
+	})
+}
+
+// SignalProcesses signals processes of req.Container, a cleanup when it
+// resumes stopped ones.
+func (c *Client) SignalProcesses(ctx context.Context, req *container.SignalRequest) (pids []int, err error) {
+	call := &Call{
+		Action: "signal", Container: req.Container, Cleanup: req.Signal == "SIGCONT", DryRun: req.DryRun,
+		Tool: "sh", Image: req.Sidecar.Image, Params: map[string]string{"signal": req.Signal, "process": req.Process}, Request: req,
+	}
+	err = c.intercept(ctx, call, func(ctx context.Context) error {
+		pids, err = c.Client.SignalProcesses(ctx, req)
+		return err
+	})
+	return pids, err
+}
+
+// StartContainer starts ctr.
+func (c *Client) StartContainer(ctx context.Context, ctr *container.Container, dryrun bool) error {
+	return c.intercept(ctx, &Call{Action: "start", Container: ctr, DryRun: dryrun}, func(ctx context.Context) error {
+		return c.Client.StartContainer(ctx, ctr, dryrun)
+	})
+}
+
+// RestartContainer restarts ctr.
+func (c *Client) RestartContainer(ctx context.Context, ctr *container.Container, timeout time.Duration, dryrun bool) error {
+	call := &Call{Action: "restart", Container: ctr, DryRun: dryrun, Params: map[string]string{"timeout": timeout.String()}}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.RestartContainer(ctx, ctr, timeout, dryrun)
+	})
+}
+
+// RemoveContainer removes ctr.
+func (c *Client) RemoveContainer(ctx context.Context, ctr *container.Container, opts container.RemoveOpts) error {
+	call := &Call{Action: "remove", Container: ctr, DryRun: opts.DryRun, Params: map[string]string{
+		"force":   strconv.FormatBool(opts.Force),
+		"links":   strconv.FormatBool(opts.Links),
+		"volumes": strconv.FormatBool(opts.Volumes),
+	}}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.RemoveContainer(ctx, ctr, opts)
+	})
+}
+
+// PauseContainer pauses ctr.
+func (c *Client) PauseContainer(ctx context.Context, ctr *container.Container, dryrun bool) error {
+	return c.intercept(ctx, &Call{Action: "pause", Container: ctr, DryRun: dryrun}, func(ctx context.Context) error {
+		return c.Client.PauseContainer(ctx, ctr, dryrun)
+	})
+}
+
+// UnpauseContainer unpauses ctr, a cleanup.
+func (c *Client) UnpauseContainer(ctx context.Context, ctr *container.Container, dryrun bool) error {
+	return c.intercept(ctx, &Call{Action: "unpause", Container: ctr, Cleanup: true, DryRun: dryrun}, func(ctx context.Context) error {
+		return c.Client.UnpauseContainer(ctx, ctr, dryrun)
+	})
+}
+
+// StopContainerWithID stops a sidecar, a cleanup.
+func (c *Client) StopContainerWithID(ctx context.Context, id string, timeout time.Duration, dryrun bool) error {
+	call := &Call{Action: "stop-sidecar", Cleanup: true, DryRun: dryrun, Params: map[string]string{"sidecar": id, "timeout": timeout.String()}}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.StopContainerWithID(ctx, id, timeout, dryrun)
+	})
+}
+
+// ExecContainer runs a command in ctr.
+func (c *Client) ExecContainer(ctx context.Context, ctr *container.Container, command string, args []string, dryrun bool) (result *container.ExecResult, err error) {
+	call := &Call{Action: "exec", Container: ctr, DryRun: dryrun, Commands: [][]string{append([]string{command}, args...)}}
+	err = c.intercept(ctx, call, func(ctx context.Context) error {
+		result, err = c.Client.ExecContainer(ctx, ctr, command, args, dryrun)
+		if result != nil {
+			call.Result = result
+		}
+		return err
+	})
+	return result, err
+}
+
+// CopyToContainer writes a file into req.Container.
+func (c *Client) CopyToContainer(ctx context.Context, req *container.CopyRequest) error {
+	call := &Call{
+		Action: "copy", Container: req.Container, DryRun: req.DryRun,
+		Params: map[string]string{"path": req.Path, "size": strconv.Itoa(len(req.Content))}, Request: req,
+	}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.CopyToContainer(ctx, req)
+	})
+}
+
+// NetemContainer adds a netem qdisc.
+func (c *Client) NetemContainer(ctx context.Context, req *container.NetemRequest) error {
+	call := &Call{
+		Action: "netem", Container: req.Container, DryRun: req.DryRun,
+		Tool: "tc", Image: req.Sidecar.Image, Commands: req.TCCommands(), Duration: req.Duration, Request: req,
+	}
+	call.Params = capture(call.Params, req.Container, req.Capture)
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.NetemContainer(ctx, req)
+	})
+}
+
+// StopNetemContainer removes a netem qdisc, a cleanup.
+func (c *Client) StopNetemContainer(ctx context.Context, req *container.NetemRequest) error {
+	call := &Call{
+		Action: "netem", Container: req.Container, Cleanup: true, DryRun: req.DryRun,
+		Tool: "tc", Image: req.Sidecar.Image, Commands: req.StopTCCommands(), Request: req,
+	}
+	call.Params = capture(call.Params, req.Container, req.Capture)
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.StopNetemContainer(ctx, req)
+	})
+}
+
+// NetemStatus reads the qdiscs of the target.
+func (c *Client) NetemStatus(ctx context.Context, req *container.NetemRequest) (string, error) {
+	var out string
+	call := &Call{Action: "netem-status", Container: req.Container, Read: true, Tool: "tc", Image: req.Sidecar.Image, Request: req}
+	err := c.intercept(ctx, call, func(ctx context.Context) error {
+		var err error
+		out, err = c.Client.NetemStatus(ctx, req)
+		call.Result = out
+		return err
+	})
+	return out, err
+}
+
+func filterCall(action string, req *container.IPTablesRequest) *Call {
+	call := &Call{
+		Action: action, Container: req.Container, DryRun: req.DryRun,
+		Tool: "iptables", Image: req.Sidecar.Image, Commands: req.Commands(), Request: req,
+	}
+	if req.Backend != "" {
+		call.Params = map[string]string{"backend": req.Backend}
+	}
+	return call
+}
+
+// capture adds the packet capture of spec on c, if any, to params.
+func capture(params map[string]string, c *container.Container, spec *container.CaptureSpec) map[string]string {
+	if spec == nil {
+		return params
+	}
+	if params == nil {
+		params = map[string]string{}
+	}
+	params["capture"] = spec.Dir
+	params["capture-sidecar"] = spec.SidecarName(c)
+	params["capture-image"] = spec.Image
+	params["capture-interface"] = spec.Interface
+	if spec.Filter != "" {
+		params["capture-filter"] = spec.Filter
+	}
+	if spec.FileSize > 0 {
+		params["capture-size"] = strconv.Itoa(spec.FileSize)
+	}
+	if spec.FileCount > 0 {
+		params["capture-files"] = strconv.Itoa(spec.FileCount)
+	}
+	return params
+}
+
+// IPTablesContainer adds packet filter rules.
+func (c *Client) IPTablesContainer(ctx context.Context, req *container.IPTablesRequest) error {
+	call := filterCall("iptables", req)
+	call.Duration = req.Duration
+	call.Params = capture(call.Params, req.Container, req.Capture)
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.IPTablesContainer(ctx, req)
+	})
+}
+
+// StopIPTablesContainer removes packet filter rules, a cleanup.
+func (c *Client) StopIPTablesContainer(ctx context.Context, req *container.IPTablesRequest) error {
+	call := filterCall("iptables", req)
+	call.Cleanup = true
+	call.Params = capture(call.Params, req.Container, req.Capture)
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.StopIPTablesContainer(ctx, req)
+	})
+}
+
+// IPTablesStatus reads the packet filter state of the target.
+func (c *Client) IPTablesStatus(ctx context.Context, req *container.IPTablesRequest) (string, error) {
+	var out string
+	call := filterCall("iptables-status", req)
+	call.Read, call.DryRun, call.Commands = true, false, nil
+	err := c.intercept(ctx, call, func(ctx context.Context) error {
+		var err error
+		out, err = c.Client.IPTablesStatus(ctx, req)
+		call.Result = out
+		return err
+	})
+	return out, err
+}
+
+// StressContainer starts a stress-ng sidecar.
+func (c *Client) StressContainer(ctx context.Context, req *container.StressRequest) (*container.StressResult, error) {
+	var result *container.StressResult
+	call := &Call{
+		Action: "stress", Container: req.Container, DryRun: req.DryRun,
+		Tool: "stress-ng", Image: req.Sidecar.Image, Commands: [][]string{req.Stressors}, Duration: req.Duration, Request: req,
+	}
+	if req.InjectCgroup {
+		call.Params = map[string]string{"inject-cgroup": "true"}
+	}
+	err := c.intercept(ctx, call, func(ctx context.Context) error {
+		var err error
+		result, err = c.Client.StressContainer(ctx, req)
+		if result != nil {
+			call.Result = result
+		}
+		return err
+	})
+	if r, ok := call.Result.(*container.StressResult); ok {
+		result = r
+	}
+	return result, err
+}
+
+// ProxyContainer starts a proxy sidecar.
+func (c *Client) ProxyContainer(ctx context.Context, req *container.ProxyRequest) error {
+	call := &Call{
+		Action: req.Name, Container: req.Container, DryRun: req.DryRun, Image: req.Proxy.Image,
+		Commands: [][]string{req.Command}, Params: map[string]string{"sidecar": req.SidecarName()}, Request: req,
+	}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.ProxyContainer(ctx, req)
+	})
+}
+
+// StopProxyContainer removes a proxy sidecar, a cleanup.
+func (c *Client) StopProxyContainer(ctx context.Context, req *container.ProxyRequest) error {
+	call := &Call{
+		Action: req.Name, Container: req.Container, Cleanup: true, DryRun: req.DryRun, Image: req.Proxy.Image,
+		Params: map[string]string{"sidecar": req.SidecarName()}, Request: req,
+	}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.StopProxyContainer(ctx, req)
+	})
+}
+
+// LinkContainer changes an interface of the target.
+func (c *Client) LinkContainer(ctx context.Context, req *container.LinkRequest) (string, error) {
+	var out string
+	call := &Call{
+		Action: "link", Container: req.Container, DryRun: req.DryRun,
+		Tool: "ip", Image: req.Sidecar.Image, Commands: req.Commands, Request: req,
+	}
+	err := c.intercept(ctx, call, func(ctx context.Context) error {
+		var err error
+		out, err = c.Client.LinkContainer(ctx, req)
+		call.Result = out
+		return err
+	})
+	return out, err
+}
+
+// DisconnectNetwork disconnects the target from a network.
+func (c *Client) DisconnectNetwork(ctx context.Context, req *container.NetworkRequest) error {
+	call := &Call{Action: "disconnect", Container: req.Container, DryRun: req.DryRun, Params: map[string]string{"network": req.Network}, Request: req}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.DisconnectNetwork(ctx, req)
+	})
+}
+
+// ConnectNetwork reconnects the target to a network, a cleanup.
+func (c *Client) ConnectNetwork(ctx context.Context, req *container.NetworkRequest) error {
+	call := &Call{
+		Action: "connect", Container: req.Container, Cleanup: true, DryRun: req.DryRun,
+		Params: map[string]string{"network": req.Network}, Request: req,
+	}
+	return c.intercept(ctx, call, func(ctx context.Context) error {
+		return c.Client.ConnectNetwork(ctx, req)
+	})
+}
@@ -0,0 +1,93 @@
+package intercept
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the calls it sees and the targets it learns; with skip
+// it does not make the calls.
+type recorder struct {
+	calls   []*Call
+	targets []*container.Container
+	skip    bool
+	err     error
+}
+
+func (r *recorder) Intercept(ctx context.Context, call *Call, next func(context.Context) error) error {
+	r.calls = append(r.calls, call)
+	if r.skip {
+		return nil
+	}
+	return next(ctx)
+}
+
+func (r *recorder) Targets(_ context.Context, containers []*container.Container) error {
+	r.targets = append(r.targets, containers...)
+	return r.err
+}
+
+func TestClient_Calls(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().ExecContainer(mock.Anything, web, "curl", []string{"-s", "db"}, false).
+		Return(&container.ExecResult{ExitCode: 7}, nil).Once()
+	client.EXPECT().StopNetemContainer(mock.Anything, mock.Anything).Return(nil).Once()
+
+	r := &recorder{}
+	c := New(client, r)
+	_, err := c.ExecContainer(context.TODO(), web, "curl", []string{"-s", "db"}, false)
+	require.NoError(t, err)
+	require.NoError(t, c.StopNetemContainer(context.TODO(), &container.NetemRequest{
+		Container: web, Interface: "eth0", Sidecar: container.SidecarSpec{Image: "nettools"},
+	}))
+
+	require.Len(t, r.calls, 2)
+	assert.Equal(t, "exec", r.calls[0].Action)
+	assert.Same(t, web, r.calls[0].Container)
+	assert.Equal(t, map[string]string{"command": "curl -s db", "exit-code": "7"}, r.calls[0].Fields())
+	assert.Equal(t, "netem", r.calls[1].Action)
+	assert.True(t, r.calls[1].Cleanup)
+	assert.Equal(t, "nettools", r.calls[1].Image)
+	assert.Equal(t, map[string]string{"tc": "qdisc del dev eth0 root netem"}, r.calls[1].Fields())
+}
+
+func TestClient_ResultOfSkippedCall(t *testing.T) {
+	r := &recorder{skip: true}
+	c := New(container.NewMockClient(t), r)
+
+	result, err := c.StressContainer(context.TODO(), &container.StressRequest{Stressors: []string{"--cpu", "1"}, Duration: time.Minute})
+	require.NoError(t, err)
+	assert.Nil(t, result, "a call not made returns nothing")
+	assert.Equal(t, map[string]string{"stress-ng": "--cpu 1", "duration": "1m0s"}, r.calls[0].Fields())
+}
+
+func TestCall_FieldsEmpty(t *testing.T) {
+	assert.Nil(t, (&Call{Action: "pause"}).Fields())
+}
+
+// wrapper is a runtime client wrapping another one without intercepting it.
+type wrapper struct{ container.Client }
+
+func (w wrapper) Unwrap() container.Client { return w.Client }
+
+func TestTargets(t *testing.T) {
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	inner, outer := &recorder{}, &recorder{}
+	client := New(wrapper{New(container.NewMockClient(t), inner)}, outer)
+
+	require.NoError(t, Targets(context.TODO(), client, []*container.Container{web}))
+	assert.Equal(t, []*container.Container{web}, inner.targets, "every interceptor of the chain learns the targets")
+	assert.Equal(t, []*container.Container{web}, outer.targets)
+	assert.Empty(t, outer.calls, "selecting targets is no runtime call")
+
+	inner.err = errors.New("audit log full")
+	require.EqualError(t, Targets(context.TODO(), client, []*container.Container{web}), "audit log full")
+}
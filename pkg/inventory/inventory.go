@@ -0,0 +1,292 @@
+// Package inventory keeps an in-memory view of the runtime's containers, so
+// recurring chaos does not list every container on every run. The view is
+// bootstrapped by one listing and then follows the runtime's event stream;
+// its changes can be subscribed to, to react to containers that appear or die
+// between runs.
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// resyncDelay is the wait before following the event stream again after it
+// failed; until then listings go to the runtime.
+const resyncDelay = 5 * time.Second
+
+// subscriberBuffer is the number of changes a subscriber may lag behind.
+const subscriberBuffer = 64
+
+// Change is an event applied to the inventory.
+type Change struct {
+	container.Event
+	// Container is the container after the event; nil once destroyed.
+	Container *container.Container
+}
+
+// Inventory is a container.Client listing containers from memory while its
+// view is in sync with the runtime, and from the runtime otherwise.
+//
+// Embedding invariant: only ListContainers and Close are overridden; every
+// other call goes to the runtime, whose changes come back as events.
+type Inventory struct {
+	container.Client
+	watcher container.Watcher
+	delay   time.Duration
+
+	mu         sync.RWMutex
+	containers map[string]*container.Container
+	synced     bool
+	subs       map[chan Change]struct{}
+
+	// stopWatch ends the current event stream; only sync and follow use it.
+	stopWatch context.CancelFunc
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// New returns an Inventory of the containers of client, following the events
+// of watcher, usually the same runtime client.
+func New(client container.Client, watcher container.Watcher) *Inventory {
+	return &Inventory{
+		Client:     client,
+		watcher:    watcher,
+		delay:      resyncDelay,
+		containers: make(map[string]*container.Container),
+		subs:       make(map[chan Change]struct{}),
+	}
+}
+
+// Start bootstraps the inventory and follows the event stream until ctx is
+// done or Close is called. A failed bootstrap is returned; later stream
+// failures are logged and retried.
+func (i *Inventory) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	events, errs, err := i.sync(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	i.cancel, i.done = cancel, make(chan struct{})
+	go i.follow(ctx, events, errs)
+	return nil
+}
+
+// sync subscribes to the event stream, then lists every container, so no
+// change between the two is missed.
+func (i *Inventory) sync(ctx context.Context) (<-chan container.Event, <-chan error, error) {
+	if i.stopWatch != nil {
+		i.stopWatch()
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	i.stopWatch = cancel
+	events, errs := i.watcher.WatchContainers(watchCtx)
+	containers, err := i.Client.ListContainers(ctx, all, container.ListOpts{All: true})
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("failed to bootstrap container inventory: %w", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	clear(i.containers)
+	for _, c := range containers {
+		i.containers[c.ID()] = c
+	}
+	i.synced = true
+	log.WithField("containers", len(containers)).Debug("container inventory in sync")
+	return events, errs, nil
+}
+
+func all(*container.Container) bool { return true }
+
+// follow applies events until ctx is done, syncing again after a stream
+// failure.
+func (i *Inventory) follow(ctx context.Context, events <-chan container.Event, errs <-chan error) {
+	defer close(i.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if ok {
+				i.apply(ctx, e)
+				continue
+			}
+			err := errors.New("event stream closed")
+			select {
+			case err = <-errs:
+			default:
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			i.setSynced(false)
+			log.WithError(err).Warn("lost the container event stream, listing containers from the runtime until it resumes")
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(i.delay):
+				}
+				var syncErr error
+				if events, errs, syncErr = i.sync(ctx); syncErr == nil {
+					break
+				}
+				log.WithError(syncErr).Warn("failed to resync container inventory")
+			}
+		}
+	}
+}
+
+func (i *Inventory) setSynced(synced bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.synced = synced
+}
+
+// apply updates the inventory with e and notifies subscribers. State changes
+// of known containers need no runtime call; new and updated containers are
+// listed from the runtime.
+func (i *Inventory) apply(ctx context.Context, e container.Event) {
+	var c *container.Container
+	switch e.Kind {
+	case container.EventDestroy:
+	case container.EventDie, container.EventPause, container.EventUnpause, container.EventStart,
+		container.EventHealthy:
+		c = i.withState(e)
+	}
+	if c == nil && e.Kind != container.EventDestroy {
+		var err error
+		if c, err = i.inspect(ctx, e.ContainerID); err != nil {
+			// keep what is known; the next event or resync corrects it
+			log.WithError(err).WithField("id", e.ContainerID).Warn("failed to inspect changed container")
+			return
+		}
+	}
+	log.WithFields(log.Fields{"event": e.Kind, "id": e.ContainerID}).Debug("container inventory changed")
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if c == nil {
+		delete(i.containers, e.ContainerID)
+	} else {
+		i.containers[e.ContainerID] = c
+	}
+	for ch := range i.subs {
+		select {
+		case ch <- Change{Event: e, Container: c}:
+		default:
+			log.WithFields(log.Fields{"event": e.Kind, "id": e.ContainerID}).Warn("container inventory subscriber lagging, dropping change")
+		}
+	}
+}
+
+// withState returns a copy of the known container of e in the state e leads
+// to, or nil when the container is unknown. Containers are never changed in
+// place: callers may still hold them.
+func (i *Inventory) withState(e container.Event) *container.Container {
+	i.mu.RLock()
+	known, ok := i.containers[e.ContainerID]
+	i.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	c := *known
+	switch e.Kind {
+	case container.EventDie:
+		c.State = container.StateExited
+	case container.EventPause:
+		c.State = container.StatePaused
+	case container.EventStart:
+		c.State = container.StateRunning
+		if !e.Time.IsZero() {
+			c.Started = e.Time
+		}
+	default:
+		c.State = container.StateRunning
+	}
+	return &c
+}
+
+// inspect lists the container id from the runtime, nil when it is gone.
+func (i *Inventory) inspect(ctx context.Context, id string) (*container.Container, error) {
+	containers, err := i.Client.ListContainers(ctx, func(c *container.Container) bool { return c.ID() == id }, container.ListOpts{All: true})
+	if err != nil || len(containers) == 0 {
+		return nil, err
+	}
+	return containers[0], nil
+}
+
+// ListContainers returns the containers of the inventory accepted by fn:
+// running and paused ones, or all with opts.All, carrying every label of
+// opts.Labels, "key" or "key=value". Out of sync, it lists from the runtime.
+func (i *Inventory) ListContainers(ctx context.Context, fn container.FilterFunc, opts container.ListOpts) ([]*container.Container, error) {
+	i.mu.RLock()
+	if !i.synced {
+		i.mu.RUnlock()
+		return i.Client.ListContainers(ctx, fn, opts)
+	}
+	var cs []*container.Container
+	for _, c := range i.containers {
+		if (opts.All || c.State != container.StateExited) && c.HasLabels(opts.Labels) && fn(c) {
+			cs = append(cs, c)
+		}
+	}
+	i.mu.RUnlock()
+	// newest first, like the runtimes list them
+	slices.SortFunc(cs, func(a, b *container.Container) int {
+		if n := b.Created.Compare(a.Created); n != 0 {
+			return n
+		}
+		return strings.Compare(a.ID(), b.ID())
+	})
+	return cs, nil
+}
+
+// Subscribe returns the changes applied to the inventory until ctx is done.
+// Changes are dropped for a subscriber lagging behind.
+func (i *Inventory) Subscribe(ctx context.Context) <-chan Change {
+	ch := make(chan Change, subscriberBuffer)
+	i.mu.Lock()
+	i.subs[ch] = struct{}{}
+	i.mu.Unlock()
+	context.AfterFunc(ctx, func() {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		delete(i.subs, ch)
+		close(ch)
+	})
+	return ch
+}
+
+// Find returns the inventory client is or wraps: a client wrapping another
+// one returns it from Unwrap.
+func Find(client container.Client) (*Inventory, bool) {
+	for client != nil {
+		if inv, ok := client.(*Inventory); ok {
+			return inv, true
+		}
+		w, ok := client.(interface{ Unwrap() container.Client })
+		if !ok {
+			break
+		}
+		client = w.Unwrap()
+	}
+	return nil, false
+}
+
+// Close stops following the event stream and closes the runtime client.
+func (i *Inventory) Close() error {
+	if i.cancel != nil {
+		i.cancel()
+		<-i.done
+	}
+	return i.Client.Close()
+}
@@ -0,0 +1,210 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// watcher is an event stream fed by the test.
+type watcher struct {
+	streams chan chan container.Event
+	errs    chan error
+}
+
+func newWatcher() *watcher {
+	return &watcher{streams: make(chan chan container.Event, 2), errs: make(chan error, 1)}
+}
+
+// stream returns the event channel of the next WatchContainers call.
+func (w *watcher) stream() chan container.Event {
+	ch := make(chan container.Event)
+	w.streams <- ch
+	return ch
+}
+
+func (w *watcher) WatchContainers(context.Context) (<-chan container.Event, <-chan error) {
+	return <-w.streams, w.errs
+}
+
+var (
+	web = &container.Container{ContainerID: "w1", ContainerName: "/web", State: container.StateRunning,
+		Labels: map[string]string{"app": "web", "tier": "front"}, Created: time.Unix(2, 0)}
+	db = &container.Container{ContainerID: "d1", ContainerName: "/db", State: container.StateRunning,
+		Labels: map[string]string{"app": "db"}, Created: time.Unix(1, 0)}
+	old = &container.Container{ContainerID: "o1", ContainerName: "/old", State: container.StateExited,
+		Labels: map[string]string{"app": "web"}}
+)
+
+func ids(cs []*container.Container) []string {
+	var out []string
+	for _, c := range cs {
+		out = append(out, c.ID())
+	}
+	return out
+}
+
+func start(t *testing.T, client container.Client, w *watcher) *Inventory {
+	t.Helper()
+	inv := New(client, w)
+	inv.delay = time.Millisecond
+	require.NoError(t, inv.Start(context.TODO()))
+	t.Cleanup(func() {
+		inv.cancel()
+		<-inv.done
+	})
+	return inv
+}
+
+func TestInventory_ListsFromMemory(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		Return([]*container.Container{old, db, web}, nil).Once()
+	w := newWatcher()
+	w.stream()
+	inv := start(t, client, w)
+
+	all := func(*container.Container) bool { return true }
+	cs, err := inv.ListContainers(context.TODO(), all, container.ListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1", "d1"}, ids(cs), "running containers, newest first")
+
+	cs, err = inv.ListContainers(context.TODO(), all, container.ListOpts{All: true, Labels: []string{"app=web"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1", "o1"}, ids(cs))
+
+	cs, err = inv.ListContainers(context.TODO(), func(c *container.Container) bool { return c.Name() == "/db" }, container.ListOpts{Labels: []string{"app"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d1"}, ids(cs))
+}
+
+func TestInventory_FollowsEvents(t *testing.T) {
+	api := &container.Container{ContainerID: "a1", ContainerName: "/api", State: container.StateRunning, Created: time.Unix(3, 0)}
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		Return([]*container.Container{db, web}, nil).Once()
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		RunAndReturn(func(_ context.Context, fn container.FilterFunc, _ container.ListOpts) ([]*container.Container, error) {
+			assert.True(t, fn(api), "a new container is inspected by ID")
+			assert.False(t, fn(web))
+			return []*container.Container{api}, nil
+		}).Once()
+	w := newWatcher()
+	events := w.stream()
+	inv := start(t, client, w)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	changes := inv.Subscribe(ctx)
+
+	events <- container.Event{Kind: container.EventDie, ContainerID: "w1"}
+	change := <-changes
+	assert.Equal(t, container.EventDie, change.Kind)
+	assert.Equal(t, container.StateExited, change.Container.State)
+	assert.Equal(t, container.StateRunning, web.State, "listed containers are not changed")
+
+	events <- container.Event{Kind: container.EventStart, ContainerID: "w1", Time: time.Unix(4, 0)}
+	change = <-changes
+	assert.Equal(t, container.StateRunning, change.Container.State)
+	assert.Equal(t, time.Unix(4, 0), change.Container.Started, "a restart is known from its event")
+
+	events <- container.Event{Kind: container.EventCreate, ContainerID: "a1"}
+	assert.Same(t, api, (<-changes).Container)
+
+	events <- container.Event{Kind: container.EventDestroy, ContainerID: "d1"}
+	assert.Nil(t, (<-changes).Container)
+
+	cs, err := inv.ListContainers(context.TODO(), func(*container.Container) bool { return true }, container.ListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1", "w1"}, ids(cs))
+}
+
+func TestInventory_ResyncsAfterStreamFailure(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		Return([]*container.Container{web}, nil).Once()
+	w := newWatcher()
+	events := w.stream()
+	inv := start(t, client, w)
+
+	// out of sync, listings go to the runtime until the resync
+	resynced := make(chan struct{})
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{All: true}).
+		RunAndReturn(func(context.Context, container.FilterFunc, container.ListOpts) ([]*container.Container, error) {
+			close(resynced)
+			return []*container.Container{db}, nil
+		}).Once()
+	w.errs <- errors.New("daemon restarting")
+	close(events)
+	next := w.stream()
+	<-resynced
+	require.Eventually(t, func() bool {
+		inv.mu.RLock()
+		defer inv.mu.RUnlock()
+		return inv.synced
+	}, time.Second, time.Millisecond)
+
+	cs, err := inv.ListContainers(context.TODO(), func(*container.Container) bool { return true }, container.ListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d1"}, ids(cs))
+	close(next)
+}
+
+func TestInventory_ListsFromRuntimeOutOfSync(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, container.ListOpts{Labels: []string{"app=web"}}).
+		Return([]*container.Container{web}, nil).Once()
+
+	cs, err := New(client, newWatcher()).ListContainers(context.TODO(), func(*container.Container) bool { return true },
+		container.ListOpts{Labels: []string{"app=web"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1"}, ids(cs))
+}
+
+func TestInventory_BootstrapFailure(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("permission denied")).Once()
+	w := newWatcher()
+	w.stream()
+
+	err := New(client, w).Start(context.TODO())
+	require.EqualError(t, err, "failed to bootstrap container inventory: permission denied")
+}
+
+func TestInventory_Close(t *testing.T) {
+	client := container.NewMockClient(t)
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	client.EXPECT().Close().Return(nil).Once()
+	w := newWatcher()
+	w.stream()
+	inv := New(client, w)
+	require.NoError(t, inv.Start(context.TODO()))
+
+	require.NoError(t, inv.Close())
+	select {
+	case <-inv.done:
+	default:
+		t.Fatal("Close stops following events")
+	}
+}
+
+// wrapper is a runtime client wrapping another one, like the tracing client.
+type wrapper struct{ container.Client }
+
+func (w wrapper) Unwrap() container.Client { return w.Client }
+
+func TestFind(t *testing.T) {
+	client := container.NewMockClient(t)
+	inv := New(client, newWatcher())
+
+	got, ok := Find(wrapper{wrapper{inv}})
+	require.True(t, ok)
+	assert.Same(t, inv, got)
+	_, ok = Find(wrapper{client})
+	assert.False(t, ok, "no inventory without --trigger or --inventory")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/events"
 )
 
 // apiClient defines the subset of *containerd.Client methods used by containerdClient.
@@ -14,5 +15,7 @@ type apiClient interface {
 	GetImage(ctx context.Context, ref string) (containerd.Image, error)
 	Pull(ctx context.Context, ref string, opts ...containerd.RemoteOpt) (containerd.Image, error)
 	NewContainer(ctx context.Context, id string, opts ...containerd.NewContainerOpts) (containerd.Container, error)
+	Version(ctx context.Context) (containerd.Version, error)
+	Subscribe(ctx context.Context, filters ...string) (<-chan *events.Envelope, <-chan error)
 	Close() error
 }
@@ -0,0 +1,135 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// captureCapabilities are the Linux capabilities tcpdump needs.
+var captureCapabilities = []string{"CAP_NET_ADMIN", "CAP_NET_RAW"}
+
+// withCapture starts the packet capture (if any) and then runs apply. The
+// capture is removed again if apply fails.
+func (c *containerdClient) withCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec, apply func() error) error {
+	if capture == nil {
+		return apply()
+	}
+	if err := c.startCapture(c.nsCtx(ctx), target, capture); err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		if stopErr := c.stopCapture(ctx, target, capture); stopErr != nil {
+			log.WithError(stopErr).Warn("failed to stop packet capture")
+		}
+		return err
+	}
+	return nil
+}
+
+// stopWithCapture runs stop and then stops the packet capture (if any), so
+// the capture records the recovery. Both steps always run.
+func (c *containerdClient) stopWithCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec, stop func() error) error {
+	err := stop()
+	if capture == nil {
+		return err
+	}
+	return errors.Join(err, c.stopCapture(ctx, target, capture))
+}
+
+// startCapture runs tcpdump in a sidecar sharing the target's network
+// namespace, with capture.Dir bind-mounted for the pcap files.
+func (c *containerdClient) startCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec) error {
+	args := capture.Args(target, time.Now())
+	log.WithFields(log.Fields{"id": target.ID(), "dir": capture.Dir, "img": capture.Image, "args": args}).Debug("starting packet capture")
+	if capture.Pull {
+		if err := c.pullImage(ctx, capture.Image); err != nil {
+			return fmt.Errorf("failed to pull capture image %s: %w", capture.Image, err)
+		}
+	}
+	targetTask, err := c.getTask(ctx, target.ID())
+	if err != nil {
+		return fmt.Errorf("failed to get target task for capture sidecar: %w", err)
+	}
+	targetPID := targetTask.Pid()
+	if targetPID == 0 {
+		return fmt.Errorf("target task for %s has PID 0 (not running)", target.ID())
+	}
+	image, err := c.client.GetImage(ctx, capture.Image)
+	if err != nil {
+		return fmt.Errorf("failed to get capture image %s: %w", capture.Image, err)
+	}
+
+	sidecarID := capture.SidecarName(target)
+	sidecar, err := c.client.NewContainer(ctx, sidecarID,
+		containerd.WithImage(image),
+		containerd.WithContainerLabels(ctr.SidecarLabels(ctr.SidecarKindCapture, target.ID())),
+		containerd.WithNewSnapshot(sidecarID+"-snapshot", image),
+		containerd.WithNewSpec(
+			oci.WithImageConfig(image),
+			oci.WithProcessArgs(args...),
+			oci.WithLinuxNamespace(specs.LinuxNamespace{
+				Type: specs.NetworkNamespace,
+				Path: fmt.Sprintf("/proc/%d/ns/net", targetPID),
+			}),
+			oci.WithCapabilities(captureCapabilities),
+			oci.WithMounts([]specs.Mount{{
+				Type:        "bind",
+				Source:      capture.Dir,
+				Destination: ctr.CaptureMountPath,
+				Options:     []string{"rbind", "rw"},
+			}}),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create capture sidecar: %w", err)
+	}
+	task, err := sidecar.NewTask(ctx, cio.NullIO)
+	if err == nil {
+		err = task.Start(ctx)
+	}
+	if err != nil {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sidecarCleanupTimeout)
+		defer cancel()
+		if cleanupErr := c.cleanupSidecar(cleanupCtx, sidecar); cleanupErr != nil {
+			log.WithError(cleanupErr).Warn("failed to clean up capture sidecar")
+		}
+		return fmt.Errorf("failed to start capture sidecar task: %w", err)
+	}
+	return nil
+}
+
+// stopCapture sends SIGTERM so tcpdump flushes the last pcap, waits briefly
+// and removes the capture sidecar. A capture that is already gone is not an
+// error.
+func (c *containerdClient) stopCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec) error {
+	ctx = c.nsCtx(ctx)
+	sidecar, err := c.client.LoadContainer(ctx, capture.SidecarName(target))
+	if errdefs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load capture sidecar: %w", err)
+	}
+	if task, taskErr := sidecar.Task(ctx, nil); taskErr == nil {
+		if waitCh, waitErr := task.Wait(ctx); waitErr == nil && task.Kill(ctx, syscall.SIGTERM) == nil {
+			timer := time.NewTimer(sidecarKillTimeout)
+			defer timer.Stop()
+			select {
+			case <-waitCh:
+			case <-timer.C:
+			}
+		}
+	}
+	return c.cleanupSidecar(ctx, sidecar)
+}
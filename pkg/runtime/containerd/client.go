@@ -135,6 +135,23 @@ func (c *containerdClient) KillContainer(ctx context.Context, container *ctr.Con
 	return c.killTask(c.nsCtx(ctx), container.ID(), signal)
 }
 
+// SignalProcesses signals processes of a container, listed and signaled by a
+// shell in a sidecar sharing the task's PID namespace or, without a sidecar
+// image, in an exec in the task.
+func (c *containerdClient) SignalProcesses(ctx context.Context, req *ctr.SignalRequest) ([]int, error) {
+	log.WithFields(log.Fields{"id": req.Container.ID(), "process": req.Process, "pids": req.PIDs, "signal": req.Signal}).
+		Debug("signaling containerd container processes")
+	if req.DryRun {
+		return nil, nil
+	}
+	return ctr.SignalWithShell(ctx, req, func(ctx context.Context, args []string) (string, error) {
+		if req.Sidecar.Image != "" {
+			return c.sidecarOutputIn(ctx, req.Container, true, req.Sidecar.Image, req.Sidecar.Pull, "sh", [][]string{args})
+		}
+		return c.execInContainerOutput(c.nsCtx(ctx), req.Container.ID(), "sh", args)
+	})
+}
+
 // StartContainer starts a container's task.
 func (c *containerdClient) StartContainer(ctx context.Context, container *ctr.Container, dryrun bool) error {
 	log.WithField("id", container.ID()).Debug("starting containerd container")
@@ -229,15 +246,15 @@ func (c *containerdClient) StopContainerWithID(ctx context.Context, containerID
 }
 
 // ExecContainer executes a command inside a running container.
-func (c *containerdClient) ExecContainer(ctx context.Context, container *ctr.Container, command string, args []string, dryrun bool) error {
+func (c *containerdClient) ExecContainer(ctx context.Context, container *ctr.Container, command string, args []string, dryrun bool) (*ctr.ExecResult, error) {
 	log.WithFields(log.Fields{"id": container.ID(), "command": command, "args": args}).Debug("exec in containerd container")
 	if dryrun {
-		return nil
+		return nil, nil
 	}
 	if len(args) == 0 {
 		if fields := strings.Fields(command); len(fields) > 0 {
 			command, args = fields[0], fields[1:]
 		}
 	}
-	return c.execInContainer(c.nsCtx(ctx), container.ID(), command, args)
+	return c.execInContainerResult(c.nsCtx(ctx), container.ID(), command, args)
 }
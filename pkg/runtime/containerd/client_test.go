@@ -3,7 +3,6 @@ package containerd
 import (
 	"context"
 	"fmt"
-	"net"
 	"strings"
 	"syscall"
 	"testing"
@@ -351,6 +350,17 @@ func TestListContainers(t *testing.T) {
 			wantLen:   1,
 			wantState: ctr.StateExited,
 		},
+		{
+			name: "paused_included_when_all",
+			containers: func() []containerd.Container {
+				task := new(mockTask)
+				task.On("Status", mock.Anything).Return(containerd.Status{Status: containerd.Paused}, nil)
+				return []containerd.Container{newMockContainer("c1", "nginx:latest", nil, task)}
+			},
+			opts:      ctr.ListOpts{All: true},
+			wantLen:   1,
+			wantState: ctr.StatePaused,
+		},
 		{
 			name: "no_task_skipped_when_not_all",
 			containers: func() []containerd.Container {
@@ -559,6 +569,13 @@ func TestKillContainer_Dryrun(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSignalProcesses_Dryrun(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	pids, err := client.SignalProcesses(context.Background(), &ctr.SignalRequest{Container: testContainer("c1"), Process: "worker", Signal: "SIGSTOP", DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, pids)
+}
+
 func TestKillContainer_Success(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -842,8 +859,9 @@ func TestParseSignal_InvalidInputs(t *testing.T) {
 
 func TestExecContainer_Dryrun(t *testing.T) {
 	client := newTestClient(NewMockapiClient(t))
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", []string{"-la"}, true)
+	result, err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", []string{"-la"}, true)
 	assert.NoError(t, err)
+	assert.Nil(t, result)
 }
 
 func TestExecContainer_Success(t *testing.T) {
@@ -856,8 +874,9 @@ func TestExecContainer_Success(t *testing.T) {
 	setupLoadContainer(api, "c1", mc)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "echo", []string{"hello"}, false)
+	result, err := client.ExecContainer(context.Background(), testContainer("c1"), "echo", []string{"hello"}, false)
 	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode)
 	proc.AssertCalled(t, "Start", mock.Anything)
 	proc.AssertCalled(t, "Delete", mock.Anything)
 }
@@ -872,9 +891,9 @@ func TestExecContainer_NonZeroExit(t *testing.T) {
 	setupLoadContainer(api, "c1", mc)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "false", nil, false)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "exited with code 1")
+	result, err := client.ExecContainer(context.Background(), testContainer("c1"), "false", nil, false)
+	require.NoError(t, err, "a non-zero exit is in the result")
+	assert.Equal(t, 1, result.ExitCode)
 }
 
 func TestExecContainer_LoadError(t *testing.T) {
@@ -882,7 +901,7 @@ func TestExecContainer_LoadError(t *testing.T) {
 	api.EXPECT().LoadContainer(mock.Anything, "c1").Return(nil, assert.AnError)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
+	_, err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load container")
 }
@@ -893,7 +912,7 @@ func TestExecContainer_TaskError(t *testing.T) {
 	setupLoadContainer(api, "c1", mc)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
+	_, err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to get task")
 }
@@ -907,7 +926,7 @@ func TestExecContainer_ExecError(t *testing.T) {
 	setupLoadContainer(api, "c1", mc)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
+	_, err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to exec")
 }
@@ -927,11 +946,36 @@ func TestExecContainer_StartError(t *testing.T) {
 	setupLoadContainer(api, "c1", mc)
 
 	client := newTestClient(api)
-	err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
+	_, err := client.ExecContainer(context.Background(), testContainer("c1"), "ls", nil, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to start exec")
 }
 
+func TestExecContainer_Canceled(t *testing.T) {
+	proc := new(mockProcess)
+	exitCh := make(chan containerd.ExitStatus, 1)
+	proc.On("Wait", mock.Anything).Return((<-chan containerd.ExitStatus)(exitCh), nil)
+	proc.On("Start", mock.Anything).Return(nil)
+	proc.On("Kill", mock.Anything, syscall.SIGKILL).Run(func(mock.Arguments) {
+		exitCh <- *containerd.NewExitStatus(137, time.Now(), nil)
+	}).Return(nil)
+	proc.On("Delete", mock.Anything).Return(nil)
+
+	task := newRunningTask()
+	setupExec(task, proc)
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	client := newTestClient(api)
+	_, err := client.ExecContainer(ctx, testContainer("c1"), "sleep", []string{"60"}, false)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	proc.AssertCalled(t, "Kill", mock.Anything, syscall.SIGKILL)
+	proc.AssertCalled(t, "Delete", mock.Anything)
+}
+
 func TestNetemContainer_Dryrun(t *testing.T) {
 	client := newTestClient(NewMockapiClient(t))
 	err := client.NetemContainer(context.Background(), &ctr.NetemRequest{
@@ -961,6 +1005,59 @@ func TestNetemContainer_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNetemContainer_CaptureFailureSkipsNetem(t *testing.T) {
+	api := NewMockapiClient(t)
+	api.EXPECT().LoadContainer(mock.Anything, "c1").Return(nil, errdefs.ErrNotFound).Once()
+
+	client := newTestClient(api)
+	err := client.NetemContainer(context.Background(), &ctr.NetemRequest{
+		Container: testContainer("c1"),
+		Interface: "eth0",
+		Command:   []string{"delay", "100ms"},
+		Capture:   &ctr.CaptureSpec{Dir: "/tmp/pcap", Interface: "eth0", Image: "nettools"},
+	})
+	assert.ErrorContains(t, err, "failed to get target task for capture sidecar")
+}
+
+func TestStopNetemContainer_CaptureAlreadyGone(t *testing.T) {
+	proc := newSuccessProcess()
+	task := newRunningTask()
+	setupExec(task, proc)
+
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+	api.EXPECT().LoadContainer(mock.Anything, "pumba-capture-c1").Return(nil, errdefs.ErrNotFound)
+
+	client := newTestClient(api)
+	err := client.StopNetemContainer(context.Background(), &ctr.NetemRequest{
+		Container: testContainer("c1"),
+		Interface: "eth0",
+		Capture:   &ctr.CaptureSpec{Dir: "/tmp/pcap", Interface: "eth0", Image: "nettools"},
+	})
+	require.NoError(t, err)
+}
+
+func TestNetemStatus_DirectExec(t *testing.T) {
+	proc := newSuccessProcess()
+	task := newRunningTask()
+	task.On("Exec", mock.Anything, mock.Anything, mock.MatchedBy(func(p *specs.Process) bool {
+		return strings.Join(p.Args, " ") == "tc -s qdisc show dev eth0"
+	})).Return(proc, nil)
+
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+
+	client := newTestClient(api)
+	_, err := client.NetemStatus(context.Background(), &ctr.NetemRequest{
+		Container: testContainer("c1"),
+		Interface: "eth0",
+	})
+	require.NoError(t, err)
+	proc.AssertExpectations(t)
+}
+
 func TestStopNetemContainer_Dryrun(t *testing.T) {
 	client := newTestClient(NewMockapiClient(t))
 	err := client.StopNetemContainer(context.Background(), &ctr.NetemRequest{
@@ -1035,6 +1132,34 @@ func TestIPTablesContainer_ExecError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to run iptables command")
 }
 
+func TestIPTablesContainer_AutoBackendResolvedOnce(t *testing.T) {
+	task := newRunningTask()
+	// the nft probe fails, the iptables rule runs
+	task.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(newFailProcess(1), nil).Once()
+	task.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(newSuccessProcess(), nil).Once()
+
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+
+	client := newTestClient(api)
+	req := &ctr.IPTablesRequest{
+		Container: testContainer("c1"),
+		CmdPrefix: []string{"-I", "INPUT"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Backend:   ctr.FilterBackendAuto,
+	}
+	require.NoError(t, client.IPTablesContainer(context.Background(), req))
+	assert.Equal(t, ctr.FilterBackendIPTables, req.Backend, "the install carries the resolved backend")
+	task.AssertNumberOfCalls(t, "Exec", 2)
+
+	// teardown never probes: an unresolved backend is refused
+	stop := *req
+	stop.CmdPrefix, stop.Backend = []string{"-D", "INPUT"}, ctr.FilterBackendAuto
+	err := newTestClient(NewMockapiClient(t)).StopIPTablesContainer(context.Background(), &stop)
+	assert.ErrorContains(t, err, "packet filter backend auto is not resolved")
+}
+
 func TestStopIPTablesContainer_Dryrun(t *testing.T) {
 	client := newTestClient(NewMockapiClient(t))
 	err := client.StopIPTablesContainer(context.Background(), &ctr.IPTablesRequest{
@@ -1064,6 +1189,88 @@ func TestStopIPTablesContainer_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestProxyContainer_Dryrun(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	req := &ctr.ProxyRequest{Container: testContainer("c1"), Name: "dns", Command: []string{"/pumba"}, DryRun: true}
+	assert.NoError(t, client.ProxyContainer(context.Background(), req))
+	assert.NoError(t, client.StopProxyContainer(context.Background(), req))
+}
+
+func TestProxyContainer_EmptyCommand(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	err := client.ProxyContainer(context.Background(), &ctr.ProxyRequest{Container: testContainer("c1"), Name: "dns"})
+	assert.ErrorContains(t, err, "proxy command is empty")
+}
+
+func TestStopProxyContainer_ProxyAlreadyGone(t *testing.T) {
+	proc := newSuccessProcess()
+	task := newRunningTask()
+	setupExec(task, proc)
+
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+	api.EXPECT().LoadContainer(mock.Anything, "pumba-dns-proxy-c1").Return(nil, errdefs.ErrNotFound)
+
+	client := newTestClient(api)
+	err := client.StopProxyContainer(context.Background(), &ctr.ProxyRequest{
+		Container: testContainer("c1"),
+		Name:      "dns",
+		Redirects: [][]string{{"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestStopProxyContainer_ReportsRedirectAndLoadErrors(t *testing.T) {
+	task := newRunningTask()
+	task.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+	api.EXPECT().LoadContainer(mock.Anything, "pumba-dns-proxy-c1").Return(nil, assert.AnError)
+
+	client := newTestClient(api)
+	err := client.StopProxyContainer(context.Background(), &ctr.ProxyRequest{
+		Container: testContainer("c1"),
+		Name:      "dns",
+		Redirects: [][]string{{"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to remove dns proxy redirects")
+	assert.Contains(t, err.Error(), "failed to load dns proxy sidecar")
+}
+
+func TestLinkContainer_Dryrun(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	out, err := client.LinkContainer(context.Background(), &ctr.LinkRequest{
+		Container: testContainer("c1"),
+		Commands:  [][]string{{"link", "set", "dev", "eth0", "down"}},
+		DryRun:    true,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestLinkContainer_TargetNotFound(t *testing.T) {
+	api := NewMockapiClient(t)
+	api.EXPECT().LoadContainer(mock.Anything, "c1").Return(nil, errdefs.ErrNotFound)
+	client := newTestClient(api)
+	_, err := client.LinkContainer(context.Background(), &ctr.LinkRequest{
+		Container: testContainer("c1"),
+		Commands:  [][]string{{"link", "set", "dev", "eth0", "down"}},
+		Sidecar:   ctr.SidecarSpec{Image: "nettools"},
+	})
+	assert.ErrorContains(t, err, "failed to get target task for sidecar")
+}
+
+func TestNetworkAttach_Unsupported(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	req := &ctr.NetworkRequest{Container: testContainer("c1"), Network: "backend"}
+	assert.ErrorIs(t, client.DisconnectNetwork(context.Background(), req), errNetworkAttach)
+	assert.ErrorIs(t, client.ConnectNetwork(context.Background(), req), errNetworkAttach)
+}
+
 func TestStressContainer_Dryrun(t *testing.T) {
 	client := newTestClient(NewMockapiClient(t))
 	id, outCh, errCh, err := stressIDOutErr(client.StressContainer(context.Background(),
@@ -1538,150 +1745,38 @@ func TestStressContainer_SidecarPullImageError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to pull image")
 }
 
-func TestBuildNetemCommands(t *testing.T) {
-	t.Parallel()
+func TestDiagnose_VersionError(t *testing.T) {
+	api := NewMockapiClient(t)
+	api.EXPECT().Version(mock.Anything).Return(containerd.Version{}, assert.AnError).Once()
 
-	tests := []struct {
-		name     string
-		iface    string
-		cmds     []string
-		ips      []*net.IPNet
-		sports   []string
-		dports   []string
-		wantCmds [][]string
-	}{
-		{
-			name:     "basic_delay",
-			iface:    "eth0",
-			cmds:     []string{"delay", "100ms"},
-			wantCmds: [][]string{{"qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms"}},
-		},
-		{
-			name:     "multiple_commands",
-			iface:    "eth0",
-			cmds:     []string{"delay", "100ms", "loss", "10%"},
-			wantCmds: [][]string{{"qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100ms", "loss", "10%"}},
-		},
-		{
-			name:  "ip_filtering_creates_prio_qdisc",
-			iface: "eth0",
-			cmds:  []string{"delay", "100ms"},
-			ips:   func() []*net.IPNet { _, n, _ := net.ParseCIDR("10.0.0.0/8"); return []*net.IPNet{n} }(),
-			wantCmds: [][]string{
-				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
-				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "dst", "10.0.0.0/8", "flowid", "1:3"},
-			},
-		},
-		{
-			name:   "sport_filtering",
-			iface:  "eth0",
-			cmds:   []string{"delay", "100ms"},
-			sports: []string{"80"},
-			wantCmds: [][]string{
-				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
-				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "sport", "80", "0xffff", "flowid", "1:3"},
-			},
-		},
-		{
-			name:   "dport_filtering",
-			iface:  "eth0",
-			cmds:   []string{"delay", "100ms"},
-			dports: []string{"443"},
-			wantCmds: [][]string{
-				{"qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:", "sfq"},
-				{"qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "delay", "100ms"},
-				{"filter", "add", "dev", "eth0", "protocol", "ip", "parent", "1:0", "prio", "1", "u32", "match", "ip", "dport", "443", "0xffff", "flowid", "1:3"},
-			},
-		},
-	}
+	checks := newTestClient(api).Diagnose(context.Background(), &ctr.DiagnoseRequest{Sidecar: "nettools"})
+	require.Len(t, checks, 1)
+	assert.Equal(t, ctr.CheckFail, checks[0].Status)
+	assert.Contains(t, checks[0].Hint, "--containerd-socket")
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			cmds := buildNetemCommands(tt.iface, tt.cmds, tt.ips, tt.sports, tt.dports)
-			assert.Equal(t, tt.wantCmds, cmds)
-		})
+func TestDiagnose_ImageMissing(t *testing.T) { //nolint:paralleltest // mutates package-level cgroupReader
+	setCgroupReaderFunc(t, func(uint32) ([]byte, error) { return []byte("0::/user.slice/session-1.scope\n"), nil })
+	api := NewMockapiClient(t)
+	api.EXPECT().Version(mock.Anything).Return(containerd.Version{Version: "v2.0.4"}, nil).Once()
+	api.EXPECT().GetImage(mock.Anything, "nettools").Return(nil, errdefs.ErrNotFound).Once()
+
+	checks := newTestClient(api).Diagnose(context.Background(), &ctr.DiagnoseRequest{Sidecar: "nettools"})
+	require.Len(t, checks, 7)
+	assert.Equal(t, ctr.Check{Name: "runtime", Status: ctr.CheckPass, Detail: "containerd v2.0.4, namespace test-ns"}, checks[0])
+	assert.Equal(t, "cgroup v2, driver resolved per target from its cgroup path", checks[1].Detail)
+	assert.Equal(t, ctr.CheckFail, checks[2].Status, "missing nettools image")
+	for _, c := range checks[3:] {
+		assert.Equal(t, ctr.CheckWarn, c.Status, "probes are skipped without the image")
 	}
 }
 
-func TestBuildStopNetemCommands(t *testing.T) {
-	t.Parallel()
-
-	t.Run("without_filters", func(t *testing.T) {
-		t.Parallel()
-		cmds := buildStopNetemCommands("eth0", false)
-		assert.Equal(t, [][]string{{"qdisc", "del", "dev", "eth0", "root"}}, cmds)
-	})
-
-	t.Run("with_filters", func(t *testing.T) {
-		t.Parallel()
-		cmds := buildStopNetemCommands("eth0", true)
-		assert.Len(t, cmds, 4)
-		assert.Equal(t, []string{"qdisc", "del", "dev", "eth0", "parent", "1:1", "handle", "10:"}, cmds[0])
-		assert.Equal(t, []string{"qdisc", "del", "dev", "eth0", "root", "handle", "1:", "prio"}, cmds[3])
+func TestCgroupCheck_V1(t *testing.T) { //nolint:paralleltest // mutates package-level cgroupReader
+	setCgroupReaderFunc(t, func(uint32) ([]byte, error) {
+		return []byte("12:memory:/user.slice\n11:cpu,cpuacct:/user.slice\n0::/user.slice\n"), nil
 	})
-}
-
-func TestBuildIPTablesCommands(t *testing.T) {
-	t.Parallel()
-
-	_, srcNet, _ := net.ParseCIDR("10.0.0.0/8")
-	_, dstNet, _ := net.ParseCIDR("192.168.1.0/24")
-
-	tests := []struct {
-		name     string
-		flags    []string
-		target   []string
-		srcIPs   []*net.IPNet
-		dstIPs   []*net.IPNet
-		srcPorts []string
-		dstPorts []string
-		want     [][]string
-	}{
-		{
-			name:   "basic_rule",
-			flags:  []string{"-A", "INPUT"},
-			target: []string{"-j", "DROP"},
-			want:   [][]string{{"-A", "INPUT", "-j", "DROP"}},
-		},
-		{
-			name:   "with_src_and_dst_ips",
-			flags:  []string{"-A", "INPUT"},
-			target: []string{"-j", "DROP"},
-			srcIPs: []*net.IPNet{srcNet},
-			dstIPs: []*net.IPNet{dstNet},
-			want: [][]string{
-				{"-A", "INPUT", "-s", "10.0.0.0/8", "-j", "DROP"},
-				{"-A", "INPUT", "-d", "192.168.1.0/24", "-j", "DROP"},
-			},
-		},
-		{
-			name:     "with_src_and_dst_ports",
-			flags:    []string{"-A", "INPUT"},
-			target:   []string{"-j", "DROP"},
-			srcPorts: []string{"80", "443"},
-			dstPorts: []string{"8080"},
-			want: [][]string{
-				{"-A", "INPUT", "--sport", "80", "-j", "DROP"},
-				{"-A", "INPUT", "--sport", "443", "-j", "DROP"},
-				{"-A", "INPUT", "--dport", "8080", "-j", "DROP"},
-			},
-		},
-	}
+	assert.Equal(t, "cgroup v1, driver resolved per target from its cgroup path", cgroupCheck().Detail)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			cmds := buildIPTablesCommands(tt.flags, tt.target, tt.srcIPs, tt.dstIPs, tt.srcPorts, tt.dstPorts)
-			assert.Equal(t, tt.want, cmds)
-		})
-	}
+	setCgroupReaderFunc(t, func(uint32) ([]byte, error) { return nil, assert.AnError })
+	assert.Equal(t, ctr.CheckWarn, cgroupCheck().Status)
 }
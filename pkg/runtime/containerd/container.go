@@ -33,10 +33,13 @@ func toContainer(ctx context.Context, c containerd.Container, all bool) (*ctr.Co
 		if serr != nil {
 			return nil, false, fmt.Errorf("failed to get task status for %s: %w", c.ID(), serr)
 		}
-		if status.Status == containerd.Running {
+		switch {
+		case status.Status == containerd.Running:
 			state = ctr.StateRunning
-		} else if !all {
+		case !all:
 			return nil, true, nil
+		case status.Status == containerd.Paused:
+			state = ctr.StatePaused
 		}
 	}
 
@@ -48,6 +51,7 @@ func toContainer(ctx context.Context, c containerd.Container, all bool) (*ctr.Co
 		State:         state,
 		Labels:        info.Labels,
 		Networks:      make(map[string]ctr.NetworkLink),
+		Created:       info.CreatedAt,
 	}, false, nil
 }
 
@@ -0,0 +1,55 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// rootfsWriter writes a file into the root filesystem of a process, the
+// mounted snapshot of its container, through /proc/<pid>/root: Pumba runs in
+// the host PID namespace, as for cgroupReader. The path is resolved in an
+// os.Root, so symlinks in the container cannot lead the write out of it.
+// Overrideable in tests.
+var rootfsWriter = func(pid uint32, path string, content []byte, mode os.FileMode) error {
+	root, err := os.OpenRoot(fmt.Sprintf("/proc/%d/root", pid))
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	f, err := root.OpenFile(strings.TrimPrefix(path, "/"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	// the mode given to OpenFile is masked by the umask and ignored when the
+	// file exists
+	return root.Chmod(strings.TrimPrefix(path, "/"), mode)
+}
+
+// CopyToContainer writes a file into the root filesystem of a container's
+// task; containerd has no copy API.
+func (c *containerdClient) CopyToContainer(ctx context.Context, req *ctr.CopyRequest) error {
+	log.WithFields(log.Fields{"id": req.Container.ID(), "path": req.Path, "size": len(req.Content)}).Debug("copy file to containerd container")
+	if req.DryRun {
+		return nil
+	}
+	task, err := c.getTask(c.nsCtx(ctx), req.Container.ID())
+	if err != nil {
+		return err
+	}
+	if err := rootfsWriter(task.Pid(), req.Path, req.Content, req.Mode); err != nil {
+		return fmt.Errorf("failed to copy %s to container %s: %w", req.Path, req.Container.ID(), err)
+	}
+	return nil
+}
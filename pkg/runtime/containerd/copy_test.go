@@ -0,0 +1,69 @@
+package containerd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRootfsWriterFunc(t *testing.T, fn func(uint32, string, []byte, os.FileMode) error) {
+	t.Helper()
+	orig := rootfsWriter
+	rootfsWriter = fn
+	t.Cleanup(func() { rootfsWriter = orig })
+}
+
+func TestCopyToContainer(t *testing.T) { //nolint:paralleltest // mutates package-level rootfsWriter
+	task := newRunningTask()
+	task.pid = 42
+	mc := newMockContainer("c1", "nginx", nil, task)
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+	var got []any
+	setRootfsWriterFunc(t, func(pid uint32, path string, content []byte, mode os.FileMode) error {
+		got = []any{pid, path, string(content), mode}
+		return nil
+	})
+
+	client := newTestClient(api)
+	req := &ctr.CopyRequest{Container: testContainer("c1"), Path: "/tmp/fault.sh", Content: []byte("exit 0"), Mode: 0o700}
+	require.NoError(t, client.CopyToContainer(context.Background(), req))
+	assert.Equal(t, []any{uint32(42), "/tmp/fault.sh", "exit 0", os.FileMode(0o700)}, got)
+}
+
+func TestCopyToContainer_DryRun(t *testing.T) {
+	client := newTestClient(NewMockapiClient(t))
+	req := &ctr.CopyRequest{Container: testContainer("c1"), Path: "/tmp/fault.sh", DryRun: true}
+	assert.NoError(t, client.CopyToContainer(context.Background(), req))
+}
+
+func TestCopyToContainer_WriteError(t *testing.T) { //nolint:paralleltest // mutates package-level rootfsWriter
+	mc := newMockContainer("c1", "nginx", nil, newRunningTask())
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", mc)
+	setRootfsWriterFunc(t, func(uint32, string, []byte, os.FileMode) error { return os.ErrPermission })
+
+	client := newTestClient(api)
+	req := &ctr.CopyRequest{Container: testContainer("c1"), Path: "/tmp/fault.sh"}
+	err := client.CopyToContainer(context.Background(), req)
+	require.ErrorIs(t, err, os.ErrPermission)
+	assert.Contains(t, err.Error(), "failed to copy /tmp/fault.sh to container c1")
+}
+
+func TestRootfsWriter(t *testing.T) {
+	// the root filesystem of this process is the host's: write into a temp dir
+	path := filepath.Join(t.TempDir(), "fault.sh")
+	require.NoError(t, os.WriteFile(path, []byte("stale content"), 0o600))
+	require.NoError(t, rootfsWriter(uint32(os.Getpid()), path, []byte("exit 0"), 0o700)) //nolint:gosec
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "exit 0", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+}
@@ -0,0 +1,61 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
+)
+
+// Diagnose runs the `pumba doctor` checks: containerd connectivity and
+// version, the host cgroup version, sidecar images and the capability and
+// kernel module probes. containerd does not report its cgroup driver or
+// whether it runs rootless: the driver is resolved per target from its
+// cgroup path, and the probes fail if the sidecars lack privileges.
+func (c *containerdClient) Diagnose(ctx context.Context, req *ctr.DiagnoseRequest) []ctr.Check {
+	ctx = c.nsCtx(ctx)
+	v, err := c.client.Version(ctx)
+	if err != nil {
+		return []ctr.Check{preflight.Fail(preflight.CheckRuntime, err.Error(),
+			"check that containerd is running and that --containerd-socket points at its socket and is readable by Pumba")}
+	}
+	checks := []ctr.Check{
+		preflight.Pass(preflight.CheckRuntime, fmt.Sprintf("containerd %s, namespace %s", v.Version, c.namespace)),
+		cgroupCheck(),
+	}
+	return append(checks, preflight.Sidecar(ctx, req, c.ensureImage, c.probe)...)
+}
+
+// cgroupCheck reports the cgroup version of the host, read from Pumba's own
+// /proc/<pid>/cgroup: a single "0::" line is the unified v2 hierarchy.
+// Pumba shares the host with containerd, whose socket is local.
+func cgroupCheck() ctr.Check {
+	data, err := cgroupReader(uint32(os.Getpid())) //nolint:gosec
+	if err != nil {
+		return preflight.Warn(preflight.CheckCgroup, err.Error(), "stress needs /proc of the host; run Pumba in the host PID namespace")
+	}
+	version := "v1"
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) == 1 && strings.HasPrefix(lines[0], "0::") {
+		version = "v2"
+	}
+	return preflight.Pass(preflight.CheckCgroup, "cgroup "+version+", driver resolved per target from its cgroup path")
+}
+
+// ensureImage pulls img or, without pull, looks it up in the namespace.
+func (c *containerdClient) ensureImage(ctx context.Context, img string, pull bool) error {
+	if pull {
+		return c.pullImage(ctx, img)
+	}
+	_, err := c.client.GetImage(ctx, img)
+	return err
+}
+
+// probe runs command with each of argsList in a sidecar of img with a
+// network namespace of its own.
+func (c *containerdClient) probe(ctx context.Context, img, command string, argsList [][]string) error {
+	_, err := c.sidecarOutput(ctx, nil, img, false, command, argsList)
+	return err
+}
@@ -0,0 +1,90 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	apievents "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/typeurl/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// errEventStreamClosed is sent when containerd ends the event stream.
+var errEventStreamClosed = errors.New("event stream closed")
+
+// WatchContainers streams the container and task events of the client's
+// namespace from containerd's event service.
+func (c *containerdClient) WatchContainers(ctx context.Context) (<-chan ctr.Event, <-chan error) {
+	envelopes, envErrs := c.client.Subscribe(c.nsCtx(ctx), fmt.Sprintf("namespace==%s", c.namespace))
+	out := make(chan ctr.Event)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-envErrs:
+				if ctx.Err() == nil {
+					errs <- fmt.Errorf("failed to watch containerd events: %w", err)
+				}
+				return
+			case env, ok := <-envelopes:
+				if !ok {
+					errs <- errEventStreamClosed
+					return
+				}
+				e, ok := toEvent(env)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, errs
+}
+
+// toEvent converts a containerd event envelope, reporting false for the
+// events that do not change a container.
+func toEvent(env *events.Envelope) (ctr.Event, bool) {
+	e := ctr.Event{Time: env.Timestamp}
+	if env.Event == nil {
+		return e, false
+	}
+	v, err := typeurl.UnmarshalAny(env.Event)
+	if err != nil {
+		log.WithError(err).WithField("topic", env.Topic).Debug("skipping undecodable containerd event")
+		return e, false
+	}
+	switch ev := v.(type) {
+	case *apievents.ContainerCreate:
+		e.Kind, e.ContainerID = ctr.EventCreate, ev.GetID()
+	case *apievents.ContainerUpdate:
+		e.Kind, e.ContainerID = ctr.EventUpdate, ev.GetID()
+	case *apievents.ContainerDelete:
+		e.Kind, e.ContainerID = ctr.EventDestroy, ev.GetID()
+	case *apievents.TaskStart:
+		e.Kind, e.ContainerID = ctr.EventStart, ev.GetContainerID()
+	case *apievents.TaskExit:
+		// exec'd processes exit too; the init process has the container's ID
+		if ev.GetID() != ev.GetContainerID() {
+			return e, false
+		}
+		e.Kind, e.ContainerID = ctr.EventDie, ev.GetContainerID()
+	case *apievents.TaskPaused:
+		e.Kind, e.ContainerID = ctr.EventPause, ev.GetContainerID()
+	case *apievents.TaskResumed:
+		e.Kind, e.ContainerID = ctr.EventUnpause, ev.GetContainerID()
+	default:
+		return e, false
+	}
+	return e, true
+}
@@ -0,0 +1,70 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	apievents "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/typeurl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func envelope(t *testing.T, topic string, v any) *events.Envelope {
+	t.Helper()
+	a, err := typeurl.MarshalAny(v)
+	require.NoError(t, err)
+	return &events.Envelope{Namespace: "k8s.io", Topic: topic, Event: a}
+}
+
+func TestToEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		topic  string
+		event  any
+		want   ctr.Event
+		wantOK bool
+	}{
+		{name: "create", topic: "/containers/create", event: &apievents.ContainerCreate{ID: "a1"}, want: ctr.Event{Kind: ctr.EventCreate, ContainerID: "a1"}, wantOK: true},
+		{name: "update", topic: "/containers/update", event: &apievents.ContainerUpdate{ID: "a1"}, want: ctr.Event{Kind: ctr.EventUpdate, ContainerID: "a1"}, wantOK: true},
+		{name: "delete", topic: "/containers/delete", event: &apievents.ContainerDelete{ID: "a1"}, want: ctr.Event{Kind: ctr.EventDestroy, ContainerID: "a1"}, wantOK: true},
+		{name: "start", topic: "/tasks/start", event: &apievents.TaskStart{ContainerID: "a1"}, want: ctr.Event{Kind: ctr.EventStart, ContainerID: "a1"}, wantOK: true},
+		{name: "exit", topic: "/tasks/exit", event: &apievents.TaskExit{ContainerID: "a1", ID: "a1"}, want: ctr.Event{Kind: ctr.EventDie, ContainerID: "a1"}, wantOK: true},
+		{name: "exec exit", topic: "/tasks/exit", event: &apievents.TaskExit{ContainerID: "a1", ID: "exec-1"}},
+		{name: "paused", topic: "/tasks/paused", event: &apievents.TaskPaused{ContainerID: "a1"}, want: ctr.Event{Kind: ctr.EventPause, ContainerID: "a1"}, wantOK: true},
+		{name: "resumed", topic: "/tasks/resumed", event: &apievents.TaskResumed{ContainerID: "a1"}, want: ctr.Event{Kind: ctr.EventUnpause, ContainerID: "a1"}, wantOK: true},
+		{name: "image", topic: "/images/create", event: &apievents.ImageCreate{Name: "nginx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := toEvent(envelope(t, tt.topic, tt.event))
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWatchContainers(t *testing.T) {
+	envelopes := make(chan *events.Envelope)
+	envErrs := make(chan error, 1)
+	api := NewMockapiClient(t)
+	api.EXPECT().Subscribe(mock.Anything, "namespace==k8s.io").Return(envelopes, envErrs).Once()
+	go func() {
+		envelopes <- envelope(t, "/tasks/paused", &apievents.TaskPaused{ContainerID: "a1"})
+		envErrs <- errors.New("connection reset")
+	}()
+
+	out, errs := (&containerdClient{client: api, namespace: "k8s.io"}).WatchContainers(context.TODO())
+	var got []ctr.Event
+	for e := range out {
+		got = append(got, e)
+	}
+	assert.Equal(t, []ctr.Event{{Kind: ctr.EventPause, ContainerID: "a1"}}, got)
+	require.EqualError(t, <-errs, "failed to watch containerd events: connection reset")
+}
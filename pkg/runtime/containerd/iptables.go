@@ -2,9 +2,11 @@ package containerd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -12,37 +14,115 @@ import (
 //
 //nolint:dupl // intentionally parallel to StopIPTablesContainer; install/remove use identical IPTables commands on this runtime
 func (c *containerdClient) IPTablesContainer(ctx context.Context, req *ctr.IPTablesRequest) error {
-	log.WithField("id", req.Container.ID()).Debug("iptables on containerd container")
+	log.WithFields(log.Fields{"id": req.Container.ID(), "backend": req.Backend}).Debug("iptables on containerd container")
 	if req.DryRun {
 		return nil
 	}
-	commands := buildIPTablesCommands(req.CmdPrefix, req.CmdSuffix, req.SrcIPs, req.DstIPs, req.SPorts, req.DPorts)
-	if req.Sidecar.Image != "" {
-		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "iptables", commands)
-	}
-	return c.runIPTablesCommands(ctx, req.Container.ID(), commands)
+	commands := req.Commands()
+	return c.withCapture(ctx, req.Container, req.Capture, func() error {
+		c.resolveBackend(ctx, req)
+		return c.runFilterCommands(ctx, req, commands)
+	})
 }
 
 // StopIPTablesContainer removes iptables rules from a container.
 //
 //nolint:dupl // intentionally parallel to IPTablesContainer; install/remove use identical IPTables commands on this runtime
 func (c *containerdClient) StopIPTablesContainer(ctx context.Context, req *ctr.IPTablesRequest) error {
-	log.WithField("id", req.Container.ID()).Debug("stop iptables on containerd container")
+	log.WithFields(log.Fields{"id": req.Container.ID(), "backend": req.Backend}).Debug("stop iptables on containerd container")
 	if req.DryRun {
 		return nil
 	}
-	commands := buildIPTablesCommands(req.CmdPrefix, req.CmdSuffix, req.SrcIPs, req.DstIPs, req.SPorts, req.DPorts)
+	commands := req.Commands()
+	return c.stopWithCapture(ctx, req.Container, req.Capture, func() error {
+		return c.runFilterCommands(ctx, req, commands)
+	})
+}
+
+// IPTablesStatus lists the packet filter state of the target's network
+// namespace with the backend the request resolves to.
+func (c *containerdClient) IPTablesStatus(ctx context.Context, req *ctr.IPTablesRequest) (string, error) {
+	status := *req
+	c.resolveBackend(ctx, &status)
+	tool, err := filterTool(&status)
+	if err != nil {
+		return "", err
+	}
+	return c.toolOutput(ctx, req.Container, req.Sidecar.Image, status.Sidecar.Pull, tool, filterStatusArgs(tool))
+}
+
+// filterStatusArgs returns the argument lists printing the state of tool:
+// the rules of the filter and nat tables for iptables, the tables for nft
+// (Pumba's rules live in a dedicated table).
+func filterStatusArgs(tool string) [][]string {
+	if tool == nftables.Tool {
+		return [][]string{nftables.ProbeArgs}
+	}
+	return [][]string{{"-S"}, {"-t", "nat", "-S"}}
+}
+
+// runFilterCommands runs commands (iptables syntax) with the packet filter
+// backend of req, either in a sidecar or directly in the target.
+func (c *containerdClient) runFilterCommands(ctx context.Context, req *ctr.IPTablesRequest, commands [][]string) error {
+	tool, err := filterTool(req)
+	if err != nil {
+		return err
+	}
+	if tool == nftables.Tool {
+		if commands, err = nftables.Translate(commands); err != nil {
+			return fmt.Errorf("failed to translate iptables rules: %w", err)
+		}
+	}
 	if req.Sidecar.Image != "" {
-		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "iptables", commands)
+		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, tool, commands)
+	}
+	return c.runIPTablesCommands(ctx, req.Container.ID(), tool, commands)
+}
+
+// resolveBackend replaces the auto backend of req with the one it resolves
+// to: it runs `nft list tables` in the target netns and falls back to
+// iptables on failure. The install request carries the resolved backend to
+// the removal, so teardown never probes again. A probe through the sidecar
+// already pulled its image, so Pull is cleared.
+func (c *containerdClient) resolveBackend(ctx context.Context, req *ctr.IPTablesRequest) {
+	if req.Backend != ctr.FilterBackendAuto {
+		return
+	}
+	var err error
+	if req.Sidecar.Image != "" {
+		err = c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, nftables.Tool, [][]string{nftables.ProbeArgs})
+	} else {
+		err = c.execInContainer(c.nsCtx(ctx), req.Container.ID(), nftables.Tool, nftables.ProbeArgs)
+	}
+	req.Sidecar.Pull = false
+	if err != nil {
+		log.WithError(err).WithField("id", req.Container.ID()).Debug("nft probe failed, using iptables backend")
+		req.Backend = ctr.FilterBackendIPTables
+		return
+	}
+	req.Backend = ctr.FilterBackendNFTables
+}
+
+// filterTool maps req.Backend to a binary. The auto backend must have been
+// resolved by the install, see resolveBackend.
+func filterTool(req *ctr.IPTablesRequest) (string, error) {
+	switch req.Backend {
+	case "", ctr.FilterBackendIPTables:
+		return "iptables", nil
+	case ctr.FilterBackendNFTables:
+		return nftables.Tool, nil
+	case ctr.FilterBackendAuto:
+		return "", errors.New("packet filter backend auto is not resolved: pass the backend the rules were installed with")
+	default:
+		return "", fmt.Errorf("unsupported packet filter backend: %s", req.Backend)
 	}
-	return c.runIPTablesCommands(ctx, req.Container.ID(), commands)
 }
 
-func (c *containerdClient) runIPTablesCommands(ctx context.Context, containerID string, commands [][]string) error {
+func (c *containerdClient) runIPTablesCommands(ctx context.Context, containerID, tool string, commands [][]string) error {
 	ctx = c.nsCtx(ctx)
 	for _, args := range commands {
-		if err := c.execInContainer(ctx, containerID, "iptables", args); err != nil {
-			return fmt.Errorf("failed to run iptables command: %w", err)
+		if err := c.execInContainer(ctx, containerID, tool, args); err != nil {
+			return fmt.Errorf("failed to run %s command: %w", tool, err)
 		}
 	}
 	return nil
@@ -0,0 +1,33 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// errNetworkAttach is returned by the network attach methods: containerd has
+// no network API, container networking is owned by CNI outside the runtime.
+var errNetworkAttach = errors.New("network disconnect/connect is not supported by the containerd runtime; use 'link down' instead")
+
+// LinkContainer runs `ip` commands in a sidecar sharing the target's network
+// namespace and returns their combined stdout.
+func (c *containerdClient) LinkContainer(ctx context.Context, req *ctr.LinkRequest) (string, error) {
+	log.WithFields(log.Fields{"id": req.Container.ID(), "commands": req.Commands, "img": req.Sidecar.Image}).Debug("link on containerd container")
+	if req.DryRun {
+		return "", nil
+	}
+	return c.sidecarOutput(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "ip", req.Commands)
+}
+
+// DisconnectNetwork is not supported by containerd.
+func (c *containerdClient) DisconnectNetwork(_ context.Context, _ *ctr.NetworkRequest) error {
+	return errNetworkAttach
+}
+
+// ConnectNetwork is not supported by containerd.
+func (c *containerdClient) ConnectNetwork(_ context.Context, _ *ctr.NetworkRequest) error {
+	return errNetworkAttach
+}
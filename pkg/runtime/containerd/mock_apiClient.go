@@ -7,6 +7,8 @@ import (
 
 	client "github.com/containerd/containerd/v2/client"
 
+	events "github.com/containerd/containerd/v2/core/events"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -407,6 +409,137 @@ func (_c *MockapiClient_Pull_Call) RunAndReturn(run func(context.Context, string
 	return _c
 }
 
+// Subscribe provides a mock function with given fields: ctx, filters
+func (_m *MockapiClient) Subscribe(ctx context.Context, filters ...string) (<-chan *events.Envelope, <-chan error) {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan *events.Envelope
+	var r1 <-chan error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) (<-chan *events.Envelope, <-chan error)); ok {
+		return rf(ctx, filters...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) <-chan *events.Envelope); ok {
+		r0 = rf(ctx, filters...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan *events.Envelope)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...string) <-chan error); ok {
+		r1 = rf(ctx, filters...)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	return r0, r1
+}
+
+// MockapiClient_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type MockapiClient_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filters ...string
+func (_e *MockapiClient_Expecter) Subscribe(ctx interface{}, filters ...interface{}) *MockapiClient_Subscribe_Call {
+	return &MockapiClient_Subscribe_Call{Call: _e.mock.On("Subscribe",
+		append([]interface{}{ctx}, filters...)...)}
+}
+
+func (_c *MockapiClient_Subscribe_Call) Run(run func(ctx context.Context, filters ...string)) *MockapiClient_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockapiClient_Subscribe_Call) Return(_a0 <-chan *events.Envelope, _a1 <-chan error) *MockapiClient_Subscribe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockapiClient_Subscribe_Call) RunAndReturn(run func(context.Context, ...string) (<-chan *events.Envelope, <-chan error)) *MockapiClient_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Version provides a mock function with given fields: ctx
+func (_m *MockapiClient) Version(ctx context.Context) (client.Version, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Version")
+	}
+
+	var r0 client.Version
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (client.Version, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) client.Version); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(client.Version)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockapiClient_Version_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Version'
+type MockapiClient_Version_Call struct {
+	*mock.Call
+}
+
+// Version is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockapiClient_Expecter) Version(ctx interface{}) *MockapiClient_Version_Call {
+	return &MockapiClient_Version_Call{Call: _e.mock.On("Version", ctx)}
+}
+
+func (_c *MockapiClient_Version_Call) Run(run func(ctx context.Context)) *MockapiClient_Version_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockapiClient_Version_Call) Return(_a0 client.Version, _a1 error) *MockapiClient_Version_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockapiClient_Version_Call) RunAndReturn(run func(context.Context) (client.Version, error)) *MockapiClient_Version_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockapiClient creates a new instance of MockapiClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockapiClient(t interface {
@@ -14,11 +14,13 @@ func (c *containerdClient) NetemContainer(ctx context.Context, req *ctr.NetemReq
 	if req.DryRun {
 		return nil
 	}
-	tcCommands := buildNetemCommands(req.Interface, req.Command, req.IPs, req.SPorts, req.DPorts)
-	if req.Sidecar.Image != "" {
-		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "tc", tcCommands)
-	}
-	return c.runTCCommands(c.nsCtx(ctx), req.Container.ID(), tcCommands)
+	tcCommands := req.TCCommands()
+	return c.withCapture(ctx, req.Container, req.Capture, func() error {
+		if req.Sidecar.Image != "" {
+			return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "tc", tcCommands)
+		}
+		return c.runTCCommands(c.nsCtx(ctx), req.Container.ID(), tcCommands)
+	})
 }
 
 // StopNetemContainer removes network emulation from a container.
@@ -27,12 +29,23 @@ func (c *containerdClient) StopNetemContainer(ctx context.Context, req *ctr.Nete
 	if req.DryRun {
 		return nil
 	}
-	hasFilters := len(req.IPs) > 0 || len(req.SPorts) > 0 || len(req.DPorts) > 0
-	tcCommands := buildStopNetemCommands(req.Interface, hasFilters)
-	if req.Sidecar.Image != "" {
-		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "tc", tcCommands)
+	tcCommands := req.StopTCCommands()
+	return c.stopWithCapture(ctx, req.Container, req.Capture, func() error {
+		if req.Sidecar.Image != "" {
+			return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "tc", tcCommands)
+		}
+		return c.runTCCommands(c.nsCtx(ctx), req.Container.ID(), tcCommands)
+	})
+}
+
+// NetemStatus lists the qdiscs of the request interface, or of every
+// interface when Interface is empty, in the target's network namespace.
+func (c *containerdClient) NetemStatus(ctx context.Context, req *ctr.NetemRequest) (string, error) {
+	args := []string{"-s", "qdisc", "show"}
+	if req.Interface != "" {
+		args = append(args, "dev", req.Interface)
 	}
-	return c.runTCCommands(c.nsCtx(ctx), req.Container.ID(), tcCommands)
+	return c.toolOutput(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "tc", [][]string{args})
 }
 
 func (c *containerdClient) runTCCommands(ctx context.Context, containerID string, commands [][]string) error {
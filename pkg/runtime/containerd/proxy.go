@@ -0,0 +1,184 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProxyContainer starts a long-lived proxy sidecar sharing the target's
+// network namespace and installs the nat redirects to it. The proxy is
+// removed again if the redirects cannot be installed.
+func (c *containerdClient) ProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	log.WithFields(log.Fields{"id": req.Container.ID(), "proxy": req.Name, "proxy-image": req.Proxy.Image}).Debug("proxy on containerd container")
+	if req.DryRun {
+		return nil
+	}
+	if len(req.Command) == 0 {
+		return errors.New("proxy command is empty")
+	}
+	proxy, err := c.startProxySidecar(c.nsCtx(ctx), req)
+	if err != nil {
+		return err
+	}
+	if err := c.proxyRedirects(ctx, req, "-I"); err != nil {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sidecarCleanupTimeout)
+		defer cancel()
+		if cleanupErr := c.cleanupSidecar(c.nsCtx(cleanupCtx), proxy); cleanupErr != nil {
+			log.WithError(cleanupErr).Warn("failed to clean up proxy sidecar")
+		}
+		return fmt.Errorf("failed to redirect traffic to %s proxy: %w", req.Name, err)
+	}
+	return nil
+}
+
+// StopProxyContainer removes the nat redirects and then the proxy sidecar.
+// Both steps always run so a failed rule removal does not leak the proxy.
+func (c *containerdClient) StopProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	log.WithFields(log.Fields{"id": req.Container.ID(), "proxy": req.Name}).Debug("stop proxy on containerd container")
+	if req.DryRun {
+		return nil
+	}
+	var errs []error
+	if err := c.proxyRedirects(ctx, req, "-D"); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove %s proxy redirects: %w", req.Name, err))
+	}
+	nsCtx := c.nsCtx(ctx)
+	proxy, err := c.client.LoadContainer(nsCtx, req.SidecarName())
+	switch {
+	case errdefs.IsNotFound(err):
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to load %s proxy sidecar: %w", req.Name, err))
+	default:
+		if err := c.cleanupSidecar(nsCtx, proxy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// proxyStartGrace is how long a proxy sidecar must keep running before the
+// redirects to it are installed: a proxy that cannot start exits at once, and
+// redirecting to it would blackhole the traffic. Replaced in tests.
+var proxyStartGrace = time.Second
+
+// startProxySidecar creates and starts the proxy container, running
+// req.Command as ctr.ProxyUser in the target's network namespace with the
+// target's resolv.conf, and waits proxyStartGrace for it to keep running.
+func (c *containerdClient) startProxySidecar(ctx context.Context, req *ctr.ProxyRequest) (containerd.Container, error) {
+	uid, err := strconv.ParseUint(ctr.ProxyUser, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy user %q: %w", ctr.ProxyUser, err)
+	}
+	if req.Proxy.Pull {
+		if err := c.pullImage(ctx, req.Proxy.Image); err != nil {
+			return nil, fmt.Errorf("failed to pull proxy image %s: %w", req.Proxy.Image, err)
+		}
+	}
+	targetTask, err := c.getTask(ctx, req.Container.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target task for proxy sidecar: %w", err)
+	}
+	targetPID := targetTask.Pid()
+	if targetPID == 0 {
+		return nil, fmt.Errorf("target task for %s has PID 0 (not running)", req.Container.ID())
+	}
+	image, err := c.client.GetImage(ctx, req.Proxy.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy image %s: %w", req.Proxy.Image, err)
+	}
+
+	sidecarID := req.SidecarName()
+	proxy, err := c.client.NewContainer(ctx, sidecarID,
+		containerd.WithImage(image),
+		containerd.WithContainerLabels(ctr.SidecarLabels(req.Name+"-proxy", req.Container.ID())),
+		containerd.WithNewSnapshot(sidecarID+"-snapshot", image),
+		containerd.WithNewSpec(
+			oci.WithImageConfig(image),
+			oci.WithProcessArgs(req.Command...),
+			oci.WithUIDGID(uint32(uid), uint32(uid)),
+			oci.WithLinuxNamespace(specs.LinuxNamespace{
+				Type: specs.NetworkNamespace,
+				Path: fmt.Sprintf("/proc/%d/ns/net", targetPID),
+			}),
+			withTargetResolvConf(targetPID),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy sidecar: %w", err)
+	}
+	task, err := proxy.NewTask(ctx, cio.NullIO)
+	var exitCh <-chan containerd.ExitStatus
+	if err == nil {
+		// wait before start so an immediate exit is not missed
+		exitCh, err = task.Wait(ctx)
+	}
+	if err == nil {
+		err = task.Start(ctx)
+	}
+	if err == nil {
+		err = waitProxyStart(ctx, exitCh)
+	}
+	if err != nil {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sidecarCleanupTimeout)
+		defer cancel()
+		if cleanupErr := c.cleanupSidecar(cleanupCtx, proxy); cleanupErr != nil {
+			log.WithError(cleanupErr).Warn("failed to clean up proxy sidecar")
+		}
+		return nil, fmt.Errorf("failed to start proxy sidecar task: %w", err)
+	}
+	return proxy, nil
+}
+
+// withTargetResolvConf bind-mounts the resolv.conf of the target process
+// into the sidecar, as Docker does for containers sharing a network
+// namespace: the DNS proxy forwards to its first nameserver by default, and
+// the pumba image has none of its own.
+func withTargetResolvConf(targetPID uint32) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *oci.Spec) error {
+		s.Mounts = append(s.Mounts, specs.Mount{
+			Destination: "/etc/resolv.conf",
+			Type:        "bind",
+			Source:      fmt.Sprintf("/proc/%d/root/etc/resolv.conf", targetPID),
+			Options:     []string{"rbind", "ro"},
+		})
+		return nil
+	}
+}
+
+// waitProxyStart fails when the proxy exits within proxyStartGrace.
+func waitProxyStart(ctx context.Context, exitCh <-chan containerd.ExitStatus) error {
+	timer := time.NewTimer(proxyStartGrace)
+	defer timer.Stop()
+	select {
+	case status := <-exitCh:
+		code, _, err := status.Result()
+		if err != nil {
+			return fmt.Errorf("proxy exited at start: %w", err)
+		}
+		return fmt.Errorf("proxy exited at start with status %d", code)
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *containerdClient) proxyRedirects(ctx context.Context, req *ctr.ProxyRequest, op string) error {
+	argsList := req.RedirectArgs(op)
+	if req.Sidecar.Image != "" {
+		return c.sidecarExec(ctx, req.Container, req.Sidecar.Image, req.Sidecar.Pull, "iptables", argsList)
+	}
+	return c.runIPTablesCommands(ctx, req.Container.ID(), "iptables", argsList)
+}
@@ -0,0 +1,91 @@
+package containerd
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setProxyStartGrace(t *testing.T, d time.Duration) {
+	t.Helper()
+	orig := proxyStartGrace
+	proxyStartGrace = d
+	t.Cleanup(func() { proxyStartGrace = orig })
+}
+
+// dnsProxyRequest has no --upstream, so the proxy resolves it from the
+// resolv.conf it sees.
+func dnsProxyRequest() *ctr.ProxyRequest {
+	return &ctr.ProxyRequest{
+		Container: testContainer("c1"),
+		Name:      "dns",
+		Command:   []string{"pumba", "dns-proxy", "--port", "15353", "--action", "nxdomain"},
+		Redirects: [][]string{{"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}},
+		Proxy:     ctr.SidecarSpec{Image: "pumba"},
+	}
+}
+
+// setupProxySidecar expects the proxy sidecar of the target c1 (PID 42) to be
+// created and started; its task reports exits on exitCh.
+func setupProxySidecar(t *testing.T, targetTask *mockTask, exitCh <-chan containerd.ExitStatus) (*MockapiClient, *mockContainer) {
+	t.Helper()
+	api := NewMockapiClient(t)
+	setupLoadContainer(api, "c1", newMockContainer("c1", "nginx", nil, targetTask))
+	api.EXPECT().GetImage(mock.Anything, "pumba").Return(&stubImage{}, nil)
+
+	sidecarTask := &mockTask{}
+	sidecarTask.On("Wait", mock.Anything).Return(exitCh, nil)
+	sidecarTask.On("Start", mock.Anything).Return(nil)
+	sidecarTask.On("Kill", mock.Anything, syscall.SIGKILL).Return(nil).Maybe()
+	sidecarTask.On("Delete", mock.Anything).Return(nil).Maybe()
+	sidecar := new(mockContainer)
+	sidecar.On("ID").Return("pumba-dns-proxy-c1").Maybe()
+	sidecar.On("NewTask", mock.Anything).Return(sidecarTask, nil)
+	sidecar.On("Task", mock.Anything).Return(sidecarTask, nil).Maybe()
+	sidecar.On("Delete", mock.Anything).Return(nil).Maybe()
+	t.Cleanup(func() { sidecar.AssertExpectations(t) })
+	api.EXPECT().NewContainer(mock.Anything, "pumba-dns-proxy-c1",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(sidecar, nil)
+	return api, sidecar
+}
+
+func TestWithTargetResolvConf(t *testing.T) {
+	var spec oci.Spec
+	require.NoError(t, withTargetResolvConf(42)(context.Background(), nil, nil, &spec))
+	assert.Equal(t, []specs.Mount{{
+		Destination: "/etc/resolv.conf", Type: "bind", Source: "/proc/42/root/etc/resolv.conf", Options: []string{"rbind", "ro"},
+	}}, spec.Mounts, "the proxy forwards to the target's nameserver without --upstream")
+}
+
+func TestProxyContainer_DefaultUpstream(t *testing.T) {
+	setProxyStartGrace(t, 10*time.Millisecond)
+	targetTask := newRunningTaskWithPID(42)
+	setupExec(targetTask, newSuccessProcess())
+	api, _ := setupProxySidecar(t, targetTask, make(chan containerd.ExitStatus))
+
+	require.NoError(t, newTestClient(api).ProxyContainer(context.Background(), dnsProxyRequest()))
+	targetTask.AssertNumberOfCalls(t, "Exec", 1)
+}
+
+func TestProxyContainer_ExitedProxySkipsRedirects(t *testing.T) {
+	setProxyStartGrace(t, time.Minute)
+	exitCh := make(chan containerd.ExitStatus, 2)
+	exitCh <- *containerd.NewExitStatus(1, time.Now(), nil)
+	exitCh <- *containerd.NewExitStatus(1, time.Now(), nil)
+	targetTask := newRunningTaskWithPID(42)
+	api, sidecar := setupProxySidecar(t, targetTask, exitCh)
+
+	err := newTestClient(api).ProxyContainer(context.Background(), dnsProxyRequest())
+	require.EqualError(t, err, "failed to start proxy sidecar task: proxy exited at start with status 1")
+	targetTask.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	sidecar.AssertCalled(t, "Delete", mock.Anything)
+}
@@ -8,12 +8,14 @@ import (
 	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/errdefs"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -27,44 +29,90 @@ var networkCapabilities = []string{"CAP_NET_ADMIN", "CAP_NET_RAW"}
 // sidecarExec creates a short-lived sidecar container that shares the target
 // container's network namespace and runs the given command+args inside it.
 func (c *containerdClient) sidecarExec(ctx context.Context, target *ctr.Container, sidecarImage string, pull bool, command string, argsList [][]string) error {
+	_, err := c.sidecarOutput(ctx, target, sidecarImage, pull, command, argsList)
+	return err
+}
+
+// toolOutput runs command with each of argsList in the target's network
+// namespace, in a sidecar of sidecarImage or, without an image, in the target
+// itself, and returns the combined stdout.
+func (c *containerdClient) toolOutput(ctx context.Context, target *ctr.Container, sidecarImage string, pull bool, command string, argsList [][]string) (string, error) {
+	if sidecarImage != "" {
+		return c.sidecarOutput(ctx, target, sidecarImage, pull, command, argsList)
+	}
+	var out strings.Builder
+	for _, args := range argsList {
+		stdout, err := c.execInContainerOutput(c.nsCtx(ctx), target.ID(), command, args)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(stdout)
+	}
+	return out.String(), nil
+}
+
+// sidecarOutput is sidecarExec returning the combined stdout of all commands.
+func (c *containerdClient) sidecarOutput(ctx context.Context, target *ctr.Container, sidecarImage string, pull bool, command string, argsList [][]string) (string, error) {
+	return c.sidecarOutputIn(ctx, target, false, sidecarImage, pull, command, argsList)
+}
+
+// sidecarOutputIn is sidecarOutput, with sharePID also joining the target's
+// PID namespace, for sidecars that see and signal its processes.
+func (c *containerdClient) sidecarOutputIn(ctx context.Context, target *ctr.Container, sharePID bool, sidecarImage string, pull bool, command string, argsList [][]string) (_ string, err error) {
 	ctx = c.nsCtx(ctx)
+	attrs := []attribute.KeyValue{tracing.AttrTool.String(command), tracing.AttrImage.String(sidecarImage)}
+	if target != nil {
+		attrs = append(attrs, tracing.AttrContainerID.String(target.ID()))
+	}
+	ctx, span := tracing.Start(ctx, "sidecar", attrs...)
+	defer func() { tracing.End(span, err) }()
 
 	if pull {
 		if err := c.pullImage(ctx, sidecarImage); err != nil {
-			return fmt.Errorf("failed to pull sidecar image %s: %w", sidecarImage, err)
+			return "", fmt.Errorf("failed to pull sidecar image %s: %w", sidecarImage, err)
 		}
 	}
 
-	targetTask, err := c.getTask(ctx, target.ID())
-	if err != nil {
-		return fmt.Errorf("failed to get target task for sidecar: %w", err)
-	}
-	targetPID := targetTask.Pid()
-	if targetPID == 0 {
-		return fmt.Errorf("target task for %s has PID 0 (not running)", target.ID())
+	specOpts := []oci.SpecOpts{oci.WithProcessArgs("sleep", "infinity"), oci.WithCapabilities(networkCapabilities)}
+	// a nil target keeps the default new network namespace, for the doctor
+	// probes
+	var targetID string
+	if target != nil {
+		targetID = target.ID()
+		targetTask, err := c.getTask(ctx, targetID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get target task for sidecar: %w", err)
+		}
+		targetPID := targetTask.Pid()
+		if targetPID == 0 {
+			return "", fmt.Errorf("target task for %s has PID 0 (not running)", targetID)
+		}
+		specOpts = append(specOpts, oci.WithLinuxNamespace(specs.LinuxNamespace{
+			Type: specs.NetworkNamespace,
+			Path: fmt.Sprintf("/proc/%d/ns/net", targetPID),
+		}))
+		if sharePID {
+			specOpts = append(specOpts, oci.WithLinuxNamespace(specs.LinuxNamespace{
+				Type: specs.PIDNamespace,
+				Path: fmt.Sprintf("/proc/%d/ns/pid", targetPID),
+			}), oci.WithAddedCapabilities([]string{"CAP_KILL"}))
+		}
 	}
 
 	image, err := c.client.GetImage(ctx, sidecarImage)
 	if err != nil {
-		return fmt.Errorf("failed to get sidecar image %s: %w", sidecarImage, err)
+		return "", fmt.Errorf("failed to get sidecar image %s: %w", sidecarImage, err)
 	}
 
 	sidecarID := fmt.Sprintf("pumba-sidecar-%d", execCounter.Add(1))
 	sidecarContainer, err := c.client.NewContainer(ctx, sidecarID,
 		containerd.WithImage(image),
+		containerd.WithContainerLabels(ctr.SidecarLabels(command, targetID)),
 		containerd.WithNewSnapshot(sidecarID+"-snapshot", image),
-		containerd.WithNewSpec(
-			oci.WithImageConfig(image),
-			oci.WithProcessArgs("sleep", "infinity"),
-			oci.WithLinuxNamespace(specs.LinuxNamespace{
-				Type: specs.NetworkNamespace,
-				Path: fmt.Sprintf("/proc/%d/ns/net", targetPID),
-			}),
-			oci.WithCapabilities(networkCapabilities),
-		),
+		containerd.WithNewSpec(append([]oci.SpecOpts{oci.WithImageConfig(image)}, specOpts...)...),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create sidecar container: %w", err)
+		return "", fmt.Errorf("failed to create sidecar container: %w", err)
 	}
 
 	// Use context.WithoutCancel so cleanup succeeds even if the parent ctx is canceled.
@@ -79,23 +127,29 @@ func (c *containerdClient) sidecarExec(ctx context.Context, target *ctr.Containe
 
 	task, err := sidecarContainer.NewTask(ctx, cio.NullIO)
 	if err != nil {
-		return fmt.Errorf("failed to create sidecar task: %w", err)
+		return "", fmt.Errorf("failed to create sidecar task: %w", err)
 	}
 	if err := task.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start sidecar task: %w", err)
+		return "", fmt.Errorf("failed to start sidecar task: %w", err)
 	}
 
+	var output strings.Builder
 	for _, args := range argsList {
-		if err := c.runSidecarCmd(ctx, task, command, args); err != nil {
-			return err
+		out, err := c.runSidecarCmd(ctx, task, command, args)
+		if err != nil {
+			return "", err
 		}
+		output.WriteString(out)
 	}
 
-	return nil
+	return output.String(), nil
 }
 
-// runSidecarCmd executes a single command inside a running sidecar task.
-func (c *containerdClient) runSidecarCmd(ctx context.Context, task containerd.Task, command string, args []string) error {
+// runSidecarCmd executes a single command inside a running sidecar task and
+// returns its stdout.
+func (c *containerdClient) runSidecarCmd(ctx context.Context, task containerd.Task, command string, args []string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "sidecar.exec", tracing.AttrTool.String(command), tracing.AttrArgs.StringSlice(args))
+	defer func() { tracing.End(span, err) }()
 	cmdArgs := make([]string, 0, 1+len(args))
 	cmdArgs = append(cmdArgs, command)
 	cmdArgs = append(cmdArgs, args...)
@@ -113,17 +167,20 @@ func (c *containerdClient) runSidecarCmd(ctx context.Context, task containerd.Ta
 		},
 	}
 
-	if err := execTask(ctx, task, pspec, execID, fmt.Sprintf("sidecar exec '%s'", strings.Join(cmdArgs, " "))); err != nil {
-		return err
+	out, err := execTaskOutput(ctx, task, pspec, execID, fmt.Sprintf("sidecar exec '%s'", strings.Join(cmdArgs, " ")))
+	if err != nil {
+		return "", err
 	}
 	log.WithField("args", strings.Join(args, " ")).Debug("sidecar exec completed")
-	return nil
+	return out, nil
 }
 
 // pullImage pulls an image via containerd.
-func (c *containerdClient) pullImage(ctx context.Context, ref string) error {
+func (c *containerdClient) pullImage(ctx context.Context, ref string) (err error) {
+	ctx, span := tracing.Start(ctx, "image.pull", tracing.AttrImage.String(ref))
+	defer func() { tracing.End(span, err) }()
 	log.WithField("image", ref).Debug("pulling image via containerd")
-	_, err := c.client.Pull(ctx, ref, containerd.WithPullUnpack)
+	_, err = c.client.Pull(ctx, ref, containerd.WithPullUnpack)
 	if err != nil {
 		return fmt.Errorf("failed to pull image %s: %w", ref, err)
 	}
@@ -131,7 +188,9 @@ func (c *containerdClient) pullImage(ctx context.Context, ref string) error {
 }
 
 // cleanupSidecar kills the task and removes the sidecar container and its snapshot.
-func (c *containerdClient) cleanupSidecar(ctx context.Context, cntr containerd.Container) error {
+func (c *containerdClient) cleanupSidecar(ctx context.Context, cntr containerd.Container) (err error) {
+	ctx, span := tracing.Start(ctx, "sidecar.remove", tracing.AttrContainerID.String(cntr.ID()))
+	defer func() { tracing.End(span, err) }()
 	task, err := cntr.Task(ctx, nil)
 	if err != nil && !errdefs.IsNotFound(err) {
 		log.WithError(err).Warn("failed to get sidecar task for cleanup")
@@ -100,7 +100,7 @@ func (c *containerdClient) createStressSidecar(
 		containerd.WithImage(image),
 		containerd.WithNewSnapshot(sidecarID+"-snapshot", image),
 		containerd.WithNewSpec(specOpts...),
-		containerd.WithContainerLabels(map[string]string{"com.gaiaadm.pumba.skip": "true"}),
+		containerd.WithContainerLabels(ctr.SidecarLabels(ctr.SidecarKindStress, target.ID())),
 	)
 	if err != nil {
 		return "", nil, nil, nil, fmt.Errorf("failed to create stress sidecar: %w", err)
@@ -10,6 +10,7 @@ import (
 	"syscall"
 	"time"
 
+	ctr "github.com/alexei-led/pumba/pkg/container"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/errdefs"
@@ -164,47 +165,99 @@ func (c *containerdClient) resumeTask(ctx context.Context, containerID string) e
 // execTask runs a command inside a containerd task and waits for completion.
 // Handles the full exec lifecycle: create, wait, start, collect exit status, delete.
 func execTask(ctx context.Context, task containerd.Task, pspec *specs.Process, execID, description string) error {
+	_, err := execTaskOutput(ctx, task, pspec, execID, description)
+	return err
+}
+
+// execTaskOutput is execTask returning the command's stdout.
+func execTaskOutput(ctx context.Context, task containerd.Task, pspec *specs.Process, execID, description string) (string, error) {
+	result, err := execTaskResult(ctx, task, pspec, execID, description)
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("%s exited with code %d: %s", description, result.ExitCode, result.Stderr)
+	}
+	return result.Stdout, nil
+}
+
+// execTaskResult is execTask returning the command's output and exit code; a
+// non-zero exit is no error. When ctx ends first, the command is killed.
+func execTaskResult(ctx context.Context, task containerd.Task, pspec *specs.Process, execID, description string) (*ctr.ExecResult, error) {
 	var stdout, stderr bytes.Buffer
+	start := time.Now()
 	execProcess, err := task.Exec(ctx, execID, pspec, cio.NewCreator(
 		cio.WithStreams(nil, &stdout, &stderr),
 	))
 	if err != nil {
-		return fmt.Errorf("failed to exec %s: %w", description, err)
+		return nil, fmt.Errorf("failed to exec %s: %w", description, err)
 	}
 	defer func() {
-		if _, delErr := execProcess.Delete(ctx); delErr != nil {
+		// the exec must go even when ctx ended
+		if _, delErr := execProcess.Delete(context.WithoutCancel(ctx)); delErr != nil {
 			log.WithError(delErr).WithField("exec", description).Warn("failed to delete exec process")
 		}
 	}()
 
 	exitCh, err := execProcess.Wait(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to wait on %s: %w", description, err)
+		return nil, fmt.Errorf("failed to wait on %s: %w", description, err)
 	}
 
 	if err := execProcess.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start %s: %w", description, err)
+		return nil, fmt.Errorf("failed to start %s: %w", description, err)
 	}
 
 	select {
 	case status := <-exitCh:
 		code, _, err := status.Result()
 		if err != nil {
-			return fmt.Errorf("%s failed: %w", description, err)
+			return nil, fmt.Errorf("%s failed: %w", description, err)
 		}
-		if code != 0 {
-			return fmt.Errorf("%s exited with code %d: %s", description, code, stderr.String())
+		// wait for the output copy to finish before reading the output
+		if pio := execProcess.IO(); pio != nil {
+			pio.Wait()
 		}
-		return nil
+		return &ctr.ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: int(code), Duration: time.Since(start)}, nil
 	case <-ctx.Done():
-		return fmt.Errorf("%s canceled: %w", description, ctx.Err())
+		if killErr := execProcess.Kill(context.WithoutCancel(ctx), syscall.SIGKILL); killErr != nil {
+			log.WithError(killErr).WithField("exec", description).Warn("failed to kill canceled exec process")
+		} else {
+			<-exitCh
+		}
+		return nil, fmt.Errorf("%s canceled: %w", description, ctx.Err())
 	}
 }
 
 func (c *containerdClient) execInContainer(ctx context.Context, containerID, command string, args []string) error {
+	_, err := c.execInContainerOutput(ctx, containerID, command, args)
+	return err
+}
+
+// execInContainerOutput is execInContainer returning the command's stdout.
+func (c *containerdClient) execInContainerOutput(ctx context.Context, containerID, command string, args []string) (string, error) {
+	task, pspec, execID, err := c.execSpec(ctx, containerID, command, args)
+	if err != nil {
+		return "", err
+	}
+	return execTaskOutput(ctx, task, pspec, execID, fmt.Sprintf("exec in %s '%s'", containerID, strings.Join(pspec.Args, " ")))
+}
+
+// execInContainerResult is execInContainer returning the command's result.
+func (c *containerdClient) execInContainerResult(ctx context.Context, containerID, command string, args []string) (*ctr.ExecResult, error) {
+	task, pspec, execID, err := c.execSpec(ctx, containerID, command, args)
+	if err != nil {
+		return nil, err
+	}
+	return execTaskResult(ctx, task, pspec, execID, fmt.Sprintf("exec in %s '%s'", containerID, strings.Join(pspec.Args, " ")))
+}
+
+// execSpec returns the task of a container and the process spec running
+// command in it as root.
+func (c *containerdClient) execSpec(ctx context.Context, containerID, command string, args []string) (containerd.Task, *specs.Process, string, error) {
 	task, err := c.getTask(ctx, containerID)
 	if err != nil {
-		return err
+		return nil, nil, "", err
 	}
 
 	cmdArgs := make([]string, 0, 1+len(args))
@@ -217,6 +270,5 @@ func (c *containerdClient) execInContainer(ctx context.Context, containerID, com
 		Env:  []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
 		User: specs.User{UID: 0, GID: 0},
 	}
-
-	return execTask(ctx, task, pspec, execID, fmt.Sprintf("exec in %s '%s'", containerID, strings.Join(cmdArgs, " ")))
+	return task, pspec, execID, nil
 }
@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	cerrdefs "github.com/containerd/errdefs"
+	ctypes "github.com/docker/docker/api/types/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// captureStopTimeout is how long tcpdump gets to flush the pcap on SIGTERM
+// before the capture sidecar is killed.
+const captureStopTimeout = 5 * time.Second
+
+// withCapture starts the packet capture (if any) and then runs apply. The
+// capture is removed again if apply fails, so a failed injection does not
+// leave tcpdump running.
+func (client dockerClient) withCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec, dryRun bool, apply func() error) error {
+	if capture == nil || dryRun {
+		return apply()
+	}
+	if err := client.startCapture(ctx, target, capture); err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		if stopErr := client.stopCapture(ctx, target, capture); stopErr != nil {
+			log.WithError(stopErr).Warn("failed to stop packet capture")
+		}
+		return err
+	}
+	return nil
+}
+
+// stopWithCapture runs stop and then stops the packet capture (if any), so
+// the capture records the recovery. Both steps always run.
+func (client dockerClient) stopWithCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec, dryRun bool, stop func() error) error {
+	err := stop()
+	if capture == nil || dryRun {
+		return err
+	}
+	return errors.Join(err, client.stopCapture(ctx, target, capture))
+}
+
+// startCapture runs tcpdump in a sidecar joining the target's network
+// namespace, writing the pcap to capture.Dir on the host.
+func (client dockerClient) startCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec) error {
+	args := capture.Args(target, time.Now())
+	log.WithFields(log.Fields{
+		"id":   target.ID(),
+		"name": target.Name(),
+		"dir":  capture.Dir,
+		"img":  capture.Image,
+		"args": args,
+	}).Debug("starting packet capture")
+	if capture.Pull {
+		if err := client.pullSidecarImage(ctx, capture.Image, "capture"); err != nil {
+			return err
+		}
+	}
+	hconfig := ctypes.HostConfig{
+		CapAdd:      []string{"NET_ADMIN", "NET_RAW"},
+		NetworkMode: ctypes.NetworkMode("container:" + target.ID()),
+		Binds:       []string{capture.Dir + ":" + ctr.CaptureMountPath},
+	}
+	config := ctypes.Config{
+		Labels:     ctr.SidecarLabels(ctr.SidecarKindCapture, target.ID()),
+		Entrypoint: args[:1],
+		Cmd:        args[1:],
+		Image:      capture.Image,
+	}
+	name := capture.SidecarName(target)
+	createResponse, err := client.containerAPI.ContainerCreate(ctx, &config, &hconfig, nil, nil, name)
+	if err != nil {
+		return fmt.Errorf("failed to create capture-container from image %q: %w", capture.Image, err)
+	}
+	if err = client.containerAPI.ContainerStart(ctx, createResponse.ID, ctypes.StartOptions{}); err != nil {
+		_ = client.removeSidecar(ctx, createResponse.ID)
+		return fmt.Errorf("failed to start capture-container: %w", err)
+	}
+	return nil
+}
+
+// stopCapture stops tcpdump gracefully, so the last pcap is flushed, and
+// removes the capture sidecar. A capture that is already gone is not an error.
+func (client dockerClient) stopCapture(ctx context.Context, target *ctr.Container, capture *ctr.CaptureSpec) error {
+	name := capture.SidecarName(target)
+	log.WithFields(log.Fields{"id": target.ID(), "capture": name}).Debug("stopping packet capture")
+	timeout := int(captureStopTimeout.Seconds())
+	if err := client.containerAPI.ContainerStop(ctx, name, ctypes.StopOptions{Timeout: &timeout}); err != nil && !cerrdefs.IsNotFound(err) {
+		log.WithError(err).WithField("capture", name).Warn("failed to stop capture-container, removing it")
+	}
+	if err := client.removeSidecar(ctx, name); err != nil {
+		return fmt.Errorf("failed to remove capture-container: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexei-led/pumba/mocks"
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	cerrdefs "github.com/containerd/errdefs"
+	ctypes "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCaptureNetemRequest() *ctr.NetemRequest {
+	return &ctr.NetemRequest{
+		Container: &ctr.Container{ContainerID: "abc123", ContainerName: "/web"},
+		Interface: "eth0",
+		Command:   []string{"delay", "500ms"},
+		Capture:   &ctr.CaptureSpec{Dir: "/tmp/pcap", Interface: "eth0", Filter: "port 80", Image: "nettools"},
+	}
+}
+
+func expectCaptureStart(api *mocks.APIClient, ctx context.Context) {
+	api.EXPECT().ContainerCreate(ctx, mock.MatchedBy(func(c *ctypes.Config) bool {
+		return c.Image == "nettools" && assert.ObjectsAreEqual([]string{"tcpdump"}, []string(c.Entrypoint)) &&
+			c.Cmd[len(c.Cmd)-1] == "port 80" && c.Labels["com.gaiaadm.pumba.skip"] == "true"
+	}), mock.MatchedBy(func(h *ctypes.HostConfig) bool {
+		return h.NetworkMode == "container:abc123" && assert.ObjectsAreEqual([]string{"/tmp/pcap:/capture"}, h.Binds)
+	}), mock.Anything, mock.Anything, "pumba-capture-abc123").Return(ctypes.CreateResponse{ID: "captureID"}, nil)
+	api.EXPECT().ContainerStart(ctx, "captureID", ctypes.StartOptions{}).Return(nil)
+}
+
+func expectCaptureStop(api *mocks.APIClient, ctx context.Context) {
+	api.EXPECT().ContainerStop(ctx, "pumba-capture-abc123", mock.Anything).Return(nil)
+	api.EXPECT().ContainerRemove(mock.Anything, "pumba-capture-abc123", ctypes.RemoveOptions{Force: true}).Return(nil)
+}
+
+func TestNetemContainer_StartsCaptureFirst(t *testing.T) {
+	ctx := context.TODO()
+	api := NewMockEngine(t)
+	expectCaptureStart(api, ctx)
+	expectExec(api, ctx, "abc123", "netem", []string{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "delay", "500ms"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.NetemContainer(ctx, testCaptureNetemRequest()))
+}
+
+func TestNetemContainer_FailedInjectionStopsCapture(t *testing.T) {
+	ctx := context.TODO()
+	api := NewMockEngine(t)
+	expectCaptureStart(api, ctx)
+	expectExec(api, ctx, "abc123", "netem", []string{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "delay", "500ms"}, 1)
+	expectCaptureStop(api, ctx)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	assert.Error(t, client.NetemContainer(ctx, testCaptureNetemRequest()))
+}
+
+func TestStopNetemContainer_StopsCaptureAfterNetem(t *testing.T) {
+	ctx := context.TODO()
+	api := NewMockEngine(t)
+	expectExec(api, ctx, "abc123", "netem", []string{"tc", "qdisc", "del", "dev", "eth0", "root", "netem"}, 0)
+	expectCaptureStop(api, ctx)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.StopNetemContainer(ctx, testCaptureNetemRequest()))
+}
+
+func TestStopIPTablesContainer_CaptureAlreadyGone(t *testing.T) {
+	ctx := context.TODO()
+	api := NewMockEngine(t)
+	expectExec(api, ctx, "abc123", "rule", []string{"iptables", "-D", "INPUT", "-i", "eth0", "-j", "DROP"}, 0)
+	api.EXPECT().ContainerStop(ctx, "pumba-capture-abc123", mock.Anything).Return(cerrdefs.ErrNotFound)
+	api.EXPECT().ContainerRemove(mock.Anything, "pumba-capture-abc123", ctypes.RemoveOptions{Force: true}).Return(cerrdefs.ErrNotFound)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.StopIPTablesContainer(ctx, &ctr.IPTablesRequest{
+		Container: &ctr.Container{ContainerID: "abc123", ContainerName: "/web"},
+		CmdPrefix: []string{"-D", "INPUT", "-i", "eth0"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Capture:   &ctr.CaptureSpec{Dir: "/tmp/pcap", Interface: "eth0", Image: "nettools"},
+	}))
+}
+
+func TestNetemContainer_DryRunSkipsCapture(t *testing.T) {
+	req := testCaptureNetemRequest()
+	req.DryRun = true
+	client := dockerClient{containerAPI: NewMockEngine(t)}
+	require.NoError(t, client.NetemContainer(context.TODO(), req))
+	require.NoError(t, client.StopNetemContainer(context.TODO(), req))
+}
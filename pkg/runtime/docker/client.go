@@ -39,13 +39,15 @@ func NewFromAPI(api *dockerapi.Client) (ctr.Client, error) {
 	if api == nil {
 		return nil, errors.New("docker: api client must not be nil")
 	}
-	return dockerClient{containerAPI: api, imageAPI: api, systemAPI: api}, nil
+	return dockerClient{containerAPI: api, imageAPI: api, systemAPI: api, networkAPI: api, images: newImageCache()}, nil
 }
 
 type dockerClient struct {
 	containerAPI dockerapi.ContainerAPIClient
 	imageAPI     dockerapi.ImageAPIClient
 	systemAPI    dockerapi.SystemAPIClient
+	networkAPI   dockerapi.NetworkAPIClient
+	images       *imageCache
 }
 
 // Close is a no-op for the Docker client; the underlying HTTP connections are managed by the SDK.
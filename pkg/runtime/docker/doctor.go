@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
+	"github.com/docker/docker/api/types/system"
+)
+
+// rootlessMarker is the substring the daemon publishes in /info
+// SecurityOptions when it runs rootless.
+const rootlessMarker = "name=rootless"
+
+// Diagnose runs the `pumba doctor` checks: daemon connectivity and version,
+// cgroup driver and version, rootless mode, sidecar images and the
+// capability and kernel module probes. Everything else needs a reachable
+// daemon, so a failed /info call is the only check reported.
+func (client dockerClient) Diagnose(ctx context.Context, req *ctr.DiagnoseRequest) []ctr.Check {
+	info, err := client.systemAPI.Info(ctx)
+	if err != nil {
+		return []ctr.Check{preflight.Fail(preflight.CheckRuntime, err.Error(),
+			"check that the daemon is running and that --host points at its API socket and is readable by Pumba")}
+	}
+	checks := []ctr.Check{
+		preflight.Pass(preflight.CheckRuntime,
+			fmt.Sprintf("server %s on %s, kernel %s", info.ServerVersion, info.OperatingSystem, info.KernelVersion)),
+		preflight.Cgroup(info.CgroupDriver, info.CgroupVersion),
+		rootlessCheck(&info),
+	}
+	return append(checks, preflight.Sidecar(ctx, req, client.ensureImage, client.probe)...)
+}
+
+// rootlessCheck warns about a rootless daemon: its sidecars only get
+// NET_ADMIN over namespaces owned by the daemon's user namespace.
+func rootlessCheck(info *system.Info) ctr.Check {
+	if slices.ContainsFunc(info.SecurityOptions, func(opt string) bool { return strings.Contains(opt, rootlessMarker) }) {
+		return preflight.Warn(preflight.CheckRootless, "daemon runs rootless",
+			"netem, iptables, link and stress may fail on containers outside the daemon's user namespace; use a rootful daemon for full coverage")
+	}
+	return preflight.Pass(preflight.CheckRootless, "daemon runs rootful")
+}
+
+// ensureImage pulls img or, without pull, looks it up locally.
+func (client dockerClient) ensureImage(ctx context.Context, img string, pull bool) error {
+	if pull {
+		return client.pullSidecarImage(ctx, img, "sidecar")
+	}
+	_, err := client.imageAPI.ImageInspect(ctx, img)
+	return err
+}
+
+// probe runs tool with each of argsList in a sidecar of img with a network
+// namespace of its own.
+func (client dockerClient) probe(ctx context.Context, img, tool string, argsList [][]string) error {
+	_, err := client.runSidecarOutput(ctx, nil, argsList, img, tool, false)
+	return err
+}
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
+	"github.com/docker/docker/api/types"
+	ctypes "github.com/docker/docker/api/types/container"
+	imagetypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiagnose_InfoFailure(t *testing.T) {
+	api := NewMockEngine(t)
+	api.EXPECT().Info(mock.Anything).Return(system.Info{}, errors.New("connection refused")).Once()
+
+	client := dockerClient{containerAPI: api, imageAPI: api, systemAPI: api}
+	checks := client.Diagnose(context.TODO(), &ctr.DiagnoseRequest{Sidecar: "nettools"})
+	assert.Len(t, checks, 1)
+	assert.Equal(t, preflight.CheckRuntime, checks[0].Name)
+	assert.Equal(t, ctr.CheckFail, checks[0].Status)
+	assert.Contains(t, checks[0].Hint, "--host")
+}
+
+func TestDiagnose(t *testing.T) {
+	api := NewMockEngine(t)
+	api.EXPECT().Info(mock.Anything).Return(system.Info{
+		ServerVersion: "27.3.1", OperatingSystem: "Ubuntu 24.04", KernelVersion: "6.8.0",
+		CgroupDriver: "systemd", CgroupVersion: "2",
+		SecurityOptions: []string{"name=seccomp,profile=builtin", "name=rootless"},
+	}, nil).Once()
+	api.EXPECT().ImageInspect(mock.Anything, "nettools").Return(imagetypes.InspectResponse{}, nil).Once()
+	api.EXPECT().ImageInspect(mock.Anything, "stress-ng").Return(imagetypes.InspectResponse{}, errors.New("No such image")).Once()
+
+	// every probe runs in its own sidecar without a target network namespace
+	api.EXPECT().ContainerCreate(mock.Anything, mock.MatchedBy(func(c *ctypes.Config) bool {
+		return c.Image == "nettools" && c.Labels["com.gaiaadm.pumba.target"] == ""
+	}), mock.MatchedBy(func(h *ctypes.HostConfig) bool {
+		return h.NetworkMode == "none" && h.CapAdd[0] == "NET_ADMIN"
+	}), mock.Anything, mock.Anything, "").Return(ctypes.CreateResponse{ID: "probe"}, nil).Times(len(preflight.Probes))
+	api.EXPECT().ContainerStart(mock.Anything, "probe", ctypes.StartOptions{}).Return(nil).Times(len(preflight.Probes))
+	api.EXPECT().ContainerExecCreate(mock.Anything, "probe", mock.Anything).
+		RunAndReturn(func(_ context.Context, _ string, opts ctypes.ExecOptions) (ctypes.ExecCreateResponse, error) {
+			return ctypes.ExecCreateResponse{ID: opts.Cmd[0]}, nil
+		})
+	api.EXPECT().ContainerExecAttach(mock.Anything, mock.Anything, ctypes.ExecAttachOptions{}).
+		RunAndReturn(func(context.Context, string, ctypes.ExecAttachOptions) (types.HijackedResponse, error) {
+			return fakeExecAttach(), nil
+		})
+	api.EXPECT().ContainerExecInspect(mock.Anything, "tc").Return(ctypes.ExecInspect{ExitCode: 2}, nil).Once()
+	api.EXPECT().ContainerExecInspect(mock.Anything, mock.Anything).Return(ctypes.ExecInspect{}, nil)
+	api.EXPECT().ContainerRemove(mock.Anything, "probe", ctypes.RemoveOptions{Force: true}).Return(nil).Times(len(preflight.Probes))
+
+	client := dockerClient{containerAPI: api, imageAPI: api, systemAPI: api}
+	checks := client.Diagnose(context.TODO(), &ctr.DiagnoseRequest{Sidecar: "nettools", Images: []string{"stress-ng"}})
+
+	statuses := map[string][]string{}
+	for _, c := range checks {
+		statuses[c.Name] = append(statuses[c.Name], c.Status)
+	}
+	assert.Equal(t, map[string][]string{
+		preflight.CheckRuntime:      {ctr.CheckPass},
+		preflight.CheckCgroup:       {ctr.CheckPass},
+		preflight.CheckRootless:     {ctr.CheckWarn},
+		preflight.CheckImage:        {ctr.CheckPass, ctr.CheckFail},
+		preflight.CheckCapabilities: {ctr.CheckPass},
+		preflight.CheckNetem:        {ctr.CheckFail},
+		preflight.CheckIPTables:     {ctr.CheckPass},
+		preflight.CheckNFTables:     {ctr.CheckPass},
+	}, statuses)
+	assert.Equal(t, "server 27.3.1 on Ubuntu 24.04, kernel 6.8.0", checks[0].Detail)
+	assert.Equal(t, "systemd driver, cgroup v2", checks[1].Detail)
+}
@@ -0,0 +1,95 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// errEventStreamClosed is sent when the daemon ends the event stream.
+var errEventStreamClosed = errors.New("event stream closed")
+
+// WatchContainers streams the container events of the /events endpoint, and
+// the network attach changes as updates of the attached container.
+func (client dockerClient) WatchContainers(ctx context.Context) (<-chan ctr.Event, <-chan error) {
+	msgs, msgErrs := client.systemAPI.Events(ctx, events.ListOptions{Filters: filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("type", string(events.NetworkEventType)),
+	)})
+	out := make(chan ctr.Event)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-msgErrs:
+				if ctx.Err() == nil {
+					errs <- fmt.Errorf("failed to watch docker events: %w", err)
+				}
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					errs <- errEventStreamClosed
+					return
+				}
+				e, ok := toEvent(msg)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, errs
+}
+
+// toEvent converts a docker event message, reporting false for the messages
+// that do not change a container.
+func toEvent(msg events.Message) (ctr.Event, bool) {
+	e := ctr.Event{ContainerID: msg.Actor.ID, Time: time.Unix(0, msg.TimeNano)}
+	if msg.Type == events.NetworkEventType {
+		if msg.Action != events.ActionConnect && msg.Action != events.ActionDisconnect {
+			return e, false
+		}
+		e.Kind, e.ContainerID = ctr.EventUpdate, msg.Actor.Attributes["container"]
+		return e, e.ContainerID != ""
+	}
+	switch msg.Action {
+	case events.ActionCreate:
+		e.Kind = ctr.EventCreate
+	case events.ActionStart:
+		e.Kind = ctr.EventStart
+	case events.ActionHealthStatusHealthy:
+		e.Kind = ctr.EventHealthy
+	case events.ActionHealthStatus:
+		// podman's compat API reports the status as an attribute
+		if msg.Actor.Attributes["health_status"] != "healthy" {
+			return e, false
+		}
+		e.Kind = ctr.EventHealthy
+	case events.ActionDie:
+		e.Kind = ctr.EventDie
+	case events.ActionPause:
+		e.Kind = ctr.EventPause
+	case events.ActionUnPause:
+		e.Kind = ctr.EventUnpause
+	case events.ActionRename, events.ActionUpdate:
+		e.Kind = ctr.EventUpdate
+	case events.ActionDestroy:
+		e.Kind = ctr.EventDestroy
+	default:
+		return e, false
+	}
+	return e, true
+}
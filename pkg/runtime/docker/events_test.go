@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchContainers(t *testing.T) {
+	// like the SDK: unbuffered messages, then an error once the stream fails
+	msgs := make(chan events.Message)
+	msgErrs := make(chan error, 1)
+	api := NewMockEngine(t)
+	api.EXPECT().Events(mock.Anything, mock.Anything).Return(msgs, msgErrs).Once()
+	go func() {
+		msgs <- events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: events.Actor{ID: "a1"}}
+		msgs <- events.Message{Type: events.ContainerEventType, Action: events.ActionExecStart, Actor: events.Actor{ID: "a1"}}
+		msgs <- events.Message{Type: events.NetworkEventType, Action: events.ActionDisconnect,
+			Actor: events.Actor{ID: "net1", Attributes: map[string]string{"container": "a1"}}}
+		msgErrs <- errors.New("daemon restarting")
+	}()
+
+	client := dockerClient{systemAPI: api}
+	out, errs := client.WatchContainers(context.TODO())
+	var got []ctr.Event
+	for e := range out {
+		got = append(got, e)
+	}
+	require.Len(t, got, 2, "exec events do not change containers")
+	assert.Equal(t, ctr.EventDie, got[0].Kind)
+	assert.Equal(t, "a1", got[0].ContainerID)
+	assert.Equal(t, ctr.EventUpdate, got[1].Kind, "network attach changes update the container")
+	assert.Equal(t, "a1", got[1].ContainerID)
+	require.EqualError(t, <-errs, "failed to watch docker events: daemon restarting")
+}
+
+func TestWatchContainers_Canceled(t *testing.T) {
+	api := NewMockEngine(t)
+	api.EXPECT().Events(mock.Anything, mock.Anything).Return(make(chan events.Message), make(chan error)).Once()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	out, errs := dockerClient{systemAPI: api}.WatchContainers(ctx)
+	cancel()
+	_, ok := <-out
+	assert.False(t, ok)
+	assert.Empty(t, errs)
+}
+
+func TestToEvent_Healthy(t *testing.T) {
+	e, ok := toEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatusHealthy, Actor: events.Actor{ID: "a1"}})
+	require.True(t, ok)
+	assert.Equal(t, ctr.EventHealthy, e.Kind)
+
+	// podman reports the status as an attribute
+	e, ok = toEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatus,
+		Actor: events.Actor{ID: "a1", Attributes: map[string]string{"health_status": "healthy"}}})
+	require.True(t, ok)
+	assert.Equal(t, ctr.EventHealthy, e.Kind)
+
+	_, ok = toEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatusUnhealthy, Actor: events.Actor{ID: "a1"}})
+	assert.False(t, ok)
+}
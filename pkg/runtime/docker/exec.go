@@ -1,18 +1,28 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"math"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
 	ctypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	log "github.com/sirupsen/logrus"
 )
 
-// ExecContainer executes a command in a container
-func (client dockerClient) ExecContainer(ctx context.Context, c *ctr.Container, command string, args []string, dryrun bool) error {
+// ExecContainer executes a command in a container. Docker cannot stop an exec:
+// when ctx ends first, the result is abandoned. With a ctr.WithExecTimeout
+// the command runs under `timeout -s KILL` in the container, so it does not
+// run on after the timeout; the image needs `timeout` (busybox, coreutils).
+func (client dockerClient) ExecContainer(ctx context.Context, c *ctr.Container, command string, args []string, dryrun bool) (*ctr.ExecResult, error) {
 	log.WithFields(log.Fields{
 		"name":    c.Name(),
 		"id":      c.ID(),
@@ -20,46 +30,99 @@ func (client dockerClient) ExecContainer(ctx context.Context, c *ctr.Container,
 		"dryrun":  dryrun,
 	}).Info("exec container")
 	if dryrun {
-		return nil
+		return nil, nil
+	}
+	start := time.Now()
+	cmd := append([]string{command}, args...)
+	if timeout, ok := ctr.ExecTimeout(ctx); ok {
+		cmd = killAfter(timeout, cmd)
 	}
 	createRes, err := client.containerAPI.ContainerExecCreate(
 		ctx, c.ID(), ctypes.ExecOptions{
 			User:         "root",
 			AttachStdout: true,
 			AttachStderr: true,
-			Cmd:          append([]string{command}, args...),
+			Cmd:          cmd,
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("exec create failed: %w", err)
+		return nil, fmt.Errorf("exec create failed: %w", err)
 	}
 
 	attachRes, err := client.containerAPI.ContainerExecAttach(
 		ctx, createRes.ID, ctypes.ExecAttachOptions{},
 	)
 	if err != nil {
-		return fmt.Errorf("exec attach failed: %w", err)
+		return nil, fmt.Errorf("exec attach failed: %w", err)
 	}
 	defer attachRes.Close()
 
-	output, err := io.ReadAll(attachRes.Reader)
+	var stdout, stderr bytes.Buffer
+	copied := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(&stdout, &stderr, attachRes.Reader)
+		copied <- err
+	}()
+	select {
+	case err = <-copied:
+	case <-ctx.Done():
+		// unblock the copy; its buffers are not read after this
+		attachRes.Close()
+		return nil, fmt.Errorf("exec %s: %w", command, ctx.Err())
+	}
 	if err != nil {
-		return fmt.Errorf("reading output from exec reader failed: %w", err)
+		return nil, fmt.Errorf("reading output from exec reader failed: %w", err)
 	}
-	log.WithFields(log.Fields{
-		"name":    c.Name(),
-		"id":      c.ID(),
-		"command": command,
-		"args":    args,
-		"dryrun":  dryrun,
-	}).Info(string(output))
-
 	res, err := client.containerAPI.ContainerExecInspect(ctx, createRes.ID)
 	if err != nil {
-		return fmt.Errorf("exec inspect failed: %w", err)
+		return nil, fmt.Errorf("exec inspect failed: %w", err)
+	}
+	result := &ctr.ExecResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: res.ExitCode, Duration: time.Since(start)}
+	log.WithFields(log.Fields{
+		"name":      c.Name(),
+		"id":        c.ID(),
+		"command":   command,
+		"args":      args,
+		"exit-code": result.ExitCode,
+		"duration":  result.Duration,
+	}).Info(strings.TrimSpace(result.Stdout + result.Stderr))
+	return result, nil
+}
+
+// killAfter wraps cmd to be killed after d, rounded up to whole seconds as
+// every `timeout` implementation accepts them.
+func killAfter(d time.Duration, cmd []string) []string {
+	secs := max(int64(math.Ceil(d.Seconds())), 1)
+	return append([]string{"timeout", "-s", "KILL", strconv.FormatInt(secs, 10)}, cmd...)
+}
+
+// CopyToContainer writes a file into a container through the archive API,
+// which Podman's compat API serves as well.
+func (client dockerClient) CopyToContainer(ctx context.Context, req *ctr.CopyRequest) error {
+	log.WithFields(log.Fields{
+		"name":   req.Container.Name(),
+		"id":     req.Container.ID(),
+		"path":   req.Path,
+		"size":   len(req.Content),
+		"dryrun": req.DryRun,
+	}).Debug("copy file to container")
+	if req.DryRun {
+		return nil
+	}
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	hdr := &tar.Header{Name: path.Base(req.Path), Mode: int64(req.Mode.Perm()), Size: int64(len(req.Content)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", req.Path, err)
+	}
+	if _, err := tw.Write(req.Content); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", req.Path, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", req.Path, err)
 	}
-	if res.ExitCode != 0 {
-		return fmt.Errorf("exec failed %s: exit code %d", command, res.ExitCode)
+	if err := client.containerAPI.CopyToContainer(ctx, req.Container.ID(), path.Dir(req.Path), &archive, ctypes.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy %s to container: %w", req.Path, err)
 	}
 	return nil
 }
@@ -80,8 +143,29 @@ func (client dockerClient) runExecAttached(ctx context.Context, execID string) e
 	return nil
 }
 
+// execAttachedOutput is runExecAttached returning the exec's stdout; the
+// attached stream is demultiplexed and stderr is discarded.
+func (client dockerClient) execAttachedOutput(ctx context.Context, execID string) ([]byte, error) {
+	resp, err := client.containerAPI.ContainerExecAttach(ctx, execID, ctypes.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, io.Discard, resp.Reader); err != nil {
+		return nil, fmt.Errorf("read exec %s output: %w", execID, err)
+	}
+	return stdout.Bytes(), nil
+}
+
 // execute command on container
 func (client dockerClient) execOnContainer(ctx context.Context, c *ctr.Container, execCmd string, execArgs []string, privileged bool) error {
+	_, err := client.execOnContainerOutput(ctx, c, execCmd, execArgs, privileged)
+	return err
+}
+
+// execOnContainerOutput is execOnContainer returning the command's stdout.
+func (client dockerClient) execOnContainerOutput(ctx context.Context, c *ctr.Container, execCmd string, execArgs []string, privileged bool) (string, error) {
 	log.WithFields(log.Fields{
 		"id":         c.ID(),
 		"name":       c.Name(),
@@ -100,18 +184,18 @@ func (client dockerClient) execOnContainer(ctx context.Context, c *ctr.Container
 	}
 	exec, err := client.containerAPI.ContainerExecCreate(ctx, c.ID(), checkExists)
 	if err != nil {
-		return fmt.Errorf("failed to create exec configuration to check if command exists: %w", err)
+		return "", fmt.Errorf("failed to create exec configuration to check if command exists: %w", err)
 	}
 	log.WithField("command", execCmd).Debugf("checking if command exists")
 	if err = client.runExecAttached(ctx, exec.ID); err != nil {
-		return fmt.Errorf("failed to check if command exists in a container: %w", err)
+		return "", fmt.Errorf("failed to check if command exists in a container: %w", err)
 	}
 	checkInspect, err := client.containerAPI.ContainerExecInspect(ctx, exec.ID)
 	if err != nil {
-		return fmt.Errorf("failed to inspect check execution: %w", err)
+		return "", fmt.Errorf("failed to inspect check execution: %w", err)
 	}
 	if checkInspect.ExitCode != 0 {
-		return fmt.Errorf("command '%s' not found inside the %s container", execCmd, c.ID())
+		return "", fmt.Errorf("command '%s' not found inside the %s container", execCmd, c.ID())
 	}
 
 	// if command found execute it
@@ -127,18 +211,19 @@ func (client dockerClient) execOnContainer(ctx context.Context, c *ctr.Container
 	// execute the command
 	exec, err = client.containerAPI.ContainerExecCreate(ctx, c.ID(), config)
 	if err != nil {
-		return fmt.Errorf("failed to create exec configuration for a command: %w", err)
+		return "", fmt.Errorf("failed to create exec configuration for a command: %w", err)
 	}
 	log.Debugf("starting exec %s %s (%s)", execCmd, execArgs, exec.ID)
-	if err = client.runExecAttached(ctx, exec.ID); err != nil {
-		return fmt.Errorf("failed to start command execution: %w", err)
+	output, err := client.execAttachedOutput(ctx, exec.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to start command execution: %w", err)
 	}
 	exitInspect, err := client.containerAPI.ContainerExecInspect(ctx, exec.ID)
 	if err != nil {
-		return fmt.Errorf("failed to inspect command execution: %w", err)
+		return "", fmt.Errorf("failed to inspect command execution: %w", err)
 	}
 	if exitInspect.ExitCode != 0 {
-		return fmt.Errorf("command '%s' failed in %s container; run it in manually to debug", execCmd, c.ID())
+		return "", fmt.Errorf("command '%s' failed in %s container; run it in manually to debug", execCmd, c.ID())
 	}
-	return nil
+	return string(output), nil
 }
@@ -1,16 +1,21 @@
 package docker
 
 import (
+	"archive/tar"
 	"bufio"
 	"context"
 	"errors"
-	"strings"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/alexei-led/pumba/mocks"
 	ctr "github.com/alexei-led/pumba/pkg/container"
 	"github.com/docker/docker/api/types"
 	ctypes "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_dockerClient_execOnContainer(t *testing.T) {
@@ -189,6 +194,7 @@ func TestExecContainer(t *testing.T) {
 		name    string
 		args    args
 		mockSet func(*mocks.APIClient, context.Context, *ctr.Container, string, []string, bool)
+		want    *ctr.ExecResult
 		wantErr bool
 	}{
 		{
@@ -221,17 +227,10 @@ func TestExecContainer(t *testing.T) {
 					AttachStderr: true,
 					Cmd:          cmdWithArgs,
 				}).Return(ctypes.ExecCreateResponse{ID: "execID"}, nil)
-				mockReader := strings.NewReader("hello\n")
-				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(func() types.HijackedResponse {
-					conn := &mockConn{}
-					conn.On("Close").Return(nil)
-					return types.HijackedResponse{
-						Conn:   conn,
-						Reader: bufio.NewReader(mockReader),
-					}
-				}(), nil)
+				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput("hello\n", ""), nil)
 				api.EXPECT().ContainerExecInspect(ctx, "execID").Return(ctypes.ExecInspect{ExitCode: 0}, nil)
 			},
+			want:    &ctr.ExecResult{Stdout: "hello\n"},
 			wantErr: false,
 		},
 		{
@@ -251,17 +250,10 @@ func TestExecContainer(t *testing.T) {
 					AttachStderr: true,
 					Cmd:          cmdWithArgs,
 				}).Return(ctypes.ExecCreateResponse{ID: "execID"}, nil)
-				mockReader := strings.NewReader("total 0\n")
-				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(func() types.HijackedResponse {
-					conn := &mockConn{}
-					conn.On("Close").Return(nil)
-					return types.HijackedResponse{
-						Conn:   conn,
-						Reader: bufio.NewReader(mockReader),
-					}
-				}(), nil)
+				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput("total 0\n", ""), nil)
 				api.EXPECT().ContainerExecInspect(ctx, "execID").Return(ctypes.ExecInspect{ExitCode: 0}, nil)
 			},
+			want:    &ctr.ExecResult{Stdout: "total 0\n"},
 			wantErr: false,
 		},
 		{
@@ -280,17 +272,10 @@ func TestExecContainer(t *testing.T) {
 					AttachStderr: true,
 					Cmd:          []string{command},
 				}).Return(ctypes.ExecCreateResponse{ID: "execID"}, nil)
-				mockReader := strings.NewReader("/\n")
-				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(func() types.HijackedResponse {
-					conn := &mockConn{}
-					conn.On("Close").Return(nil)
-					return types.HijackedResponse{
-						Conn:   conn,
-						Reader: bufio.NewReader(mockReader),
-					}
-				}(), nil)
+				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput("/\n", ""), nil)
 				api.EXPECT().ContainerExecInspect(ctx, "execID").Return(ctypes.ExecInspect{ExitCode: 0}, nil)
 			},
+			want:    &ctr.ExecResult{Stdout: "/\n"},
 			wantErr: false,
 		},
 		{
@@ -310,18 +295,11 @@ func TestExecContainer(t *testing.T) {
 					AttachStderr: true,
 					Cmd:          cmdWithArgs,
 				}).Return(ctypes.ExecCreateResponse{ID: "execID"}, nil)
-				mockReader := strings.NewReader("ls: /nonexistent: No such file or directory\n")
-				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(func() types.HijackedResponse {
-					conn := &mockConn{}
-					conn.On("Close").Return(nil)
-					return types.HijackedResponse{
-						Conn:   conn,
-						Reader: bufio.NewReader(mockReader),
-					}
-				}(), nil)
+				api.EXPECT().ContainerExecAttach(ctx, "execID", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput("", "ls: /nonexistent: No such file or directory\n"), nil)
 				api.EXPECT().ContainerExecInspect(ctx, "execID").Return(ctypes.ExecInspect{ExitCode: 1}, nil)
 			},
-			wantErr: true,
+			want:    &ctr.ExecResult{Stderr: "ls: /nonexistent: No such file or directory\n", ExitCode: 1},
+			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
@@ -330,12 +308,81 @@ func TestExecContainer(t *testing.T) {
 			tt.mockSet(api, tt.args.ctx, tt.args.c, tt.args.command, tt.args.execArgs, tt.args.dryrun)
 
 			client := dockerClient{containerAPI: api, imageAPI: api}
-			err := client.ExecContainer(tt.args.ctx, tt.args.c, tt.args.command, tt.args.execArgs, tt.args.dryrun)
+			got, err := client.ExecContainer(tt.args.ctx, tt.args.c, tt.args.command, tt.args.execArgs, tt.args.dryrun)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("dockerClient.ExecContainer() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if got != nil {
+				got.Duration = 0
+			}
+			assert.Equal(t, tt.want, got)
 			api.AssertExpectations(t)
 		})
 	}
 }
+
+func TestExecContainer_Canceled(t *testing.T) {
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	// the command is killed in the container once the deadline passes, as
+	// Docker cannot stop the exec
+	api.EXPECT().ContainerExecCreate(mock.Anything, c.ID(), ctypes.ExecOptions{
+		User: "root", AttachStdout: true, AttachStderr: true, Cmd: []string{"timeout", "-s", "KILL", "1", "sleep", "60"},
+	}).Return(ctypes.ExecCreateResponse{ID: "execID"}, nil)
+	// the command never ends: its output stays open
+	pr, pw := io.Pipe()
+	t.Cleanup(func() { pw.Close() })
+	resp := fakeExecAttach()
+	resp.Reader = bufio.NewReader(pr)
+	api.EXPECT().ContainerExecAttach(mock.Anything, "execID", ctypes.ExecAttachOptions{}).Return(resp, nil)
+
+	ctx, cancel := context.WithTimeout(ctr.WithExecTimeout(context.TODO(), 10*time.Millisecond), 10*time.Millisecond)
+	defer cancel()
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	_, err := client.ExecContainer(ctx, c, "sleep", []string{"60"}, false)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestKillAfter(t *testing.T) {
+	assert.Equal(t, []string{"timeout", "-s", "KILL", "1", "sh"}, killAfter(10*time.Millisecond, []string{"sh"}))
+	assert.Equal(t, []string{"timeout", "-s", "KILL", "3", "curl", "-f"}, killAfter(2500*time.Millisecond, []string{"curl", "-f"}))
+	assert.Equal(t, []string{"timeout", "-s", "KILL", "1", "true"}, killAfter(-time.Second, []string{"true"}), "a passed deadline still kills")
+}
+
+func TestCopyToContainer(t *testing.T) {
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	api.EXPECT().CopyToContainer(mock.Anything, c.ID(), "/tmp", mock.Anything, ctypes.CopyToContainerOptions{}).
+		RunAndReturn(func(_ context.Context, _, _ string, content io.Reader, _ ctypes.CopyToContainerOptions) error {
+			tr := tar.NewReader(content)
+			hdr, err := tr.Next()
+			require.NoError(t, err)
+			assert.Equal(t, "fault.sh", hdr.Name)
+			assert.Equal(t, int64(0o700), hdr.Mode)
+			data, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			assert.Equal(t, "exit 0", string(data))
+			_, err = tr.Next()
+			assert.Equal(t, io.EOF, err)
+			return nil
+		}).Once()
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	req := &ctr.CopyRequest{Container: c, Path: "/tmp/fault.sh", Content: []byte("exit 0"), Mode: 0o700}
+	require.NoError(t, client.CopyToContainer(context.TODO(), req))
+
+	req.DryRun = true
+	require.NoError(t, client.CopyToContainer(context.TODO(), req), "a dry run copies nothing")
+}
+
+func TestCopyToContainer_Error(t *testing.T) {
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	api.EXPECT().CopyToContainer(mock.Anything, c.ID(), "/tmp", mock.Anything, ctypes.CopyToContainerOptions{}).
+		Return(errors.New("read-only file system")).Once()
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	req := &ctr.CopyRequest{Container: c, Path: "/tmp/fault.sh", Mode: 0o700}
+	assert.EqualError(t, client.CopyToContainer(context.TODO(), req), "failed to copy /tmp/fault.sh to container: read-only file system")
+}
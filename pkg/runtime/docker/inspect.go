@@ -3,12 +3,17 @@ package docker
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
 	ctypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	imagetypes "github.com/docker/docker/api/types/image"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 )
 
 // dockerInspectToContainer converts Docker inspect responses into a runtime-agnostic Container.
@@ -22,10 +27,19 @@ func dockerInspectToContainer(info ctypes.InspectResponse, img *imagetypes.Inspe
 		c.ContainerID = info.ID
 		c.ContainerName = info.Name
 		c.Image = info.Image
+		if created, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+			c.Created = created
+		}
 		if info.State != nil {
-			if info.State.Running {
+			if started, err := time.Parse(time.RFC3339Nano, info.State.StartedAt); err == nil && !started.IsZero() {
+				c.Started = started
+			}
+			switch {
+			case info.State.Paused:
+				c.State = ctr.StatePaused
+			case info.State.Running:
 				c.State = ctr.StateRunning
-			} else {
+			default:
 				c.State = ctr.StateExited
 			}
 		}
@@ -35,7 +49,11 @@ func dockerInspectToContainer(info ctypes.InspectResponse, img *imagetypes.Inspe
 	}
 	if info.NetworkSettings != nil {
 		for name, ep := range info.NetworkSettings.Networks {
-			c.Networks[name] = ctr.NetworkLink{Links: ep.Links}
+			link := ctr.NetworkLink{Links: ep.Links, Aliases: ep.Aliases}
+			if ep.IPAMConfig != nil {
+				link.IPv4Address = ep.IPAMConfig.IPv4Address
+			}
+			c.Networks[name] = link
 		}
 	}
 	return c
@@ -50,31 +68,141 @@ func (client dockerClient) ListContainers(ctx context.Context, fn ctr.FilterFunc
 	return client.listContainers(ctx, fn, ctypes.ListOptions{All: opts.All, Filters: filterArgs})
 }
 
+// inspectWorkers bounds the concurrent container inspections of a listing.
+const inspectWorkers = 8
+
+// summaryToContainer converts a ContainerList entry into a Container holding
+// the fields a FilterFunc decides on, to filter before inspecting.
+func summaryToContainer(s ctypes.Summary) *ctr.Container {
+	c := &ctr.Container{
+		ContainerID:   s.ID,
+		ContainerName: summaryName(s.Names),
+		Image:         s.Image,
+		ImageID:       s.ImageID,
+		Labels:        s.Labels,
+		Created:       time.Unix(s.Created, 0),
+	}
+	if c.Labels == nil {
+		c.Labels = make(map[string]string)
+	}
+	switch s.State {
+	case ctypes.StatePaused:
+		c.State = ctr.StatePaused
+	case ctypes.StateRunning:
+		c.State = ctr.StateRunning
+	default:
+		c.State = ctr.StateExited
+	}
+	return c
+}
+
+// summaryName returns the container's own name among names, which also holds
+// the names it is linked as, e.g. /web/db.
+func summaryName(names []string) string {
+	for _, name := range names {
+		if strings.Count(name, "/") <= 1 {
+			return name
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// listContainers filters the ContainerList entries with fn, then inspects the
+// remaining ones concurrently and filters the result again.
 func (client dockerClient) listContainers(ctx context.Context, fn ctr.FilterFunc, opts ctypes.ListOptions) ([]*ctr.Container, error) {
 	log.Debug("listing containers")
 	containers, err := client.containerAPI.ContainerList(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
-	var cs []*ctr.Container
+	var ids []string
 	for _, container := range containers {
-		containerInfo, err := client.containerAPI.ContainerInspect(ctx, container.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		if fn(summaryToContainer(container)) {
+			ids = append(ids, container.ID)
 		}
-		log.WithFields(log.Fields{
-			"name": containerInfo.Name,
-			"id":   containerInfo.ID,
-		}).Debug("found container")
+	}
+	cs := make([]*ctr.Container, len(ids))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(inspectWorkers)
+	for i, id := range ids {
+		eg.Go(func() error {
+			c, err := client.inspectContainer(egCtx, id)
+			if err != nil {
+				return err
+			}
+			if fn(c) {
+				cs[i] = c
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(cs, func(c *ctr.Container) bool { return c == nil }), nil
+}
 
-		imgInfo, err := client.imageAPI.ImageInspect(ctx, containerInfo.Image)
-		if err != nil {
-			log.WithError(err).WithField("image", containerInfo.Image).Warn("failed to inspect container image, skipping image metadata")
-		}
-		c := dockerInspectToContainer(containerInfo, &imgInfo)
-		if fn(c) {
-			cs = append(cs, c)
-		}
+// inspectContainer inspects the container id and its image.
+func (client dockerClient) inspectContainer(ctx context.Context, id string) (*ctr.Container, error) {
+	containerInfo, err := client.containerAPI.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	log.WithFields(log.Fields{
+		"name": containerInfo.Name,
+		"id":   containerInfo.ID,
+	}).Debug("found container")
+
+	imgInfo, err := client.inspectImage(ctx, containerInfo.Image)
+	if err != nil {
+		log.WithError(err).WithField("image", containerInfo.Image).Warn("failed to inspect container image, skipping image metadata")
+	}
+	return dockerInspectToContainer(containerInfo, &imgInfo), nil
+}
+
+// inspectImage inspects the image id, once per client: image IDs are content
+// addresses, so their metadata never changes.
+func (client dockerClient) inspectImage(ctx context.Context, id string) (imagetypes.InspectResponse, error) {
+	if img, ok := client.images.get(id); ok {
+		return img, nil
+	}
+	img, err := client.imageAPI.ImageInspect(ctx, id)
+	if err != nil {
+		return img, err
+	}
+	client.images.put(id, img)
+	return img, nil
+}
+
+// imageCache holds image inspections by image ID across listings. A nil
+// imageCache caches nothing.
+type imageCache struct {
+	mu     sync.Mutex
+	images map[string]imagetypes.InspectResponse
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{images: make(map[string]imagetypes.InspectResponse)}
+}
+
+func (c *imageCache) get(id string) (imagetypes.InspectResponse, bool) {
+	if c == nil {
+		return imagetypes.InspectResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img, ok := c.images[id]
+	return img, ok
+}
+
+func (c *imageCache) put(id string, img imagetypes.InspectResponse) {
+	if c == nil {
+		return
 	}
-	return cs, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.images[id] = img
 }
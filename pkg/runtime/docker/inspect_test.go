@@ -3,7 +3,9 @@ package docker
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
 	ctypes "github.com/docker/docker/api/types/container"
@@ -11,6 +13,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestListContainers_Success(t *testing.T) {
@@ -42,26 +45,83 @@ func TestListContainers_Success(t *testing.T) {
 }
 
 func TestListContainers_Filter(t *testing.T) {
-	containerDetails := DetailsResponse(AsMap("ID", "foo", "Name", "/bar", "Image", "abc123"))
 	allContainersResponse := Containers(Response(AsMap(
 		"ID", "foo",
 		"Names", []string{"bar"})),
 	)
-	imageDetails := ImageDetailsResponse(AsMap("ID", "abc123"))
 
 	api := NewMockEngine(t)
 	api.EXPECT().ContainerList(mock.Anything, mock.Anything).Return(allContainersResponse, nil)
-	api.EXPECT().ContainerInspect(mock.Anything, "foo").Return(containerDetails, nil)
-	api.EXPECT().ImageInspect(mock.Anything, "abc123").Return(imageDetails, nil)
 
 	client := dockerClient{containerAPI: api, imageAPI: api}
 	containers, err := client.ListContainers(context.TODO(), mockNoContainers, ctr.ListOpts{})
 
 	assert.NoError(t, err)
-	assert.Len(t, containers, 0)
+	assert.Len(t, containers, 0, "containers filtered out on the list summary are not inspected")
 	api.AssertExpectations(t)
 }
 
+func TestListContainers_FilterBeforeInspect(t *testing.T) {
+	allContainersResponse := Containers(
+		Response(AsMap("ID", "a1", "Names", []string{"/api"}, "State", "running")),
+		Response(AsMap("ID", "d1", "Names", []string{"/api/db", "/db"}, "State", "running")),
+		Response(AsMap("ID", "w1", "Names", []string{"/web"}, "State", "running")),
+		Response(AsMap("ID", "s1", "Names", []string{"/skip"}, "Labels", map[string]string{"com.gaiaadm.pumba.skip": "true"})),
+	)
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerList(mock.Anything, mock.Anything).Return(allContainersResponse, nil)
+	api.EXPECT().ContainerInspect(mock.Anything, "a1").Return(DetailsResponse(AsMap("ID", "a1", "Name", "/api", "Image", "img1")), nil).Once()
+	api.EXPECT().ContainerInspect(mock.Anything, "d1").Return(DetailsResponse(AsMap("ID", "d1", "Name", "/db", "Image", "img1")), nil).Once()
+	api.EXPECT().ImageInspect(mock.Anything, "img1").Return(ImageDetailsResponse(AsMap("ID", "img1")), nil).Once()
+
+	var seen []string
+	var mu sync.Mutex
+	fn := func(c *ctr.Container) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, c.Name())
+		return !c.IsPumbaSkip() && c.Name() != "/web"
+	}
+	client := dockerClient{containerAPI: api, imageAPI: api, images: newImageCache()}
+	containers, err := client.ListContainers(context.TODO(), fn, ctr.ListOpts{})
+
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	assert.Equal(t, "a1", containers[0].ID(), "inspected containers keep the list order")
+	assert.Equal(t, "/db", containers[1].Name())
+	assert.Equal(t, "img1", containers[1].ImageID)
+	assert.ElementsMatch(t, []string{"/api", "/db", "/web", "/skip", "/api", "/db"}, seen,
+		"filtered on the summary, using the container's own name, then again after inspect")
+}
+
+func TestListContainers_CachesImages(t *testing.T) {
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerList(mock.Anything, mock.Anything).
+		Return(Containers(Response(AsMap("ID", "foo", "Names", []string{"/bar"}))), nil).Twice()
+	api.EXPECT().ContainerInspect(mock.Anything, "foo").
+		Return(DetailsResponse(AsMap("ID", "foo", "Name", "/bar", "Image", "abc123")), nil).Twice()
+	api.EXPECT().ImageInspect(mock.Anything, "abc123").Return(ImageDetailsResponse(AsMap("ID", "abc123")), nil).Once()
+
+	client := dockerClient{containerAPI: api, imageAPI: api, images: newImageCache()}
+	for range 2 {
+		containers, err := client.ListContainers(context.TODO(), mockAllContainers, ctr.ListOpts{})
+		require.NoError(t, err)
+		require.Len(t, containers, 1)
+		assert.Equal(t, "abc123", containers[0].ImageID)
+	}
+}
+
+func TestSummaryToContainer(t *testing.T) {
+	c := summaryToContainer(ctypes.Summary{
+		ID: "abc", Names: []string{"/web/db", "/db"}, Image: "postgres:16", ImageID: "sha256:1",
+		State: ctypes.StatePaused, Created: 1700000000,
+	})
+	assert.Equal(t, &ctr.Container{
+		ContainerID: "abc", ContainerName: "/db", Image: "postgres:16", ImageID: "sha256:1",
+		State: ctr.StatePaused, Created: time.Unix(1700000000, 0), Labels: map[string]string{},
+	}, c)
+}
+
 func TestListContainers_ListError(t *testing.T) {
 	api := NewMockEngine(t)
 	api.EXPECT().ContainerList(mock.Anything, mock.Anything).Return(Containers(), errors.New("oops"))
@@ -125,7 +185,7 @@ func TestDockerInspectToContainer(t *testing.T) {
 					ID:    "abc123",
 					Name:  "/mycontainer",
 					Image: "nginx:1.25",
-					State: &ctypes.State{Running: true},
+					State: &ctypes.State{Running: true, StartedAt: "2026-10-16T10:00:00.5Z"},
 				},
 				Config: &ctypes.Config{
 					Labels: map[string]string{"env": "prod"},
@@ -145,6 +205,7 @@ func TestDockerInspectToContainer(t *testing.T) {
 				State:         ctr.StateRunning,
 				Labels:        map[string]string{"env": "prod"},
 				Networks:      map[string]ctr.NetworkLink{"bridge": {Links: []string{"db:db"}}},
+				Started:       time.Date(2026, 10, 16, 10, 0, 0, 5e8, time.UTC),
 			},
 		},
 		{
@@ -154,7 +215,7 @@ func TestDockerInspectToContainer(t *testing.T) {
 					ID:    "def456",
 					Name:  "/stopped",
 					Image: "alpine",
-					State: &ctypes.State{Running: false},
+					State: &ctypes.State{Running: false, StartedAt: "0001-01-01T00:00:00Z"},
 				},
 				Config:          &ctypes.Config{Labels: map[string]string{}},
 				NetworkSettings: &ctypes.NetworkSettings{Networks: map[string]*network.EndpointSettings{}},
@@ -170,6 +231,29 @@ func TestDockerInspectToContainer(t *testing.T) {
 				Networks:      map[string]ctr.NetworkLink{},
 			},
 		},
+		{
+			name: "paused container",
+			info: ctypes.InspectResponse{
+				ContainerJSONBase: &ctypes.ContainerJSONBase{
+					ID:    "ghi789",
+					Name:  "/paused",
+					Image: "alpine",
+					State: &ctypes.State{Running: true, Paused: true},
+				},
+				Config:          &ctypes.Config{Labels: map[string]string{}},
+				NetworkSettings: &ctypes.NetworkSettings{Networks: map[string]*network.EndpointSettings{}},
+			},
+			img: &imagetypes.InspectResponse{ID: "sha256:alpine123"},
+			expected: &ctr.Container{
+				ContainerID:   "ghi789",
+				ContainerName: "/paused",
+				Image:         "alpine",
+				ImageID:       "sha256:alpine123",
+				State:         ctr.StatePaused,
+				Labels:        map[string]string{},
+				Networks:      map[string]ctr.NetworkLink{},
+			},
+		},
 		{
 			name: "nil ContainerJSONBase",
 			info: ctypes.InspectResponse{
@@ -224,7 +308,11 @@ func TestDockerInspectToContainer(t *testing.T) {
 				NetworkSettings: &ctypes.NetworkSettings{
 					Networks: map[string]*network.EndpointSettings{
 						"frontend": {Links: []string{"api:api"}},
-						"backend":  {Links: []string{"db:db", "cache:cache"}},
+						"backend": {
+							Links:      []string{"db:db", "cache:cache"},
+							Aliases:    []string{"app"},
+							IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "10.10.0.5"},
+						},
 					},
 				},
 			},
@@ -238,7 +326,7 @@ func TestDockerInspectToContainer(t *testing.T) {
 				Labels:        map[string]string{},
 				Networks: map[string]ctr.NetworkLink{
 					"frontend": {Links: []string{"api:api"}},
-					"backend":  {Links: []string{"db:db", "cache:cache"}},
+					"backend":  {Links: []string{"db:db", "cache:cache"}, Aliases: []string{"app"}, IPv4Address: "10.10.0.5"},
 				},
 			},
 		},
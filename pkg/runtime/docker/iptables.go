@@ -2,10 +2,12 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -23,12 +25,15 @@ func (client dockerClient) IPTablesContainer(ctx context.Context, req *ctr.IPTab
 		"duration":      req.Duration,
 		"img":           req.Sidecar.Image,
 		"pull":          req.Sidecar.Pull,
+		"backend":       req.Backend,
 		"dryrun":        req.DryRun,
 	}).Info("running iptables on container")
-	if len(req.SrcIPs) == 0 && len(req.DstIPs) == 0 && len(req.SPorts) == 0 && len(req.DPorts) == 0 {
-		return client.ipTablesContainer(ctx, req)
-	}
-	return client.ipTablesContainerWithIPFilter(ctx, req)
+	return client.withCapture(ctx, req.Container, req.Capture, req.DryRun, func() error {
+		if !req.DryRun {
+			client.resolveBackend(ctx, req)
+		}
+		return client.applyIPTables(ctx, req)
+	})
 }
 
 // StopIPTablesContainer stops the iptables container injected into the given container network namespace
@@ -44,104 +49,124 @@ func (client dockerClient) StopIPTablesContainer(ctx context.Context, req *ctr.I
 		"dports":        req.DPorts,
 		"img":           req.Sidecar.Image,
 		"pull":          req.Sidecar.Pull,
+		"backend":       req.Backend,
 		"dryrun":        req.DryRun,
 	}).Info("stopping iptables on container")
-	if len(req.SrcIPs) == 0 && len(req.DstIPs) == 0 && len(req.SPorts) == 0 && len(req.DPorts) == 0 {
-		return client.ipTablesContainer(ctx, req)
+	return client.stopWithCapture(ctx, req.Container, req.Capture, req.DryRun, func() error {
+		return client.applyIPTables(ctx, req)
+	})
+}
+
+// IPTablesStatus lists the packet filter state of the target's network
+// namespace with the backend the request resolves to.
+func (client dockerClient) IPTablesStatus(ctx context.Context, req *ctr.IPTablesRequest) (string, error) {
+	status := *req
+	client.resolveBackend(ctx, &status)
+	tool, err := filterTool(&status)
+	if err != nil {
+		return "", err
 	}
-	return client.ipTablesContainerWithIPFilter(ctx, req)
+	return client.toolOutput(ctx, req.Container, tool, filterStatusArgs(tool), req.Sidecar.Image, status.Sidecar.Pull)
 }
 
-func (client dockerClient) ipTablesContainer(ctx context.Context, req *ctr.IPTablesRequest) error {
+// filterStatusArgs returns the argument lists printing the state of tool:
+// the rules of the filter and nat tables for iptables, the tables for nft
+// (Pumba's rules live in a dedicated table).
+func filterStatusArgs(tool string) [][]string {
+	if tool == nftables.Tool {
+		return [][]string{nftables.ProbeArgs}
+	}
+	return [][]string{{"-S"}, {"-t", "nat", "-S"}}
+}
+
+// applyIPTables runs the request's rules; CmdPrefix selects insert or delete.
+func (client dockerClient) applyIPTables(ctx context.Context, req *ctr.IPTablesRequest) error {
 	log.WithFields(log.Fields{
 		"name":      req.Container.Name(),
 		"id":        req.Container.ID(),
 		"cmdPrefix": strings.Join(req.CmdPrefix, " "),
 		"cmdSuffix": strings.Join(req.CmdSuffix, " "),
+		"srcIPs":    req.SrcIPs,
+		"dstIPs":    req.DstIPs,
+		"Sports":    req.SPorts,
+		"Dports":    req.DPorts,
 		"img":       req.Sidecar.Image,
 		"pull":      req.Sidecar.Pull,
 		"dryrun":    req.DryRun,
 	}).Debug("execute iptables for container")
-	if !req.DryRun {
-		var command []string
-		command = append(command, req.CmdPrefix...)
-		command = append(command, req.CmdSuffix...)
-		log.WithField("iptables", strings.Join(command, " ")).Debug("executing iptables")
-		return client.ipTablesCommands(ctx, req.Container, [][]string{command}, req.Sidecar.Image, req.Sidecar.Pull)
+	if req.DryRun {
+		return nil
+	}
+	// one rule per IP/port filter element, see the iptables statistic
+	// extension: https://www.man7.org/linux/man-pages/man8/iptables-extensions.8.html
+	if err := client.ipTablesCommands(ctx, req, req.Commands()); err != nil {
+		return fmt.Errorf("failed to run iptables commands: %w", err)
 	}
 	return nil
 }
 
-func (client dockerClient) ipTablesContainerWithIPFilter(ctx context.Context, req *ctr.IPTablesRequest) error {
-	log.WithFields(log.Fields{
-		"name":   req.Container.Name(),
-		"id":     req.Container.ID(),
-		"srcIPs": req.SrcIPs,
-		"dstIPs": req.DstIPs,
-		"Sports": req.SPorts,
-		"Dports": req.DPorts,
-		"img":    req.Sidecar.Image,
-		"pull":   req.Sidecar.Pull,
-		"dryrun": req.DryRun,
-	}).Debug("execute iptables for container with IP(s) filter")
-	if !req.DryRun {
-		// use docker client ExecStart to run iptables rules to filter network
-		commands := [][]string{}
-
-		// See more about the iptables statistics extension: https://www.man7.org/linux/man-pages/man8/iptables-extensions.8.html
-		// # drop traffic to a specific source address
-
-		for _, ip := range req.SrcIPs {
-			cmd := []string{}
-			cmd = append(cmd, req.CmdPrefix...)
-			cmd = append(cmd, "-s", ip.String())
-			cmd = append(cmd, req.CmdSuffix...)
-			commands = append(commands, cmd)
-		}
-
-		// # drop traffic to a specific destination address
-		for _, ip := range req.DstIPs {
-			cmd := []string{}
-			cmd = append(cmd, req.CmdPrefix...)
-			cmd = append(cmd, "-d", ip.String())
-			cmd = append(cmd, req.CmdSuffix...)
-			commands = append(commands, cmd)
-		}
-
-		// # drop traffic to a specific source port
-		for _, sport := range req.SPorts {
-			cmd := []string{}
-			cmd = append(cmd, req.CmdPrefix...)
-			cmd = append(cmd, "--sport", sport)
-			cmd = append(cmd, req.CmdSuffix...)
-			commands = append(commands, cmd)
-		}
-
-		// # drop traffic to a specific destination port
-		for _, dport := range req.DPorts {
-			cmd := []string{}
-			cmd = append(cmd, req.CmdPrefix...)
-			cmd = append(cmd, "--dport", dport)
-			cmd = append(cmd, req.CmdSuffix...)
-			commands = append(commands, cmd)
-		}
-
-		err := client.ipTablesCommands(ctx, req.Container, commands, req.Sidecar.Image, req.Sidecar.Pull)
-		if err != nil {
-			return fmt.Errorf("failed to run iptables commands: %w", err)
+// ipTablesCommands runs argsList (iptables syntax) against the target,
+// translating it to nft first when the request selects or auto-detects the
+// nftables backend.
+func (client dockerClient) ipTablesCommands(ctx context.Context, req *ctr.IPTablesRequest, argsList [][]string) error {
+	tool, err := filterTool(req)
+	if err != nil {
+		return err
+	}
+	if tool == nftables.Tool {
+		if argsList, err = nftables.Translate(argsList); err != nil {
+			return fmt.Errorf("failed to translate iptables rules: %w", err)
 		}
 	}
-	return nil
-}
-
-func (client dockerClient) ipTablesCommands(ctx context.Context, c *ctr.Container, argsList [][]string, tcimg string, pull bool) error {
-	if tcimg == "" {
+	if req.Sidecar.Image == "" {
 		for _, args := range argsList {
-			if err := client.execOnContainer(ctx, c, "iptables", args, true); err != nil {
-				return fmt.Errorf("error running iptables command on container: %v: %w", strings.Join(args, " "), err)
+			if err := client.execOnContainer(ctx, req.Container, tool, args, true); err != nil {
+				return fmt.Errorf("error running %s command on container: %v: %w", tool, strings.Join(args, " "), err)
 			}
 		}
 		return nil
 	}
-	return client.runSidecar(ctx, c, argsList, tcimg, "iptables", pull)
+	return client.runSidecar(ctx, req.Container, argsList, req.Sidecar.Image, tool, req.Sidecar.Pull)
+}
+
+// resolveBackend replaces the auto backend of req with the one it resolves
+// to: it probes the target netns (through the sidecar image when one is
+// configured) with `nft list tables` and falls back to iptables when that
+// fails. The install request carries the resolved backend to the removal, so
+// teardown never probes again and cannot pick another tool than the one that
+// installed the rules. Pull is cleared once the probe has pulled the sidecar
+// image so the rule run does not pull it a second time.
+func (client dockerClient) resolveBackend(ctx context.Context, req *ctr.IPTablesRequest) {
+	if req.Backend != ctr.FilterBackendAuto {
+		return
+	}
+	var err error
+	if req.Sidecar.Image == "" {
+		err = client.execOnContainer(ctx, req.Container, nftables.Tool, nftables.ProbeArgs, true)
+	} else {
+		err = client.runSidecar(ctx, req.Container, [][]string{nftables.ProbeArgs}, req.Sidecar.Image, nftables.Tool, req.Sidecar.Pull)
+	}
+	req.Sidecar.Pull = false
+	if err != nil {
+		log.WithError(err).WithField("id", req.Container.ID()).Debug("nft probe failed, using iptables backend")
+		req.Backend = ctr.FilterBackendIPTables
+		return
+	}
+	log.WithField("id", req.Container.ID()).Debug("nft probe succeeded, using nftables backend")
+	req.Backend = ctr.FilterBackendNFTables
+}
+
+// filterTool maps req.Backend to the packet filter binary. The auto backend
+// must have been resolved by the install, see resolveBackend.
+func filterTool(req *ctr.IPTablesRequest) (string, error) {
+	switch req.Backend {
+	case "", ctr.FilterBackendIPTables:
+		return "iptables", nil
+	case ctr.FilterBackendNFTables:
+		return nftables.Tool, nil
+	case ctr.FilterBackendAuto:
+		return "", errors.New("packet filter backend auto is not resolved: pass the backend the rules were installed with")
+	default:
+		return "", fmt.Errorf("unsupported packet filter backend: %s", req.Backend)
+	}
 }
@@ -12,6 +12,7 @@ import (
 	ctypes "github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIPTablesContainer(t *testing.T) {
@@ -213,7 +214,125 @@ func TestIPTablesForSimpleCases(t *testing.T) {
 		api.EXPECT().ContainerExecInspect(mock.Anything, "exec-id").Return(ctypes.ExecInspect{}, nil)
 
 		client := dockerClient{containerAPI: api}
-		err := client.runSidecarExec(ctx, "container-id", "iptables", []string{"-L"})
+		_, err := client.runSidecarExec(ctx, "container-id", "iptables", []string{"-L"})
 		assert.NoError(t, err)
 	})
 }
+
+// expectExec registers the which-check plus the exec for cmd on the target.
+func expectExec(api *mocks.APIClient, ctx context.Context, id, execID string, cmd []string, exitCode int) {
+	api.EXPECT().ContainerExecCreate(ctx, id, ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: []string{"which", cmd[0]}}).Return(ctypes.ExecCreateResponse{ID: execID + "-which"}, nil).Once()
+	api.EXPECT().ContainerExecAttach(ctx, execID+"-which", ctypes.ExecAttachOptions{}).Return(fakeExecAttach(), nil)
+	api.EXPECT().ContainerExecInspect(ctx, execID+"-which").Return(ctypes.ExecInspect{}, nil)
+	api.EXPECT().ContainerExecCreate(ctx, id, ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: cmd, Privileged: true}).Return(ctypes.ExecCreateResponse{ID: execID}, nil)
+	api.EXPECT().ContainerExecAttach(ctx, execID, ctypes.ExecAttachOptions{}).Return(fakeExecAttach(), nil)
+	api.EXPECT().ContainerExecInspect(ctx, execID).Return(ctypes.ExecInspect{ExitCode: exitCode}, nil)
+}
+
+func TestIPTablesContainer_NFTablesBackend(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	expectExec(api, ctx, c.ID(), "table", []string{"nft", "add", "table", "inet", "pumba"}, 0)
+	expectExec(api, ctx, c.ID(), "chain", []string{"nft", "add", "chain", "inet", "pumba", "input",
+		"{", "type", "filter", "hook", "input", "priority", "0", ";", "policy", "accept", ";", "}"}, 0)
+	expectExec(api, ctx, c.ID(), "rule", []string{"nft", "insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "drop"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	err := client.IPTablesContainer(ctx, &ctr.IPTablesRequest{
+		Container: c,
+		CmdPrefix: []string{"-I", "INPUT", "-i", "eth0"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Backend:   ctr.FilterBackendNFTables,
+	})
+	assert.NoError(t, err)
+}
+
+func TestIPTablesContainer_AutoBackendFallsBackToIPTables(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	expectExec(api, ctx, c.ID(), "probe", []string{"nft", "list", "tables"}, 1)
+	expectExec(api, ctx, c.ID(), "add", []string{"iptables", "-I", "INPUT", "-i", "eth0", "-j", "DROP"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	req := &ctr.IPTablesRequest{
+		Container: c,
+		CmdPrefix: []string{"-I", "INPUT", "-i", "eth0"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Backend:   ctr.FilterBackendAuto,
+	}
+	require.NoError(t, client.IPTablesContainer(ctx, req))
+	assert.Equal(t, ctr.FilterBackendIPTables, req.Backend, "the install carries the resolved backend")
+}
+
+func TestIPTablesContainer_AutoBackendUsesNFTables(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	expectExec(api, ctx, c.ID(), "probe", []string{"nft", "list", "tables"}, 0)
+	expectExec(api, ctx, c.ID(), "table", []string{"nft", "add", "table", "inet", "pumba"}, 0)
+	expectExec(api, ctx, c.ID(), "chain", []string{"nft", "add", "chain", "inet", "pumba", "input",
+		"{", "type", "filter", "hook", "input", "priority", "0", ";", "policy", "accept", ";", "}"}, 0)
+	expectExec(api, ctx, c.ID(), "rule", []string{"nft", "insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "drop"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	req := &ctr.IPTablesRequest{
+		Container: c,
+		CmdPrefix: []string{"-I", "INPUT", "-i", "eth0"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Backend:   ctr.FilterBackendAuto,
+	}
+	require.NoError(t, client.IPTablesContainer(ctx, req))
+	assert.Equal(t, ctr.FilterBackendNFTables, req.Backend, "the install carries the resolved backend")
+}
+
+func TestStopIPTablesContainer_AutoBackendNotProbed(t *testing.T) {
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	// no exec expectations: teardown must not probe, a probe failing there
+	// would pick another tool than the one that installed the rules
+	client := dockerClient{containerAPI: NewMockEngine(t)}
+	err := client.StopIPTablesContainer(context.TODO(), &ctr.IPTablesRequest{
+		Container: c,
+		CmdPrefix: []string{"-D", "INPUT", "-i", "eth0"},
+		CmdSuffix: []string{"-j", "DROP"},
+		Backend:   ctr.FilterBackendAuto,
+	})
+	assert.ErrorContains(t, err, "packet filter backend auto is not resolved")
+}
+
+func TestIPTablesStatus_Sidecar(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerCreate(ctx, mock.MatchedBy(func(cfg *ctypes.Config) bool {
+		return cfg.Image == "nettools"
+	}), mock.Anything, mock.Anything, mock.Anything, "").Return(ctypes.CreateResponse{ID: "sidecarID"}, nil)
+	api.EXPECT().ContainerStart(ctx, "sidecarID", ctypes.StartOptions{}).Return(nil)
+	api.EXPECT().ContainerExecCreate(ctx, "sidecarID", ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: []string{"nft", "list", "tables"}}).
+		Return(ctypes.ExecCreateResponse{ID: "list"}, nil)
+	api.EXPECT().ContainerExecAttach(ctx, "list", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput("table inet pumba\n", ""), nil)
+	api.EXPECT().ContainerExecInspect(ctx, "list").Return(ctypes.ExecInspect{}, nil)
+	api.EXPECT().ContainerRemove(mock.Anything, "sidecarID", ctypes.RemoveOptions{Force: true}).Return(nil)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	out, err := client.IPTablesStatus(ctx, &ctr.IPTablesRequest{
+		Container: c,
+		Sidecar:   ctr.SidecarSpec{Image: "nettools"},
+		Backend:   ctr.FilterBackendNFTables,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "table inet pumba\n", out)
+}
+
+func TestIPTablesStatus_FilterAndNatTables(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	expectExec(api, ctx, c.ID(), "filter", []string{"iptables", "-S"}, 0)
+	expectExec(api, ctx, c.ID(), "nat", []string{"iptables", "-t", "nat", "-S"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	_, err := client.IPTablesStatus(ctx, &ctr.IPTablesRequest{Container: c, Backend: ctr.FilterBackendIPTables})
+	require.NoError(t, err)
+}
@@ -34,6 +34,30 @@ func (client dockerClient) KillContainer(ctx context.Context, c *ctr.Container,
 	return nil
 }
 
+// SignalProcesses signals processes of a container, listed and signaled by a
+// shell in a sidecar sharing its PID namespace or, without a sidecar image,
+// in the container itself.
+func (client dockerClient) SignalProcesses(ctx context.Context, req *ctr.SignalRequest) ([]int, error) {
+	log.WithFields(log.Fields{
+		"name":    req.Container.Name(),
+		"id":      req.Container.ID(),
+		"process": req.Process,
+		"pids":    req.PIDs,
+		"signal":  req.Signal,
+		"img":     req.Sidecar.Image,
+		"dryrun":  req.DryRun,
+	}).Info("signaling container processes")
+	if req.DryRun {
+		return nil, nil
+	}
+	return ctr.SignalWithShell(ctx, req, func(ctx context.Context, args []string) (string, error) {
+		if req.Sidecar.Image != "" {
+			return client.sidecarOutput(ctx, req.Container, true, [][]string{args}, req.Sidecar.Image, "sh", req.Sidecar.Pull)
+		}
+		return client.execOnContainerOutput(ctx, req.Container, "sh", args, false)
+	})
+}
+
 // RestartContainer restarts a container
 func (client dockerClient) RestartContainer(ctx context.Context, c *ctr.Container, timeout time.Duration, dryrun bool) error {
 	log.WithFields(log.Fields{
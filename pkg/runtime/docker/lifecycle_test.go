@@ -72,6 +72,21 @@ func TestKillContainer_DryRun(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSignalProcesses_DryRun(t *testing.T) {
+	c := NewTestContainer(AsMap(
+		"ID", "abc123",
+		"Name", "foo",
+	))
+
+	api := NewMockEngine(t)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	pids, err := client.SignalProcesses(context.TODO(), &ctr.SignalRequest{Container: c, Process: "worker", Signal: "SIGSTOP", DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, pids)
+}
+
 func TestStopContainer_CustomSignalSuccess(t *testing.T) {
 	c := NewTestContainer(AsMap(
 		"ID", "abc123",
@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	networktypes "github.com/docker/docker/api/types/network"
+	log "github.com/sirupsen/logrus"
+)
+
+// LinkContainer runs `ip` commands in a sidecar joining the target's network
+// namespace and returns their combined stdout.
+func (client dockerClient) LinkContainer(ctx context.Context, req *ctr.LinkRequest) (string, error) {
+	log.WithFields(log.Fields{
+		"name":     req.Container.Name(),
+		"id":       req.Container.ID(),
+		"commands": req.Commands,
+		"img":      req.Sidecar.Image,
+		"pull":     req.Sidecar.Pull,
+		"dryrun":   req.DryRun,
+	}).Info("changing network link of container")
+	if req.DryRun {
+		return "", nil
+	}
+	return client.runSidecarOutput(ctx, req.Container, req.Commands, req.Sidecar.Image, "ip", req.Sidecar.Pull)
+}
+
+// DisconnectNetwork force-disconnects the container from the named network.
+func (client dockerClient) DisconnectNetwork(ctx context.Context, req *ctr.NetworkRequest) error {
+	log.WithFields(log.Fields{
+		"name":    req.Container.Name(),
+		"id":      req.Container.ID(),
+		"network": req.Network,
+		"dryrun":  req.DryRun,
+	}).Info("disconnecting container from network")
+	if req.DryRun {
+		return nil
+	}
+	if err := client.networkAPI.NetworkDisconnect(ctx, req.Network, req.Container.ID(), true); err != nil {
+		return fmt.Errorf("failed to disconnect container %s from network %s: %w", req.Container.Name(), req.Network, err)
+	}
+	return nil
+}
+
+// ConnectNetwork reconnects the container to the named network with the
+// aliases and static address it had before the disconnect.
+func (client dockerClient) ConnectNetwork(ctx context.Context, req *ctr.NetworkRequest) error {
+	log.WithFields(log.Fields{
+		"name":    req.Container.Name(),
+		"id":      req.Container.ID(),
+		"network": req.Network,
+		"dryrun":  req.DryRun,
+	}).Info("connecting container to network")
+	if req.DryRun {
+		return nil
+	}
+	settings := &networktypes.EndpointSettings{}
+	if link, ok := req.Container.Networks[req.Network]; ok {
+		settings.Aliases = link.Aliases
+		settings.Links = link.Links
+		if link.IPv4Address != "" {
+			settings.IPAMConfig = &networktypes.EndpointIPAMConfig{IPv4Address: link.IPv4Address}
+		}
+	}
+	if err := client.networkAPI.NetworkConnect(ctx, req.Network, req.Container.ID(), settings); err != nil {
+		return fmt.Errorf("failed to connect container %s to network %s: %w", req.Container.Name(), req.Network, err)
+	}
+	return nil
+}
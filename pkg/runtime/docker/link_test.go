@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/docker/docker/api/types"
+	ctypes "github.com/docker/docker/api/types/container"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExecAttachOutput returns a HijackedResponse streaming stdout and stderr
+// multiplexed the way the engine sends non-TTY exec output.
+func fakeExecAttachOutput(stdout, stderr string) types.HijackedResponse {
+	var buf bytes.Buffer
+	_, _ = stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(stdout))
+	_, _ = stdcopy.NewStdWriter(&buf, stdcopy.Stderr).Write([]byte(stderr))
+	resp := fakeExecAttach()
+	resp.Reader = bufio.NewReader(&buf)
+	return resp
+}
+
+func TestLinkContainer_ReturnsSidecarOutput(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerCreate(ctx, mock.MatchedBy(func(cfg *ctypes.Config) bool {
+		return cfg.Image == "nettools"
+	}), mock.MatchedBy(func(h *ctypes.HostConfig) bool {
+		return h.NetworkMode == "container:abc123" && h.CapAdd[0] == "NET_ADMIN"
+	}), mock.Anything, mock.Anything, "").Return(ctypes.CreateResponse{ID: "sidecarID"}, nil)
+	api.EXPECT().ContainerStart(ctx, "sidecarID", ctypes.StartOptions{}).Return(nil)
+	for i, args := range [][]string{{"-o", "link", "show", "dev", "eth0"}, {"link", "set", "dev", "eth0", "mtu", "1200"}} {
+		execID := []string{"show", "set"}[i]
+		api.EXPECT().ContainerExecCreate(ctx, "sidecarID", ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: append([]string{"ip"}, args...)}).
+			Return(ctypes.ExecCreateResponse{ID: execID}, nil)
+		api.EXPECT().ContainerExecInspect(ctx, execID).Return(ctypes.ExecInspect{}, nil)
+	}
+	api.EXPECT().ContainerExecAttach(ctx, "show", ctypes.ExecAttachOptions{}).
+		Return(fakeExecAttachOutput("2: eth0@if7: <BROADCAST,UP> mtu 1500\n", "warning\n"), nil)
+	api.EXPECT().ContainerExecAttach(ctx, "set", ctypes.ExecAttachOptions{}).Return(fakeExecAttach(), nil)
+	api.EXPECT().ContainerRemove(mock.Anything, "sidecarID", ctypes.RemoveOptions{Force: true}).Return(nil)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	out, err := client.LinkContainer(ctx, &ctr.LinkRequest{
+		Container: c,
+		Commands:  [][]string{{"-o", "link", "show", "dev", "eth0"}, {"link", "set", "dev", "eth0", "mtu", "1200"}},
+		Sidecar:   ctr.SidecarSpec{Image: "nettools"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2: eth0@if7: <BROADCAST,UP> mtu 1500\n", out)
+}
+
+func TestLinkContainer_DryRun(t *testing.T) {
+	client := dockerClient{containerAPI: NewMockEngine(t)}
+	out, err := client.LinkContainer(context.TODO(), &ctr.LinkRequest{
+		Container: &ctr.Container{ContainerID: "abc123"},
+		Commands:  [][]string{{"link", "set", "dev", "eth0", "down"}},
+		DryRun:    true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestDisconnectNetwork(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	api := NewMockEngine(t)
+	api.EXPECT().NetworkDisconnect(ctx, "backend", "abc123", true).Return(nil).Once()
+	client := dockerClient{networkAPI: api}
+	require.NoError(t, client.DisconnectNetwork(ctx, &ctr.NetworkRequest{Container: c, Network: "backend"}))
+
+	api.EXPECT().NetworkDisconnect(ctx, "backend", "abc123", true).Return(errors.New("no such network")).Once()
+	err := client.DisconnectNetwork(ctx, &ctr.NetworkRequest{Container: c, Network: "backend"})
+	assert.ErrorContains(t, err, "failed to disconnect container test-container from network backend")
+
+	require.NoError(t, client.DisconnectNetwork(ctx, &ctr.NetworkRequest{Container: c, Network: "backend", DryRun: true}))
+}
+
+func TestConnectNetwork_RestoresEndpoint(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{
+		ContainerID:   "abc123",
+		ContainerName: "test-container",
+		Networks: map[string]ctr.NetworkLink{
+			"backend":  {Aliases: []string{"api"}, IPv4Address: "10.10.0.5"},
+			"frontend": {Aliases: []string{"web"}},
+		},
+	}
+	api := NewMockEngine(t)
+	api.EXPECT().NetworkConnect(ctx, "backend", "abc123", &networktypes.EndpointSettings{
+		Aliases:    []string{"api"},
+		IPAMConfig: &networktypes.EndpointIPAMConfig{IPv4Address: "10.10.0.5"},
+	}).Return(nil)
+	api.EXPECT().NetworkConnect(ctx, "frontend", "abc123", &networktypes.EndpointSettings{Aliases: []string{"web"}}).Return(nil)
+
+	client := dockerClient{networkAPI: api}
+	require.NoError(t, client.ConnectNetwork(ctx, &ctr.NetworkRequest{Container: c, Network: "backend"}))
+	require.NoError(t, client.ConnectNetwork(ctx, &ctr.NetworkRequest{Container: c, Network: "frontend"}))
+}
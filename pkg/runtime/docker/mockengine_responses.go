@@ -16,10 +16,14 @@ func Containers(containers ...ctypes.Summary) []ctypes.Summary {
 func Response(params map[string]any) ctypes.Summary {
 	ID := lookupWithDefault(params, "ID", "defaultID").(string)
 	Names := lookupWithDefault(params, "Names", []string{"foo", "bar"}).([]string)
+	Labels := lookupWithDefault(params, "Labels", map[string]string{}).(map[string]string)
+	State := lookupWithDefault(params, "State", "").(string)
 
 	return ctypes.Summary{
-		ID:    ID,
-		Names: Names,
+		ID:     ID,
+		Names:  Names,
+		Labels: Labels,
+		State:  State,
 	}
 }
 
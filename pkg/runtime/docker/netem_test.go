@@ -18,6 +18,7 @@ import (
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNetemContainer_Success(t *testing.T) {
@@ -259,7 +260,7 @@ func Test_tcContainerCommands(t *testing.T) {
 	}
 
 	config := ctypes.Config{
-		Labels:     map[string]string{"com.gaiaadm.pumba.skip": "true"},
+		Labels:     ctr.SidecarLabels("tc", "targetID"),
 		Entrypoint: []string{"tail"},
 		Cmd:        []string{"-f", "/dev/null"},
 		Image:      "pumba/tcimage",
@@ -650,3 +651,24 @@ func TestStopNetemIPTables(t *testing.T) {
 		})
 	}
 }
+
+func TestNetemStatus(t *testing.T) {
+	ctx := context.TODO()
+	c := &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"}
+	qdiscs := "qdisc netem 8001: root refcnt 2 limit 1000 delay 100ms\n"
+
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerExecCreate(ctx, "abc123", ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Cmd: []string{"which", "tc"}}).
+		Return(ctypes.ExecCreateResponse{ID: "which"}, nil)
+	api.EXPECT().ContainerExecAttach(ctx, "which", ctypes.ExecAttachOptions{}).Return(fakeExecAttach(), nil)
+	api.EXPECT().ContainerExecInspect(ctx, "which").Return(ctypes.ExecInspect{}, nil)
+	api.EXPECT().ContainerExecCreate(ctx, "abc123", ctypes.ExecOptions{AttachStdout: true, AttachStderr: true, Privileged: true,
+		Cmd: []string{"tc", "-s", "qdisc", "show", "dev", "eth0"}}).Return(ctypes.ExecCreateResponse{ID: "show"}, nil)
+	api.EXPECT().ContainerExecAttach(ctx, "show", ctypes.ExecAttachOptions{}).Return(fakeExecAttachOutput(qdiscs, ""), nil)
+	api.EXPECT().ContainerExecInspect(ctx, "show").Return(ctypes.ExecInspect{}, nil)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	out, err := client.NetemStatus(ctx, &ctr.NetemRequest{Container: c, Interface: "eth0"})
+	require.NoError(t, err)
+	assert.Equal(t, qdiscs, out)
+}
@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	ctypes "github.com/docker/docker/api/types/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProxyContainer starts a long-lived proxy sidecar joining the target's
+// network namespace and then installs the nat redirects through an ephemeral
+// iptables sidecar (or directly in the target when no iptables image is set).
+// The proxy is removed again if the redirects cannot be installed.
+func (client dockerClient) ProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	log.WithFields(log.Fields{
+		"name":      req.Container.Name(),
+		"id":        req.Container.ID(),
+		"proxy":     req.Name,
+		"command":   req.Command,
+		"redirects": req.Redirects,
+		"proxy-img": req.Proxy.Image,
+		"img":       req.Sidecar.Image,
+		"dryrun":    req.DryRun,
+	}).Info("starting proxy for container")
+	if req.DryRun {
+		return nil
+	}
+	if len(req.Command) == 0 {
+		return errors.New("proxy command is empty")
+	}
+	tool := req.Name + "-proxy"
+	if req.Proxy.Pull {
+		if err := client.pullSidecarImage(ctx, req.Proxy.Image, tool); err != nil {
+			return err
+		}
+	}
+	hconfig := ctypes.HostConfig{
+		NetworkMode: ctypes.NetworkMode("container:" + req.Container.ID()),
+	}
+	// StopSignal: SIGKILL for the same reason as runSidecar: the proxy holds
+	// no state worth a graceful shutdown and force-remove must be immediate.
+	config := ctypes.Config{
+		Labels:     ctr.SidecarLabels(tool, req.Container.ID()),
+		Entrypoint: req.Command[:1],
+		Cmd:        req.Command[1:],
+		Image:      req.Proxy.Image,
+		User:       ctr.ProxyUser,
+		StopSignal: "SIGKILL",
+	}
+	createResponse, err := client.containerAPI.ContainerCreate(ctx, &config, &hconfig, nil, nil, req.SidecarName())
+	if err != nil {
+		return fmt.Errorf("failed to create %s-container from image %q: %w", tool, req.Proxy.Image, err)
+	}
+	log.WithField("id", createResponse.ID).Debugf("%s container created, starting it", tool)
+	if err = client.containerAPI.ContainerStart(ctx, createResponse.ID, ctypes.StartOptions{}); err != nil {
+		_ = client.removeSidecar(ctx, createResponse.ID)
+		return fmt.Errorf("failed to start %s-container: %w", tool, err)
+	}
+	if err = client.proxyRedirects(ctx, req, "-I"); err != nil {
+		_ = client.removeSidecar(ctx, createResponse.ID)
+		return fmt.Errorf("failed to redirect traffic to %s: %w", tool, err)
+	}
+	return nil
+}
+
+// StopProxyContainer removes the nat redirects and then the proxy sidecar.
+// Both steps always run so a failed rule removal does not leak the proxy.
+func (client dockerClient) StopProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	log.WithFields(log.Fields{
+		"name":   req.Container.Name(),
+		"id":     req.Container.ID(),
+		"proxy":  req.Name,
+		"img":    req.Sidecar.Image,
+		"dryrun": req.DryRun,
+	}).Info("stopping proxy for container")
+	if req.DryRun {
+		return nil
+	}
+	var errs []error
+	if err := client.proxyRedirects(ctx, req, "-D"); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove %s-proxy redirects: %w", req.Name, err))
+	}
+	if err := client.removeSidecar(ctx, req.SidecarName()); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove %s-proxy container: %w", req.Name, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (client dockerClient) proxyRedirects(ctx context.Context, req *ctr.ProxyRequest, op string) error {
+	argsList := req.RedirectArgs(op)
+	if req.Sidecar.Image == "" {
+		for _, args := range argsList {
+			if err := client.execOnContainer(ctx, req.Container, "iptables", args, true); err != nil {
+				return fmt.Errorf("error running iptables command on container: %v: %w", strings.Join(args, " "), err)
+			}
+		}
+		return nil
+	}
+	return client.runSidecar(ctx, req.Container, argsList, req.Sidecar.Image, "iptables", req.Sidecar.Pull)
+}
@@ -0,0 +1,94 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	ctypes "github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testProxyRequest() *ctr.ProxyRequest {
+	return &ctr.ProxyRequest{
+		Container: &ctr.Container{ContainerID: "abc123", ContainerName: "test-container"},
+		Name:      "dns",
+		Command:   []string{"/pumba", "dns-proxy", "--action", "nxdomain"},
+		Redirects: [][]string{{"OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}},
+		Proxy:     ctr.SidecarSpec{Image: "ghcr.io/alexei-led/pumba"},
+	}
+}
+
+func TestProxyContainer_DryRun(t *testing.T) {
+	api := NewMockEngine(t)
+	req := testProxyRequest()
+	req.DryRun = true
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.ProxyContainer(context.TODO(), req))
+	require.NoError(t, client.StopProxyContainer(context.TODO(), req))
+}
+
+func TestProxyContainer_StartsProxyAndRedirects(t *testing.T) {
+	ctx := context.TODO()
+	req := testProxyRequest()
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerCreate(ctx, mock.MatchedBy(func(c *ctypes.Config) bool {
+		return c.Image == "ghcr.io/alexei-led/pumba" &&
+			assert.ObjectsAreEqual([]string{"/pumba"}, []string(c.Entrypoint)) &&
+			assert.ObjectsAreEqual([]string{"dns-proxy", "--action", "nxdomain"}, []string(c.Cmd)) &&
+			c.User == ctr.ProxyUser && c.Labels["com.gaiaadm.pumba.skip"] == "true"
+	}), mock.MatchedBy(func(h *ctypes.HostConfig) bool {
+		return h.NetworkMode == "container:abc123"
+	}), mock.Anything, mock.Anything, "pumba-dns-proxy-abc123").Return(ctypes.CreateResponse{ID: "proxyID"}, nil)
+	api.EXPECT().ContainerStart(ctx, "proxyID", ctypes.StartOptions{}).Return(nil)
+	expectExec(api, ctx, "abc123", "redirect",
+		[]string{"iptables", "-t", "nat", "-I", "OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}, 0)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.ProxyContainer(ctx, req))
+}
+
+func TestProxyContainer_RedirectFailureRemovesProxy(t *testing.T) {
+	ctx := context.TODO()
+	req := testProxyRequest()
+	api := NewMockEngine(t)
+	api.EXPECT().ContainerCreate(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "pumba-dns-proxy-abc123").
+		Return(ctypes.CreateResponse{ID: "proxyID"}, nil)
+	api.EXPECT().ContainerStart(ctx, "proxyID", ctypes.StartOptions{}).Return(nil)
+	expectExec(api, ctx, "abc123", "redirect",
+		[]string{"iptables", "-t", "nat", "-I", "OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}, 1)
+	api.EXPECT().ContainerRemove(mock.Anything, "proxyID", ctypes.RemoveOptions{Force: true}).Return(nil)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	err := client.ProxyContainer(ctx, req)
+	assert.ErrorContains(t, err, "failed to redirect traffic to dns-proxy")
+}
+
+func TestStopProxyContainer_RemovesRedirectsAndProxy(t *testing.T) {
+	ctx := context.TODO()
+	req := testProxyRequest()
+	api := NewMockEngine(t)
+	expectExec(api, ctx, "abc123", "redirect",
+		[]string{"iptables", "-t", "nat", "-D", "OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}, 0)
+	api.EXPECT().ContainerRemove(mock.Anything, "pumba-dns-proxy-abc123", ctypes.RemoveOptions{Force: true}).Return(nil)
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	require.NoError(t, client.StopProxyContainer(ctx, req))
+}
+
+func TestStopProxyContainer_RemovesProxyWhenRedirectRemovalFails(t *testing.T) {
+	ctx := context.TODO()
+	req := testProxyRequest()
+	api := NewMockEngine(t)
+	expectExec(api, ctx, "abc123", "redirect",
+		[]string{"iptables", "-t", "nat", "-D", "OUTPUT", "-p", "udp", "--dport", "53", "-j", "REDIRECT", "--to-ports", "15353"}, 1)
+	api.EXPECT().ContainerRemove(mock.Anything, "pumba-dns-proxy-abc123", ctypes.RemoveOptions{Force: true}).Return(errors.New("boom"))
+
+	client := dockerClient{containerAPI: api, imageAPI: api}
+	err := client.StopProxyContainer(ctx, req)
+	assert.ErrorContains(t, err, "failed to remove dns-proxy redirects")
+	assert.ErrorContains(t, err, "failed to remove dns-proxy container")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/alexei-led/pumba/pkg/tracing"
 	imagetypes "github.com/docker/docker/api/types/image"
 	log "github.com/sirupsen/logrus"
 )
@@ -24,7 +25,9 @@ type imagePullResponse struct {
 }
 
 // pullImage pulls a Docker image and drains the progress stream.
-func (client dockerClient) pullImage(ctx context.Context, img string) error {
+func (client dockerClient) pullImage(ctx context.Context, img string) (err error) {
+	ctx, span := tracing.Start(ctx, "image.pull", tracing.AttrImage.String(img))
+	defer func() { tracing.End(span, err) }()
 	log.WithField("img", img).Debug("pulling image")
 	events, err := client.imageAPI.ImagePull(ctx, img, imagetypes.PullOptions{})
 	if err != nil {
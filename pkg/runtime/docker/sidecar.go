@@ -10,6 +10,7 @@ import (
 	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/tracing"
 	cerrdefs "github.com/containerd/errdefs"
 	ctypes "github.com/docker/docker/api/types/container"
 	imagetypes "github.com/docker/docker/api/types/image"
@@ -30,7 +31,9 @@ const (
 // when the caller's ctx was canceled by SIGTERM — otherwise pumba would
 // leak the sidecar AND the rules it installed in the target's netns,
 // because the caller early-returns on this error.
-func (client dockerClient) removeSidecar(ctx context.Context, id string) error {
+func (client dockerClient) removeSidecar(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "sidecar.remove", tracing.AttrContainerID.String(id))
+	defer func() { tracing.End(span, err) }()
 	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sidecarRemoveTimeout)
 	defer cancel()
 	if err := client.containerAPI.ContainerRemove(cleanupCtx, id, ctypes.RemoveOptions{Force: true}); err != nil {
@@ -69,8 +72,33 @@ func (client dockerClient) sidecarRemovalComplete(ctx context.Context, id string
 // network namespace, runs argsList through `tool` (tc or iptables), and is
 // force-removed on completion. Used by both netem and iptables paths.
 func (client dockerClient) runSidecar(ctx context.Context, target *ctr.Container, argsList [][]string, img, tool string, pull bool) error {
+	_, err := client.runSidecarOutput(ctx, target, argsList, img, tool, pull)
+	return err
+}
+
+// runSidecarOutput is runSidecar returning the combined stdout of all
+// commands, for callers that read state from the target's netns (ip link).
+// A nil target gives the sidecar a network namespace of its own, for the
+// doctor probes.
+func (client dockerClient) runSidecarOutput(ctx context.Context, target *ctr.Container, argsList [][]string, img, tool string, pull bool) (string, error) {
+	return client.sidecarOutput(ctx, target, false, argsList, img, tool, pull)
+}
+
+// sidecarOutput is runSidecarOutput, with sharePID also joining the target's
+// PID namespace, for sidecars that see and signal its processes.
+func (client dockerClient) sidecarOutput(ctx context.Context, target *ctr.Container, sharePID bool, argsList [][]string, img, tool string, pull bool) (_ string, err error) {
+	targetID, networkMode, pidMode := "", ctypes.NetworkMode("none"), ctypes.PidMode("")
+	if target != nil {
+		targetID, networkMode = target.ID(), ctypes.NetworkMode("container:"+target.ID())
+		if sharePID {
+			pidMode = ctypes.PidMode("container:" + target.ID())
+		}
+	}
+	ctx, span := tracing.Start(ctx, "sidecar", tracing.AttrTool.String(tool), tracing.AttrImage.String(img),
+		tracing.AttrContainerID.String(targetID))
+	defer func() { tracing.End(span, err) }()
 	log.WithFields(log.Fields{
-		"container": target.ID(),
+		"container": targetID,
 		"img":       img,
 		"tool":      tool,
 		"pull":      pull,
@@ -80,7 +108,8 @@ func (client dockerClient) runSidecar(ctx context.Context, target *ctr.Container
 	hconfig := ctypes.HostConfig{
 		AutoRemove:   false,
 		CapAdd:       []string{"NET_ADMIN"},
-		NetworkMode:  ctypes.NetworkMode("container:" + target.ID()),
+		NetworkMode:  networkMode,
+		PidMode:      pidMode,
 		PortBindings: nat.PortMap{},
 		DNS:          []string{},
 		DNSOptions:   []string{},
@@ -89,7 +118,7 @@ func (client dockerClient) runSidecar(ctx context.Context, target *ctr.Container
 	log.WithField("network", hconfig.NetworkMode).Debug("network mode")
 	if pull {
 		if err := client.pullSidecarImage(ctx, img, tool); err != nil {
-			return err
+			return "", err
 		}
 	}
 
@@ -100,7 +129,7 @@ func (client dockerClient) runSidecar(ctx context.Context, target *ctr.Container
 	// SIGTERM, which otherwise makes Podman wait the full 10 s StopTimeout
 	// before escalating (~tens of seconds per chaos cycle).
 	config := ctypes.Config{
-		Labels:     map[string]string{"com.gaiaadm.pumba.skip": "true"},
+		Labels:     ctr.SidecarLabels(tool, targetID),
 		Entrypoint: []string{"tail"},
 		Cmd:        []string{"-f", "/dev/null"},
 		Image:      img,
@@ -110,28 +139,51 @@ func (client dockerClient) runSidecar(ctx context.Context, target *ctr.Container
 	log.WithField("img", config.Image).Debugf("creating %s-container", tool)
 	createResponse, err := client.containerAPI.ContainerCreate(ctx, &config, &hconfig, nil, nil, "")
 	if err != nil {
-		return fmt.Errorf("failed to create %s-container from image %q: %w", tool, img, err)
+		return "", fmt.Errorf("failed to create %s-container from image %q: %w", tool, img, err)
 	}
 	log.WithField("id", createResponse.ID).Debugf("%s container created, starting it", tool)
 	if err = client.containerAPI.ContainerStart(ctx, createResponse.ID, ctypes.StartOptions{}); err != nil {
 		_ = client.removeSidecar(ctx, createResponse.ID)
-		return fmt.Errorf("failed to start %s-container: %w", tool, err)
+		return "", fmt.Errorf("failed to start %s-container: %w", tool, err)
 	}
 
+	var output strings.Builder
 	for _, args := range argsList {
-		if err = client.runSidecarExec(ctx, createResponse.ID, tool, args); err != nil {
+		out, err := client.runSidecarExec(ctx, createResponse.ID, tool, args)
+		if err != nil {
 			_ = client.removeSidecar(ctx, createResponse.ID)
-			return fmt.Errorf("error running %s command on container: %v: %w", tool, strings.Join(args, " "), err)
+			return "", fmt.Errorf("error running %s command on container: %v: %w", tool, strings.Join(args, " "), err)
 		}
+		output.Write(out)
 	}
 
 	if err = client.removeSidecar(ctx, createResponse.ID); err != nil {
-		return fmt.Errorf("failed to remove %s-container: %w", tool, err)
+		return "", fmt.Errorf("failed to remove %s-container: %w", tool, err)
 	}
-	return nil
+	return output.String(), nil
 }
 
-func (client dockerClient) pullSidecarImage(ctx context.Context, img, tool string) error {
+// toolOutput runs tool with each of argsList in the target's network
+// namespace, in a sidecar of img or, without an image, in the target itself,
+// and returns the combined stdout.
+func (client dockerClient) toolOutput(ctx context.Context, target *ctr.Container, tool string, argsList [][]string, img string, pull bool) (string, error) {
+	if img != "" {
+		return client.runSidecarOutput(ctx, target, argsList, img, tool, pull)
+	}
+	var out strings.Builder
+	for _, args := range argsList {
+		stdout, err := client.execOnContainerOutput(ctx, target, tool, args, true)
+		if err != nil {
+			return "", fmt.Errorf("error running %s command on container: %v: %w", tool, strings.Join(args, " "), err)
+		}
+		out.WriteString(stdout)
+	}
+	return out.String(), nil
+}
+
+func (client dockerClient) pullSidecarImage(ctx context.Context, img, tool string) (err error) {
+	ctx, span := tracing.Start(ctx, "image.pull", tracing.AttrImage.String(img), tracing.AttrTool.String(tool))
+	defer func() { tracing.End(span, err) }()
 	log.WithField("img", img).Debugf("pulling %s-img", tool)
 	events, err := client.imageAPI.ImagePull(ctx, img, imagetypes.PullOptions{})
 	if err != nil {
@@ -152,10 +204,13 @@ func (client dockerClient) pullSidecarImage(ctx context.Context, img, tool strin
 }
 
 // runSidecarExec creates and runs an exec inside the sidecar container,
-// invoking `tool` (tc or iptables) with args. The exit code is inspected so
-// that a non-zero status (e.g. tc rejecting bad args, iptables rule rejected
-// by kernel) surfaces as an error instead of silent success.
-func (client dockerClient) runSidecarExec(ctx context.Context, sidecarID, tool string, args []string) error {
+// invoking `tool` (tc or iptables) with args, and returns its stdout. The exit
+// code is inspected so that a non-zero status (e.g. tc rejecting bad args,
+// iptables rule rejected by kernel) surfaces as an error instead of silent
+// success.
+func (client dockerClient) runSidecarExec(ctx context.Context, sidecarID, tool string, args []string) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "sidecar.exec", tracing.AttrTool.String(tool), tracing.AttrArgs.StringSlice(args))
+	defer func() { tracing.End(span, err) }()
 	execConfig := ctypes.ExecOptions{
 		AttachStdout: true,
 		AttachStderr: true,
@@ -163,18 +218,19 @@ func (client dockerClient) runSidecarExec(ctx context.Context, sidecarID, tool s
 	}
 	execCreateResponse, err := client.containerAPI.ContainerExecCreate(ctx, sidecarID, execConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create %s-container exec: %w", tool, err)
+		return nil, fmt.Errorf("failed to create %s-container exec: %w", tool, err)
 	}
-	if err := client.runExecAttached(ctx, execCreateResponse.ID); err != nil {
-		return fmt.Errorf("failed to start %s-container exec: %w", tool, err)
+	out, err := client.execAttachedOutput(ctx, execCreateResponse.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s-container exec: %w", tool, err)
 	}
 	insp, err := client.containerAPI.ContainerExecInspect(ctx, execCreateResponse.ID)
 	if err != nil {
-		return fmt.Errorf("failed to inspect %s-container exec: %w", tool, err)
+		return nil, fmt.Errorf("failed to inspect %s-container exec: %w", tool, err)
 	}
 	if insp.ExitCode != 0 {
-		return fmt.Errorf("%s %s failed with exit code %d", tool, strings.Join(args, " "), insp.ExitCode)
+		return nil, fmt.Errorf("%s %s failed with exit code %d", tool, strings.Join(args, " "), insp.ExitCode)
 	}
 	log.WithField("args", strings.Join(args, " ")).Debugf("run command on %s-container", tool)
-	return nil
+	return out, nil
 }
@@ -51,7 +51,7 @@ func stressContainerConfig(targetID string, stressors []string, img, driver, cgr
 			}).Debug("using inject-cgroup mode with driver-based path")
 		}
 		return ctypes.Config{
-				Labels:     map[string]string{"com.gaiaadm.pumba.skip": "true"},
+				Labels:     ctr.SidecarLabels(ctr.SidecarKindStress, targetID),
 				Image:      img,
 				Entrypoint: []string{"/cg-inject"},
 				Cmd:        cmd,
@@ -64,7 +64,7 @@ func stressContainerConfig(targetID string, stressors []string, img, driver, cgr
 	// default child-cgroup mode: use --cgroup-parent with the resolved path
 	log.WithField("cgroup-parent", cgroupParent).Debug("resolved cgroup parent")
 	return ctypes.Config{
-			Labels:     map[string]string{"com.gaiaadm.pumba.skip": "true"},
+			Labels:     ctr.SidecarLabels(ctr.SidecarKindStress, targetID),
 			Image:      img,
 			Entrypoint: []string{"/stress-ng"},
 			Cmd:        stressors,
@@ -0,0 +1,332 @@
+// Package nftables translates the iptables argument lists built by the
+// runtime adapters into equivalent `nft` invocations. Every rule lands in a
+// dedicated `inet pumba` table so Pumba never edits chains owned by the host
+// or by other tools, and removal is a single `delete table`.
+//
+// The translator understands exactly the iptables vocabulary Pumba emits
+// (chain selection, interface, protocol, address and port matches, the
+// statistic, conntrack, hashlimit and connlimit modules and a terminal
+// verdict); anything else is rejected so an
+// unsupported rule fails loudly instead of silently matching more traffic.
+package nftables
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Tool is the nftables userspace binary.
+	Tool = "nft"
+	// Family is the address family of the Pumba table; inet covers IPv4 and IPv6.
+	Family = "inet"
+	// Table is the dedicated table holding every Pumba-installed rule.
+	Table = "pumba"
+
+	// probabilityScale is the modulus used to express iptables' statistic
+	// --probability as an nft `numgen random` comparison (0.01% resolution).
+	probabilityScale = 10000
+)
+
+// ProbeArgs lists the tables in the current netns. A zero exit status means
+// the nft binary is present and the kernel speaks nf_tables.
+var ProbeArgs = []string{"list", "tables"}
+
+// Translate converts iptables argument lists (without the leading binary)
+// into nft argument lists. Install lists (-I/-A) are prefixed with the
+// idempotent table and base-chain declarations; removal lists (-D) collapse
+// into a single `delete table` since the table only ever holds Pumba rules.
+// Mixing install and removal lists in a single call is an error.
+func Translate(argsList [][]string) ([][]string, error) {
+	if len(argsList) == 0 {
+		return nil, nil
+	}
+	var (
+		rules   [][]string
+		chains  []string
+		removal bool
+	)
+	for i, args := range argsList {
+		r, err := parseRule(args)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			removal = r.remove
+		} else if r.remove != removal {
+			return nil, errors.New("nftables: cannot mix rule installation and removal in one batch")
+		}
+		if removal {
+			continue
+		}
+		if !slices.Contains(chains, r.chain) {
+			chains = append(chains, r.chain)
+		}
+		rules = append(rules, r.nftArgs())
+	}
+	if removal {
+		return [][]string{{"delete", "table", Family, Table}}, nil
+	}
+	commands := make([][]string, 0, 1+len(chains)+len(rules))
+	commands = append(commands, []string{"add", "table", Family, Table})
+	for _, chain := range chains {
+		commands = append(commands, []string{
+			"add", "chain", Family, Table, chain,
+			"{", "type", "filter", "hook", chain, "priority", "0", ";", "policy", "accept", ";", "}",
+		})
+	}
+	return append(commands, rules...), nil
+}
+
+// rule is the parsed form of a single iptables invocation.
+type rule struct {
+	remove  bool
+	insert  bool
+	chain   string
+	exprs   []string
+	verdict string
+}
+
+func (r *rule) nftArgs() []string {
+	verb := "add"
+	if r.insert {
+		verb = "insert"
+	}
+	args := make([]string, 0, 5+len(r.exprs)+1) //nolint:mnd
+	args = append(args, verb, "rule", Family, Table, r.chain)
+	args = append(args, r.exprs...)
+	return append(args, r.verdict)
+}
+
+//nolint:gocyclo // flat switch over the supported iptables vocabulary
+func parseRule(args []string) (*rule, error) {
+	if len(args) < 2 { //nolint:mnd
+		return nil, fmt.Errorf("nftables: incomplete iptables rule: %q", strings.Join(args, " "))
+	}
+	r := &rule{}
+	switch args[0] {
+	case "-I":
+		r.insert = true
+	case "-A":
+	case "-D":
+		r.remove = true
+	default:
+		return nil, fmt.Errorf("nftables: unsupported iptables command %q", args[0])
+	}
+	chain, err := hook(args[1])
+	if err != nil {
+		return nil, err
+	}
+	r.chain = chain
+
+	proto := ""
+	rest := args[2:]
+	next := func(opt string) (string, error) {
+		if len(rest) < 2 { //nolint:mnd
+			return "", fmt.Errorf("nftables: missing value for iptables option %q", opt)
+		}
+		v := rest[1]
+		rest = rest[1:]
+		return v, nil
+	}
+	for ; len(rest) > 0; rest = rest[1:] {
+		opt := rest[0]
+		switch opt {
+		case "-i", "-o":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			key := "iifname"
+			if opt == "-o" {
+				key = "oifname"
+			}
+			r.exprs = append(r.exprs, key, v)
+		case "-p":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			proto = v
+			r.exprs = append(r.exprs, "meta", "l4proto", v)
+		case "-s", "-d":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			family := "ip"
+			if strings.Contains(v, ":") {
+				family = "ip6"
+			}
+			key := "saddr"
+			if opt == "-d" {
+				key = "daddr"
+			}
+			r.exprs = append(r.exprs, family, key, v)
+		case "--sport", "--dport":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			if proto != "tcp" && proto != "udp" {
+				return nil, fmt.Errorf("nftables: %s requires -p tcp or -p udp", opt)
+			}
+			r.exprs = append(r.exprs, proto, strings.TrimPrefix(opt, "--"), strings.ReplaceAll(v, ":", "-"))
+		case "-m":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			opts, consumed := moduleOptions(rest[1:])
+			exprs, err := match(v, opts)
+			if err != nil {
+				return nil, err
+			}
+			r.exprs = append(r.exprs, exprs...)
+			rest = rest[consumed:]
+		case "-j":
+			v, err := next(opt)
+			if err != nil {
+				return nil, err
+			}
+			verdict, err := target(v)
+			if err != nil {
+				return nil, err
+			}
+			r.verdict = verdict
+		default:
+			return nil, fmt.Errorf("nftables: unsupported iptables option %q", opt)
+		}
+	}
+	if r.verdict == "" && !r.remove {
+		return nil, fmt.Errorf("nftables: iptables rule without target: %q", strings.Join(args, " "))
+	}
+	return r, nil
+}
+
+// moduleOptions collects the `--key value` pairs following a match module
+// name and reports how many arguments it consumed.
+func moduleOptions(args []string) (map[string]string, int) {
+	opts := map[string]string{}
+	consumed := 0
+	for consumed+1 < len(args) {
+		key := args[consumed]
+		if !strings.HasPrefix(key, "--") || strings.HasPrefix(args[consumed+1], "-") {
+			break
+		}
+		opts[key] = args[consumed+1]
+		consumed += 2
+	}
+	return opts, consumed
+}
+
+// match translates a `-m <module>` match with its options.
+func match(module string, opts map[string]string) ([]string, error) {
+	switch module {
+	case "statistic":
+		return statistic(opts)
+	case "conntrack":
+		state := opts["--ctstate"]
+		if state == "" {
+			return nil, errors.New("nftables: conntrack match without --ctstate")
+		}
+		return []string{"ct", "state", strings.ToLower(state)}, nil
+	case "hashlimit":
+		return hashlimit(opts)
+	case "connlimit":
+		return connlimit(opts)
+	default:
+		return nil, fmt.Errorf("nftables: unsupported iptables match module %q", module)
+	}
+}
+
+func statistic(opts map[string]string) ([]string, error) {
+	switch opts["--mode"] {
+	case "random":
+		p, err := strconv.ParseFloat(opts["--probability"], 64)
+		if err != nil || p < 0 || p > 1 {
+			return nil, fmt.Errorf("nftables: invalid statistic probability %q", opts["--probability"])
+		}
+		threshold := int(math.Round(p * probabilityScale))
+		return []string{"numgen", "random", "mod", strconv.Itoa(probabilityScale), "<", strconv.Itoa(threshold)}, nil
+	case "nth":
+		every, err := strconv.Atoi(opts["--every"])
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("nftables: invalid statistic every %q", opts["--every"])
+		}
+		packet := 0
+		if v, ok := opts["--packet"]; ok {
+			if packet, err = strconv.Atoi(v); err != nil || packet < 0 || packet >= every {
+				return nil, fmt.Errorf("nftables: invalid statistic packet %q", v)
+			}
+		}
+		return []string{"numgen", "inc", "mod", strconv.Itoa(every), "==", strconv.Itoa(packet)}, nil
+	default:
+		return nil, fmt.Errorf("nftables: unsupported statistic mode %q", opts["--mode"])
+	}
+}
+
+// hashlimit maps --hashlimit-above onto `limit rate over`; per-source limits
+// (--hashlimit-mode srcip) become a meter keyed on the source address.
+func hashlimit(opts map[string]string) ([]string, error) {
+	rate := opts["--hashlimit-above"]
+	if rate == "" {
+		return nil, errors.New("nftables: hashlimit match without --hashlimit-above")
+	}
+	limit := []string{"limit", "rate", "over", rate}
+	if burst, ok := opts["--hashlimit-burst"]; ok {
+		limit = append(limit, "burst", burst, "packets")
+	}
+	switch opts["--hashlimit-mode"] {
+	case "":
+		return limit, nil
+	case "srcip":
+		name := opts["--hashlimit-name"]
+		if name == "" {
+			name = Table
+		}
+		meter := append([]string{"meter", name + "_hashlimit", "{", "ip", "saddr"}, limit...)
+		return append(meter, "}"), nil
+	default:
+		return nil, fmt.Errorf("nftables: unsupported hashlimit mode %q", opts["--hashlimit-mode"])
+	}
+}
+
+// connlimit maps --connlimit-above onto `ct count over`; a /32 mask counts
+// per source address through a meter, a /0 mask counts globally.
+func connlimit(opts map[string]string) ([]string, error) {
+	above := opts["--connlimit-above"]
+	if above == "" {
+		return nil, errors.New("nftables: connlimit match without --connlimit-above")
+	}
+	switch opts["--connlimit-mask"] {
+	case "0":
+		return []string{"ct", "count", "over", above}, nil
+	case "", "32":
+		return []string{"meter", Table + "_connlimit", "{", "ip", "saddr", "ct", "count", "over", above, "}"}, nil
+	default:
+		return nil, fmt.Errorf("nftables: unsupported connlimit mask %q", opts["--connlimit-mask"])
+	}
+}
+
+func hook(chain string) (string, error) {
+	switch chain {
+	case "INPUT", "OUTPUT", "FORWARD":
+		return strings.ToLower(chain), nil
+	default:
+		return "", fmt.Errorf("nftables: unsupported iptables chain %q", chain)
+	}
+}
+
+func target(jump string) (string, error) {
+	switch jump {
+	case "DROP", "ACCEPT", "REJECT":
+		return strings.ToLower(jump), nil
+	default:
+		return "", fmt.Errorf("nftables: unsupported iptables target %q", jump)
+	}
+}
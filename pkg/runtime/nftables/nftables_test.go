@@ -0,0 +1,118 @@
+package nftables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var inputChain = []string{
+	"add", "chain", "inet", "pumba", "input",
+	"{", "type", "filter", "hook", "input", "priority", "0", ";", "policy", "accept", ";", "}",
+}
+
+func TestTranslate_Install(t *testing.T) {
+	tests := []struct {
+		name string
+		args [][]string
+		want [][]string
+	}{
+		{
+			name: "random loss on interface",
+			args: [][]string{{"-I", "INPUT", "-i", "eth0", "-m", "statistic", "--mode", "random", "--probability", "0.25", "-j", "DROP"}},
+			want: [][]string{
+				{"add", "table", "inet", "pumba"},
+				inputChain,
+				{"insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "numgen", "random", "mod", "10000", "<", "2500", "drop"},
+			},
+		},
+		{
+			name: "nth loss with protocol and port filters",
+			args: [][]string{
+				{"-I", "INPUT", "-i", "eth0", "-p", "tcp", "--dport", "80", "-m", "statistic", "--mode", "nth", "--every", "5", "--packet", "2", "-j", "DROP"},
+				{"-I", "INPUT", "-i", "eth0", "-p", "tcp", "-s", "10.0.0.0/24", "-m", "statistic", "--mode", "nth", "--every", "5", "--packet", "2", "-j", "DROP"},
+			},
+			want: [][]string{
+				{"add", "table", "inet", "pumba"},
+				inputChain,
+				{"insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "meta", "l4proto", "tcp", "tcp", "dport", "80", "numgen", "inc", "mod", "5", "==", "2", "drop"},
+				{"insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "meta", "l4proto", "tcp", "ip", "saddr", "10.0.0.0/24", "numgen", "inc", "mod", "5", "==", "2", "drop"},
+			},
+		},
+		{
+			name: "per-source rate throttle",
+			args: [][]string{{"-I", "INPUT", "-i", "eth0", "-p", "tcp", "-m", "conntrack", "--ctstate", "NEW",
+				"-m", "hashlimit", "--hashlimit-above", "10/second", "--hashlimit-burst", "5", "--hashlimit-mode", "srcip", "--hashlimit-name", "pumba", "-j", "DROP"}},
+			want: [][]string{
+				{"add", "table", "inet", "pumba"},
+				inputChain,
+				{"insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "meta", "l4proto", "tcp", "ct", "state", "new",
+					"meter", "pumba_hashlimit", "{", "ip", "saddr", "limit", "rate", "over", "10/second", "burst", "5", "packets", "}", "drop"},
+			},
+		},
+		{
+			name: "global connection cap",
+			args: [][]string{{"-I", "INPUT", "-i", "eth0", "-m", "conntrack", "--ctstate", "NEW",
+				"-m", "connlimit", "--connlimit-above", "20", "--connlimit-mask", "0", "-j", "DROP"}},
+			want: [][]string{
+				{"add", "table", "inet", "pumba"},
+				inputChain,
+				{"insert", "rule", "inet", "pumba", "input", "iifname", "eth0", "ct", "state", "new", "ct", "count", "over", "20", "drop"},
+			},
+		},
+		{
+			name: "append with ipv6 destination",
+			args: [][]string{{"-A", "INPUT", "-d", "fd00::/64", "-j", "DROP"}},
+			want: [][]string{
+				{"add", "table", "inet", "pumba"},
+				inputChain,
+				{"add", "rule", "inet", "pumba", "input", "ip6", "daddr", "fd00::/64", "drop"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Translate(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTranslate_RemoveCollapsesToDeleteTable(t *testing.T) {
+	got, err := Translate([][]string{
+		{"-D", "INPUT", "-i", "eth0", "-s", "10.0.0.1/32", "-j", "DROP"},
+		{"-D", "INPUT", "-i", "eth0", "-s", "10.0.0.2/32", "-j", "DROP"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"delete", "table", "inet", "pumba"}}, got)
+}
+
+func TestTranslate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    [][]string
+		wantErr string
+	}{
+		{"unknown command", [][]string{{"-N", "INPUT"}}, "unsupported iptables command"},
+		{"unknown chain", [][]string{{"-I", "PREROUTING", "-j", "DROP"}}, "unsupported iptables chain"},
+		{"unknown option", [][]string{{"-I", "INPUT", "--tcp-flags", "SYN", "-j", "DROP"}}, "unsupported iptables option"},
+		{"unknown module", [][]string{{"-I", "INPUT", "-m", "string", "-j", "DROP"}}, "unsupported iptables match module"},
+		{"unknown target", [][]string{{"-I", "INPUT", "-j", "LOG"}}, "unsupported iptables target"},
+		{"missing value", [][]string{{"-I", "INPUT", "-i"}}, "missing value"},
+		{"port without protocol", [][]string{{"-I", "INPUT", "--dport", "80", "-j", "DROP"}}, "requires -p tcp"},
+		{"no target", [][]string{{"-I", "INPUT", "-i", "eth0"}}, "without target"},
+		{"bad probability", [][]string{{"-I", "INPUT", "-m", "statistic", "--mode", "random", "--probability", "2", "-j", "DROP"}}, "invalid statistic probability"},
+		{"bad packet", [][]string{{"-I", "INPUT", "-m", "statistic", "--mode", "nth", "--every", "2", "--packet", "2", "-j", "DROP"}}, "invalid statistic packet"},
+		{"hashlimit without rate", [][]string{{"-I", "INPUT", "-m", "hashlimit", "--hashlimit-burst", "5", "-j", "DROP"}}, "without --hashlimit-above"},
+		{"bad connlimit mask", [][]string{{"-I", "INPUT", "-m", "connlimit", "--connlimit-above", "5", "--connlimit-mask", "24", "-j", "DROP"}}, "unsupported connlimit mask"},
+		{"mixed batch", [][]string{{"-I", "INPUT", "-j", "DROP"}, {"-D", "INPUT", "-j", "DROP"}}, "cannot mix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Translate(tt.args)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package podman
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
 	"github.com/alexei-led/pumba/pkg/runtime/docker"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
 	"github.com/docker/docker/api/types"
 	ctypes "github.com/docker/docker/api/types/container"
 	imagetypes "github.com/docker/docker/api/types/image"
@@ -62,9 +64,25 @@ var (
 //     stop-without-start on a rootless socket fails with the same diagnostic.
 //   - IPTablesContainer     — same rootless constraint as NetemContainer.
 //   - StopIPTablesContainer — mirrors the IPTablesContainer rootless guard.
+//     NetemStatus and IPTablesStatus are inherited: they only read back
+//     state after a guarded injection has succeeded.
+//   - ProxyContainer        — same rootless constraint as IPTablesContainer
+//     (the nat redirects need NET_ADMIN in the target's netns).
+//   - StopProxyContainer    — mirrors the ProxyContainer rootless guard.
+//   - LinkContainer         — same rootless constraint as NetemContainer
+//     (`ip link set` needs NET_ADMIN in the target's netns). The network
+//     attach methods are inherited: Podman's compat API implements
+//     network disconnect/connect for rootful and rootless sockets alike.
+//     SignalProcesses is inherited too: its exec, or its sidecar joining
+//     the target's PID namespace, needs only the default KILL capability.
 //   - StressContainer       — diverges in cgroup leaf naming
 //     (libpod-<id>.scope vs Docker's docker-<id>.scope) and in the
 //     `--cgroup-parent` host-config path; see stress.go and cgroup.go.
+//   - Diagnose              — fails the rootless check of the Docker
+//     delegate on a rootless socket, where the guards above reject every
+//     command that needs kernel privileges.
+//   - WatchContainers       — not part of ctr.Client, so not promoted from
+//     the delegate; forwards to its /events stream, which Podman serves.
 //
 // Embedding invariant: when adding a method to ctr.Client, audit Podman
 // behavior — either confirm Docker's implementation works unchanged on the
@@ -170,3 +188,60 @@ func (p *podmanClient) StopIPTablesContainer(ctx context.Context, req *ctr.IPTab
 	}
 	return p.Client.StopIPTablesContainer(ctx, req)
 }
+
+// ProxyContainer starts a proxy sidecar and installs nat redirects in the
+// target's network namespace. Same rootless constraint as IPTablesContainer.
+func (p *podmanClient) ProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	if p.rootless {
+		return rootlessError(req.Name, p.socketURI)
+	}
+	return p.Client.ProxyContainer(ctx, req)
+}
+
+// StopProxyContainer removes the redirects and proxy started by
+// ProxyContainer. Mirrors the rootless guard.
+func (p *podmanClient) StopProxyContainer(ctx context.Context, req *ctr.ProxyRequest) error {
+	if p.rootless {
+		return rootlessError(req.Name, p.socketURI)
+	}
+	return p.Client.StopProxyContainer(ctx, req)
+}
+
+// LinkContainer changes a network interface in the target's network
+// namespace. Same rootless constraint as NetemContainer.
+func (p *podmanClient) LinkContainer(ctx context.Context, req *ctr.LinkRequest) (string, error) {
+	if p.rootless {
+		return "", rootlessError("link", p.socketURI)
+	}
+	return p.Client.LinkContainer(ctx, req)
+}
+
+// Diagnose runs the Docker checks over the compat socket. A rootless socket
+// fails the rootless check: every command guarded above is rejected on it.
+func (p *podmanClient) Diagnose(ctx context.Context, req *ctr.DiagnoseRequest) []ctr.Check {
+	checks := p.Client.Diagnose(ctx, req)
+	if !p.rootless {
+		return checks
+	}
+	for i := range checks {
+		if checks[i].Name == preflight.CheckRootless {
+			checks[i] = preflight.Fail(preflight.CheckRootless, "rootless socket at "+p.socketURI,
+				rootlessError("network chaos and stress", p.socketURI).Error())
+		}
+	}
+	return checks
+}
+
+// WatchContainers streams container events from the compat /events endpoint
+// through the Docker delegate.
+func (p *podmanClient) WatchContainers(ctx context.Context) (<-chan ctr.Event, <-chan error) {
+	w, ok := p.Client.(ctr.Watcher)
+	if !ok {
+		errs := make(chan error, 1)
+		errs <- errors.New("podman runtime: the delegate does not stream events")
+		out := make(chan ctr.Event)
+		close(out)
+		return out, errs
+	}
+	return w.WatchContainers(ctx)
+}
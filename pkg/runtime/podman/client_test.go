@@ -8,6 +8,7 @@ import (
 	"time"
 
 	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/preflight"
 	"github.com/docker/docker/api/types/system"
 	dockerapi "github.com/docker/docker/client"
 	"github.com/stretchr/testify/mock"
@@ -145,6 +146,27 @@ func TestPodmanClient_RootlessGuards_ReturnError(t *testing.T) {
 		require.Contains(t, err.Error(), "iptables")
 		require.Contains(t, err.Error(), p.socketURI)
 	})
+
+	t.Run("ProxyContainer", func(t *testing.T) {
+		err := p.ProxyContainer(ctx, &ctr.ProxyRequest{Container: target, Name: "dns"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "dns")
+		require.Contains(t, err.Error(), p.socketURI)
+	})
+
+	t.Run("StopProxyContainer", func(t *testing.T) {
+		err := p.StopProxyContainer(ctx, &ctr.ProxyRequest{Container: target, Name: "dns"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "dns")
+		require.Contains(t, err.Error(), p.socketURI)
+	})
+
+	t.Run("LinkContainer", func(t *testing.T) {
+		_, err := p.LinkContainer(ctx, &ctr.LinkRequest{Container: target, Commands: [][]string{{"link", "set", "dev", "eth0", "down"}}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "link")
+		require.Contains(t, err.Error(), p.socketURI)
+	})
 }
 
 func TestPodmanClient_RootfulGuards_Delegate(t *testing.T) {
@@ -198,6 +220,17 @@ func TestPodmanClient_RootfulGuards_Delegate(t *testing.T) {
 	}
 	mockDelegate.EXPECT().StopIPTablesContainer(ctx, stopIPReq).Return(nil).Once()
 	require.NoError(t, p.StopIPTablesContainer(ctx, stopIPReq))
+
+	proxyReq := &ctr.ProxyRequest{Container: target, Name: "dns", Sidecar: ctr.SidecarSpec{Image: img}}
+	mockDelegate.EXPECT().ProxyContainer(ctx, proxyReq).Return(nil).Once()
+	require.NoError(t, p.ProxyContainer(ctx, proxyReq))
+	mockDelegate.EXPECT().StopProxyContainer(ctx, proxyReq).Return(nil).Once()
+	require.NoError(t, p.StopProxyContainer(ctx, proxyReq))
+
+	linkReq := &ctr.LinkRequest{Container: target, Commands: [][]string{{"link", "set", "dev", "eth0", "up"}}, Sidecar: ctr.SidecarSpec{Image: img}}
+	mockDelegate.EXPECT().LinkContainer(ctx, linkReq).Return("", nil).Once()
+	_, err := p.LinkContainer(ctx, linkReq)
+	require.NoError(t, err)
 }
 
 func TestPodmanClient_PromotedMethodsDelegate(t *testing.T) {
@@ -233,3 +266,58 @@ func stubFetchInfo(t *testing.T, fn func(context.Context, apiBackend) (system.In
 	fetchInfo = fn
 	t.Cleanup(func() { fetchInfo = orig })
 }
+
+func TestPodmanClient_Diagnose(t *testing.T) {
+	ctx := context.Background()
+	req := &ctr.DiagnoseRequest{Sidecar: "img"}
+	delegated := func() []ctr.Check {
+		return []ctr.Check{
+			preflight.Pass(preflight.CheckRuntime, "server 5.2.0"),
+			preflight.Warn(preflight.CheckRootless, "daemon runs rootless", "use a rootful daemon"),
+		}
+	}
+
+	t.Run("rootless", func(t *testing.T) {
+		mockDelegate := ctr.NewMockClient(t)
+		mockDelegate.EXPECT().Diagnose(ctx, req).Return(delegated()).Once()
+		p := &podmanClient{Client: mockDelegate, rootless: true, socketURI: "unix:///run/user/1000/podman/podman.sock"}
+
+		checks := p.Diagnose(ctx, req)
+		require.Len(t, checks, 2)
+		require.Equal(t, delegated()[0], checks[0])
+		require.Equal(t, ctr.CheckFail, checks[1].Status)
+		require.Contains(t, checks[1].Detail, p.socketURI)
+		require.Contains(t, checks[1].Hint, "--rootful")
+	})
+
+	t.Run("rootful", func(t *testing.T) {
+		mockDelegate := ctr.NewMockClient(t)
+		mockDelegate.EXPECT().Diagnose(ctx, req).Return(delegated()).Once()
+		p := &podmanClient{Client: mockDelegate, socketURI: "unix:///run/podman/podman.sock"}
+		require.Equal(t, delegated(), p.Diagnose(ctx, req))
+	})
+}
+
+// watchingDelegate is a Docker delegate streaming one event.
+type watchingDelegate struct {
+	*ctr.MockClient
+}
+
+func (watchingDelegate) WatchContainers(context.Context) (<-chan ctr.Event, <-chan error) {
+	out := make(chan ctr.Event, 1)
+	out <- ctr.Event{Kind: ctr.EventDie, ContainerID: "a1"}
+	close(out)
+	return out, make(chan error)
+}
+
+func TestPodmanClient_WatchContainers(t *testing.T) {
+	p := &podmanClient{Client: watchingDelegate{ctr.NewMockClient(t)}}
+	out, _ := p.WatchContainers(context.TODO())
+	require.Equal(t, ctr.Event{Kind: ctr.EventDie, ContainerID: "a1"}, <-out)
+
+	p = &podmanClient{Client: ctr.NewMockClient(t)}
+	out, errs := p.WatchContainers(context.TODO())
+	_, ok := <-out
+	require.False(t, ok)
+	require.EqualError(t, <-errs, "podman runtime: the delegate does not stream events")
+}
@@ -219,7 +219,7 @@ func TestStressContainer_DefaultSystemd_SetsCgroupParent(t *testing.T) {
 
 	require.NotNil(t, gotConfig)
 	require.Equal(t, "stress-ng:latest", gotConfig.Image)
-	require.Equal(t, "true", gotConfig.Labels[skipLabelKey])
+	require.Equal(t, ctr.SidecarLabels(ctr.SidecarKindStress, "abc123"), gotConfig.Labels)
 	require.Equal(t, []string{"/stress-ng"}, []string(gotConfig.Entrypoint))
 	require.Equal(t, []string{"--cpu", "2"}, []string(gotConfig.Cmd))
 
@@ -391,9 +391,9 @@ func TestStressContainer_StartError(t *testing.T) {
 }
 
 func TestBuildStressConfig_DefaultSystemd(t *testing.T) {
-	cfg, hc := buildStressConfig("img", []string{"--cpu", "1"}, driverSystemd, "/machine.slice/libpod-abc.scope", "/machine.slice", "/machine.slice/libpod-abc.scope", false)
+	cfg, hc := buildStressConfig("abc", "img", []string{"--cpu", "1"}, driverSystemd, "/machine.slice/libpod-abc.scope", "/machine.slice", "/machine.slice/libpod-abc.scope", false)
 	require.Equal(t, "img", cfg.Image)
-	require.Equal(t, "true", cfg.Labels[skipLabelKey])
+	require.Equal(t, ctr.SidecarLabels(ctr.SidecarKindStress, "abc"), cfg.Labels)
 	require.Equal(t, []string{"/stress-ng"}, []string(cfg.Entrypoint))
 	require.Equal(t, []string{"--cpu", "1"}, []string(cfg.Cmd))
 	require.True(t, hc.AutoRemove)
@@ -402,14 +402,14 @@ func TestBuildStressConfig_DefaultSystemd(t *testing.T) {
 }
 
 func TestBuildStressConfig_DefaultCgroupfs(t *testing.T) {
-	cfg, hc := buildStressConfig("img", []string{"--cpu", "1"}, driverCgroupfs, "/libpod/abc", "/libpod", "/libpod/abc", false)
+	cfg, hc := buildStressConfig("abc", "img", []string{"--cpu", "1"}, driverCgroupfs, "/libpod/abc", "/libpod", "/libpod/abc", false)
 	require.Equal(t, []string{"/stress-ng"}, []string(cfg.Entrypoint))
 	require.Equal(t, []string{"--cpu", "1"}, []string(cfg.Cmd))
 	require.Equal(t, "/libpod/abc", hc.Resources.CgroupParent, "cgroupfs nests sidecar under target's full path for shared OOM scope")
 }
 
 func TestBuildStressConfig_Inject(t *testing.T) {
-	cfg, hc := buildStressConfig("img", []string{"--cpu", "1"}, driverSystemd, "/machine.slice/libpod-abc.scope", "/machine.slice", "/machine.slice/libpod-abc.scope/container", true)
+	cfg, hc := buildStressConfig("abc", "img", []string{"--cpu", "1"}, driverSystemd, "/machine.slice/libpod-abc.scope", "/machine.slice", "/machine.slice/libpod-abc.scope/container", true)
 	require.Equal(t, []string{"/cg-inject"}, []string(cfg.Entrypoint))
 	require.Equal(t,
 		[]string{"--cgroup-path", "/machine.slice/libpod-abc.scope/container", "--", "/stress-ng", "--cpu", "1"},
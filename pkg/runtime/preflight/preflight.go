@@ -0,0 +1,159 @@
+// Package preflight holds the runtime-independent parts of the `pumba doctor`
+// checks: the check names, the cgroup verdict and the sidecar image and
+// kernel probes every runtime runs the same way. The probes run in a
+// throwaway nettools sidecar with a network namespace of its own, so they
+// prove the sidecar gets NET_ADMIN and the host kernel provides netem and
+// the packet filter tables without touching any container.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/runtime/nftables"
+)
+
+// Check names.
+const (
+	CheckRuntime      = "runtime"
+	CheckCgroup       = "cgroup"
+	CheckRootless     = "rootless"
+	CheckImage        = "image"
+	CheckCapabilities = "capabilities"
+	CheckNetem        = "sch_netem"
+	CheckIPTables     = "iptables"
+	CheckNFTables     = "nftables"
+)
+
+// Probe is a command proving that a capability or kernel feature needed by
+// chaos commands is available.
+type Probe struct {
+	Name     string
+	Tool     string
+	ArgsList [][]string
+	// Optional probes warn instead of failing: they cover features only
+	// some commands use.
+	Optional bool
+	Detail   string
+	Hint     string
+}
+
+// Probes run, in order, in a nettools sidecar with a network namespace of
+// its own.
+var Probes = []Probe{
+	{
+		Name:     CheckCapabilities,
+		Tool:     "ip",
+		ArgsList: [][]string{{"link", "set", "dev", "lo", "up"}},
+		Detail:   "sidecar is granted NET_ADMIN",
+		Hint:     "the runtime refused NET_ADMIN to the sidecar; run Pumba against a rootful runtime that allows adding capabilities",
+	},
+	{
+		Name:     CheckNetem,
+		Tool:     "tc",
+		ArgsList: [][]string{{"qdisc", "add", "dev", "lo", "root", "netem", "delay", "1ms"}},
+		Detail:   "netem qdisc can be installed",
+		Hint:     "load the netem kernel module on the host: modprobe sch_netem (some distributions ship it in a kernel-modules-extra package)",
+	},
+	{
+		Name:     CheckIPTables,
+		Tool:     "iptables",
+		ArgsList: [][]string{{"-S"}, {"-t", "nat", "-S"}},
+		Detail:   "filter and nat tables are available",
+		Hint:     "load ip_tables and iptable_nat on the host, or use the iptables command with --backend nftables",
+	},
+	{
+		Name:     CheckNFTables,
+		Tool:     nftables.Tool,
+		ArgsList: [][]string{nftables.ProbeArgs},
+		Optional: true,
+		Detail:   "nftables backend is available",
+		Hint:     "--backend nftables needs the nf_tables kernel module and nft in the sidecar image",
+	},
+}
+
+// Pass returns a passed check.
+func Pass(name, detail string) ctr.Check {
+	return ctr.Check{Name: name, Status: ctr.CheckPass, Detail: detail}
+}
+
+// Warn returns a warned check.
+func Warn(name, detail, hint string) ctr.Check {
+	return ctr.Check{Name: name, Status: ctr.CheckWarn, Detail: detail, Hint: hint}
+}
+
+// Fail returns a failed check.
+func Fail(name, detail, hint string) ctr.Check {
+	return ctr.Check{Name: name, Status: ctr.CheckFail, Detail: detail, Hint: hint}
+}
+
+// Cgroup checks the cgroup driver and version reported by the runtime. The
+// stress command places its sidecar in the target's cgroup and only knows
+// the layouts of the systemd and cgroupfs drivers.
+func Cgroup(driver, version string) ctr.Check {
+	detail := fmt.Sprintf("%s driver, cgroup v%s", driver, version)
+	switch driver {
+	case "systemd", "cgroupfs":
+		return Pass(CheckCgroup, detail)
+	case "":
+		return Warn(CheckCgroup, "runtime does not report its cgroup driver",
+			"stress assumes the cgroupfs layout; if it fails to find the target cgroup, use --inject-cgroup")
+	default:
+		return Warn(CheckCgroup, detail,
+			"stress supports the systemd and cgroupfs drivers; if it fails to find the target cgroup, use --inject-cgroup")
+	}
+}
+
+// Sidecar checks that the images of req are available, pulling them when
+// req.Pull is set, and runs the Probes in the nettools image. ensure pulls
+// an image or looks it up locally; run executes a tool with each of
+// argsList in a fresh sidecar of img.
+func Sidecar(ctx context.Context, req *ctr.DiagnoseRequest,
+	ensure func(ctx context.Context, img string, pull bool) error,
+	run func(ctx context.Context, img, tool string, argsList [][]string) error,
+) []ctr.Check {
+	hint := "pull the image first or pass --pull-image"
+	if req.Pull {
+		hint = "check the image reference, registry access and credentials"
+	}
+	var checks []ctr.Check
+	var seen []string
+	sidecarOK := false
+	for _, img := range append([]string{req.Sidecar}, req.Images...) {
+		if img == "" || slices.Contains(seen, img) {
+			continue
+		}
+		seen = append(seen, img)
+		if err := ensure(ctx, img, req.Pull); err != nil {
+			checks = append(checks, Fail(CheckImage, fmt.Sprintf("%s: %v", img, err), hint))
+			continue
+		}
+		checks = append(checks, Pass(CheckImage, img+" is available"))
+		sidecarOK = sidecarOK || img == req.Sidecar
+	}
+	for _, p := range Probes {
+		if !sidecarOK {
+			checks = append(checks, Warn(p.Name, "skipped: no nettools sidecar image to probe with", "fix the image check above"))
+			continue
+		}
+		if err := run(ctx, req.Sidecar, p.Tool, p.ArgsList); err != nil {
+			detail := strings.TrimSpace(err.Error())
+			if p.Optional {
+				checks = append(checks, Warn(p.Name, detail, p.Hint))
+			} else {
+				checks = append(checks, Fail(p.Name, detail, p.Hint))
+			}
+			continue
+		}
+		checks = append(checks, Pass(p.Name, p.Detail))
+	}
+	return checks
+}
+
+// Failed reports whether any of checks failed.
+func Failed(checks []ctr.Check) bool {
+	return slices.ContainsFunc(checks, func(c ctr.Check) bool { return c.Status == ctr.CheckFail })
+}
@@ -0,0 +1,77 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ctr "github.com/alexei-led/pumba/pkg/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCgroup(t *testing.T) {
+	tests := []struct {
+		driver, version string
+		status          string
+	}{
+		{"systemd", "2", ctr.CheckPass},
+		{"cgroupfs", "1", ctr.CheckPass},
+		{"none", "1", ctr.CheckWarn},
+		{"", "", ctr.CheckWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			c := Cgroup(tt.driver, tt.version)
+			assert.Equal(t, CheckCgroup, c.Name)
+			assert.Equal(t, tt.status, c.Status)
+		})
+	}
+	assert.Equal(t, "systemd driver, cgroup v2", Cgroup("systemd", "2").Detail)
+}
+
+func TestSidecar(t *testing.T) {
+	var ensured []string
+	ensure := func(_ context.Context, img string, pull bool) error {
+		assert.True(t, pull)
+		ensured = append(ensured, img)
+		return nil
+	}
+	run := func(_ context.Context, img, tool string, _ [][]string) error {
+		assert.Equal(t, "nettools", img)
+		switch tool {
+		case "iptables":
+			return errors.New("iptables -S failed with exit code 3")
+		case "nft":
+			return errors.New("nft: not found")
+		}
+		return nil
+	}
+
+	checks := Sidecar(context.TODO(), &ctr.DiagnoseRequest{
+		Sidecar: "nettools", Images: []string{"stress-ng", "nettools", ""}, Pull: true,
+	}, ensure, run)
+	assert.Equal(t, []string{"nettools", "stress-ng"}, ensured, "images are checked once")
+	assert.Equal(t, []ctr.Check{
+		Pass(CheckImage, "nettools is available"),
+		Pass(CheckImage, "stress-ng is available"),
+		Pass(CheckCapabilities, Probes[0].Detail),
+		Pass(CheckNetem, Probes[1].Detail),
+		Fail(CheckIPTables, "iptables -S failed with exit code 3", Probes[2].Hint),
+		Warn(CheckNFTables, "nft: not found", Probes[3].Hint),
+	}, checks)
+	assert.True(t, Failed(checks))
+}
+
+func TestSidecar_ImageUnavailable(t *testing.T) {
+	ensure := func(context.Context, string, bool) error { return errors.New("No such image") }
+	run := func(context.Context, string, string, [][]string) error {
+		t.Fatal("probes must not run without the nettools image")
+		return nil
+	}
+	checks := Sidecar(context.TODO(), &ctr.DiagnoseRequest{Sidecar: "nettools"}, ensure, run)
+	assert.Len(t, checks, 1+len(Probes))
+	assert.Equal(t, Fail(CheckImage, "nettools: No such image", "pull the image first or pass --pull-image"), checks[0])
+	for _, c := range checks[1:] {
+		assert.Equal(t, ctr.CheckWarn, c.Status)
+	}
+}
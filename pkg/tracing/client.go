@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	"github.com/alexei-led/pumba/pkg/intercept"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Interceptor starts a span around every runtime call. Cleanup calls run on
+// contexts detached from cancellation, which keep the span of the action
+// they revert as parent.
+type Interceptor struct{}
+
+// NewClient returns a client passing calls to client in spans.
+func NewClient(client container.Client) *intercept.Client {
+	return intercept.New(client, Interceptor{})
+}
+
+// Intercept runs the call in a span named after its action.
+func (Interceptor) Intercept(ctx context.Context, call *intercept.Call, next func(context.Context) error) error {
+	attrs := []attribute.KeyValue{AttrAction.String(call.Action), AttrCleanup.Bool(call.Cleanup), AttrDryRun.Bool(call.DryRun)}
+	if c := call.Container; c != nil {
+		attrs = append(attrs, AttrContainerID.String(c.ID()), AttrContainerName.String(intercept.Name(c)))
+	}
+	if call.Tool != "" {
+		attrs = append(attrs, AttrTool.String(call.Tool))
+	}
+	if call.Image != "" {
+		attrs = append(attrs, AttrImage.String(call.Image))
+	}
+	if len(call.Commands) > 0 {
+		lines := make([]string, 0, len(call.Commands))
+		for _, cmd := range call.Commands {
+			lines = append(lines, strings.Join(cmd, " "))
+		}
+		attrs = append(attrs, AttrArgs.StringSlice(lines))
+	}
+	if call.Duration != 0 {
+		attrs = append(attrs, attribute.String("pumba.duration", call.Duration.String()))
+	}
+	for k, v := range call.Params {
+		attrs = append(attrs, attribute.String("pumba."+strings.ReplaceAll(k, "-", "_"), v))
+	}
+	ctx, span := Start(ctx, "runtime."+call.Action, attrs...)
+	err := next(ctx)
+	if result, ok := call.Result.(*container.ExecResult); ok {
+		span.SetAttributes(attribute.Int("pumba.exit_code", result.ExitCode))
+	}
+	End(span, err)
+	return err
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHook correlates log entries with traces: an entry logged with a context
+// carrying a span (log.WithContext) gets the trace_id and span_id fields,
+// seen by the formatter and by hooks added after it, e.g. the Slack hook,
+// and is added as an event to the span when it is recording.
+type LogHook struct{}
+
+// Levels returns all levels.
+func (LogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire adds the span context of the entry, if any.
+func (LogHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	span := trace.SpanFromContext(entry.Context)
+	sc := span.SpanContext()
+	if !sc.IsValid() {
+		return nil
+	}
+	if span.IsRecording() {
+		attrs := make([]attribute.KeyValue, 0, len(entry.Data)+1)
+		attrs = append(attrs, attribute.String("log.severity", entry.Level.String()))
+		for k, v := range entry.Data {
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+		}
+		span.AddEvent(entry.Message, trace.WithAttributes(attrs...))
+	}
+	entry.Data["trace_id"] = sc.TraceID().String()
+	entry.Data["span_id"] = sc.SpanID().String()
+	return nil
+}
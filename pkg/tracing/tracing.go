@@ -0,0 +1,113 @@
+// Package tracing puts Pumba's faults on the OpenTelemetry timeline of the
+// services under test. Chaos runs, per-container actions, runtime calls,
+// sidecars and image pulls start spans through Start; Start is a no-op until
+// Setup installs a tracer provider exporting the spans via OTLP/HTTP.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the instrumentation scope of Pumba's spans.
+const TracerName = "github.com/alexei-led/pumba"
+
+// tracesPath is the OTLP/HTTP path of the traces signal.
+const tracesPath = "/v1/traces"
+
+// Setup installs a global tracer provider exporting spans to endpoint,
+// either host:port (plain HTTP when insecure) or a URL; a URL without a path
+// gets the OTLP traces path. The returned func flushes pending spans and
+// shuts the provider down.
+func Setup(ctx context.Context, endpoint string, insecure bool, version string) (func(context.Context) error, error) {
+	var opts []otlptracehttp.Option
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("bad OTLP endpoint %q: %w", endpoint, err)
+		}
+		if u.Path == "" || u.Path == "/" {
+			u.Path = tracesPath
+		}
+		opts = append(opts, otlptracehttp.WithEndpointURL(u.String()))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "pumba"),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+	return provider.Shutdown, nil
+}
+
+// enabled is set by Setup; until then Start leaves contexts untouched.
+var enabled atomic.Bool
+
+// Start starts a span named name, a child of the span in ctx. Without Setup
+// it returns ctx and a no-op span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !enabled.Load() {
+		return ctx, noop.Span{}
+	}
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// AddEvent records an event on the span in ctx.
+func AddEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// Attributes of Pumba's spans.
+const (
+	// AttrCommand is the chaos command, e.g. netem.
+	AttrCommand = attribute.Key("pumba.command")
+	// AttrAction is the runtime action, e.g. pause or netem.
+	AttrAction = attribute.Key("pumba.action")
+	// AttrCleanup marks the actions reverting an earlier one.
+	AttrCleanup = attribute.Key("pumba.cleanup")
+	// AttrDryRun marks actions only logged.
+	AttrDryRun = attribute.Key("pumba.dry_run")
+	// AttrContainerID and AttrContainerName identify the target container.
+	AttrContainerID   = attribute.Key("container.id")
+	AttrContainerName = attribute.Key("container.name")
+	// AttrTool and AttrImage describe a sidecar: the tool it runs and its
+	// image.
+	AttrTool  = attribute.Key("pumba.tool")
+	AttrImage = attribute.Key("container.image.name")
+	// AttrArgs are the arguments of a command.
+	AttrArgs = attribute.Key("pumba.args")
+)
@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alexei-led/pumba/pkg/container"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is an in-process OTLP/HTTP traces endpoint.
+type collector struct {
+	mu    sync.Mutex
+	paths []string
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, r.URL.Path)
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			c.spans = append(c.spans, ss.GetSpans()...)
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(nil)
+}
+
+func (c *collector) span(name string) *tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.spans {
+		if s.GetName() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func attr(s *tracepb.Span, key string) string {
+	for _, kv := range s.GetAttributes() {
+		if kv.GetKey() == key {
+			return kv.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+// setupCollector starts a collector and exports to it until the test ends.
+func setupCollector(t *testing.T, endpoint func(*httptest.Server) string, insecure bool) (*collector, func(context.Context) error) {
+	t.Helper()
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	shutdown, err := Setup(context.TODO(), endpoint(srv), insecure, "test")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		enabled.Store(false)
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+	return c, shutdown
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx := context.TODO()
+	got, span := Start(ctx, "chaos.run")
+	assert.Equal(t, ctx, got, "without Setup contexts are left untouched")
+	assert.False(t, span.IsRecording())
+}
+
+func TestSetup_ExportsRuntimeCallsAndLogEvents(t *testing.T) {
+	c, shutdown := setupCollector(t, func(srv *httptest.Server) string { return srv.URL }, false)
+
+	web := &container.Container{ContainerID: "w1", ContainerName: "/web"}
+	client := container.NewMockClient(t)
+	client.EXPECT().PauseContainer(mock.Anything, web, false).Return(nil).Once()
+
+	var out strings.Builder
+	logger := log.New()
+	logger.SetOutput(&out)
+	logger.AddHook(LogHook{})
+
+	ctx, span := Start(context.TODO(), "chaos.container", AttrContainerName.String("web"))
+	require.NoError(t, NewClient(client).PauseContainer(ctx, web, false))
+	logger.WithContext(ctx).WithField("duration", "10s").Warn("pausing container")
+	End(span, nil)
+	require.NoError(t, shutdown(context.TODO()))
+
+	assert.Equal(t, []string{"/v1/traces"}, c.paths)
+	parent, call := c.span("chaos.container"), c.span("runtime.pause")
+	require.NotNil(t, parent)
+	require.NotNil(t, call)
+	assert.Equal(t, parent.GetSpanId(), call.GetParentSpanId())
+	assert.Equal(t, "w1", attr(call, "container.id"))
+	assert.Equal(t, "web", attr(call, "container.name"))
+	assert.Equal(t, "pause", attr(call, "pumba.action"))
+	require.Len(t, parent.GetEvents(), 1)
+	assert.Equal(t, "pausing container", parent.GetEvents()[0].GetName())
+	assert.Contains(t, out.String(), "span_id=", "log entries carry the span context")
+	assert.Contains(t, out.String(), "trace_id=")
+}
+
+func TestSetup_HostPortEndpoint(t *testing.T) {
+	c, shutdown := setupCollector(t, func(srv *httptest.Server) string { return srv.Listener.Addr().String() }, true)
+
+	client := container.NewMockClient(t)
+	req := &container.NetemRequest{Container: &container.Container{ContainerID: "a1"}, Interface: "eth0", Command: []string{"delay", "1s"}}
+	client.EXPECT().StopNetemContainer(mock.Anything, req).Return(assert.AnError).Once()
+
+	require.Error(t, NewClient(client).StopNetemContainer(context.TODO(), req))
+	require.NoError(t, shutdown(context.TODO()))
+
+	call := c.span("runtime.netem")
+	require.NotNil(t, call)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, call.GetStatus().GetCode())
+	for _, kv := range call.GetAttributes() {
+		if kv.GetKey() == string(AttrCleanup) {
+			assert.True(t, kv.GetValue().GetBoolValue())
+		}
+	}
+}
+
+func TestLogHook_NoSpan(t *testing.T) {
+	entry := log.NewEntry(log.New()).WithContext(context.TODO())
+	require.NoError(t, LogHook{}.Fire(entry))
+	assert.NotContains(t, entry.Data, "trace_id")
+}